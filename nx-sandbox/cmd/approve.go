@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/approval"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	approveEnvironment  string
+	approveArchitecture string
+	approveApprover     string
+	approveList         bool
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <artifact>",
+	Short: color.GreenString("Approve infrastructure creation"),
+	Long: color.BlueString(`Approve infrastructure creation for an artifact in an environment.
+The approver must belong to one of the approver teams configured in
+.nx-sandbox/config.yaml. Approval sets infrastructure.enabled in the
+inventory and is recorded in .nx-sandbox/approvals.json.
+
+Examples:
+  nx-sandbox approve nx-bff-web-payment --env dev1
+  nx-sandbox approve nx-bff-web-payment --env sit1 --architecture individual --approver alice
+  nx-sandbox approve --list`),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if approveList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runApproveCmd,
+}
+
+func initApproveCmd() {
+	rootCmd.AddCommand(approveCmd)
+
+	approveCmd.Flags().StringVar(&approveEnvironment, "env", "", "Target environment")
+	approveCmd.Flags().StringVar(&approveArchitecture, "architecture", string(models.ArchitectureCentralized), "Architecture type (centralized, individual)")
+	approveCmd.Flags().StringVar(&approveApprover, "approver", os.Getenv("USER"), "User approving the request")
+	approveCmd.Flags().BoolVar(&approveList, "list", false, "List recorded approvals")
+}
+
+func runApproveCmd(cmd *cobra.Command, args []string) error {
+	baseDir := resolveBaseDir()
+	approver := approval.NewApprover(baseDir)
+
+	if approveList {
+		return listApprovals(approver)
+	}
+
+	if approveEnvironment == "" {
+		return fmt.Errorf("--env is required")
+	}
+
+	color.Cyan("🔐 Approving infrastructure for %s in %s...", args[0], approveEnvironment)
+
+	record, err := approver.Approve(approval.Request{
+		Artifact:     args[0],
+		Environment:  approveEnvironment,
+		Architecture: models.Architecture(approveArchitecture),
+		Approver:     approveApprover,
+	})
+	if err != nil {
+		color.Red("Error approving infrastructure: %v", err)
+		printApproverHint(baseDir, approveApprover)
+		return err
+	}
+
+	color.Green("✅ Infrastructure approved!")
+	fmt.Printf("   Artifact: %s\n", record.Artifact)
+	fmt.Printf("   Environment: %s\n", record.Environment)
+	fmt.Printf("   Architecture: %s\n", record.Architecture)
+	fmt.Printf("   Approved by: %s (%s)\n", record.Approver, record.Team)
+	if len(record.Components) > 0 {
+		fmt.Printf("   Components: %s\n", strings.Join(record.Components, ", "))
+	} else {
+		color.Yellow("   No components are enabled in the inventory yet")
+	}
+
+	path, err := inventory.Find(baseDir, record.Artifact, record.Environment)
+	if err != nil {
+		return err
+	}
+	return proposeChanges(fmt.Sprintf("Approve infrastructure for %s", record.Artifact), []string{path})
+}
+
+// printApproverHint explains how to configure approvers when user is not in
+// any approver team. The default teams have no members, so a sandbox without
+// its own config.yaml cannot approve until one is written.
+func printApproverHint(baseDir, user string) {
+	cfg, err := config.Load(baseDir)
+	if err != nil {
+		return
+	}
+	if _, ok := cfg.ApproverTeam(user); ok {
+		return
+	}
+
+	if user == "" {
+		user = "<user>"
+	}
+	color.Yellow("Add the approver to a team in %s, for example:", config.Path(baseDir))
+	fmt.Println("  approvers:")
+	fmt.Println("    teams:")
+	fmt.Printf("      platform-engineers: [%s]\n", user)
+}
+
+func listApprovals(approver approval.Approver) error {
+	records, err := approver.History()
+	if err != nil {
+		color.Red("Error reading approvals: %v", err)
+		return err
+	}
+
+	if len(records) == 0 {
+		color.Yellow("No approvals recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APPROVED AT\tARTIFACT\tENVIRONMENT\tARCHITECTURE\tAPPROVER\tCOMPONENTS")
+	fmt.Fprintln(w, "-----------\t--------\t-----------\t------------\t--------\t----------")
+	for _, record := range records {
+		components := strings.Join(record.Components, ",")
+		if components == "" {
+			components = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s (%s)\t%s\n",
+			record.ApprovedAt.Format("2006-01-02 15:04:05"),
+			record.Artifact,
+			record.Environment,
+			record.Architecture,
+			record.Approver,
+			record.Team,
+			components)
+	}
+	return w.Flush()
+}
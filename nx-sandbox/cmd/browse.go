@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/audit"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/browser"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	browseLayer  string
+	browseEnv    string
+	browseSource string
+	browseQuery  string
+)
+
+var browseCmd = &cobra.Command{
+	Use:   "browse",
+	Short: color.GreenString("Browse artifacts in an interactive terminal UI"),
+	Long: color.BlueString(`Browse inventory and environment artifacts in a terminal UI with fuzzy
+search, filters and a detail pane showing the inventory entry and values.
+
+Keys:
+  ↑/↓ j/k   move               /         fuzzy search
+  L E S     cycle layer, environment and source filters
+  P         show pinned only   p         pin or unpin
+  t         prepare for testing (copy into test-artifacts/), showing the
+            diff instead when the prepared copy has changes
+  T         prepare again, discarding changes to the prepared copy
+  enter     show the detail pane again
+  d         diff the prepared copy against the original
+  e         open in $EDITOR (the prepared copy when there is one)
+  J/K       scroll the detail pane
+  r         reload             q         quit
+
+Examples:
+  nx-sandbox browse
+  nx-sandbox browse --layer bff --env dev1
+  nx-sandbox browse --query seat`),
+	Args: cobra.NoArgs,
+	RunE: runBrowseCmd,
+}
+
+func initBrowseCmd() {
+	rootCmd.AddCommand(browseCmd)
+
+	browseCmd.Flags().StringVar(&browseLayer, "layer", "", "Start with a layer filter")
+	browseCmd.Flags().StringVar(&browseEnv, "env", "", "Start with an environment filter")
+	browseCmd.Flags().StringVar(&browseSource, "source", "", "Start with a source filter (inventory, environment)")
+	browseCmd.Flags().StringVar(&browseQuery, "query", "", "Start with a search")
+}
+
+func runBrowseCmd(cmd *cobra.Command, args []string) error {
+	baseDir := resolveBaseDir()
+	workspace := browser.NewWorkspace(baseDir)
+
+	artifacts, err := workspace.Artifacts()
+	if err != nil {
+		color.Red("Error listing artifacts: %v", err)
+		return err
+	}
+	pins, err := workspace.Pins()
+	if err != nil {
+		color.Red("Error reading pins: %v", err)
+		return err
+	}
+
+	model := browser.NewModel(artifacts, pins)
+	model.Color = !color.NoColor
+	model.Layer = browseLayer
+	model.Environment = browseEnv
+	model.Source = models.ArtifactSource(browseSource)
+	model.Query = browseQuery
+	model.SetArtifacts(artifacts)
+
+	term, err := browser.OpenTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		color.Red("Error opening terminal: %v", err)
+		return err
+	}
+	defer term.Close()
+
+	for {
+		detail := ""
+		if artifact, ok := model.Selected(); ok {
+			if detail, err = workspace.Detail(artifact); err != nil {
+				detail += "\n" + err.Error()
+			}
+		}
+
+		width, height := term.Size()
+		term.Draw(model.Render(width, height, detail))
+
+		key, err := term.ReadKey()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		action := model.HandleKey(key)
+		artifact, selected := model.Selected()
+
+		switch action {
+		case browser.ActionQuit:
+			return nil
+
+		case browser.ActionReload:
+			artifacts, err := workspace.Artifacts()
+			if err != nil {
+				model.Status = fmt.Sprintf("Error listing artifacts: %v", err)
+				continue
+			}
+			model.SetArtifacts(artifacts)
+			model.Status = fmt.Sprintf("Reloaded %d artifacts", len(artifacts))
+
+		case browser.ActionPin:
+			op := beginBrowseAudit(baseDir, "pin", artifact, filepath.Join(layout.StateDirName, browser.PinsFileName))
+			err := workspace.SavePins(model.Pins())
+			if err != nil {
+				model.Status = fmt.Sprintf("Error saving pins: %v", err)
+			}
+			finishBrowseAudit(model, op, err)
+
+		case browser.ActionPrepare, browser.ActionOverwrite:
+			if !selected {
+				continue
+			}
+			overwrite := action == browser.ActionOverwrite
+			if !overwrite {
+				if text, err := workspace.Diff(artifact); err == nil && text != "" {
+					model.ShowPane("diff", text)
+					model.Status = fmt.Sprintf("The prepared copy of %s differs from the original; press T to overwrite it", artifact.Name)
+					continue
+				}
+			}
+			op := beginBrowseAudit(baseDir, "prepare", artifact, relativeToBase(baseDir, workspace.TestDir(artifact)))
+			copied, err := workspace.Prepare(artifact, overwrite)
+			if err != nil {
+				model.Status = fmt.Sprintf("Error preparing %s: %v", artifact.Name, err)
+			} else {
+				model.Status = fmt.Sprintf("Prepared %d files in %s", len(copied), workspace.TestDir(artifact))
+			}
+			finishBrowseAudit(model, op, err)
+
+		case browser.ActionDiff:
+			if !selected {
+				continue
+			}
+			text, err := workspace.Diff(artifact)
+			switch {
+			case err != nil:
+				model.Status = err.Error()
+			case text == "":
+				model.Status = fmt.Sprintf("The prepared copy of %s has no changes", artifact.Name)
+			default:
+				model.ShowPane("diff", text)
+			}
+
+		case browser.ActionEdit:
+			if !selected {
+				continue
+			}
+			path, err := workspace.EditTarget(artifact)
+			if err != nil {
+				model.Status = err.Error()
+				continue
+			}
+			if err := term.Suspend(); err != nil {
+				return err
+			}
+			op := beginBrowseAudit(baseDir, "edit", artifact, relativeToBase(baseDir, path))
+			editErr := openEditor(path)
+			if err := term.Resume(); err != nil {
+				return err
+			}
+			if editErr != nil {
+				model.Status = fmt.Sprintf("Error running editor: %v", editErr)
+			} else {
+				model.Status = fmt.Sprintf("Edited %s", filepath.Base(filepath.Dir(path))+"/"+filepath.Base(path))
+			}
+			finishBrowseAudit(model, op, editErr)
+		}
+	}
+}
+
+// beginBrowseAudit starts auditing a browse action on an artifact. Each
+// action is recorded on its own, hashing only the path it may change.
+func beginBrowseAudit(baseDir, action string, artifact models.SandboxArtifact, root string) *audit.Operation {
+	args := []string{artifact.Name}
+	if artifact.Environment != "" {
+		args = append(args, "--env="+artifact.Environment)
+	}
+	return audit.Begin(baseDir, "browse "+action, args, currentUser(), root)
+}
+
+// finishBrowseAudit records a browse action, reporting audit log failures in
+// the status line since the terminal is in use
+func finishBrowseAudit(model *browser.Model, op *audit.Operation, err error) {
+	if auditErr := op.Finish(err); auditErr != nil {
+		model.Status = fmt.Sprintf("Failed to update the audit log: %v", auditErr)
+	}
+}
+
+// relativeToBase returns path relative to the sandbox root
+func relativeToBase(baseDir, path string) string {
+	if rel, err := filepath.Rel(baseDir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// openEditor opens path in $VISUAL or $EDITOR, falling back to vi
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/capacity"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/promote"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/pullrequest"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	capacityEnv      string
+	capacityServices bool
+	capacityOutput   string
+	capacityPR       string
+	capacityPromote  string
+	capacityFrom     string
+	capacityTo       string
+)
+
+var capacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: color.CyanString("Estimate the CPU, memory and cost of the charts"),
+	Long: color.BlueString(`Aggregate the CPU and memory of every chart by layer and environment from its
+values.yaml. The minimum uses replicaCount (or autoscaling.minReplicas) times
+the resource requests; the maximum uses autoscaling.maxReplicas times the
+limits. A missing limit falls back to the request and vice versa.
+
+Monthly costs come from the unit-price table in .nx-sandbox/config.yaml:
+
+  capacity:
+    currency: USD
+    hours_per_month: 730
+    prices:
+      cpu: 0.04048      # per core-hour
+      memory: 0.004445  # per GiB-hour
+    environments:
+      prod1:
+        cpu: 0.05
+
+With --pr or --promote, only the capacity a pending change would add or
+remove is shown, so sizing mistakes are caught before review.
+
+Examples:
+  nx-sandbox capacity
+  nx-sandbox capacity --env prod1 --services
+  nx-sandbox capacity --pr 3
+  nx-sandbox capacity --promote nx-bff-test-service --from uat1 -o json`),
+	RunE: runCapacityCmd,
+}
+
+func initCapacityCmd() {
+	rootCmd.AddCommand(capacityCmd)
+
+	capacityCmd.Flags().StringVar(&capacityEnv, "env", "", "Only include this environment")
+	capacityCmd.Flags().BoolVar(&capacityServices, "services", false, "List every service instead of the layer totals")
+	capacityCmd.Flags().StringVarP(&capacityOutput, "output", "o", "table", "Output format (table, json)")
+	capacityCmd.Flags().StringVar(&capacityPR, "pr", "", "Show the delta of an open pull request")
+	capacityCmd.Flags().StringVar(&capacityPromote, "promote", "", "Show the delta of promoting this service")
+	capacityCmd.Flags().StringVar(&capacityFrom, "from", "", "Source environment of --promote")
+	capacityCmd.Flags().StringVar(&capacityTo, "to", "", "Target environment of --promote (default the next environment)")
+	capacityCmd.MarkFlagsMutuallyExclusive("pr", "promote")
+	capacityCmd.MarkFlagsRequiredTogether("promote", "from")
+}
+
+func runCapacityCmd(cmd *cobra.Command, args []string) error {
+	if capacityOutput != "table" && capacityOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", capacityOutput)
+	}
+
+	baseDir := resolveBaseDir()
+	estimator := capacity.NewEstimator(baseDir)
+
+	var files map[string][]byte
+	var description string
+	switch {
+	case capacityPR != "":
+		id, err := parsePRID(capacityPR)
+		if err != nil {
+			return err
+		}
+		pr, err := pullrequest.NewManager(baseDir).Get(id)
+		if err != nil {
+			color.Red("Error reading pull request: %v", err)
+			return err
+		}
+		if files, err = capacity.PullRequestFiles(baseDir, pr); err != nil {
+			color.Red("Error reading pull request files: %v", err)
+			return err
+		}
+		description = fmt.Sprintf("#%d %s", pr.ID, pr.Title)
+	case capacityPromote != "":
+		to := capacityTo
+		if to == "" {
+			cfg, err := config.Load(baseDir)
+			if err != nil {
+				color.Red("Error loading config: %v", err)
+				return err
+			}
+			next, ok := cfg.NextEnvironment(capacityFrom)
+			if !ok {
+				return fmt.Errorf("%s has no next environment to promote to", capacityFrom)
+			}
+			to = next
+		}
+		plan, err := promote.NewPromoter(baseDir).Plan(promote.Request{
+			Service: capacityPromote,
+			From:    capacityFrom,
+			To:      to,
+		})
+		if err != nil {
+			color.Red("Error planning promotion: %v", err)
+			return err
+		}
+		files = capacity.PromotionFiles(plan)
+		description = fmt.Sprintf("promote %s from %s to %s", capacityPromote, capacityFrom, to)
+	default:
+		report, err := estimator.Estimate(capacityEnv)
+		if err != nil {
+			color.Red("Error estimating capacity: %v", err)
+			return err
+		}
+		if capacityOutput == "json" {
+			return printCapacityJSON(report)
+		}
+		return printCapacityReport(report)
+	}
+
+	delta, err := estimator.Delta(capacityEnv, description, files)
+	if err != nil {
+		color.Red("Error estimating capacity: %v", err)
+		return err
+	}
+	if capacityOutput == "json" {
+		return printCapacityJSON(delta)
+	}
+	return printCapacityDelta(delta)
+}
+
+func printCapacityJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func printCapacityReport(report *models.CapacityReport) error {
+	if len(report.Services) == 0 {
+		color.Yellow("No chart values found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if capacityServices {
+		fmt.Fprintln(w, "ENVIRONMENT\tLAYER\tSERVICE\tREPLICAS\tCPU\tMEMORY\tCOST/MONTH")
+		fmt.Fprintln(w, "-----------\t-----\t-------\t--------\t---\t------\t----------")
+		for _, s := range report.Services {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Environment, s.Layer, s.Service, capacityColumns(s.CapacityFigures, report.Currency))
+		}
+	} else {
+		fmt.Fprintln(w, "ENVIRONMENT\tLAYER\tSERVICES\tREPLICAS\tCPU\tMEMORY\tCOST/MONTH")
+		fmt.Fprintln(w, "-----------\t-----\t--------\t--------\t---\t------\t----------")
+		for _, l := range report.Layers {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", l.Environment, l.Layer, l.Services, capacityColumns(l.CapacityFigures, report.Currency))
+		}
+		for _, e := range report.Environments {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", e.Environment, "(all)", e.Services, capacityColumns(e.CapacityFigures, report.Currency))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	for _, s := range report.Services {
+		for _, warning := range s.Warnings {
+			color.Yellow("⚠️  %s/%s: %s", s.Environment, s.Service, warning)
+		}
+	}
+	color.Cyan("📊 Total: %s", capacitySummary(report.Total, report.Currency))
+	return nil
+}
+
+func printCapacityDelta(delta *models.CapacityDelta) error {
+	color.Cyan("📊 Capacity delta of %s", delta.Description)
+	fmt.Println()
+
+	if len(delta.Changes) == 0 {
+		color.Green("✅ No capacity change")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENVIRONMENT\tLAYER\tSERVICE\tREPLICAS\tCPU\tMEMORY\tCOST/MONTH")
+	fmt.Fprintln(w, "-----------\t-----\t-------\t--------\t---\t------\t----------")
+	for _, c := range delta.Changes {
+		var before, after models.CapacityFigures
+		if c.Before != nil {
+			before = *c.Before
+		}
+		if c.After != nil {
+			after = *c.After
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Environment, c.Layer, c.Service, capacityDeltaColumns(before, after, delta.Currency))
+	}
+	for _, l := range delta.Layers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.Environment, l.Layer, "(layer)", capacityDeltaColumns(l.Before, l.After, delta.Currency))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Before: %s\n", capacitySummary(delta.Before, delta.Currency))
+	fmt.Printf("After:  %s\n", capacitySummary(delta.After, delta.Currency))
+	summary := fmt.Sprintf("Delta:  %s max CPU, %s max memory, %s/month max",
+		signed(delta.After.MaxCPU-delta.Before.MaxCPU, "%.2f"),
+		signed((delta.After.MaxMemory-delta.Before.MaxMemory)/(1<<30), "%.2f GiB"),
+		signed(delta.After.MaxCost-delta.Before.MaxCost, "%.2f "+delta.Currency))
+	if delta.After.MaxCost > delta.Before.MaxCost {
+		color.Yellow("%s", summary)
+	} else {
+		color.Green("%s", summary)
+	}
+	return nil
+}
+
+// capacityColumns renders the replica, CPU, memory and cost columns as min–max ranges
+func capacityColumns(f models.CapacityFigures, currency string) string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s",
+		minMax(fmt.Sprint(f.MinReplicas), fmt.Sprint(f.MaxReplicas)),
+		minMax(fmt.Sprintf("%.2f", f.MinCPU), fmt.Sprintf("%.2f", f.MaxCPU)),
+		minMax(formatGiB(f.MinMemory), formatGiB(f.MaxMemory)),
+		minMax(fmt.Sprintf("%.2f", f.MinCost), fmt.Sprintf("%.2f", f.MaxCost))+" "+currency)
+}
+
+// capacityDeltaColumns renders the maximum of each column before and after a change
+func capacityDeltaColumns(before, after models.CapacityFigures, currency string) string {
+	return fmt.Sprintf("%d → %d\t%.2f → %.2f (%s)\t%s → %s\t%.2f → %.2f %s (%s)",
+		before.MaxReplicas, after.MaxReplicas,
+		before.MaxCPU, after.MaxCPU, signed(after.MaxCPU-before.MaxCPU, "%.2f"),
+		formatGiB(before.MaxMemory), formatGiB(after.MaxMemory),
+		before.MaxCost, after.MaxCost, currency, signed(after.MaxCost-before.MaxCost, "%.2f"))
+}
+
+func capacitySummary(f models.CapacityFigures, currency string) string {
+	return fmt.Sprintf("%s replicas, %s CPU, %s memory, %s %s/month",
+		minMax(fmt.Sprint(f.MinReplicas), fmt.Sprint(f.MaxReplicas)),
+		minMax(fmt.Sprintf("%.2f", f.MinCPU), fmt.Sprintf("%.2f", f.MaxCPU)),
+		minMax(formatGiB(f.MinMemory), formatGiB(f.MaxMemory)),
+		minMax(fmt.Sprintf("%.2f", f.MinCost), fmt.Sprintf("%.2f", f.MaxCost)), currency)
+}
+
+func minMax(min, max string) string {
+	if min == max {
+		return max
+	}
+	return min + "–" + max
+}
+
+// formatGiB renders a memory size in bytes as GiB, the unit it is priced in
+func formatGiB(bytes float64) string {
+	return fmt.Sprintf("%.2f GiB", bytes/(1<<30))
+}
+
+func signed(value float64, format string) string {
+	if value >= 0 {
+		return "+" + fmt.Sprintf(format, value)
+	}
+	return fmt.Sprintf(format, value)
+}
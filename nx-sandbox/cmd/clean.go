@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/BritishAirways-Nexus/nx-sandbox/internal/sandbox"
@@ -8,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var cleanDryRun bool
+
 var cleanCmd = &cobra.Command{
 	Use:   "clean",
 	Short: color.RedString("Clean sandbox artifacts"),
@@ -15,12 +18,15 @@ var cleanCmd = &cobra.Command{
 This removes test artifacts older than 7 days and local artifacts older than 30 days.
 
 Examples:
-  nx-sandbox clean`),
+  nx-sandbox clean
+  nx-sandbox clean --dry-run`),
 	RunE: runCleanCmd,
 }
 
 func initCleanCmd() {
 	rootCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "List the artifacts that would be removed without removing them")
 }
 
 func runCleanCmd(cmd *cobra.Command, args []string) error {
@@ -37,6 +43,28 @@ func runCleanCmd(cmd *cobra.Command, args []string) error {
 	// Create sandbox manager
 	manager := sandbox.NewSandboxManager(baseDir)
 
+	candidates, err := manager.CleanPlan()
+	if err != nil {
+		color.Red("Error during cleanup: %v", err)
+		return err
+	}
+
+	verb := "Removing"
+	if cleanDryRun {
+		verb = "Would remove"
+	}
+
+	var total int64
+	for _, candidate := range candidates {
+		fmt.Printf("%s old artifact: %s (%s, older than %s)\n", verb, candidate.Path, formatBytes(candidate.Size), candidate.MaxAge)
+		total += candidate.Size
+	}
+
+	if cleanDryRun {
+		color.Yellow("🔍 Dry run: %d artifacts (%s) would be removed", len(candidates), formatBytes(total))
+		return nil
+	}
+
 	// Perform cleanup
 	if err := manager.Clean(); err != nil {
 		color.Red("Error during cleanup: %v", err)
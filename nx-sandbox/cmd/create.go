@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/scaffold"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createLayer       string
+	createDomain      string
+	createService     string
+	createDescription string
+	createOwner       string
+	createEnvs        string
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: color.GreenString("Scaffold a new artifact"),
+	Long: color.BlueString(`Create the inventory entry and environment Helm charts for a new artifact.
+Files are rendered from templates; place overrides in .nx-sandbox/templates
+(nx-app-inventory.yaml.tmpl, Chart.yaml.tmpl, values.yaml.tmpl).
+Existing files are never overwritten.
+
+Examples:
+  nx-sandbox create nx-bff-web-loyalty --envs dev1,sit1
+  nx-sandbox create nx-tc-order-creator --layer tc --domain order --service order-creator --envs dev1`),
+	Args: cobra.ExactArgs(1),
+	RunE: runCreateCmd,
+}
+
+func initCreateCmd() {
+	rootCmd.AddCommand(createCmd)
+
+	createCmd.Flags().StringVar(&createLayer, "layer", "", "Artifact layer (defaults to the layer in the name)")
+	createCmd.Flags().StringVar(&createDomain, "domain", "web", "Artifact domain")
+	createCmd.Flags().StringVar(&createService, "service", "", "Service name (defaults to the name without the nx-<layer>- prefix)")
+	createCmd.Flags().StringVar(&createDescription, "description", "", "Artifact description")
+	createCmd.Flags().StringVar(&createOwner, "owner", "devx-team", "Owning team")
+	createCmd.Flags().StringVar(&createEnvs, "envs", "dev1", "Comma-separated list of environments")
+}
+
+func runCreateCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	color.Cyan("🏗️  Creating artifact %s...", name)
+
+	scaffolder := scaffold.NewScaffolder(resolveBaseDir())
+
+	created, err := scaffolder.Create(scaffold.ArtifactRequest{
+		Name:         name,
+		Layer:        createLayer,
+		Domain:       createDomain,
+		Service:      createService,
+		Description:  createDescription,
+		Owner:        createOwner,
+		Environments: splitList(createEnvs),
+	})
+	if err != nil {
+		color.Red("Error creating artifact: %v", err)
+		return err
+	}
+
+	color.Green("✅ Created %d file(s):", len(created))
+	for _, path := range created {
+		fmt.Printf("   + %s\n", path)
+	}
+
+	return proposeChanges(fmt.Sprintf("Create artifact %s", name), created)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
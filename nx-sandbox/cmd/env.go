@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/envvars"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envEnvironment string
+	envFromFile    string
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: color.YellowString("Manage artifact environment variables"),
+	Long: color.BlueString(`Manage the env section of an artifact's Helm values in an environment repository.
+Variable names must be uppercase. Values that look like secrets are refused
+unless given as a secret reference: secretRef:<secret-name>/<key>.
+
+Examples:
+  nx-sandbox env list nx-bff-test-service --env dev1
+  nx-sandbox env set nx-bff-test-service --env dev1 LOG_LEVEL=debug FEATURE_X=true
+  nx-sandbox env set nx-bff-test-service --env dev1 DB_PASSWORD=secretRef:db-credentials/password
+  nx-sandbox env set nx-bff-test-service --env dev1 --from-file .env
+  nx-sandbox env unset nx-bff-test-service --env dev1 FEATURE_X`),
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list <artifact>",
+	Short: "List environment variables",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEnvListCmd,
+}
+
+var envSetCmd = &cobra.Command{
+	Use:   "set <artifact> [KEY=VALUE...]",
+	Short: "Set environment variables",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runEnvSetCmd,
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:   "unset <artifact> KEY...",
+	Short: "Remove environment variables",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runEnvUnsetCmd,
+}
+
+func initEnvCmd() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envListCmd, envSetCmd, envUnsetCmd)
+
+	envCmd.PersistentFlags().StringVar(&envEnvironment, "env", "", "Target environment")
+	envCmd.MarkPersistentFlagRequired("env")
+	envSetCmd.Flags().StringVar(&envFromFile, "from-file", "", "Import variables from a dotenv file")
+}
+
+func runEnvListCmd(cmd *cobra.Command, args []string) error {
+	manager := envvars.NewManager(resolveBaseDir())
+
+	vars, err := manager.List(args[0], envEnvironment)
+	if err != nil {
+		color.Red("Error listing environment variables: %v", err)
+		return err
+	}
+
+	if len(vars) == 0 {
+		color.Yellow("No environment variables set for %s in %s.", args[0], envEnvironment)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVALUE")
+	fmt.Fprintln(w, "----\t-----")
+	for _, v := range vars {
+		fmt.Fprintf(w, "%s\t%s\n", v.Name, formatEnvValue(v))
+	}
+	return w.Flush()
+}
+
+func runEnvSetCmd(cmd *cobra.Command, args []string) error {
+	var vars []models.EnvVar
+
+	if envFromFile != "" {
+		file, err := os.Open(envFromFile)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", envFromFile, err)
+		}
+		defer file.Close()
+
+		imported, err := envvars.ParseDotenv(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", envFromFile, err)
+		}
+		vars = append(vars, imported...)
+	}
+
+	for _, assignment := range args[1:] {
+		v, err := envvars.ParseAssignment(assignment)
+		if err != nil {
+			return err
+		}
+		vars = append(vars, v)
+	}
+
+	manager := envvars.NewManager(resolveBaseDir())
+	path, err := manager.Set(args[0], envEnvironment, vars)
+	if err != nil {
+		color.Red("Error setting environment variables: %v", err)
+		return err
+	}
+
+	color.Green("✅ Set %d variable(s) in %s", len(vars), path)
+	for _, v := range vars {
+		fmt.Printf("   %s=%s\n", v.Name, formatEnvValue(v))
+	}
+	return proposeChanges(fmt.Sprintf("Set environment variables for %s in %s", args[0], envEnvironment), []string{path})
+}
+
+func runEnvUnsetCmd(cmd *cobra.Command, args []string) error {
+	manager := envvars.NewManager(resolveBaseDir())
+
+	path, err := manager.Unset(args[0], envEnvironment, args[1:])
+	if err != nil {
+		color.Red("Error removing environment variables: %v", err)
+		return err
+	}
+
+	color.Green("✅ Removed %d variable(s) from %s", len(args)-1, path)
+	return proposeChanges(fmt.Sprintf("Remove environment variables for %s in %s", args[0], envEnvironment), []string{path})
+}
+
+func formatEnvValue(v models.EnvVar) string {
+	if v.SecretRef != nil {
+		return fmt.Sprintf("%s%s/%s", envvars.SecretRefPrefix, v.SecretRef.Name, v.SecretRef.Key)
+	}
+	return v.Value
+}
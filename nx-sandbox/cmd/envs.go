@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/parity"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envsLayer  string
+	envsOutput string
+	envsStrict bool
+)
+
+var envsCmd = &cobra.Command{
+	Use:   "envs",
+	Short: color.CyanString("Inspect the environment repositories"),
+}
+
+var envsCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare layers and charts across the environment repositories",
+	Long: color.BlueString(`Compare every nx-bolt-environment-* repository: the layers and services each
+environment has, charts whose version or appVersion differ between
+environments, and services that skip an environment of the promotion chain,
+e.g. present in dev1 and uat1 but not sit1.
+
+With --strict the command fails when the environments are not in parity,
+for use in CI together with -o json.
+
+Examples:
+  nx-sandbox envs compare
+  nx-sandbox envs compare --layer bff
+  nx-sandbox envs compare -o json --strict`),
+	Args: cobra.NoArgs,
+	RunE: runEnvsCompareCmd,
+}
+
+func initEnvsCmd() {
+	rootCmd.AddCommand(envsCmd)
+	envsCmd.AddCommand(envsCompareCmd)
+
+	envsCompareCmd.Flags().StringVar(&envsLayer, "layer", "", "Only compare this layer")
+	envsCompareCmd.Flags().StringVarP(&envsOutput, "output", "o", "table", "Output format (table, json)")
+	envsCompareCmd.Flags().BoolVar(&envsStrict, "strict", false, "Exit non-zero when the environments are not in parity")
+}
+
+func runEnvsCompareCmd(cmd *cobra.Command, args []string) error {
+	if envsOutput != "table" && envsOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", envsOutput)
+	}
+
+	report, err := parity.NewComparer(resolveBaseDir()).Compare(envsLayer)
+	if err != nil {
+		color.Red("Error comparing environments: %v", err)
+		return err
+	}
+
+	if envsOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else if err := printParityReport(report); err != nil {
+		return err
+	}
+
+	if envsStrict && !report.InParity {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("environments are not in parity")
+	}
+	return nil
+}
+
+func printParityReport(report *models.ParityReport) error {
+	color.Cyan("🌍 Environments: %s", strings.Join(report.Environments, " → "))
+	fmt.Println()
+
+	header := strings.Join(report.Environments, "\t")
+	var dashes []string
+	for _, env := range report.Environments {
+		dashes = append(dashes, strings.Repeat("-", len(env)))
+	}
+	underline := strings.Join(dashes, "\t")
+
+	color.Cyan("📂 Layers")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "LAYER\t%s\n", header)
+	fmt.Fprintf(w, "-----\t%s\n", underline)
+	for _, layer := range report.Layers {
+		present := make(map[string]bool)
+		for _, env := range layer.Environments {
+			present[env] = true
+		}
+		var cells []string
+		for _, env := range report.Environments {
+			cells = append(cells, presenceMark(present[env]))
+		}
+		fmt.Fprintf(w, "%s\t%s\n", layer.Layer, strings.Join(cells, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	color.Cyan("📦 Chart versions")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "LAYER\tSERVICE\t%s\n", header)
+	fmt.Fprintf(w, "-----\t-------\t%s\n", underline)
+	for _, service := range report.Services {
+		charts := make(map[string]models.ChartVersion)
+		for _, chart := range service.Charts {
+			charts[chart.Environment] = chart
+		}
+		var cells []string
+		for _, env := range report.Environments {
+			chart, ok := charts[env]
+			switch {
+			case !ok:
+				cells = append(cells, "-")
+			case chart.Version == "" && chart.AppVersion == "":
+				cells = append(cells, "no chart")
+			default:
+				cells = append(cells, fmt.Sprintf("%s (%s)", orDash(chart.Version), orDash(chart.AppVersion)))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", service.Layer, service.Service, strings.Join(cells, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	var differences int
+	for _, layer := range report.Layers {
+		if len(layer.Missing) > 0 {
+			color.Yellow("⚠️  Layer %s is missing from %s", layer.Layer, strings.Join(layer.Missing, ", "))
+			differences++
+		}
+	}
+	for _, service := range report.Services {
+		if len(service.Skipped) > 0 {
+			color.Red("❌ %s skips %s in the promotion chain", service.Service, strings.Join(service.Skipped, ", "))
+			differences++
+		} else if len(service.Missing) > 0 {
+			color.Yellow("⚠️  %s is missing from %s", service.Service, strings.Join(service.Missing, ", "))
+			differences++
+		}
+		if service.VersionDrift {
+			color.Yellow("⚠️  %s chart version differs: %s", service.Service, chartField(service.Charts, "version"))
+			differences++
+		}
+		if service.AppVersionDrift {
+			color.Yellow("⚠️  %s appVersion differs: %s", service.Service, chartField(service.Charts, "appVersion"))
+			differences++
+		}
+	}
+
+	if differences == 0 {
+		color.Green("✅ All environments are in parity")
+	} else {
+		fmt.Printf("\n%d difference(s) found\n", differences)
+	}
+	return nil
+}
+
+func presenceMark(present bool) string {
+	if present {
+		return "✓"
+	}
+	return "-"
+}
+
+// chartField lists a chart field per environment, e.g. dev1=1.1.0, sit1=1.0.0
+func chartField(charts []models.ChartVersion, field string) string {
+	var values []string
+	for _, chart := range charts {
+		value := chart.Version
+		if field == "appVersion" {
+			value = chart.AppVersion
+		}
+		values = append(values, fmt.Sprintf("%s=%s", chart.Environment, orDash(value)))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/golden"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/scaffold"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var goldenOutput string
+
+var goldenCmd = &cobra.Command{
+	Use:   "golden",
+	Short: color.GreenString("Check the scaffold templates against golden files"),
+	Long: color.BlueString(`Render the scaffold templates, including the overrides in
+.nx-sandbox/templates, for the cases declared in .nx-sandbox/golden.yaml and
+compare the output with the golden files in .nx-sandbox/golden/<case>/:
+
+  cases:
+    - name: loyalty
+      artifact: nx-bff-web-loyalty
+      domain: loyalty
+      environments: [dev1, prod1]
+
+Output is normalized before comparing: timestamps are replaced and YAML and
+JSON mapping keys are sorted, so only a change of content is a difference.`),
+}
+
+var goldenVerifyCmd = &cobra.Command{
+	Use:   "verify [case...]",
+	Short: "Compare the rendered templates with the golden files",
+	Long: color.BlueString(`Render every case, or only the given ones, and show a diff for each file
+that differs from its golden file, has no golden file or is no longer
+rendered. Fails when any file does not match.
+
+Examples:
+  nx-sandbox golden verify
+  nx-sandbox golden verify loyalty -o json`),
+	RunE: runGoldenVerifyCmd,
+}
+
+var goldenUpdateCmd = &cobra.Command{
+	Use:   "update [case...]",
+	Short: "Rewrite the golden files from the rendered templates",
+	Long: color.BlueString(`Render every case, or only the given ones, write the normalized output to
+the golden files and remove golden files that are no longer rendered.
+
+Examples:
+  nx-sandbox golden update
+  nx-sandbox golden update loyalty`),
+	RunE: runGoldenUpdateCmd,
+}
+
+func initGoldenCmd() {
+	rootCmd.AddCommand(goldenCmd)
+	goldenCmd.AddCommand(goldenVerifyCmd)
+	goldenCmd.AddCommand(goldenUpdateCmd)
+
+	goldenVerifyCmd.Flags().StringVarP(&goldenOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runGoldenVerifyCmd(cmd *cobra.Command, args []string) error {
+	if goldenOutput != "table" && goldenOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", goldenOutput)
+	}
+
+	report, err := runGoldenCases(args, golden.Verify)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return nil
+	}
+
+	if goldenOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printGoldenReport(report)
+	}
+
+	if failures := report.Failures(); failures > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d golden file(s) do not match", failures)
+	}
+	return nil
+}
+
+func runGoldenUpdateCmd(cmd *cobra.Command, args []string) error {
+	report, err := runGoldenCases(args, golden.Update)
+	if err != nil || report == nil {
+		return err
+	}
+
+	for _, c := range report.Cases {
+		for _, file := range c.Files {
+			switch file.Status {
+			case models.GoldenMissing:
+				fmt.Printf("   + %s\n", relativePath(file.Path))
+			case models.GoldenMismatch:
+				fmt.Printf("   ~ %s\n", relativePath(file.Path))
+			case models.GoldenStale:
+				fmt.Printf("   - %s\n", relativePath(file.Path))
+			}
+		}
+	}
+	if changed := report.Failures(); changed > 0 {
+		color.Green("✅ Updated %d golden file(s)", changed)
+	} else {
+		color.Green("✅ Golden files are up to date")
+	}
+	return nil
+}
+
+// runGoldenCases renders the selected cases and passes each to fn with its
+// golden directory. It returns nil when no cases are declared.
+func runGoldenCases(names []string, fn func(dir string, files map[string][]byte) ([]models.GoldenFile, error)) (*models.GoldenReport, error) {
+	baseDir := resolveBaseDir()
+	cases, err := scaffold.LoadGoldenCases(baseDir)
+	if err != nil {
+		color.Red("Error loading golden cases: %v", err)
+		return nil, err
+	}
+	if len(cases) == 0 {
+		color.Yellow("No golden cases declared in %s", scaffold.GoldenCasesPath(baseDir))
+		return nil, nil
+	}
+
+	selected := cases
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			found := false
+			for _, c := range cases {
+				if c.Name == name {
+					selected = append(selected, c)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown golden case '%s'", name)
+			}
+		}
+	}
+
+	report := &models.GoldenReport{}
+	for _, c := range selected {
+		files, err := scaffold.RenderGoldenCase(baseDir, c)
+		if err != nil {
+			color.Red("Error rendering templates: %v", err)
+			return nil, err
+		}
+		results, err := fn(scaffold.GoldenDir(baseDir, c.Name), files)
+		if err != nil {
+			color.Red("Error comparing golden files: %v", err)
+			return nil, err
+		}
+		report.Cases = append(report.Cases, models.GoldenCaseResult{Name: c.Name, Files: results})
+	}
+	return report, nil
+}
+
+func printGoldenReport(report *models.GoldenReport) {
+	files := 0
+	for _, c := range report.Cases {
+		for _, file := range c.Files {
+			files++
+			switch file.Status {
+			case models.GoldenMatch:
+				color.Green("✓ %s/%s", c.Name, file.Name)
+			case models.GoldenStale:
+				color.Red("✗ %s/%s is no longer rendered", c.Name, file.Name)
+			case models.GoldenMissing:
+				color.Red("✗ %s/%s has no golden file", c.Name, file.Name)
+				printDiff(file.Diff)
+			default:
+				color.Red("✗ %s/%s differs from %s", c.Name, file.Name, relativePath(file.Path))
+				printDiff(file.Diff)
+			}
+		}
+	}
+
+	fmt.Println()
+	if failures := report.Failures(); failures == 0 {
+		color.Green("✅ %d file(s) in %d case(s) match their golden files", files, len(report.Cases))
+	} else {
+		color.Red("❌ %d of %d file(s) do not match (run: nx-sandbox golden update)", failures, files)
+	}
+}
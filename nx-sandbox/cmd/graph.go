@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/graph"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphEnvironment string
+	graphOutput      string
+	graphImpact      string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: color.CyanString("Show which artifacts share infrastructure"),
+	Long: color.BlueString(`Build the dependency graph artifact → component → resource from the enabled
+inventory components and the external section of the Helm values. Artifacts
+that use the same Redis cluster_id or DynamoDB table share the resource.
+
+Without --output the resources are listed with the artifacts that depend on
+them. The graph can be exported as DOT, Mermaid or JSON; dependencies only
+declared in Helm values are drawn dashed. --impact lists the artifacts that
+break if a resource is removed, given as type/name or by name.
+
+Examples:
+  nx-sandbox graph
+  nx-sandbox graph --env dev1 -o dot | dot -Tsvg > graph.svg
+  nx-sandbox graph -o mermaid
+  nx-sandbox graph --impact redis/payments
+  nx-sandbox graph --impact nx-bff-web-payment-dev1 -o json`),
+	Args: cobra.NoArgs,
+	RunE: runGraphCmd,
+}
+
+func initGraphCmd() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().StringVar(&graphEnvironment, "env", "", "Only this environment")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "table", "Output format (table, dot, mermaid, json)")
+	graphCmd.Flags().StringVar(&graphImpact, "impact", "", "List the artifacts that depend on a resource")
+}
+
+func runGraphCmd(cmd *cobra.Command, args []string) error {
+	switch graphOutput {
+	case "table", "json":
+	case "dot", "mermaid":
+		if graphImpact != "" {
+			return fmt.Errorf("--impact supports table or json output")
+		}
+	default:
+		return fmt.Errorf("invalid output format '%s': expected table, dot, mermaid or json", graphOutput)
+	}
+
+	g, err := graph.NewBuilder(resolveBaseDir()).Build(graphEnvironment)
+	if err != nil {
+		color.Red("Error building dependency graph: %v", err)
+		return err
+	}
+
+	if graphImpact != "" {
+		return printGraphImpact(g, graphImpact)
+	}
+
+	switch graphOutput {
+	case "dot":
+		fmt.Print(graph.DOT(g))
+		return nil
+	case "mermaid":
+		fmt.Print(graph.Mermaid(g))
+		return nil
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(g)
+	}
+
+	var resources []models.GraphNode
+	for _, node := range g.Nodes {
+		if node.Kind == models.GraphResource {
+			resources = append(resources, node)
+		}
+	}
+	if len(resources) == 0 {
+		color.Yellow("No artifacts depend on shared infrastructure.")
+		return nil
+	}
+
+	shared := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tDEPENDENTS\tARTIFACTS")
+	fmt.Fprintln(w, "--------\t----------\t---------")
+	for _, resource := range resources {
+		impacts := graph.Impact(g, resource.ID)
+		var names []string
+		for _, impact := range impacts {
+			names = append(names, impact.Artifact)
+		}
+		if len(impacts) > 1 {
+			shared++
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", resource.ID, len(impacts), strings.Join(names, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if shared > 0 {
+		fmt.Println()
+		color.Cyan("🔗 %d resource(s) are shared by several artifacts", shared)
+	}
+	return nil
+}
+
+func printGraphImpact(g *models.DependencyGraph, resource string) error {
+	node, err := graph.FindResource(g, resource)
+	if err != nil {
+		color.Red("Error finding resource: %v", err)
+		return err
+	}
+	impacts := graph.Impact(g, node.ID)
+
+	if graphOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(impacts)
+	}
+
+	color.Cyan("💥 Removing %s affects %d artifact(s):", node.ID, len(impacts))
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ARTIFACT\tENVIRONMENT\tCOMPONENT\tDECLARED IN")
+	fmt.Fprintln(w, "--------\t-----------\t---------\t-----------")
+	for _, impact := range impacts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", impact.Artifact, orDash(impact.Environment), impact.Component, strings.Join(impact.Sources, ", "))
+	}
+	return w.Flush()
+}
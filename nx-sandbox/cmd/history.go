@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/audit"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/snapshot"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	historyArtifact string
+	historyCommand  string
+	historySince    string
+	historyUntil    string
+	historyLimit    int
+	historyPaths    bool
+	historyOutput   string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: color.MagentaString("Show the audit log of sandbox changes"),
+	Long: color.BlueString(`Show the audit log kept in .nx-sandbox/audit.jsonl. Every command that
+changes the sandbox records its arguments, user, duration, result and the
+paths it created, modified or removed with their SHA-256 before and after.
+
+--since and --until take a date (2006-01-02), a timestamp (RFC 3339) or an
+age such as 90m, 24h or 7d.
+
+Examples:
+  nx-sandbox history
+  nx-sandbox history --artifact nx-bff-web-payment --paths
+  nx-sandbox history --command env --since 7d
+  nx-sandbox history --since 2025-01-01 --until 2025-01-31 -o json`),
+	Args: cobra.NoArgs,
+	RunE: runHistoryCmd,
+}
+
+func initHistoryCmd() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyArtifact, "artifact", "", "Only operations on this artifact")
+	historyCmd.Flags().StringVar(&historyCommand, "command", "", "Only this command, e.g. clean or 'env set'")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only operations at or after this time")
+	historyCmd.Flags().StringVar(&historyUntil, "until", "", "Only operations at or before this time")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 0, "Only the most recent N operations")
+	historyCmd.Flags().BoolVarP(&historyPaths, "paths", "p", false, "List the changed paths with their hashes")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runHistoryCmd(cmd *cobra.Command, args []string) error {
+	if historyOutput != "table" && historyOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", historyOutput)
+	}
+
+	now := time.Now()
+	filter := models.AuditFilter{
+		Artifact:  historyArtifact,
+		Operation: historyCommand,
+	}
+	var err error
+	if filter.Since, err = parseTimeFlag("since", historySince, now); err != nil {
+		return err
+	}
+	if filter.Until, err = parseTimeFlag("until", historyUntil, now); err != nil {
+		return err
+	}
+
+	entries, err := audit.NewLog(resolveBaseDir()).Entries(filter)
+	if err != nil {
+		color.Red("Error reading audit log: %v", err)
+		return err
+	}
+	if historyLimit > 0 && len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	if historyOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		color.Yellow("No audited operations found.")
+		return nil
+	}
+
+	if historyPaths {
+		for _, entry := range entries {
+			printAuditEntry(entry)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tOPERATION\tARGS\tRESULT\tDURATION\tPATHS")
+	fmt.Fprintln(w, "----\t----\t---------\t----\t------\t--------\t-----")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"), orDash(entry.User), entry.Operation,
+			orDash(strings.Join(entry.Args, " ")), entry.Result,
+			time.Duration(entry.DurationMS)*time.Millisecond, len(entry.Paths))
+	}
+	return w.Flush()
+}
+
+// printAuditEntry prints an entry with its changed paths and their hashes
+func printAuditEntry(entry models.AuditEntry) {
+	header := fmt.Sprintf("%s %s %s", entry.Time.Local().Format("2006-01-02 15:04:05"), orDash(entry.User), entry.Operation)
+	if len(entry.Args) > 0 {
+		header += " " + strings.Join(entry.Args, " ")
+	}
+	duration := time.Duration(entry.DurationMS) * time.Millisecond
+	if entry.Result == models.AuditFailure {
+		color.Red("%s (%s, %s)", header, entry.Result, duration)
+		fmt.Printf("   Error: %s\n", entry.Error)
+	} else {
+		color.Cyan("%s (%s, %s)", header, entry.Result, duration)
+	}
+
+	if len(entry.Paths) == 0 {
+		fmt.Println("   No paths changed")
+	}
+	for _, change := range entry.Paths {
+		fmt.Printf("   %s %s  %s → %s\n", changeMarker(change), change.Path, shortHash(change.Before), shortHash(change.After))
+	}
+	fmt.Println()
+}
+
+// auditSpec describes how a mutating command is audited
+type auditSpec struct {
+	// roots lists the directories the command may change, relative to the
+	// sandbox root; empty means audit.DefaultRoots
+	roots []string
+	// redactValues hides the values of KEY=VALUE arguments
+	redactValues bool
+}
+
+// readOnlyFlags mark runs of mutating commands that change nothing
+var readOnlyFlags = []string{"dry-run", "list", "print-default"}
+
+// auditCommands records every run of the mutating commands in the audit log.
+// New mutating commands must be added here. browse audits each prepare, pin
+// and edit itself rather than the whole session.
+func auditCommands() {
+	artifactDirs := auditSpec{roots: []string{"test-artifacts", "local-artifacts"}}
+	snapshotManifests := auditSpec{roots: []string{filepath.Join(layout.StateDirName, snapshot.DirName, "manifests")}}
+	specs := map[*cobra.Command]auditSpec{
+		cleanCmd:             artifactDirs,
+		cloneCmd:             artifactDirs,
+		createCmd:            {},
+		approveCmd:           {},
+		envSetCmd:            {redactValues: true},
+		envUnsetCmd:          {},
+		promoteCmd:           {},
+		infraApplyCmd:        {},
+		terraformGenerateCmd: {},
+		reposInitCmd:         {},
+		prMergeCmd:           {},
+		snapshotCreateCmd:    snapshotManifests,
+		snapshotDeleteCmd:    snapshotManifests,
+		snapshotRestoreCmd:   {roots: snapshot.Roots},
+		seedCmd:              {},
+		workflowRunCmd:       {},
+		meshAddCmd:           {},
+		meshRemoveCmd:        {},
+		goldenUpdateCmd:      {roots: []string{filepath.Join(layout.StateDirName, "golden")}},
+	}
+	for c, spec := range specs {
+		c.RunE = audited(spec, c.RunE)
+	}
+}
+
+// audited wraps a command's RunE so each run is appended to the audit log
+func audited(spec auditSpec, run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		for _, name := range readOnlyFlags {
+			if f := cmd.Flags().Lookup(name); f != nil && f.Value.String() == "true" {
+				return run(cmd, args)
+			}
+		}
+
+		operation := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+		op := audit.Begin(resolveBaseDir(), operation, auditArgs(cmd, args, spec.redactValues), currentUser(), spec.roots...)
+		err := run(cmd, args)
+		if auditErr := op.Finish(err); auditErr != nil {
+			color.Yellow("⚠️  Failed to update the audit log: %v", auditErr)
+		}
+		return err
+	}
+}
+
+// auditArgs returns the positional arguments followed by the flags set
+func auditArgs(cmd *cobra.Command, args []string, redactValues bool) []string {
+	recorded := []string{}
+	for _, arg := range args {
+		if name, _, ok := strings.Cut(arg, "="); ok && redactValues {
+			arg = name + "=***"
+		}
+		recorded = append(recorded, arg)
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		recorded = append(recorded, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return recorded
+}
+
+// parseTimeFlag accepts a date, an RFC 3339 timestamp or an age before now
+func parseTimeFlag(name, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if age, err := time.ParseDuration(value); err == nil && age >= 0 {
+		return now.Add(-age), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --%s '%s': expected a date, an RFC 3339 time or an age such as 24h or 7d", name, value)
+}
+
+func changeMarker(change models.AuditChange) string {
+	switch {
+	case change.Before == "":
+		return color.GreenString("+")
+	case change.After == "":
+		return color.RedString("-")
+	}
+	return color.YellowString("~")
+}
+
+func shortHash(hash string) string {
+	if hash == "" {
+		return "∅"
+	}
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/awsclient"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/infra"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	infraEnvironment string
+	infraEndpoint    string
+	infraRegion      string
+)
+
+var infraCmd = &cobra.Command{
+	Use:   "infra",
+	Short: color.MagentaString("Provision inventory components on LocalStack"),
+	Long: color.BlueString(`Create and inspect the AWS resources declared in artifact inventories.
+Commands talk to an AWS-compatible endpoint, LocalStack by default. The
+endpoint and region come from the aws section of .nx-sandbox/config.yaml
+and can be overridden with --endpoint and --region.
+
+Examples:
+  nx-sandbox infra apply nx-bff-web-payment --env dev1
+  nx-sandbox infra apply nx-bff-web-payment --env dev1 --endpoint http://localhost:4566
+  nx-sandbox infra drift
+  nx-sandbox infra drift nx-bff-web-payment --env dev1`),
+}
+
+var infraApplyCmd = &cobra.Command{
+	Use:   "apply <artifact>",
+	Short: "Create the enabled components of an approved artifact",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runInfraApplyCmd,
+}
+
+var infraDriftCmd = &cobra.Command{
+	Use:   "drift [artifact]",
+	Short: "Compare inventories with the provisioned resources",
+	Long: `Compare the components declared in inventories with the resources found on
+the AWS endpoint. Reports enabled components whose resource is missing,
+resources that exist for components that are not enabled, resources whose
+settings differ from the inventory, and infrastructure.deployed flags that do
+not match. Exits non-zero when drift is found.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInfraDriftCmd,
+}
+
+func initInfraCmd() {
+	rootCmd.AddCommand(infraCmd)
+	infraCmd.AddCommand(infraApplyCmd, infraDriftCmd)
+
+	infraCmd.PersistentFlags().StringVar(&infraEndpoint, "endpoint", "", "AWS endpoint (default from config, "+config.DefaultAWSEndpoint+")")
+	infraCmd.PersistentFlags().StringVar(&infraRegion, "region", "", "AWS region (default from config, "+config.DefaultAWSRegion+")")
+	infraApplyCmd.Flags().StringVar(&infraEnvironment, "env", "", "Target environment")
+	infraApplyCmd.MarkFlagRequired("env")
+	infraDriftCmd.Flags().StringVar(&infraEnvironment, "env", "", "Only check this environment")
+}
+
+func runInfraApplyCmd(cmd *cobra.Command, args []string) error {
+	baseDir := resolveBaseDir()
+
+	opts, err := awsOptions(baseDir)
+	if err != nil {
+		color.Red("Error loading configuration: %v", err)
+		return err
+	}
+
+	color.Cyan("🏗️  Provisioning %s in %s via %s...", args[0], infraEnvironment, opts.Endpoint)
+
+	result, err := infra.NewProvisioner(baseDir, opts).Apply(args[0], infraEnvironment)
+	if err != nil {
+		color.Red("Error provisioning infrastructure: %v", err)
+		return err
+	}
+
+	for _, resource := range result.Resources {
+		status := "exists"
+		if resource.Created {
+			status = "created"
+		}
+		fmt.Printf("  ✓ %-16s %s (%s)\n", resource.Component, resource.Name, status)
+		if resource.Endpoint != "" {
+			fmt.Printf("    %s\n", resource.Endpoint)
+		}
+	}
+
+	color.Green("✅ Infrastructure applied!")
+	fmt.Printf("   Inventory updated: %s\n", result.Path)
+
+	return proposeChanges(fmt.Sprintf("Record infrastructure outputs for %s", result.Artifact), []string{result.Path})
+}
+
+func runInfraDriftCmd(cmd *cobra.Command, args []string) error {
+	baseDir := resolveBaseDir()
+
+	opts, err := awsOptions(baseDir)
+	if err != nil {
+		color.Red("Error loading configuration: %v", err)
+		return err
+	}
+
+	artifact := ""
+	if len(args) > 0 {
+		artifact = args[0]
+	}
+
+	color.Cyan("🔍 Checking infrastructure drift against %s...", opts.Endpoint)
+
+	reports, err := infra.NewDriftDetector(baseDir, opts).Detect(artifact, infraEnvironment)
+	if err != nil {
+		color.Red("Error detecting drift: %v", err)
+		return err
+	}
+
+	drifted := 0
+	for _, report := range reports {
+		if !report.HasDrift() {
+			fmt.Printf("  ✓ %s (%s): in sync\n", report.Artifact, report.Environment)
+			continue
+		}
+
+		drifted++
+		color.Red("  ✗ %s (%s): %d difference(s)", report.Artifact, report.Environment, len(report.Items))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, item := range report.Items {
+			fmt.Fprintf(w, "      %s\t%s\t%s\t%s\n", item.Kind, item.Component, item.Resource, item.Detail)
+		}
+		w.Flush()
+	}
+
+	fmt.Println()
+	if drifted > 0 {
+		color.Red("❌ Drift detected in %d of %d inventories", drifted, len(reports))
+		cmd.SilenceUsage = true
+		return fmt.Errorf("drift detected in %d inventories", drifted)
+	}
+
+	color.Green("✅ No drift detected in %d inventories", len(reports))
+	return nil
+}
+
+// awsOptions resolves the AWS endpoint from the configuration and command-line overrides
+func awsOptions(baseDir string) (awsclient.Options, error) {
+	cfg, err := config.Load(baseDir)
+	if err != nil {
+		return awsclient.Options{}, err
+	}
+
+	opts := awsclient.Options{
+		Endpoint: cfg.AWS.Endpoint,
+		Region:   cfg.AWS.Region,
+	}
+	if infraEndpoint != "" {
+		opts.Endpoint = infraEndpoint
+	}
+	if infraRegion != "" {
+		opts.Region = infraRegion
+	}
+	return opts, nil
+}
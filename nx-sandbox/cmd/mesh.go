@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/mesh"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	meshEnvironment string
+	meshOutput      string
+)
+
+var meshCmd = &cobra.Command{
+	Use:   "mesh",
+	Short: color.CyanString("Manage Kuma mesh external services"),
+	Long: color.BlueString(`Manage the externalServices of an environment's kuma-resources-default-mesh
+values. Artifacts that enable redis or dynamo need an ExternalService so
+their traffic can leave the mesh; 'add' generates the entries from the
+artifact's inventory and 'remove' deletes them again.
+
+Entries are checked before they are written: names must be unique, hosts a
+hostname or IP address without a port, and ports between 1 and 65535.
+
+Examples:
+  nx-sandbox mesh list --env dev1
+  nx-sandbox mesh add nx-bff-web-payment --env dev1
+  nx-sandbox mesh remove nx-bff-web-payment --env dev1`),
+}
+
+var meshListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List external services",
+	Args:  cobra.NoArgs,
+	RunE:  runMeshListCmd,
+}
+
+var meshAddCmd = &cobra.Command{
+	Use:   "add <artifact>",
+	Short: "Add the external services an artifact's components need",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMeshAddCmd,
+}
+
+var meshRemoveCmd = &cobra.Command{
+	Use:   "remove <artifact|name>",
+	Short: "Remove an artifact's external services, or one entry by name",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMeshRemoveCmd,
+}
+
+func initMeshCmd() {
+	rootCmd.AddCommand(meshCmd)
+	meshCmd.AddCommand(meshListCmd, meshAddCmd, meshRemoveCmd)
+
+	meshCmd.PersistentFlags().StringVar(&meshEnvironment, "env", "", "Target environment")
+	meshCmd.MarkPersistentFlagRequired("env")
+	meshListCmd.Flags().StringVarP(&meshOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runMeshListCmd(cmd *cobra.Command, args []string) error {
+	if meshOutput != "table" && meshOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", meshOutput)
+	}
+
+	services, err := mesh.NewManager(resolveBaseDir()).List(meshEnvironment)
+	if err != nil {
+		color.Red("Error listing external services: %v", err)
+		return err
+	}
+	problems := mesh.Check(services)
+
+	if meshOutput == "json" {
+		if services == nil {
+			services = []models.ExternalService{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(services)
+	}
+
+	if len(services) == 0 {
+		color.Yellow("No external services in %s.", meshEnvironment)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tARTIFACT\tCOMPONENT\tADDRESS\tPROTOCOL\tTLS")
+	fmt.Fprintln(w, "----\t--------\t---------\t-------\t--------\t---")
+	for _, service := range services {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s:%d\t%s\t%t\n", service.Name, orDash(service.Artifact), orDash(service.Component),
+			service.Host, service.Port, service.Protocol, service.TLS)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		fmt.Println()
+		for _, problem := range problems {
+			color.Red("✗ externalServices[%d].%s: %s", problem.Index, problem.Field, problem.Message)
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d invalid external service field(s) in %s", len(problems), meshEnvironment)
+	}
+	return nil
+}
+
+func runMeshAddCmd(cmd *cobra.Command, args []string) error {
+	path, added, err := mesh.NewManager(resolveBaseDir()).Add(args[0], meshEnvironment)
+	if err != nil {
+		color.Red("Error adding external services: %v", err)
+		return err
+	}
+
+	color.Green("✅ Added %d external service(s) to %s", len(added), path)
+	for _, service := range added {
+		fmt.Printf("   %s → %s:%d (%s)\n", service.Name, service.Host, service.Port, service.Component)
+	}
+	return proposeChanges(fmt.Sprintf("Add mesh external services for %s in %s", args[0], meshEnvironment), []string{path})
+}
+
+func runMeshRemoveCmd(cmd *cobra.Command, args []string) error {
+	path, removed, err := mesh.NewManager(resolveBaseDir()).Remove(args[0], meshEnvironment)
+	if err != nil {
+		color.Red("Error removing external services: %v", err)
+		return err
+	}
+
+	color.Green("✅ Removed %d external service(s) from %s", len(removed), path)
+	for _, service := range removed {
+		fmt.Printf("   %s\n", service.Name)
+	}
+	return proposeChanges(fmt.Sprintf("Remove mesh external services for %s in %s", args[0], meshEnvironment), []string{path})
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/policy"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyFile   string
+	policyOutput string
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: color.GreenString("Enforce the platform policies"),
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check [file...]",
+	Short: "Evaluate the policies over the inventories and chart values",
+	Long: color.BlueString(`Evaluate the declarative policies in config/policies.yaml over every inventory
+and chart values.yaml, or only the given files. Each policy has a target, an
+optional when condition and a require condition:
+
+  policies:
+    - name: rds-layers
+      description: rds is only allowed in the bal and bc layers
+      severity: error
+      target: inventory
+      when: inventory.components.rds.enabled
+      require: artifact.layer in ["bal", "bc"]
+
+Inventory policies read inventory (the typed nx-app-inventory.yaml) and values
+policies read values and chart (values.yaml and Chart.yaml); both read
+artifact (name, layer, environment, domain, service, owner), teams (the
+approver teams) and environments. Expressions support ||, &&, !, ==, !=, <,
+<=, >, >=, in, lists and the functions len, matches and quantity, and are
+type-checked when the file is loaded.
+
+The command fails when a policy with error severity is violated. validate and
+the HTTP API enforce the same policies.
+
+Examples:
+  nx-sandbox policy check
+  nx-sandbox policy check repos/nx-bolt-environment-prod1/bff/nx-bff-test-service/values.yaml
+  nx-sandbox policy check --policies draft-policies.yaml -o json`),
+	RunE: runPolicyCheckCmd,
+}
+
+func initPolicyCmd() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyCheckCmd)
+
+	policyCheckCmd.Flags().StringVar(&policyFile, "policies", "", "Policy file (default config/policies.yaml)")
+	policyCheckCmd.Flags().StringVarP(&policyOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runPolicyCheckCmd(cmd *cobra.Command, args []string) error {
+	if policyOutput != "table" && policyOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", policyOutput)
+	}
+
+	baseDir := resolveBaseDir()
+	var set *policy.Set
+	var err error
+	if policyFile != "" {
+		set, err = policy.Load(policyFile)
+	} else {
+		set, err = policy.LoadDefault(baseDir)
+	}
+	if err != nil {
+		color.Red("Error loading policies: %v", err)
+		return err
+	}
+
+	checker := policy.NewChecker(baseDir, set)
+	var report *models.PolicyReport
+	if len(args) > 0 {
+		report, err = checker.CheckFiles(args)
+	} else {
+		report, err = checker.Check()
+	}
+	if err != nil {
+		color.Red("Error checking policies: %v", err)
+		return err
+	}
+
+	if policyOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else if err := printPolicyReport(report); err != nil {
+		return err
+	}
+
+	if report.Errors() > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d policy violation(s)", report.Errors())
+	}
+	return nil
+}
+
+func printPolicyReport(report *models.PolicyReport) error {
+	if len(report.Policies) == 0 {
+		color.Yellow("No policies defined.")
+		return nil
+	}
+
+	if len(report.Violations) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEVERITY\tPOLICY\tARTIFACT\tLOCATION\tMESSAGE")
+		fmt.Fprintln(w, "--------\t------\t--------\t--------\t-------")
+		for _, violation := range report.Violations {
+			location := relativePath(violation.Path)
+			if violation.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, violation.Line)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", violation.Severity, violation.Policy, violation.Artifact, location, violation.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	warnings := len(report.Violations) - report.Errors()
+	if report.Errors() == 0 {
+		color.Green("✅ %d policies passed on %d files (%d warnings)", len(report.Policies), len(report.Checked), warnings)
+	} else {
+		color.Red("❌ %d errors, %d warnings from %d policies on %d files", report.Errors(), warnings, len(report.Policies), len(report.Checked))
+	}
+	return nil
+}
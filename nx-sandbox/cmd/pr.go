@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/pullrequest"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prState string
+	prUser  string
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: color.GreenString("Manage simulated pull requests"),
+	Long: color.BlueString(`Manage pull requests simulated against the local Git repositories under repos/.
+When a repository has been initialized with 'nx-sandbox repos init', commands
+that change its files commit to a new branch and open a pull request instead
+of editing the main branch. Merge the pull request to apply the change.
+
+Examples:
+  nx-sandbox pr list
+  nx-sandbox pr list --state all
+  nx-sandbox pr show 3
+  nx-sandbox pr merge 3`),
+}
+
+var prListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pull requests",
+	Args:  cobra.NoArgs,
+	RunE:  runPRListCmd,
+}
+
+var prShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a pull request and its diff",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPRShowCmd,
+}
+
+var prMergeCmd = &cobra.Command{
+	Use:   "merge <id>",
+	Short: "Merge a pull request into its base branch",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPRMergeCmd,
+}
+
+func initPRCmd() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.AddCommand(prListCmd, prShowCmd, prMergeCmd)
+
+	prListCmd.Flags().StringVar(&prState, "state", string(models.PullRequestOpen), "Filter by state (open, merged, all)")
+	prMergeCmd.Flags().StringVar(&prUser, "user", currentUser(), "User merging the pull request")
+}
+
+func runPRListCmd(cmd *cobra.Command, args []string) error {
+	prs, err := pullrequest.NewManager(resolveBaseDir()).List()
+	if err != nil {
+		color.Red("Error listing pull requests: %v", err)
+		return err
+	}
+
+	var shown []models.PullRequest
+	for _, pr := range prs {
+		if prState == "all" || string(pr.State) == prState {
+			shown = append(shown, pr)
+		}
+	}
+
+	if len(shown) == 0 {
+		color.Yellow("No %s pull requests.", prState)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tREPO\tTITLE\tAUTHOR\tCREATED")
+	fmt.Fprintln(w, "--\t-----\t----\t-----\t------\t-------")
+	for _, pr := range shown {
+		fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\t%s\n",
+			pr.ID, pr.State, pr.Repo, pr.Title, pr.Author, pr.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func runPRShowCmd(cmd *cobra.Command, args []string) error {
+	id, err := parsePRID(args[0])
+	if err != nil {
+		return err
+	}
+
+	manager := pullrequest.NewManager(resolveBaseDir())
+	pr, err := manager.Get(id)
+	if err != nil {
+		color.Red("Error reading pull request: %v", err)
+		return err
+	}
+
+	color.Cyan("#%d %s", pr.ID, pr.Title)
+	fmt.Printf("   State: %s\n", pr.State)
+	fmt.Printf("   Repository: %s\n", pr.Repo)
+	fmt.Printf("   Branch: %s → %s\n", pr.Branch, pr.Base)
+	fmt.Printf("   Author: %s\n", pr.Author)
+	fmt.Printf("   Created: %s\n", pr.CreatedAt.Format("2006-01-02 15:04:05"))
+	if pr.MergedAt != nil {
+		fmt.Printf("   Merged: %s by %s\n", pr.MergedAt.Format("2006-01-02 15:04:05"), pr.MergedBy)
+	}
+	fmt.Printf("   Files: %s\n", strings.Join(pr.Files, ", "))
+
+	if pr.State == models.PullRequestOpen {
+		diff, err := manager.Diff(pr)
+		if err != nil {
+			color.Red("Error reading diff: %v", err)
+			return err
+		}
+		fmt.Println()
+		printDiff(diff + "\n")
+	}
+
+	return nil
+}
+
+func runPRMergeCmd(cmd *cobra.Command, args []string) error {
+	id, err := parsePRID(args[0])
+	if err != nil {
+		return err
+	}
+
+	pr, err := pullrequest.NewManager(resolveBaseDir()).Merge(id, prUser)
+	if err != nil {
+		color.Red("Error merging pull request: %v", err)
+		return err
+	}
+
+	color.Green("✅ Merged #%d into %s/%s", pr.ID, pr.Repo, pr.Base)
+	return nil
+}
+
+// proposeChanges opens pull requests for changed files in initialized repositories.
+// Files in plain directories are left as working tree changes.
+func proposeChanges(title string, paths []string) error {
+	prs, err := pullrequest.NewManager(resolveBaseDir()).Propose(title, currentUser(), paths)
+	if err != nil {
+		color.Red("Error opening pull request: %v", err)
+		return err
+	}
+
+	for _, pr := range prs {
+		color.Cyan("🔀 Opened pull request #%d in %s (%s)", pr.ID, pr.Repo, pr.Branch)
+		fmt.Printf("   Review with: nx-sandbox pr show %d\n", pr.ID)
+	}
+	return nil
+}
+
+// currentUser returns $USER, falling back to the account name
+func currentUser() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+func parsePRID(value string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid pull request id '%s'", value)
+	}
+	return id, nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/diff"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/promote"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promoteFrom   string
+	promoteTo     string
+	promoteDryRun bool
+	promoteYes    bool
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote <service>",
+	Short: color.MagentaString("Promote a service to the next environment"),
+	Long: color.BlueString(`Promote a service's Helm chart from one environment repository to the next.
+Hosts, namespaces and image tags are rewritten using the promotion settings in
+.nx-sandbox/config.yaml. Keys that only exist in the target values are kept.
+Promotions must follow the configured environment order (dev1 → sit1 → uat1 → prod1).
+
+A diff is shown before anything is written.
+
+Examples:
+  nx-sandbox promote nx-bff-test-service --from dev1 --to sit1
+  nx-sandbox promote nx-bff-test-service --from sit1 --to uat1 --dry-run
+  nx-sandbox promote nx-bff-test-service --from uat1 --to prod1 --yes`),
+	Args: cobra.ExactArgs(1),
+	RunE: runPromoteCmd,
+}
+
+func initPromoteCmd() {
+	rootCmd.AddCommand(promoteCmd)
+
+	promoteCmd.Flags().StringVar(&promoteFrom, "from", "", "Source environment")
+	promoteCmd.Flags().StringVar(&promoteTo, "to", "", "Target environment")
+	promoteCmd.Flags().BoolVar(&promoteDryRun, "dry-run", false, "Show the diff without applying it")
+	promoteCmd.Flags().BoolVarP(&promoteYes, "yes", "y", false, "Apply without asking for confirmation")
+	promoteCmd.MarkFlagRequired("from")
+	promoteCmd.MarkFlagRequired("to")
+}
+
+func runPromoteCmd(cmd *cobra.Command, args []string) error {
+	color.Cyan("🚀 Planning promotion of %s from %s to %s...", args[0], promoteFrom, promoteTo)
+
+	baseDir := resolveBaseDir()
+	promoter := promote.NewPromoter(baseDir)
+
+	plan, err := promoter.Plan(promote.Request{
+		Service: args[0],
+		From:    promoteFrom,
+		To:      promoteTo,
+	})
+	if err != nil {
+		color.Red("Error planning promotion: %v", err)
+		return err
+	}
+
+	if len(plan.Changes) == 0 {
+		color.Green("✅ %s is already up to date in %s", args[0], promoteTo)
+		return nil
+	}
+
+	fmt.Println()
+	for _, change := range plan.Changes {
+		rel, _ := filepath.Rel(baseDir, change.Path)
+		fromName := "a/" + rel
+		if change.Before == nil {
+			fromName = "/dev/null"
+		}
+		printDiff(diff.Unified(fromName, "b/"+rel, change.Before, change.After))
+	}
+
+	if promoteDryRun {
+		color.Yellow("Dry run: %d file(s) would change", len(plan.Changes))
+		return nil
+	}
+
+	if !promoteYes && !confirm(cmd, fmt.Sprintf("Apply %d change(s) to %s?", len(plan.Changes), promoteTo)) {
+		color.Yellow("Promotion cancelled")
+		return nil
+	}
+
+	if err := promoter.Apply(plan); err != nil {
+		color.Red("Error applying promotion: %v", err)
+		return err
+	}
+
+	color.Green("✅ Promoted %s to %s (%d file(s) changed)", args[0], promoteTo, len(plan.Changes))
+
+	var paths []string
+	for _, change := range plan.Changes {
+		paths = append(paths, change.Path)
+	}
+	return proposeChanges(fmt.Sprintf("Promote %s from %s to %s", args[0], promoteFrom, promoteTo), paths)
+}
+
+// printDiff prints a unified diff with added and removed lines coloured
+func printDiff(text string) {
+	for _, line := range strings.SplitAfter(text, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Print(color.New(color.Bold).Sprint(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Print(color.CyanString(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Print(color.GreenString(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Print(color.RedString(line))
+		default:
+			fmt.Print(line)
+		}
+	}
+}
+
+// confirm asks a yes/no question on the command's input
+func confirm(cmd *cobra.Command, question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: color.CyanString("Manage the mirrored repositories under repos/"),
+	Long: color.BlueString(`Manage the repositories mirrored under repos/.
+
+Examples:
+  nx-sandbox repos init
+  nx-sandbox repos init nx-artifacts-inventory nx-bolt-environment-dev1`),
+}
+
+var reposInitCmd = &cobra.Command{
+	Use:   "init [repo...]",
+	Short: "Initialize repositories as local Git repositories",
+	Long: `Initialize repositories under repos/ as local Git repositories on the main
+branch, committing their current contents. With no arguments every repository
+that is not yet a Git repository is initialized. Once initialized, commands
+that change a repository commit to a branch and open a pull request.`,
+	RunE: runReposInitCmd,
+}
+
+func initReposCmd() {
+	rootCmd.AddCommand(reposCmd)
+	reposCmd.AddCommand(reposInitCmd)
+}
+
+func runReposInitCmd(cmd *cobra.Command, args []string) error {
+	reposDir := layout.ReposDir(resolveBaseDir())
+
+	names := args
+	if len(names) == 0 {
+		entries, err := os.ReadDir(reposDir)
+		if err != nil {
+			color.Red("Error reading repositories: %v", err)
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !gitrepo.IsRepo(filepath.Join(reposDir, entry.Name())) {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		color.Yellow("Every repository is already initialized.")
+		return nil
+	}
+
+	for _, name := range names {
+		dir := filepath.Join(reposDir, name)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			err := fmt.Errorf("repository '%s' not found in %s", name, reposDir)
+			color.Red("Error initializing repository: %v", err)
+			return err
+		}
+
+		if _, err := gitrepo.Init(dir); err != nil {
+			color.Red("Error initializing %s: %v", name, err)
+			return err
+		}
+		fmt.Printf("  ✓ %s (%s)\n", name, gitrepo.DefaultBranch)
+	}
+
+	color.Green("✅ Initialized %d repositories", len(names))
+	return nil
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +20,7 @@ Examples:
   nx-sandbox list --from-inventory
   nx-sandbox status
   nx-sandbox clean
+  nx-sandbox create nx-bff-web-loyalty --envs dev1,sit1
   nx-sandbox clone BritishAirways-Nexus nx-tc-order-creator`),
 }
 
@@ -33,4 +36,43 @@ func init() {
 	initStatusCmd()
 	initCleanCmd()
 	initCloneCmd()
+	initCreateCmd()
+	initApproveCmd()
+	initEnvCmd()
+	initPromoteCmd()
+	initInfraCmd()
+	initTerraformCmd()
+	initReposCmd()
+	initPRCmd()
+	initSnapshotCmd()
+	initSeedCmd()
+	initBrowseCmd()
+	initValidateCmd()
+	initWorkflowCmd()
+	initServeCmd()
+	initWatchCmd()
+	initHistoryCmd()
+	initMeshCmd()
+	initEnvsCmd()
+	initGraphCmd()
+	initScanCmd()
+	initPolicyCmd()
+	initCapacityCmd()
+	initTestCmd()
+	initGoldenCmd()
+
+	// Record mutating commands in the audit log
+	auditCommands()
+}
+
+// resolveBaseDir returns the sandbox root, which is the parent directory
+// when the CLI is run from inside nx-sandbox
+func resolveBaseDir() string {
+	baseDir := "."
+	if wd, err := os.Getwd(); err == nil {
+		if baseDirName := getDirName(wd); baseDirName == "nx-sandbox" {
+			baseDir = ".."
+		}
+	}
+	return baseDir
 }
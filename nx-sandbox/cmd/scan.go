@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/secrets"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanAllowlist string
+	scanOutput    string
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: color.CyanString("Scan the sandbox for problems"),
+}
+
+var scanSecretsCmd = &cobra.Command{
+	Use:   "secrets [path...]",
+	Short: "Find credentials committed to the sandbox repositories",
+	Long: color.BlueString(`Walk repos/, test-artifacts/ and local-artifacts/, or the given paths, and
+report AWS keys, GitHub and Slack tokens, JWTs, private keys, credentials in
+URLs, sensitive keys assigned literal values (such as the access_key in
+provider_aws.tf) and high-entropy strings. Each finding names the file, line
+and rule; the secret itself is redacted.
+
+Known mock credentials are accepted through the allowlist, by default
+config/secrets-allowlist.yaml:
+
+  allow:
+    - rule: hardcoded-credential
+      path: repos/nexus-infrastructure/**/provider_aws.tf
+      value: mock_access_key
+      reason: LocalStack mock credentials
+
+An entry allows the findings matching all of its rule, path and value.
+The command fails when unallowed findings remain; -o sarif produces a report
+for GitHub code scanning.
+
+Examples:
+  nx-sandbox scan secrets
+  nx-sandbox scan secrets ../repos/nexus-infrastructure
+  nx-sandbox scan secrets --allowlist my-allowlist.yaml -o json
+  nx-sandbox scan secrets -o sarif > secrets.sarif`),
+	RunE: runScanSecretsCmd,
+}
+
+func initScanCmd() {
+	rootCmd.AddCommand(scanCmd)
+	scanCmd.AddCommand(scanSecretsCmd)
+
+	scanSecretsCmd.Flags().StringVar(&scanAllowlist, "allowlist", "", "Allowlist file (default config/secrets-allowlist.yaml)")
+	scanSecretsCmd.Flags().StringVarP(&scanOutput, "output", "o", "table", "Output format (table, json, sarif)")
+}
+
+func runScanSecretsCmd(cmd *cobra.Command, args []string) error {
+	switch scanOutput {
+	case "table", "json", "sarif":
+	default:
+		return fmt.Errorf("invalid output format '%s': expected table, json or sarif", scanOutput)
+	}
+
+	baseDir := resolveBaseDir()
+	allowlist, err := loadScanAllowlist(baseDir)
+	if err != nil {
+		color.Red("Error loading allowlist: %v", err)
+		return err
+	}
+
+	report, err := secrets.NewScanner(baseDir, allowlist).Scan(args)
+	if err != nil {
+		color.Red("Error scanning for secrets: %v", err)
+		return err
+	}
+
+	switch scanOutput {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case "sarif":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(secrets.SARIF(report))
+	default:
+		err = printSecretScanReport(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(report.Findings) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d potential secret(s)", len(report.Findings))
+	}
+	return nil
+}
+
+// loadScanAllowlist reads --allowlist, or the default allowlist when present
+func loadScanAllowlist(baseDir string) (*secrets.Allowlist, error) {
+	if scanAllowlist != "" {
+		return secrets.LoadAllowlist(scanAllowlist)
+	}
+	file := layout.SecretsAllowlistFile(baseDir)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return secrets.LoadAllowlist(file)
+}
+
+func printSecretScanReport(report *models.SecretScanReport) error {
+	if len(report.Findings) == 0 {
+		color.Green("✅ No secrets found in %d file(s) (%d allowlisted)", report.Scanned, report.Allowed)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tLINE\tRULE\tSNIPPET\tMESSAGE")
+	fmt.Fprintln(w, "----\t----\t----\t-------\t-------")
+	for _, finding := range report.Findings {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", finding.Path, finding.Line, finding.Rule, finding.Snippet, finding.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	color.Red("❌ %d potential secret(s) in %d scanned file(s) (%d allowlisted)", len(report.Findings), report.Scanned, report.Allowed)
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	seedSpec         string
+	seedReset        bool
+	seedPrintDefault bool
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: color.GreenString("Generate the mock repositories from a spec"),
+	Long: color.BlueString(`Generate the repos/ tree from a declarative fixture spec listing
+environments, layers, services, inventory components and chart values.
+Without --spec the built-in spec is used, which matches the historical
+mock repositories. Output is deterministic for a given spec.
+
+Existing files are only rewritten when their content is unchanged; use
+--reset to remove repos/ first. Seeding uses the built-in templates only,
+ignoring overrides in .nx-sandbox/templates.
+
+--reset also removes the Git repositories made by 'nx-sandbox repos init',
+with their branches and history, and the pull requests in
+.nx-sandbox/pulls.json that referred to them.
+
+Examples:
+  nx-sandbox seed
+  nx-sandbox seed --reset
+  nx-sandbox seed --print-default > seed.yaml
+  nx-sandbox seed --spec seed.yaml --reset`),
+	Args: cobra.NoArgs,
+	RunE: runSeedCmd,
+}
+
+func initSeedCmd() {
+	rootCmd.AddCommand(seedCmd)
+
+	seedCmd.Flags().StringVar(&seedSpec, "spec", "", "Seed spec file (default: built-in spec)")
+	seedCmd.Flags().BoolVar(&seedReset, "reset", false, "Remove repos/, including Git repositories, and all pull requests before generating")
+	seedCmd.Flags().BoolVar(&seedPrintDefault, "print-default", false, "Print the built-in spec and exit")
+}
+
+func runSeedCmd(cmd *cobra.Command, args []string) error {
+	if seedPrintDefault {
+		_, err := os.Stdout.Write(seed.DefaultSpecYAML())
+		return err
+	}
+
+	spec := seed.DefaultSpec()
+	source := "built-in spec"
+	if seedSpec != "" {
+		var err error
+		if spec, err = seed.LoadSpec(seedSpec); err != nil {
+			color.Red("Error loading seed spec: %v", err)
+			return err
+		}
+		source = seedSpec
+	}
+
+	baseDir := resolveBaseDir()
+	if seedReset {
+		color.Yellow("🧹 Removing %s...", filepath.Join(baseDir, "repos"))
+		if repos := gitRepositories(baseDir); len(repos) > 0 {
+			color.Yellow("⚠️  Removing Git repositories %s and their pull requests", strings.Join(repos, ", "))
+		}
+	}
+	color.Cyan("🌱 Seeding repositories from %s...", source)
+
+	written, err := seed.NewSeeder(baseDir).Seed(spec, seedReset)
+	if err != nil {
+		color.Red("Error seeding repositories: %v", err)
+		return err
+	}
+
+	for _, path := range written {
+		fmt.Printf("  ✓ %s\n", path)
+	}
+	color.Green("✅ Wrote %d files for %s", len(written), strings.Join(spec.Environments, ", "))
+
+	cfg, err := config.Load(baseDir)
+	if err == nil && strings.Join(cfg.Environments, ",") != strings.Join(spec.Environments, ",") {
+		color.Yellow("⚠️  Configured environments (%s) differ from the seeded ones; set environments in %s",
+			strings.Join(cfg.Environments, ", "), config.Path(baseDir))
+	}
+	return nil
+}
+
+// gitRepositories lists the repositories under repos/ initialized as Git repositories
+func gitRepositories(baseDir string) []string {
+	entries, err := os.ReadDir(layout.ReposDir(baseDir))
+	if err != nil {
+		return nil
+	}
+	var repos []string
+	for _, entry := range entries {
+		if entry.IsDir() && gitrepo.IsRepo(filepath.Join(layout.ReposDir(baseDir), entry.Name())) {
+			repos = append(repos, entry.Name())
+		}
+	}
+	return repos
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/server"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// serveTokenEnv supplies the API token without putting it on the command line
+const serveTokenEnv = "NX_SANDBOX_TOKEN"
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: color.CyanString("Serve a JSON REST API over the sandbox"),
+	Long: color.BlueString(`Serve a JSON REST API for listing artifacts, reading status, cleaning,
+validating and running workflows, so dashboards and bots can drive the
+sandbox without shelling out. The OpenAPI document is served at
+/openapi.json and every request is logged to stderr.
+
+The server binds to 127.0.0.1 by default. Binding to any other address
+requires a token, which clients send as "Authorization: Bearer <token>".
+The token can also be set with $NX_SANDBOX_TOKEN.
+
+Examples:
+  nx-sandbox serve
+  nx-sandbox serve --addr 127.0.0.1:9090
+  NX_SANDBOX_TOKEN=s3cret nx-sandbox serve --addr 0.0.0.0:8080
+  curl http://127.0.0.1:8080/api/v1/artifacts?layer=bff`),
+	Args: cobra.NoArgs,
+	RunE: runServeCmd,
+}
+
+func initServeCmd() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", os.Getenv(serveTokenEnv), "Bearer token required on every request")
+}
+
+func runServeCmd(cmd *cobra.Command, args []string) error {
+	if serveToken == "" && !server.IsLoopback(serveAddr) {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("refusing to serve on non-loopback address %s without a token: set --token or $%s", serveAddr, serveTokenEnv)
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		color.Red("Error listening on %s: %v", serveAddr, err)
+		return err
+	}
+
+	srv := &http.Server{
+		Handler: server.NewServer(resolveBaseDir(), server.Options{
+			Token:  serveToken,
+			Logger: os.Stderr,
+		}).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Serve(listener) }()
+
+	color.Green("🌐 Serving the sandbox API on http://%s%s", listener.Addr(), server.APIPrefix)
+	fmt.Printf("   OpenAPI: http://%s/openapi.json\n", listener.Addr())
+	if serveToken != "" {
+		fmt.Println("   Authentication: bearer token required")
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, http.ErrServerClosed) {
+			color.Red("Error serving API: %v", err)
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	color.Yellow("🛑 Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/snapshot"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var snapshotYes bool
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: color.CyanString("Save and restore the sandbox state"),
+	Long: color.BlueString(`Save and restore the state of repos/, test-artifacts/, local-artifacts/
+and the sandbox state in .nx-sandbox/. Snapshots are compressed and
+content-addressed under .nx-sandbox/snapshots, so files shared between
+snapshots are stored once and unchanged files are not read again.
+
+Examples:
+  nx-sandbox snapshot create before-promote
+  nx-sandbox snapshot list
+  nx-sandbox snapshot restore before-promote
+  nx-sandbox snapshot delete before-promote`),
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Save the current sandbox state",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnapshotCreateCmd,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotListCmd,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore the sandbox to a snapshot",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnapshotRestoreCmd,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a snapshot and free its unused contents",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnapshotDeleteCmd,
+}
+
+func initSnapshotCmd() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotCreateCmd, snapshotListCmd, snapshotRestoreCmd, snapshotDeleteCmd)
+
+	snapshotRestoreCmd.Flags().BoolVarP(&snapshotYes, "yes", "y", false, "Restore without asking for confirmation")
+}
+
+func runSnapshotCreateCmd(cmd *cobra.Command, args []string) error {
+	color.Cyan("📸 Creating snapshot %s...", args[0])
+
+	start := time.Now()
+	snap, err := snapshot.NewStore(resolveBaseDir()).Create(args[0])
+	if err != nil {
+		color.Red("Error creating snapshot: %v", err)
+		return err
+	}
+
+	files := 0
+	for _, file := range snap.Files {
+		if file.Mode.IsRegular() {
+			files++
+		}
+	}
+
+	color.Green("✅ Saved %d files from %s in %s", files, strings.Join(snap.Roots, ", "), time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func runSnapshotListCmd(cmd *cobra.Command, args []string) error {
+	store := snapshot.NewStore(resolveBaseDir())
+
+	snaps, err := store.List()
+	if err != nil {
+		color.Red("Error listing snapshots: %v", err)
+		return err
+	}
+	if len(snaps) == 0 {
+		color.Yellow("No snapshots. Create one with: nx-sandbox snapshot create <name>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tFILES\tSIZE")
+	fmt.Fprintln(w, "----\t-------\t-----\t----")
+	for _, snap := range snaps {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n",
+			snap.Name, snap.CreatedAt.Local().Format("2006-01-02 15:04:05"), snap.Files, formatBytes(snap.Size))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	usage, err := store.Usage()
+	if err != nil {
+		color.Red("Error measuring snapshots: %v", err)
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("Disk usage: %s in %d objects\n", formatBytes(usage.DiskUsage), usage.Objects)
+	return nil
+}
+
+func runSnapshotRestoreCmd(cmd *cobra.Command, args []string) error {
+	if !snapshotYes && !confirm(cmd, fmt.Sprintf("Discard current changes and restore snapshot %s?", args[0])) {
+		color.Yellow("Restore cancelled.")
+		return nil
+	}
+
+	color.Cyan("⏪ Restoring snapshot %s...", args[0])
+
+	start := time.Now()
+	result, err := snapshot.NewStore(resolveBaseDir()).Restore(args[0])
+	if err != nil {
+		color.Red("Error restoring snapshot: %v", err)
+		return err
+	}
+
+	fmt.Printf("   Written: %d, removed: %d, unchanged: %d\n", result.Written, result.Removed, result.Unchanged)
+	color.Green("✅ Restored %s in %s", result.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func runSnapshotDeleteCmd(cmd *cobra.Command, args []string) error {
+	freed, err := snapshot.NewStore(resolveBaseDir()).Delete(args[0])
+	if err != nil {
+		color.Red("Error deleting snapshot: %v", err)
+		return err
+	}
+
+	color.Green("✅ Deleted %s, freed %s", args[0], formatBytes(freed))
+	return nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BritishAirways-Nexus/nx-sandbox/internal/sandbox"
 	"github.com/fatih/color"
@@ -67,12 +68,8 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 	fmt.Printf("   Local Artifacts: %d\n", status.Environment.LocalArtifactsCount)
 
 	// Disk usage
-	diskUsageMB := float64(status.Environment.DiskUsage) / (1024 * 1024)
-	if diskUsageMB < 1 {
-		fmt.Printf("   Disk Usage: %.2f KB\n", float64(status.Environment.DiskUsage)/1024)
-	} else {
-		fmt.Printf("   Disk Usage: %.2f MB\n", diskUsageMB)
-	}
+	fmt.Printf("   Disk Usage: %s\n", formatBytes(status.Environment.DiskUsage))
+	fmt.Printf("   Snapshots: %d (%s)\n", status.Environment.SnapshotCount, formatBytes(status.Environment.SnapshotDiskUsage))
 
 	// Last cleanup
 	if !status.Environment.LastCleanup.IsZero() {
@@ -82,6 +79,15 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	// Pending approvals
+	if len(status.PendingApprovals) > 0 {
+		color.Yellow("⏳ Pending Approvals:")
+		for _, pending := range status.PendingApprovals {
+			fmt.Printf("   - %s (%s): %s\n", pending.Artifact, pending.Environment, strings.Join(pending.Components, ", "))
+		}
+		fmt.Println()
+	}
+
 	// Issues
 	if len(status.Issues) > 0 {
 		color.Red("⚠️  Issues:")
@@ -105,6 +111,17 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 	fmt.Println("   nx-sandbox list          # List available artifacts")
 	fmt.Println("   nx-sandbox clean         # Clean old artifacts")
 	fmt.Println("   nx-sandbox clone <org> <repo>  # Clone artifact for testing")
+	fmt.Println("   nx-sandbox approve <artifact> --env <env>  # Approve infrastructure creation")
+	fmt.Println("   nx-sandbox snapshot create <name>  # Save the sandbox state")
 
 	return nil
 }
+
+// formatBytes renders a size in KB below one megabyte and in MB above
+func formatBytes(size int64) string {
+	sizeMB := float64(size) / (1024 * 1024)
+	if sizeMB < 1 {
+		return fmt.Sprintf("%.2f KB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%.2f MB", sizeMB)
+}
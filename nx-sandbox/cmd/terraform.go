@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/terraform"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	terraformEnvironment string
+	terraformDryRun      bool
+	terraformOutput      string
+)
+
+var terraformCmd = &cobra.Command{
+	Use:   "terraform",
+	Short: color.MagentaString("Generate and review Terraform for artifacts"),
+	Long: color.BlueString(`Work with the Terraform in repos/nexus-infrastructure.
+
+Examples:
+  nx-sandbox terraform generate nx-bff-web-payment --env dev1
+  nx-sandbox terraform generate nx-bff-web-payment --env dev1 --dry-run
+  terraform show -json plan.out > plan.json && nx-sandbox terraform summarize plan.json`),
+}
+
+var terraformGenerateCmd = &cobra.Command{
+	Use:   "generate <artifact>",
+	Short: "Generate a Terraform component from an inventory",
+	Long: `Generate repos/nexus-infrastructure/components/<artifact>-<env> with one
+module call per enabled inventory component and a terraform.tfvars holding
+the inventory values. Output is deterministic, so regenerating an unchanged
+inventory produces no diff.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTerraformGenerateCmd,
+}
+
+var terraformSummarizeCmd = &cobra.Command{
+	Use:   "summarize <plan.json>",
+	Short: "Summarize terraform show -json output",
+	Long: `Summarize a plan rendered with terraform show -json into creates, updates,
+deletes and replaces per resource type. Each resource is mapped back to the
+inventory artifact that owns it, by its Artifact tag or its AWS name. Deletes
+and replaces in the last environment of the promotion chain (prod1 by
+default) are flagged as destructive. Use - to read the plan from stdin.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTerraformSummarizeCmd,
+}
+
+func initTerraformCmd() {
+	rootCmd.AddCommand(terraformCmd)
+	terraformCmd.AddCommand(terraformGenerateCmd, terraformSummarizeCmd)
+
+	terraformGenerateCmd.Flags().StringVar(&terraformEnvironment, "env", "", "Target environment")
+	terraformGenerateCmd.MarkFlagRequired("env")
+	terraformGenerateCmd.Flags().BoolVar(&terraformDryRun, "dry-run", false, "Print the generated files instead of writing them")
+	terraformSummarizeCmd.Flags().StringVarP(&terraformOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runTerraformGenerateCmd(cmd *cobra.Command, args []string) error {
+	generator := terraform.NewGenerator(resolveBaseDir())
+
+	component, err := generator.Generate(args[0], terraformEnvironment)
+	if err != nil {
+		color.Red("Error generating Terraform: %v", err)
+		return err
+	}
+
+	if terraformDryRun {
+		for _, file := range component.Files {
+			color.Cyan("# %s/%s", component.Dir, file.Name)
+			fmt.Println(string(file.Content))
+		}
+		return nil
+	}
+
+	color.Cyan("🧱 Generating Terraform for %s in %s...", component.Artifact, component.Environment)
+
+	written, err := generator.Write(component)
+	if err != nil {
+		color.Red("Error writing Terraform: %v", err)
+		return err
+	}
+
+	for _, path := range written {
+		fmt.Printf("  ✓ %s\n", path)
+	}
+	color.Green("✅ Terraform component generated!")
+
+	return proposeChanges(fmt.Sprintf("Generate Terraform for %s", component.Artifact), written)
+}
+
+func runTerraformSummarizeCmd(cmd *cobra.Command, args []string) error {
+	if terraformOutput != "table" && terraformOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", terraformOutput)
+	}
+
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		color.Red("Error reading plan: %v", err)
+		return err
+	}
+
+	summary, err := terraform.NewSummarizer(resolveBaseDir()).Summarize(data)
+	if err != nil {
+		color.Red("Error summarizing plan: %v", err)
+		return err
+	}
+
+	if terraformOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	}
+
+	if len(summary.Changes) == 0 {
+		color.Green("✅ No changes. Infrastructure matches the configuration.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE TYPE\tCREATE\tUPDATE\tDELETE\tREPLACE")
+	fmt.Fprintln(w, "-------------\t------\t------\t------\t-------")
+	for _, t := range summary.Types {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", t.Type, t.Create, t.Update, t.Delete, t.Replace)
+	}
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tADDRESS\tARTIFACT\tENVIRONMENT")
+	fmt.Fprintln(w, "------\t-------\t--------\t-----------")
+	for _, change := range summary.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.Action, change.Address, orDash(change.Artifact), orDash(change.Environment))
+	}
+	w.Flush()
+
+	if destructive := summary.DestructiveChanges(); len(destructive) > 0 {
+		fmt.Println()
+		color.Red("⚠️  %d destructive change(s) in protected environments:", len(destructive))
+		for _, change := range destructive {
+			color.Red("   %s %s (%s, %s)", change.Action, change.Address, orDash(change.Artifact), change.Environment)
+		}
+	}
+
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
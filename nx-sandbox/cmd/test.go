@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/scenario"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testJUnit    string
+	testParallel int
+	testOutput   string
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: color.GreenString("Run end-to-end scenarios"),
+}
+
+var testRunCmd = &cobra.Command{
+	Use:   "run [path...]",
+	Short: "Run scenario files in isolated sandbox roots",
+	Long: color.BlueString(`Run end-to-end scenarios. A scenario describes a seed state, a sequence of
+nx-sandbox commands and workflow dispatches, and the files, outputs and AWS
+resources expected afterwards:
+
+  name: approve-and-provision
+  seed:
+    spec: {...}               # seed spec, default the built-in one
+    files: {path: content}    # written after seeding
+  config: {...}               # .nx-sandbox/config.yaml
+  aws: true                   # in-process AWS endpoint for infra commands
+  steps:
+    - run: [approve, nx-bff-web-payment, --env, dev1, --approver, alice]
+      expect: {exit_code: 0, output_contains: [Approved]}
+    - workflow: create-artifact
+      inputs: {artifact_name: nx-bff-new-service, environment: dev1}
+      expect: {conclusion: success, outputs: {job.step.output: value}}
+  expect:
+    files:
+      - path: repos/.../nx-app-inventory.yaml
+        contains: [...]
+        values: {infrastructure.deployed: "true"}
+    aws: {repositories: [...]}
+
+Every scenario runs in a temporary sandbox root with a copy of the
+github-simulator workflows, so scenarios run in parallel and never touch
+repos/. Paths default to scenarios/ and directories are searched for .yaml
+files. The command fails when a scenario fails.
+
+Examples:
+  nx-sandbox test run
+  nx-sandbox test run scenarios/ --junit scenarios.xml
+  nx-sandbox test run scenarios/create-artifact.yaml --parallel 1 -o json`),
+	RunE: runTestRunCmd,
+}
+
+func initTestCmd() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.AddCommand(testRunCmd)
+
+	testRunCmd.Flags().StringVar(&testJUnit, "junit", "", "Write a JUnit XML report to this file")
+	testRunCmd.Flags().IntVar(&testParallel, "parallel", 0, "Scenarios to run at once (default the number of CPUs)")
+	testRunCmd.Flags().StringVarP(&testOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runTestRunCmd(cmd *cobra.Command, args []string) error {
+	if testOutput != "table" && testOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", testOutput)
+	}
+
+	baseDir := resolveBaseDir()
+	paths := args
+	if len(paths) == 0 {
+		paths = []string{filepath.Join(baseDir, "scenarios")}
+	}
+
+	if testOutput == "table" {
+		color.Cyan("🧪 Running scenarios in %s...", strings.Join(paths, ", "))
+		fmt.Println()
+	}
+	report, err := scenario.NewRunner(baseDir).Run(paths, scenario.RunOptions{Parallel: testParallel})
+	if err != nil {
+		color.Red("Error running scenarios: %v", err)
+		return err
+	}
+
+	if testJUnit != "" {
+		file, err := os.Create(testJUnit)
+		if err != nil {
+			color.Red("Error writing JUnit report: %v", err)
+			return err
+		}
+		err = scenario.WriteJUnit(file, report)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			color.Red("Error writing JUnit report: %v", err)
+			return err
+		}
+	}
+
+	if testOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printScenarioReport(report)
+		if testJUnit != "" {
+			fmt.Printf("JUnit report: %s\n", testJUnit)
+		}
+	}
+
+	if failed := report.Failed(); failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d scenario(s) failed", failed, len(report.Scenarios))
+	}
+	return nil
+}
+
+func printScenarioReport(report *models.ScenarioReport) {
+	for _, result := range report.Scenarios {
+		if result.Passed {
+			color.Green("✓ %s (%s)", result.Name, result.Duration.Round(time.Millisecond))
+			continue
+		}
+		color.Red("✗ %s (%s)", result.Name, result.Duration.Round(time.Millisecond))
+		fmt.Printf("    %s\n", relativePath(result.Path))
+		if result.Error != "" {
+			fmt.Printf("    %s\n", result.Error)
+		}
+		for _, step := range result.Steps {
+			if step.Passed {
+				fmt.Printf("    ✓ %s\n", step.Name)
+				continue
+			}
+			fmt.Printf("    ✗ %s\n", step.Name)
+			for _, failure := range step.Failures {
+				fmt.Printf("        %s\n", failure)
+			}
+			for _, line := range lastLines(step.Output, 20) {
+				fmt.Printf("        │ %s\n", line)
+			}
+		}
+		for _, failure := range result.Failures {
+			fmt.Printf("    %s\n", failure)
+		}
+	}
+
+	fmt.Println()
+	passed := len(report.Scenarios) - report.Failed()
+	if report.Failed() == 0 {
+		color.Green("✅ %d scenario(s) passed in %s", passed, report.Duration.Round(time.Millisecond))
+	} else {
+		color.Red("❌ %d failed, %d passed in %s", report.Failed(), passed, report.Duration.Round(time.Millisecond))
+	}
+}
+
+// lastLines returns up to n trailing lines of output
+func lastLines(output string, n int) []string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/policy"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var validateOutput string
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: color.GreenString("Validate inventories and Helm charts"),
+	Long: color.BlueString(`Validate the inventories in nx-artifacts-inventory and the Chart.yaml and
+values.yaml of every service in the environment repositories. Inventories are
+checked against the inventory schema and the configured environments; charts
+for metadata, replica counts, autoscaling bounds and resource requests; and
+the Kuma mesh values for duplicate names and malformed hosts and ports.
+The policies in config/policies.yaml are enforced as well (see policy check).
+
+With file arguments only those files are validated.
+
+Examples:
+  nx-sandbox validate
+  nx-sandbox validate repos/nx-bolt-environment-dev1/bff/nx-bff-test-service/values.yaml
+  nx-sandbox validate -o json`),
+	RunE: runValidateCmd,
+}
+
+func initValidateCmd() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringVarP(&validateOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runValidateCmd(cmd *cobra.Command, args []string) error {
+	if validateOutput != "table" && validateOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", validateOutput)
+	}
+
+	validator := policy.NewValidator(resolveBaseDir())
+
+	var report *models.ValidationReport
+	var err error
+	if len(args) > 0 {
+		report, err = validator.ValidateFiles(args)
+	} else {
+		report, err = validator.Validate()
+	}
+	if err != nil {
+		color.Red("Error validating: %v", err)
+		return err
+	}
+
+	if validateOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printValidationReport(report)
+	}
+
+	if !report.Valid() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("validation failed with %d error(s)", report.Errors())
+	}
+	return nil
+}
+
+// printValidationReport prints issues as path:line: severity [rule] message
+func printValidationReport(report *models.ValidationReport) {
+	for _, issue := range report.Issues {
+		location := relativePath(issue.Path)
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, issue.Line)
+		}
+		text := fmt.Sprintf("%s: %s [%s] %s", location, issue.Severity, issue.Rule, issue.Message)
+		if issue.Severity == models.SeverityError {
+			color.Red("  ✗ %s", text)
+		} else {
+			color.Yellow("  ⚠ %s", text)
+		}
+	}
+
+	warnings := len(report.Issues) - report.Errors()
+	if report.Valid() {
+		color.Green("✅ %d files valid (%d warnings)", len(report.Checked), warnings)
+	} else {
+		color.Red("❌ %d errors, %d warnings in %d files", report.Errors(), warnings, len(report.Checked))
+	}
+}
+
+// relativePath shortens a path to be relative to the working directory
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/policy"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/sandbox"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/validate"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/watch"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchPoll     bool
+	watchInterval time.Duration
+	watchDebounce time.Duration
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: color.CyanString("Re-validate repos/ as files change"),
+	Long: color.BlueString(`Watch repos/ and re-run the checks affected by each change: inventories,
+Chart.yaml and values.yaml are re-validated file by file, and the status
+summary is refreshed when an inventory changes. Bursts of changes, such as an
+editor saving several files, are collected into one batch.
+
+Native file notifications are used where available, with a polling fallback.
+
+Examples:
+  nx-sandbox watch
+  nx-sandbox watch --debounce 1s
+  nx-sandbox watch --poll --interval 2s`),
+	Args: cobra.NoArgs,
+	RunE: runWatchCmd,
+}
+
+func initWatchCmd() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().BoolVar(&watchPoll, "poll", false, "Poll for changes instead of using native notifications")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", watch.DefaultInterval, "Polling interval")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", watch.DefaultDebounce, "Quiet period before re-running checks")
+}
+
+// watchSession keeps the last results so each batch prints only what changed
+type watchSession struct {
+	baseDir   string
+	validator validate.Validator
+	manager   sandbox.SandboxManager
+	issues    map[string][]models.ValidationIssue
+	status    string
+}
+
+func runWatchCmd(cmd *cobra.Command, args []string) error {
+	baseDir := resolveBaseDir()
+	watcher, err := watch.NewWatcher(layout.ReposDir(baseDir), watch.Options{
+		Interval: watchInterval,
+		Debounce: watchDebounce,
+		Poll:     watchPoll,
+	})
+	if err != nil {
+		color.Red("Error starting watch: %v", err)
+		return err
+	}
+
+	session := &watchSession{
+		baseDir:   baseDir,
+		validator: policy.NewValidator(baseDir),
+		manager:   sandbox.NewSandboxManager(baseDir),
+		issues:    make(map[string][]models.ValidationIssue),
+	}
+	if err := session.validateAll(); err != nil {
+		color.Red("Error validating: %v", err)
+		return err
+	}
+	session.refreshStatus()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mode := watcher.Mode()
+	if mode == watch.ModePolling {
+		mode = fmt.Sprintf("%s every %s", mode, watchInterval)
+	}
+	color.Cyan("👀 Watching %s (%s). Press Ctrl+C to stop.", layout.ReposDir(baseDir), mode)
+
+	if err := watcher.Watch(ctx, session.handle); err != nil {
+		color.Red("Error watching: %v", err)
+		return err
+	}
+	fmt.Println()
+	color.Yellow("🛑 Stopped watching")
+	return nil
+}
+
+// handle re-runs the checks affected by a batch of changed paths
+func (s *watchSession) handle(changes []string) {
+	s.logf(color.CyanString, "%d change(s): %s", len(changes), summarizePaths(changes))
+
+	var files []string
+	inventoryChanged := false
+	for _, path := range changes {
+		if path == layout.ReposDir(s.baseDir) {
+			// Events were lost; start over
+			if err := s.validateAll(); err != nil {
+				s.logf(color.RedString, "validate: %v", err)
+			}
+			s.refreshStatus()
+			return
+		}
+
+		switch validate.Classify(s.baseDir, path) {
+		case validate.KindInventory:
+			inventoryChanged = true
+			files = append(files, path)
+		case validate.KindChart, validate.KindValues, validate.KindMesh:
+			files = append(files, path)
+		case "":
+			s.forgetUnder(path)
+		}
+	}
+
+	if len(files) > 0 {
+		s.validateFiles(files)
+	}
+	if inventoryChanged {
+		s.refreshStatus()
+	}
+	if len(files) == 0 && !inventoryChanged {
+		s.logf(color.WhiteString, "no checks affected")
+	}
+}
+
+// Helper methods
+
+func (s *watchSession) validateAll() error {
+	report, err := s.validator.Validate()
+	if err != nil {
+		return err
+	}
+
+	s.issues = make(map[string][]models.ValidationIssue)
+	for _, issue := range report.Issues {
+		s.issues[issue.Path] = append(s.issues[issue.Path], issue)
+	}
+	for _, issue := range report.Issues {
+		s.printIssue(issue)
+	}
+	s.logf(color.CyanString, "validate: %d files checked", len(report.Checked))
+	s.printTotals()
+	return nil
+}
+
+// validateFiles re-validates changed files and reports each one's new state
+func (s *watchSession) validateFiles(files []string) {
+	report, err := s.validator.ValidateFiles(files)
+	if err != nil {
+		s.logf(color.RedString, "validate: %v", err)
+		return
+	}
+
+	checked := make(map[string]bool)
+	for _, path := range report.Checked {
+		checked[path] = true
+	}
+	current := make(map[string][]models.ValidationIssue)
+	for _, issue := range report.Issues {
+		current[issue.Path] = append(current[issue.Path], issue)
+	}
+
+	for _, path := range files {
+		previous := s.issues[path]
+		switch {
+		case !checked[path]:
+			delete(s.issues, path)
+			s.logf(color.WhiteString, "validate: %s removed", relativePath(path))
+		case len(current[path]) > 0:
+			s.issues[path] = current[path]
+			for _, issue := range current[path] {
+				s.printIssue(issue)
+			}
+		case len(previous) > 0:
+			delete(s.issues, path)
+			s.logf(color.GreenString, "validate: ✓ %s fixed", relativePath(path))
+		default:
+			s.logf(color.GreenString, "validate: ✓ %s valid", relativePath(path))
+		}
+	}
+	s.printTotals()
+}
+
+// forgetUnder drops the results of files under a removed directory
+func (s *watchSession) forgetUnder(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for path := range s.issues {
+		if strings.HasPrefix(path, prefix) {
+			delete(s.issues, path)
+		}
+	}
+}
+
+// refreshStatus prints the status summary when it differs from the last one
+func (s *watchSession) refreshStatus() {
+	status, err := s.manager.GetStatus()
+	if err != nil {
+		s.logf(color.RedString, "status: %v", err)
+		return
+	}
+
+	health := "healthy"
+	if !status.IsHealthy {
+		health = strings.Join(status.Issues, "; ")
+	}
+	summary := fmt.Sprintf("%s, %d pending approval(s)", health, len(status.PendingApprovals))
+	if summary == s.status {
+		return
+	}
+	s.status = summary
+
+	colorize := color.GreenString
+	if !status.IsHealthy || len(status.PendingApprovals) > 0 {
+		colorize = color.YellowString
+	}
+	s.logf(colorize, "status: %s", summary)
+}
+
+// printTotals summarizes the issues remaining across the sandbox
+func (s *watchSession) printTotals() {
+	errors, warnings := 0, 0
+	for _, issues := range s.issues {
+		for _, issue := range issues {
+			if issue.Severity == models.SeverityError {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+	}
+	if errors == 0 {
+		s.logf(color.GreenString, "✅ no errors (%d warnings)", warnings)
+	} else {
+		s.logf(color.RedString, "❌ %d errors, %d warnings in %d files", errors, warnings, len(s.issues))
+	}
+}
+
+func (s *watchSession) printIssue(issue models.ValidationIssue) {
+	location := relativePath(issue.Path)
+	if issue.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, issue.Line)
+	}
+	text := fmt.Sprintf("%s: %s [%s] %s", location, issue.Severity, issue.Rule, issue.Message)
+	if issue.Severity == models.SeverityError {
+		s.logf(color.RedString, "✗ %s", text)
+	} else {
+		s.logf(color.YellowString, "⚠ %s", text)
+	}
+}
+
+// logf prints a timestamped line
+func (s *watchSession) logf(colorize func(string, ...interface{}) string, format string, args ...interface{}) {
+	fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), colorize(format, args...))
+}
+
+// summarizePaths lists up to three changed paths
+func summarizePaths(paths []string) string {
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, relativePath(path))
+	}
+	sort.Strings(names)
+	if len(names) > 3 {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:3], ", "), len(names)-3)
+	}
+	return strings.Join(names, ", ")
+}
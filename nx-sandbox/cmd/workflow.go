@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workflowInputs []string
+	workflowEvent  string
+	workflowActor  string
+	workflowDryRun bool
+	workflowOutput string
+)
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: color.MagentaString("Run the github-simulator workflows"),
+	Long: color.BlueString(`Run the GitHub Actions workflows in github-simulator/workflows against the
+repositories under repos/. Jobs run in dependency order and run: steps are
+executed with bash, with $GITHUB_OUTPUT and needs.*.outputs wired up as on
+GitHub. actions/checkout switches the job to the checked-out repository;
+other actions are skipped. git push and sleep are no-ops.
+
+Examples:
+  nx-sandbox workflow list
+  nx-sandbox workflow run create-artifact --input artifact_name=nx-bff-web-loyalty --input environment=dev1
+  nx-sandbox workflow run add-dynamo --event repository_dispatch --input artifact_name=nx-bff-web-loyalty
+  nx-sandbox workflow run approve-infra-creation --dry-run --input artifact_name=nx-bff-web-payment
+  nx-sandbox workflow runs
+  nx-sandbox workflow show 3
+  nx-sandbox workflow lint`),
+}
+
+var workflowListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workflows with their events and inputs",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkflowListCmd,
+}
+
+var workflowRunCmd = &cobra.Command{
+	Use:   "run <workflow>",
+	Short: "Run a workflow",
+	Long: `Run a workflow. workflow_dispatch inputs are checked against the workflow's
+declaration and defaults are applied. Inputs are also delivered as
+github.event.client_payload, as the slash command bot does.
+
+With --dry-run the steps run against a copy of repos/: the unified diff of
+every file the workflow would have changed and the $GITHUB_OUTPUT values of
+each step are shown, and neither repos/ nor the run history is touched.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkflowRunCmd,
+}
+
+var workflowRunsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "List recorded workflow runs",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkflowRunsCmd,
+}
+
+var workflowShowCmd = &cobra.Command{
+	Use:   "show <run-id>",
+	Short: "Show a recorded workflow run with its step logs",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorkflowShowCmd,
+}
+
+var workflowLintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Check the workflows for mistakes without running them",
+	Long: `Parse every workflow under github-simulator/workflows, or only the given
+files, and report with file and line:
+
+  - needs.<job>.outputs.<name> where the job is not in needs or does not
+    declare the output, and steps.<id>.outputs.<name> of missing, later or
+    silent steps
+  - inputs and github.event.client_payload fields without a matching
+    workflow_dispatch input
+  - environment choice options out of sync with the environments in
+    .nx-sandbox/config.yaml, and choice defaults that are not an option
+  - invalid expressions and unknown needs
+
+The command fails when any error is found.`,
+	RunE: runWorkflowLintCmd,
+}
+
+func initWorkflowCmd() {
+	rootCmd.AddCommand(workflowCmd)
+	workflowCmd.AddCommand(workflowListCmd, workflowRunCmd, workflowRunsCmd, workflowShowCmd, workflowLintCmd)
+
+	workflowRunCmd.Flags().StringArrayVarP(&workflowInputs, "input", "i", nil, "Input as name=value (repeatable)")
+	workflowRunCmd.Flags().StringVar(&workflowEvent, "event", "", "Event to trigger (workflow_dispatch, repository_dispatch)")
+	workflowRunCmd.Flags().StringVar(&workflowActor, "actor", currentUser(), "User triggering the workflow")
+	workflowRunCmd.Flags().BoolVar(&workflowDryRun, "dry-run", false, "Run against a copy of repos/ and show the planned changes")
+	workflowLintCmd.Flags().StringVarP(&workflowOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+func runWorkflowListCmd(cmd *cobra.Command, args []string) error {
+	workflows, err := workflow.NewRunner(resolveBaseDir()).Workflows()
+	if err != nil {
+		color.Red("Error listing workflows: %v", err)
+		return err
+	}
+
+	if len(workflows) == 0 {
+		color.Yellow("No workflows found in %s", workflow.Dir(resolveBaseDir()))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTITLE\tEVENTS\tINPUTS")
+	fmt.Fprintln(w, "----\t-----\t------\t------")
+	for _, wf := range workflows {
+		var inputs []string
+		for _, input := range wf.Inputs() {
+			name := input.Name
+			if !input.Required {
+				name += "?"
+			}
+			inputs = append(inputs, name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", wf.Name, wf.Title, strings.Join(wf.Events(), ", "), orDash(strings.Join(inputs, ", ")))
+	}
+	return w.Flush()
+}
+
+func runWorkflowRunCmd(cmd *cobra.Command, args []string) error {
+	inputs, err := parseInputs(workflowInputs)
+	if err != nil {
+		return err
+	}
+
+	if workflowDryRun {
+		color.Cyan("🔍 Dry-running workflow %s...", args[0])
+	} else {
+		color.Cyan("🚀 Running workflow %s...", args[0])
+	}
+
+	run, err := workflow.NewRunner(resolveBaseDir()).Run(args[0], workflow.RunOptions{
+		Event:  workflowEvent,
+		Inputs: inputs,
+		Actor:  workflowActor,
+		Output: os.Stdout,
+		DryRun: workflowDryRun,
+	})
+	if err != nil {
+		color.Red("Error running workflow: %v", err)
+		return err
+	}
+
+	fmt.Println()
+	printWorkflowJobs(run)
+	if run.DryRun {
+		return printWorkflowDryRun(cmd, run)
+	}
+	if run.Conclusion == models.ConclusionFailure {
+		color.Red("❌ Run #%d of %s failed", run.ID, run.Workflow)
+		cmd.SilenceUsage = true
+		return fmt.Errorf("workflow run #%d failed", run.ID)
+	}
+
+	color.Green("✅ Run #%d of %s succeeded", run.ID, run.Workflow)
+	return nil
+}
+
+func runWorkflowRunsCmd(cmd *cobra.Command, args []string) error {
+	runs, err := workflow.NewRunner(resolveBaseDir()).Runs()
+	if err != nil {
+		color.Red("Error listing workflow runs: %v", err)
+		return err
+	}
+
+	if len(runs) == 0 {
+		color.Yellow("No workflow runs recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tWORKFLOW\tEVENT\tCONCLUSION\tACTOR\tSTARTED")
+	fmt.Fprintln(w, "--\t--------\t-----\t----------\t-----\t-------")
+	for _, run := range runs {
+		fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID, run.Workflow, run.Event, run.Conclusion, orDash(run.Actor), run.StartedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func runWorkflowShowCmd(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid workflow run id '%s'", args[0])
+	}
+
+	run, err := workflow.NewRunner(resolveBaseDir()).GetRun(id)
+	if err != nil {
+		color.Red("Error reading workflow run: %v", err)
+		return err
+	}
+
+	color.Cyan("#%d %s (%s)", run.ID, run.Workflow, run.Conclusion)
+	fmt.Printf("   Event: %s\n", run.Event)
+	fmt.Printf("   Actor: %s\n", orDash(run.Actor))
+	fmt.Printf("   Started: %s\n", run.StartedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Duration: %s\n", run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond))
+	for _, name := range sortedInputNames(run.Inputs) {
+		fmt.Printf("   Input %s: %s\n", name, run.Inputs[name])
+	}
+	fmt.Println()
+
+	for _, job := range run.Jobs {
+		color.Cyan("▶ %s: %s", job.ID, job.Conclusion)
+		for _, step := range job.Steps {
+			fmt.Printf("  • %s: %s", step.Name, step.Conclusion)
+			if step.Message != "" {
+				fmt.Printf(" (%s)", step.Message)
+			}
+			fmt.Println()
+			for _, line := range strings.Split(strings.TrimRight(step.Log, "\n"), "\n") {
+				if line != "" {
+					fmt.Printf("      %s\n", line)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func runWorkflowLintCmd(cmd *cobra.Command, args []string) error {
+	if workflowOutput != "table" && workflowOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': expected table or json", workflowOutput)
+	}
+
+	linter := workflow.NewLinter(resolveBaseDir())
+	var report *models.ValidationReport
+	var err error
+	if len(args) > 0 {
+		report, err = linter.LintFiles(args)
+	} else {
+		report, err = linter.Lint()
+	}
+	if err != nil {
+		color.Red("Error linting workflows: %v", err)
+		return err
+	}
+
+	if workflowOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printValidationReport(report)
+	}
+
+	if !report.Valid() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d workflow error(s)", report.Errors())
+	}
+	return nil
+}
+
+// printWorkflowJobs prints the conclusion and outputs of each job
+func printWorkflowJobs(run *models.WorkflowRun) {
+	for _, job := range run.Jobs {
+		switch job.Conclusion {
+		case models.ConclusionSuccess:
+			color.Green("  ✓ %s", job.ID)
+		case models.ConclusionSkipped:
+			color.Yellow("  ⏭ %s (skipped)", job.ID)
+		default:
+			color.Red("  ✗ %s", job.ID)
+		}
+		for _, name := range sortedInputNames(job.Outputs) {
+			fmt.Printf("      %s=%s\n", name, job.Outputs[name])
+		}
+	}
+}
+
+// printWorkflowDryRun prints the step outputs and planned file changes of a dry run
+func printWorkflowDryRun(cmd *cobra.Command, run *models.WorkflowRun) error {
+	fmt.Println()
+	color.Cyan("📤 $GITHUB_OUTPUT values:")
+	outputs := 0
+	for _, job := range run.Jobs {
+		for _, step := range job.Steps {
+			for _, name := range sortedInputNames(step.Outputs) {
+				fmt.Printf("   %s / %s: %s=%s\n", job.ID, step.Name, name, step.Outputs[name])
+				outputs++
+			}
+		}
+	}
+	if outputs == 0 {
+		fmt.Println("   (none)")
+	}
+
+	fmt.Println()
+	if len(run.Changes) == 0 {
+		color.Cyan("📝 No files would change")
+	} else {
+		color.Cyan("📝 %d file(s) would change:", len(run.Changes))
+		for _, change := range run.Changes {
+			fmt.Printf("   %s %s\n", change.Change, change.Path)
+		}
+		fmt.Println()
+		for _, change := range run.Changes {
+			printDiff(change.Diff)
+		}
+	}
+
+	fmt.Println()
+	if run.Conclusion == models.ConclusionFailure {
+		color.Red("❌ Dry run of %s failed; nothing was changed", run.Workflow)
+		cmd.SilenceUsage = true
+		return fmt.Errorf("dry run of %s failed", run.Workflow)
+	}
+	color.Green("✅ Dry run of %s succeeded; nothing was changed", run.Workflow)
+	return nil
+}
+
+// parseInputs parses name=value pairs
+func parseInputs(pairs []string) (map[string]string, error) {
+	inputs := make(map[string]string)
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid input '%s': expected name=value", pair)
+		}
+		inputs[name] = value
+	}
+	return inputs, nil
+}
+
+func sortedInputNames(values map[string]string) []string {
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
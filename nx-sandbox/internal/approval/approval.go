@@ -0,0 +1,179 @@
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// StoreFileName is the approvals store inside .nx-sandbox
+const StoreFileName = "approvals.json"
+
+// Request describes an infrastructure approval
+type Request struct {
+	Artifact     string
+	Environment  string
+	Architecture models.Architecture
+	Approver     string
+}
+
+// Approver defines the interface for approving infrastructure creation
+type Approver interface {
+	Approve(req Request) (*models.ApprovalRecord, error)
+	Pending() ([]models.PendingApproval, error)
+	History() ([]models.ApprovalRecord, error)
+}
+
+// DefaultApprover checks approvers against the configured roster and keeps
+// approval records in .nx-sandbox/approvals.json
+type DefaultApprover struct {
+	baseDir string
+	now     func() time.Time
+}
+
+// NewApprover creates a new approver for a sandbox root
+func NewApprover(baseDir string) Approver {
+	return &DefaultApprover{
+		baseDir: baseDir,
+		now:     time.Now,
+	}
+}
+
+// StorePath returns the location of the approvals store
+func StorePath(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), StoreFileName)
+}
+
+// Approve enables infrastructure for an artifact and records the approval
+func (a *DefaultApprover) Approve(req Request) (*models.ApprovalRecord, error) {
+	cfg, err := config.Load(a.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Architecture == "" {
+		req.Architecture = models.ArchitectureCentralized
+	}
+	if req.Architecture != models.ArchitectureCentralized && req.Architecture != models.ArchitectureIndividual {
+		return nil, fmt.Errorf("invalid architecture '%s': expected centralized or individual", req.Architecture)
+	}
+
+	if !cfg.HasEnvironment(req.Environment) {
+		return nil, fmt.Errorf("unknown environment '%s'", req.Environment)
+	}
+
+	if req.Approver == "" {
+		return nil, fmt.Errorf("approver is required")
+	}
+	team, ok := cfg.ApproverTeam(req.Approver)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a member of any approver team in %s", req.Approver, config.Path(a.baseDir))
+	}
+
+	entry, err := inventory.Open(a.baseDir, req.Artifact, req.Environment)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Inventory.Infrastructure.Enabled {
+		return nil, fmt.Errorf("infrastructure for '%s' in %s is already approved", entry.Name(), req.Environment)
+	}
+
+	now := a.now().UTC()
+	record := models.ApprovalRecord{
+		ID:           fmt.Sprintf("%s-%s-%d", entry.Name(), req.Environment, now.Unix()),
+		Artifact:     entry.Name(),
+		Environment:  req.Environment,
+		Architecture: req.Architecture,
+		Approver:     req.Approver,
+		Team:         team,
+		Components:   entry.Inventory.Components.EnabledComponents(),
+		ApprovedAt:   now,
+	}
+
+	// The record is written first so an approved inventory always has one;
+	// it is rolled back if the inventory cannot be updated
+	previous, err := a.History()
+	if err != nil {
+		return nil, err
+	}
+	records := append(append([]models.ApprovalRecord{}, previous...), record)
+	if err := a.saveRecords(records); err != nil {
+		return nil, fmt.Errorf("failed to record approval: %w", err)
+	}
+
+	err = entry.Set("infrastructure.enabled", true)
+	if err == nil {
+		err = entry.Save()
+	}
+	if err != nil {
+		if rollbackErr := a.saveRecords(records[:len(records)-1]); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to update inventory: %w (and failed to roll back the approval record: %v)", err, rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Pending lists inventories that request components but are not yet approved
+func (a *DefaultApprover) Pending() ([]models.PendingApproval, error) {
+	entries, err := inventory.List(a.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []models.PendingApproval
+	for _, entry := range entries {
+		components := entry.Inventory.Components.EnabledComponents()
+		if entry.Inventory.Infrastructure.Enabled || len(components) == 0 {
+			continue
+		}
+		pending = append(pending, models.PendingApproval{
+			Artifact:    entry.Name(),
+			Environment: entry.Environment(),
+			Components:  components,
+			Path:        entry.Path,
+		})
+	}
+
+	return pending, nil
+}
+
+// History returns every recorded approval, oldest first
+func (a *DefaultApprover) History() ([]models.ApprovalRecord, error) {
+	data, err := os.ReadFile(StorePath(a.baseDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approvals store: %w", err)
+	}
+
+	var records []models.ApprovalRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse approvals store: %w", err)
+	}
+	return records, nil
+}
+
+// Helper methods
+
+func (a *DefaultApprover) saveRecords(records []models.ApprovalRecord) error {
+	path := StorePath(a.baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,152 @@
+package approval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+const testInventory = `schema_version: "1.0"
+artifact_metadata:
+  artifact_name: "nx-bff-web-payment-dev1"
+  layer: "bff"
+infrastructure:
+  enabled: false
+  deployed: false
+  component: "service_account"
+  environment: "dev1"
+components:
+  service_account:
+    name: "sa-nx-bff-web-payment-dev1"
+    enabled: true
+  redis:
+    enabled: true
+`
+
+const testConfig = `approvers:
+  teams:
+    platform-engineers: [alice]
+    sre-team: [bob]
+`
+
+// setupTestEnv creates a sandbox with one inventory and an approver roster
+func setupTestEnv(t *testing.T) string {
+	tmpDir := t.TempDir()
+
+	artifactDir := filepath.Join(tmpDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1")
+	os.MkdirAll(artifactDir, 0755)
+	os.WriteFile(filepath.Join(artifactDir, "nx-app-inventory.yaml"), []byte(testInventory), 0644)
+
+	os.MkdirAll(filepath.Join(tmpDir, ".nx-sandbox"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, ".nx-sandbox", "config.yaml"), []byte(testConfig), 0644)
+
+	return tmpDir
+}
+
+func TestPending(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	approver := NewApprover(baseDir)
+
+	pending, err := approver.Pending()
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 pending approval, got %d", len(pending))
+	}
+	if strings.Join(pending[0].Components, ",") != "service_account,redis" {
+		t.Errorf("Unexpected components: %v", pending[0].Components)
+	}
+}
+
+func TestApprove(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	approver := NewApprover(baseDir)
+
+	record, err := approver.Approve(Request{
+		Artifact:     "nx-bff-web-payment",
+		Environment:  "dev1",
+		Architecture: models.ArchitectureIndividual,
+		Approver:     "bob",
+	})
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	if record.Team != "sre-team" {
+		t.Errorf("Expected team 'sre-team', got '%s'", record.Team)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1", "nx-app-inventory.yaml"))
+	if !strings.Contains(string(data), "infrastructure:\n  enabled: true") {
+		t.Errorf("Expected infrastructure.enabled to be true:\n%s", data)
+	}
+
+	history, err := approver.History()
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Approver != "bob" {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+
+	pending, _ := approver.Pending()
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending approvals after approval, got %d", len(pending))
+	}
+
+	if _, err := approver.Approve(Request{Artifact: "nx-bff-web-payment", Environment: "dev1", Approver: "alice"}); err == nil {
+		t.Error("Expected second approval to fail")
+	}
+}
+
+func TestApprove_NotInRoster(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	approver := NewApprover(baseDir)
+
+	_, err := approver.Approve(Request{Artifact: "nx-bff-web-payment", Environment: "dev1", Approver: "mallory"})
+	if err == nil {
+		t.Fatal("Expected approval by non-member to fail")
+	}
+
+	history, _ := approver.History()
+	if len(history) != 0 {
+		t.Error("Rejected approval should not be recorded")
+	}
+}
+
+func TestApprove_RecordFailureLeavesInventory(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	approver := NewApprover(baseDir)
+
+	// A directory in place of the store makes recording the approval fail
+	os.MkdirAll(StorePath(baseDir), 0755)
+
+	if _, err := approver.Approve(Request{Artifact: "nx-bff-web-payment", Environment: "dev1", Approver: "alice"}); err == nil {
+		t.Fatal("Expected approval to fail when it cannot be recorded")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1", "nx-app-inventory.yaml"))
+	if !strings.Contains(string(data), "infrastructure:\n  enabled: false") {
+		t.Errorf("Expected the inventory to stay unapproved:\n%s", data)
+	}
+}
+
+func TestApprove_InvalidInput(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	approver := NewApprover(baseDir)
+
+	if _, err := approver.Approve(Request{Artifact: "nx-bff-web-payment", Environment: "dev1", Architecture: "hybrid", Approver: "alice"}); err == nil {
+		t.Error("Expected invalid architecture to fail")
+	}
+	if _, err := approver.Approve(Request{Artifact: "nx-bff-web-payment", Environment: "qa9", Approver: "alice"}); err == nil {
+		t.Error("Expected unknown environment to fail")
+	}
+	if _, err := approver.Approve(Request{Artifact: "nx-bff-missing", Environment: "dev1", Approver: "alice"}); err == nil {
+		t.Error("Expected missing inventory to fail")
+	}
+}
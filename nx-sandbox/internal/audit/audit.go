@@ -0,0 +1,353 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/snapshot"
+)
+
+// LogFileName is the audit log inside .nx-sandbox
+const LogFileName = layout.AuditLogFileName
+
+// DefaultRoots are the directories hashed around an operation that does not
+// name its own, relative to the sandbox root
+var DefaultRoots = []string{"repos", layout.StateDirName}
+
+// Log defines the interface for the audit log
+type Log interface {
+	Append(entry models.AuditEntry) error
+	Entries(filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+// DefaultLog appends one JSON entry per line to .nx-sandbox/audit.jsonl.
+// Entries are never rewritten.
+type DefaultLog struct {
+	baseDir string
+}
+
+// NewLog creates a new audit log for a sandbox root
+func NewLog(baseDir string) Log {
+	return &DefaultLog{
+		baseDir: baseDir,
+	}
+}
+
+// LogPath returns the location of the audit log
+func LogPath(baseDir string) string {
+	return layout.AuditLogFile(baseDir)
+}
+
+// Append writes an entry to the end of the log
+func (l *DefaultLog) Append(entry models.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(layout.StateDir(l.baseDir), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	file, err := os.OpenFile(LogPath(l.baseDir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the entries matching the filter, oldest first
+func (l *DefaultLog) Entries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	file, err := os.Open(LogPath(l.baseDir))
+	if os.IsNotExist(err) {
+		return []models.AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var environments []string
+	if filter.Artifact != "" {
+		cfg, err := config.Load(l.baseDir)
+		if err != nil {
+			return nil, err
+		}
+		environments = cfg.Environments
+	}
+
+	entries := []models.AuditEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", LogPath(l.baseDir), line, err)
+		}
+		if matches(entry, filter, environments) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Operation is an audited operation in progress
+type Operation struct {
+	log     Log
+	baseDir string
+	roots   []string
+	entry   models.AuditEntry
+	before  *manifest
+	scanErr error
+	now     func() time.Time
+}
+
+// Begin starts auditing an operation by hashing the files under roots,
+// relative to the sandbox root, so Finish can tell what changed. Without
+// roots, DefaultRoots are hashed. Version control directories, the snapshot
+// store and the audit log itself are never hashed.
+func Begin(baseDir, operation string, args []string, user string, roots ...string) *Operation {
+	if len(roots) == 0 {
+		roots = DefaultRoots
+	}
+	op := &Operation{
+		log:     NewLog(baseDir),
+		baseDir: baseDir,
+		roots:   roots,
+		entry: models.AuditEntry{
+			Operation: operation,
+			Args:      args,
+			User:      user,
+		},
+		now: time.Now,
+	}
+	op.entry.Time = op.now().UTC()
+	op.before, op.scanErr = scanRoots(baseDir, roots)
+	return op
+}
+
+// Finish records the operation with the paths it changed and its result.
+// The entry is written even when the changed paths cannot be determined.
+func (o *Operation) Finish(opErr error) error {
+	o.entry.DurationMS = o.now().Sub(o.entry.Time).Milliseconds()
+	o.entry.Result = models.AuditSuccess
+	if opErr != nil {
+		o.entry.Result = models.AuditFailure
+		o.entry.Error = opErr.Error()
+	}
+
+	o.entry.Paths = []models.AuditChange{}
+	scanErr := o.scanErr
+	if scanErr == nil {
+		var after *manifest
+		if after, scanErr = scanRoots(o.baseDir, o.roots); scanErr == nil {
+			o.entry.Paths = changes(o.before, after)
+		}
+	}
+
+	if err := o.log.Append(o.entry); err != nil {
+		return err
+	}
+	if scanErr != nil {
+		return fmt.Errorf("failed to determine changed paths: %w", scanErr)
+	}
+	return nil
+}
+
+// Helper methods
+
+// manifest holds the hashes of files and the directories present, keyed by
+// slash-separated paths relative to the sandbox root
+type manifest struct {
+	files map[string]string
+	dirs  map[string]bool
+}
+
+func scanRoots(baseDir string, roots []string) (*manifest, error) {
+	m := &manifest{files: make(map[string]string), dirs: make(map[string]bool)}
+	skip := map[string]bool{
+		filepath.Clean(snapshot.Dir(baseDir)): true,
+		filepath.Clean(LogPath(baseDir)):      true,
+	}
+
+	for _, root := range roots {
+		rootDir := filepath.Join(baseDir, root)
+		err := filepath.WalkDir(rootDir, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if p != rootDir && (skip[filepath.Clean(p)] || d.IsDir() && d.Name() == ".git") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			rel, err := filepath.Rel(baseDir, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			switch {
+			case d.IsDir():
+				m.dirs[rel] = true
+			case d.Type().IsRegular():
+				hash, err := hashFile(p)
+				if err != nil {
+					return err
+				}
+				m.files[rel] = hash
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", rootDir, err)
+		}
+	}
+	return m, nil
+}
+
+func hashFile(p string) (string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// changes compares two manifests. Files in a directory that was created or
+// removed as a whole are reported as that directory.
+func changes(before, after *manifest) []models.AuditChange {
+	var changed []string
+	for p, hash := range after.files {
+		if before.files[p] != hash {
+			changed = append(changed, p)
+		}
+	}
+	for p := range before.files {
+		if _, ok := after.files[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	sort.Strings(changed)
+
+	result := []models.AuditChange{}
+	grouped := make(map[string]bool)
+	for _, p := range changed {
+		var dir string
+		switch {
+		case before.files[p] == "":
+			dir = outermostDir(p, after.dirs, before.dirs)
+		case after.files[p] == "":
+			dir = outermostDir(p, before.dirs, after.dirs)
+		}
+		if dir != "" {
+			grouped[dir] = true
+			continue
+		}
+		result = append(result, models.AuditChange{Path: p, Before: before.files[p], After: after.files[p]})
+	}
+
+	for dir := range grouped {
+		result = append(result, models.AuditChange{Path: dir, Before: before.treeHash(dir), After: after.treeHash(dir)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// outermostDir returns the outermost directory containing p that is in one
+// manifest but not the other, or "" when p's own directory is in both
+func outermostDir(p string, in, notIn map[string]bool) string {
+	outermost := ""
+	for dir := path.Dir(p); dir != "." && dir != "/" && in[dir] && !notIn[dir]; dir = path.Dir(dir) {
+		outermost = dir
+	}
+	return outermost
+}
+
+// treeHash hashes the paths and hashes of the files under a directory
+func (m *manifest) treeHash(dir string) string {
+	if !m.dirs[dir] {
+		return ""
+	}
+	var paths []string
+	for p := range m.files {
+		if strings.HasPrefix(p, dir+"/") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(hash, "%s\x00%s\n", strings.TrimPrefix(p, dir+"/"), m.files[p])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func matches(entry models.AuditEntry, filter models.AuditFilter, environments []string) bool {
+	if filter.Operation != "" && entry.Operation != filter.Operation &&
+		!strings.HasPrefix(entry.Operation, filter.Operation+" ") {
+		return false
+	}
+	if !filter.Since.IsZero() && entry.Time.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && entry.Time.After(filter.Until) {
+		return false
+	}
+	if filter.Artifact == "" {
+		return true
+	}
+
+	names := map[string]bool{filter.Artifact: true}
+	for _, env := range environments {
+		names[layout.EnvironmentArtifactName(filter.Artifact, env)] = true
+	}
+	for _, arg := range entry.Args {
+		if names[arg] {
+			return true
+		}
+	}
+	for _, change := range entry.Paths {
+		for _, segment := range strings.Split(change.Path, "/") {
+			if names[segment] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/snapshot"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOperation_RecordsChanges(t *testing.T) {
+	baseDir := t.TempDir()
+	values := filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", "bff", "nx-bff-a", "values.yaml")
+	removed := filepath.Join(baseDir, "repos", "old", "nested", "file.yaml")
+	writeFile(t, values, "replicaCount: 1\n")
+	writeFile(t, removed, "x\n")
+	writeFile(t, filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", ".git", "HEAD"), "ref\n")
+
+	op := Begin(baseDir, "env set", []string{"nx-bff-a", "--env=dev1"}, "alice")
+	writeFile(t, values, "replicaCount: 2\n")
+	writeFile(t, filepath.Join(baseDir, "repos", "new", "a.yaml"), "a\n")
+	writeFile(t, filepath.Join(baseDir, "repos", "new", "b", "c.yaml"), "c\n")
+	writeFile(t, filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", ".git", "HEAD"), "changed\n")
+	os.RemoveAll(filepath.Join(baseDir, "repos", "old"))
+	if err := op.Finish(nil); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	entries, err := NewLog(baseDir).Entries(models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Operation != "env set" || entry.User != "alice" || entry.Result != models.AuditSuccess {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+
+	paths := make(map[string]models.AuditChange)
+	for _, change := range entry.Paths {
+		paths[change.Path] = change
+	}
+	if len(paths) != 3 {
+		t.Fatalf("Expected 3 changes, got %+v", entry.Paths)
+	}
+	modified := paths["repos/nx-bolt-environment-dev1/bff/nx-bff-a/values.yaml"]
+	if modified.Before == "" || modified.After == "" || modified.Before == modified.After {
+		t.Errorf("Expected before and after hashes, got %+v", modified)
+	}
+	if change := paths["repos/new"]; change.Before != "" || change.After == "" {
+		t.Errorf("Expected repos/new to be created as a whole, got %+v", change)
+	}
+	if change := paths["repos/old"]; change.Before == "" || change.After != "" {
+		t.Errorf("Expected repos/old to be removed as a whole, got %+v", change)
+	}
+}
+
+func TestOperation_RecordsFailure(t *testing.T) {
+	baseDir := t.TempDir()
+
+	op := Begin(baseDir, "approve", []string{"nx-bff-a"}, "bob")
+	if err := op.Finish(errors.New("approver is required")); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	entries, _ := NewLog(baseDir).Entries(models.AuditFilter{})
+	if len(entries) != 1 || entries[0].Result != models.AuditFailure || entries[0].Error != "approver is required" {
+		t.Errorf("Expected a failed entry, got %+v", entries)
+	}
+	if entries[0].Paths == nil {
+		t.Error("Expected an empty path list, not null")
+	}
+}
+
+func TestEntries_Filter(t *testing.T) {
+	baseDir := t.TempDir()
+	log := NewLog(baseDir)
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, entry := range []models.AuditEntry{
+		{Time: day, Operation: "create", Args: []string{"nx-bff-web-payment"}},
+		{Time: day.Add(time.Hour), Operation: "env set", Paths: []models.AuditChange{
+			{Path: "repos/nx-artifacts-inventory/nx-artifacts/bff/nx-bff-web-payment-dev1/nx-app-inventory.yaml"},
+		}},
+		{Time: day.Add(2 * time.Hour), Operation: "env unset", Args: []string{"nx-bff-web-payment-extra"}},
+		{Time: day.Add(24 * time.Hour), Operation: "environment", Args: []string{"nx-bff-web-payment"}},
+	} {
+		if err := log.Append(entry); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	for name, test := range map[string]struct {
+		filter   models.AuditFilter
+		expected int
+	}{
+		"all":             {models.AuditFilter{}, 4},
+		"artifact":        {models.AuditFilter{Artifact: "nx-bff-web-payment"}, 3},
+		"command group":   {models.AuditFilter{Operation: "env"}, 2},
+		"command":         {models.AuditFilter{Operation: "env set"}, 1},
+		"since":           {models.AuditFilter{Since: day.Add(time.Hour)}, 3},
+		"until":           {models.AuditFilter{Until: day.Add(time.Hour)}, 2},
+		"since and until": {models.AuditFilter{Since: day.Add(time.Minute), Until: day.Add(3 * time.Hour)}, 2},
+	} {
+		entries, err := log.Entries(test.filter)
+		if err != nil {
+			t.Fatalf("%s: Entries failed: %v", name, err)
+		}
+		if len(entries) != test.expected {
+			t.Errorf("%s: expected %d entries, got %d", name, test.expected, len(entries))
+		}
+	}
+}
+
+func TestEntries_MissingLog(t *testing.T) {
+	entries, err := NewLog(t.TempDir()).Entries(models.AuditFilter{})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries, got %v: %v", entries, err)
+	}
+}
+
+func TestSnapshotRestore_KeepsEntries(t *testing.T) {
+	baseDir := t.TempDir()
+	values := filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", "bff", "nx-bff-a", "values.yaml")
+	store := snapshot.NewStore(baseDir)
+	record := func(operation string, fn func() error) {
+		t.Helper()
+		op := Begin(baseDir, operation, nil, "alice")
+		if err := op.Finish(fn()); err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+	}
+	seed := func(content string) func() error {
+		return func() error { return os.WriteFile(values, []byte(content), 0644) }
+	}
+
+	writeFile(t, values, "replicaCount: 1\n")
+	record("seed", seed("replicaCount: 1\n"))
+	record("snapshot create", func() error { _, err := store.Create("s1"); return err })
+	record("seed", seed("replicaCount: 2\n"))
+	record("seed", seed("replicaCount: 3\n"))
+	record("snapshot restore", func() error { _, err := store.Restore("s1"); return err })
+
+	entries, err := NewLog(baseDir).Entries(models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Expected the restore to keep every entry, got %d", len(entries))
+	}
+	if data, _ := os.ReadFile(values); string(data) != "replicaCount: 1\n" {
+		t.Errorf("Expected the snapshot content to be restored, got %q", data)
+	}
+}
@@ -0,0 +1,61 @@
+package awsclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// Options configures the AWS-compatible endpoint the sandbox talks to
+type Options struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// Clients bundles the AWS service clients used by the sandbox
+type Clients struct {
+	DynamoDB    *dynamodb.Client
+	ECR         *ecr.Client
+	ElastiCache *elasticache.Client
+	IAM         *iam.Client
+	RDS         *rds.Client
+}
+
+// DefaultCredential is the access key and secret LocalStack accepts
+const DefaultCredential = "test"
+
+// New creates service clients that send every request to opts.Endpoint.
+// Missing credentials default to the LocalStack test credentials.
+func New(opts Options) *Clients {
+	if opts.AccessKey == "" {
+		opts.AccessKey = DefaultCredential
+	}
+	if opts.SecretKey == "" {
+		opts.SecretKey = DefaultCredential
+	}
+
+	cfg := aws.Config{
+		Region:           opts.Region,
+		Credentials:      credentials.NewStaticCredentialsProvider(opts.AccessKey, opts.SecretKey, ""),
+		BaseEndpoint:     aws.String(opts.Endpoint),
+		HTTPClient:       &http.Client{Timeout: 30 * time.Second},
+		RetryMaxAttempts: 2,
+	}
+
+	return &Clients{
+		DynamoDB:    dynamodb.NewFromConfig(cfg),
+		ECR:         ecr.NewFromConfig(cfg),
+		ElastiCache: elasticache.NewFromConfig(cfg),
+		IAM:         iam.NewFromConfig(cfg),
+		RDS:         rds.NewFromConfig(cfg),
+	}
+}
@@ -0,0 +1,456 @@
+package awsfake
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AccountID is the account used in every ARN returned by the fake
+const AccountID = "000000000000"
+
+// Region is the region used in every ARN returned by the fake
+const Region = "us-east-1"
+
+// Table is a fake DynamoDB table
+type Table struct {
+	Name         string
+	PartitionKey string
+	SortKey      string
+}
+
+// CacheCluster is a fake ElastiCache cluster
+type CacheCluster struct {
+	ID       string
+	Engine   string
+	NodeType string
+}
+
+// DBInstance is a fake RDS instance
+type DBInstance struct {
+	ID     string
+	Class  string
+	Engine string
+}
+
+// Server is an in-process AWS-compatible endpoint for tests. It implements the
+// subset of DynamoDB, ECR, IAM, RDS and ElastiCache used by the sandbox and
+// keeps every resource in memory.
+type Server struct {
+	URL string
+
+	server *httptest.Server
+
+	mu            sync.Mutex
+	tables        map[string]Table
+	repositories  map[string]bool
+	roles         map[string]bool
+	cacheClusters map[string]CacheCluster
+	dbInstances   map[string]DBInstance
+}
+
+var credentialScope = regexp.MustCompile(`Credential=[^/]+/[^/]+/[^/]+/([^/]+)/`)
+
+// NewServer starts a fake AWS endpoint. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		tables:        make(map[string]Table),
+		repositories:  make(map[string]bool),
+		roles:         make(map[string]bool),
+		cacheClusters: make(map[string]CacheCluster),
+		dbInstances:   make(map[string]DBInstance),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddTable creates a DynamoDB table directly
+func (s *Server) AddTable(table Table) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables[table.Name] = table
+}
+
+// AddRepository creates an ECR repository directly
+func (s *Server) AddRepository(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repositories[name] = true
+}
+
+// AddRole creates an IAM role directly
+func (s *Server) AddRole(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles[name] = true
+}
+
+// AddCacheCluster creates an ElastiCache cluster directly
+func (s *Server) AddCacheCluster(cluster CacheCluster) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheClusters[cluster.ID] = cluster
+}
+
+// AddDBInstance creates an RDS instance directly
+func (s *Server) AddDBInstance(instance DBInstance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dbInstances[instance.ID] = instance
+}
+
+// Tables returns the DynamoDB tables
+func (s *Server) Tables() map[string]Table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]Table)
+	for k, v := range s.tables {
+		out[k] = v
+	}
+	return out
+}
+
+// Repositories returns the ECR repository names, sorted
+func (s *Server) Repositories() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.repositories)
+}
+
+// Roles returns the IAM role names, sorted
+func (s *Server) Roles() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.roles)
+}
+
+// CacheClusters returns the ElastiCache clusters
+func (s *Server) CacheClusters() map[string]CacheCluster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]CacheCluster)
+	for k, v := range s.cacheClusters {
+		out[k] = v
+	}
+	return out
+}
+
+// DBInstances returns the RDS instances
+func (s *Server) DBInstances() map[string]DBInstance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]DBInstance)
+	for k, v := range s.dbInstances {
+		out[k] = v
+	}
+	return out
+}
+
+// Helper methods
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if target := r.Header.Get("X-Amz-Target"); target != "" {
+		var input map[string]interface{}
+		json.Unmarshal(body, &input)
+
+		operation := target[strings.Index(target, ".")+1:]
+		switch {
+		case strings.HasPrefix(target, "DynamoDB_"):
+			s.handleDynamoDB(w, operation, input)
+		case strings.HasPrefix(target, "AmazonEC2ContainerRegistry_"):
+			s.handleECR(w, operation, input)
+		default:
+			jsonError(w, "UnknownOperationException", "unsupported target "+target)
+		}
+		return
+	}
+
+	form, _ := url.ParseQuery(string(body))
+	service := ""
+	if m := credentialScope.FindStringSubmatch(r.Header.Get("Authorization")); m != nil {
+		service = m[1]
+	}
+
+	switch service {
+	case "iam":
+		s.handleIAM(w, form)
+	case "rds":
+		s.handleRDS(w, form)
+	case "elasticache":
+		s.handleElastiCache(w, form)
+	default:
+		queryError(w, "InvalidAction", "unsupported service "+service)
+	}
+}
+
+func (s *Server) handleDynamoDB(w http.ResponseWriter, operation string, input map[string]interface{}) {
+	name, _ := input["TableName"].(string)
+
+	switch operation {
+	case "CreateTable":
+		if _, ok := s.tables[name]; ok {
+			jsonError(w, "ResourceInUseException", "Table already exists: "+name)
+			return
+		}
+		table := Table{Name: name}
+		if schema, ok := input["KeySchema"].([]interface{}); ok {
+			for _, element := range schema {
+				key, _ := element.(map[string]interface{})
+				if key["KeyType"] == "HASH" {
+					table.PartitionKey, _ = key["AttributeName"].(string)
+				} else {
+					table.SortKey, _ = key["AttributeName"].(string)
+				}
+			}
+		}
+		s.tables[name] = table
+		writeJSON(w, map[string]interface{}{"TableDescription": tableDescription(table)})
+	case "DescribeTable":
+		table, ok := s.tables[name]
+		if !ok {
+			jsonError(w, "ResourceNotFoundException", "Requested resource not found: Table: "+name+" not found")
+			return
+		}
+		writeJSON(w, map[string]interface{}{"Table": tableDescription(table)})
+	case "ListTables":
+		names := []string{}
+		for n := range s.tables {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		writeJSON(w, map[string]interface{}{"TableNames": names})
+	default:
+		jsonError(w, "UnknownOperationException", "unsupported DynamoDB operation "+operation)
+	}
+}
+
+func (s *Server) handleECR(w http.ResponseWriter, operation string, input map[string]interface{}) {
+	switch operation {
+	case "CreateRepository":
+		name, _ := input["repositoryName"].(string)
+		if s.repositories[name] {
+			jsonError(w, "RepositoryAlreadyExistsException", "The repository with name '"+name+"' already exists")
+			return
+		}
+		s.repositories[name] = true
+		writeJSON(w, map[string]interface{}{"repository": repository(name)})
+	case "DescribeRepositories":
+		var names []string
+		if requested, ok := input["repositoryNames"].([]interface{}); ok && len(requested) > 0 {
+			for _, n := range requested {
+				name, _ := n.(string)
+				if !s.repositories[name] {
+					jsonError(w, "RepositoryNotFoundException", "The repository with name '"+name+"' does not exist")
+					return
+				}
+				names = append(names, name)
+			}
+		} else {
+			names = sortedKeys(s.repositories)
+		}
+		repos := []interface{}{}
+		for _, name := range names {
+			repos = append(repos, repository(name))
+		}
+		writeJSON(w, map[string]interface{}{"repositories": repos})
+	default:
+		jsonError(w, "UnknownOperationException", "unsupported ECR operation "+operation)
+	}
+}
+
+func (s *Server) handleIAM(w http.ResponseWriter, form url.Values) {
+	action := form.Get("Action")
+	name := form.Get("RoleName")
+
+	switch action {
+	case "CreateRole":
+		if s.roles[name] {
+			queryError(w, "EntityAlreadyExists", "Role with name "+name+" already exists.")
+			return
+		}
+		s.roles[name] = true
+		writeXML(w, action, "<Role>"+roleXML(name)+"</Role>")
+	case "GetRole":
+		if !s.roles[name] {
+			queryError(w, "NoSuchEntity", "The role with name "+name+" cannot be found.")
+			return
+		}
+		writeXML(w, action, "<Role>"+roleXML(name)+"</Role>")
+	case "ListRoles":
+		var members strings.Builder
+		for _, role := range sortedKeys(s.roles) {
+			members.WriteString("<member>" + roleXML(role) + "</member>")
+		}
+		writeXML(w, action, "<IsTruncated>false</IsTruncated><Roles>"+members.String()+"</Roles>")
+	default:
+		queryError(w, "InvalidAction", "unsupported IAM action "+action)
+	}
+}
+
+func (s *Server) handleRDS(w http.ResponseWriter, form url.Values) {
+	action := form.Get("Action")
+	id := form.Get("DBInstanceIdentifier")
+
+	switch action {
+	case "CreateDBInstance":
+		if _, ok := s.dbInstances[id]; ok {
+			queryError(w, "DBInstanceAlreadyExists", "DB instance already exists")
+			return
+		}
+		instance := DBInstance{ID: id, Class: form.Get("DBInstanceClass"), Engine: form.Get("Engine")}
+		s.dbInstances[id] = instance
+		writeXML(w, action, "<DBInstance>"+dbInstanceXML(instance)+"</DBInstance>")
+	case "DescribeDBInstances":
+		var instances []DBInstance
+		if id != "" {
+			instance, ok := s.dbInstances[id]
+			if !ok {
+				queryError(w, "DBInstanceNotFound", "DBInstance "+id+" not found.")
+				return
+			}
+			instances = append(instances, instance)
+		} else {
+			for _, instance := range s.dbInstances {
+				instances = append(instances, instance)
+			}
+			sort.Slice(instances, func(i, j int) bool { return instances[i].ID < instances[j].ID })
+		}
+		var members strings.Builder
+		for _, instance := range instances {
+			members.WriteString("<DBInstance>" + dbInstanceXML(instance) + "</DBInstance>")
+		}
+		writeXML(w, action, "<DBInstances>"+members.String()+"</DBInstances>")
+	default:
+		queryError(w, "InvalidAction", "unsupported RDS action "+action)
+	}
+}
+
+func (s *Server) handleElastiCache(w http.ResponseWriter, form url.Values) {
+	action := form.Get("Action")
+	id := form.Get("CacheClusterId")
+
+	switch action {
+	case "CreateCacheCluster":
+		if _, ok := s.cacheClusters[id]; ok {
+			queryError(w, "CacheClusterAlreadyExists", "Cache cluster "+id+" already exists.")
+			return
+		}
+		cluster := CacheCluster{ID: id, Engine: form.Get("Engine"), NodeType: form.Get("CacheNodeType")}
+		s.cacheClusters[id] = cluster
+		writeXML(w, action, "<CacheCluster>"+cacheClusterXML(cluster)+"</CacheCluster>")
+	case "DescribeCacheClusters":
+		var clusters []CacheCluster
+		if id != "" {
+			cluster, ok := s.cacheClusters[id]
+			if !ok {
+				queryError(w, "CacheClusterNotFound", "Cache cluster "+id+" not found.")
+				return
+			}
+			clusters = append(clusters, cluster)
+		} else {
+			for _, cluster := range s.cacheClusters {
+				clusters = append(clusters, cluster)
+			}
+			sort.Slice(clusters, func(i, j int) bool { return clusters[i].ID < clusters[j].ID })
+		}
+		var members strings.Builder
+		for _, cluster := range clusters {
+			members.WriteString("<CacheCluster>" + cacheClusterXML(cluster) + "</CacheCluster>")
+		}
+		writeXML(w, action, "<CacheClusters>"+members.String()+"</CacheClusters>")
+	default:
+		queryError(w, "InvalidAction", "unsupported ElastiCache action "+action)
+	}
+}
+
+func tableDescription(table Table) map[string]interface{} {
+	keySchema := []interface{}{map[string]string{"AttributeName": table.PartitionKey, "KeyType": "HASH"}}
+	if table.SortKey != "" {
+		keySchema = append(keySchema, map[string]string{"AttributeName": table.SortKey, "KeyType": "RANGE"})
+	}
+	return map[string]interface{}{
+		"TableName":   table.Name,
+		"TableArn":    fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", Region, AccountID, table.Name),
+		"TableStatus": "ACTIVE",
+		"KeySchema":   keySchema,
+	}
+}
+
+func repository(name string) map[string]string {
+	return map[string]string{
+		"repositoryName": name,
+		"repositoryArn":  fmt.Sprintf("arn:aws:ecr:%s:%s:repository/%s", Region, AccountID, name),
+		"repositoryUri":  fmt.Sprintf("%s.dkr.ecr.%s.localhost.localstack.cloud:4566/%s", AccountID, Region, name),
+	}
+}
+
+func roleXML(name string) string {
+	return fmt.Sprintf("<Path>/</Path><RoleName>%s</RoleName><RoleId>AROAFAKE%s</RoleId><Arn>arn:aws:iam::%s:role/%s</Arn><CreateDate>2024-01-01T00:00:00Z</CreateDate>",
+		escape(name), strings.ToUpper(strings.ReplaceAll(name, "-", "")), AccountID, escape(name))
+}
+
+func dbInstanceXML(instance DBInstance) string {
+	return fmt.Sprintf("<DBInstanceIdentifier>%s</DBInstanceIdentifier><DBInstanceClass>%s</DBInstanceClass><Engine>%s</Engine><DBInstanceStatus>available</DBInstanceStatus><Endpoint><Address>%s.rds.localhost</Address><Port>5432</Port></Endpoint>",
+		escape(instance.ID), escape(instance.Class), escape(instance.Engine), escape(instance.ID))
+}
+
+func cacheClusterXML(cluster CacheCluster) string {
+	return fmt.Sprintf("<CacheClusterId>%s</CacheClusterId><Engine>%s</Engine><CacheNodeType>%s</CacheNodeType><CacheClusterStatus>available</CacheClusterStatus><NumCacheNodes>1</NumCacheNodes><CacheNodes><CacheNode><CacheNodeId>0001</CacheNodeId><Endpoint><Address>%s.cache.localhost</Address><Port>6379</Port></Endpoint></CacheNode></CacheNodes>",
+		escape(cluster.ID), escape(cluster.Engine), escape(cluster.NodeType), escape(cluster.ID))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(v)
+}
+
+func jsonError(w http.ResponseWriter, code, message string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"__type": code, "message": message})
+}
+
+func writeXML(w http.ResponseWriter, action, result string) {
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, "<%[1]sResponse><%[1]sResult>%[2]s</%[1]sResult><ResponseMetadata><RequestId>fake</RequestId></ResponseMetadata></%[1]sResponse>", action, result)
+}
+
+func queryError(w http.ResponseWriter, code, message string) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, "<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>%s</Message></Error><RequestId>fake</RequestId></ErrorResponse>", code, escape(message))
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
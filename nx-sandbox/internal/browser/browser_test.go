@@ -0,0 +1,243 @@
+package browser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+// setupTestEnv seeds a sandbox from the built-in spec
+func setupTestEnv(t *testing.T) string {
+	tmpDir := t.TempDir()
+	if _, err := seed.NewSeeder(tmpDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	return tmpDir
+}
+
+func testArtifacts() []models.SandboxArtifact {
+	return []models.SandboxArtifact{
+		{Name: "nx-bff-web-offer-seat-dev1", Layer: "bff", Source: models.SourceInventory, Environment: "dev1"},
+		{Name: "nx-bff-web-payment-dev1", Layer: "bff", Source: models.SourceInventory, Environment: "dev1"},
+		{Name: "nx-ch-web-checkout-dev1", Layer: "ch", Source: models.SourceInventory, Environment: "dev1"},
+		{Name: "nx-bff-test-service", Layer: "bff", Source: models.SourceEnvironment, Environment: "dev1"},
+		{Name: "nx-bff-test-service", Layer: "bff", Source: models.SourceEnvironment, Environment: "sit1"},
+	}
+}
+
+func names(artifacts []models.SandboxArtifact) []string {
+	var result []string
+	for _, artifact := range artifacts {
+		result = append(result, artifact.Name+"@"+artifact.Environment)
+	}
+	return result
+}
+
+func typeKeys(m *Model, keys string) Action {
+	action := ActionNone
+	for _, r := range keys {
+		action = m.HandleKey(Key{Code: KeyRune, Rune: r})
+	}
+	return action
+}
+
+func TestMatch(t *testing.T) {
+	if _, ok := Match("wos", "nx-bff-web-offer-seat-dev1"); !ok {
+		t.Error("Expected subsequence to match")
+	}
+	if _, ok := Match("seatx", "nx-bff-web-offer-seat-dev1"); ok {
+		t.Error("Expected missing character not to match")
+	}
+
+	exact, _ := Match("pay", "nx-bff-web-payment-dev1")
+	scattered, _ := Match("pay", "nx-bff-web-offer-seat-dev1-pa-y")
+	if exact <= scattered {
+		t.Errorf("Consecutive matches should score higher: %d <= %d", exact, scattered)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a\x1b[A\x1b[B\x1b[6~\r\x7fé"))
+	want := []Key{
+		{Code: KeyRune, Rune: 'a'},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyPageDown},
+		{Code: KeyEnter},
+		{Code: KeyBackspace},
+		{Code: KeyRune, Rune: 'é'},
+	}
+	for i, expected := range want {
+		got, err := ReadKey(r)
+		if err != nil {
+			t.Fatalf("ReadKey %d failed: %v", i, err)
+		}
+		if got != expected {
+			t.Errorf("Key %d: expected %+v, got %+v", i, expected, got)
+		}
+	}
+}
+
+func TestModel_SearchAndFilters(t *testing.T) {
+	m := NewModel(testArtifacts(), nil)
+
+	m.HandleKey(Key{Code: KeyRune, Rune: '/'})
+	typeKeys(m, "pay")
+	m.HandleKey(Key{Code: KeyEnter})
+	if got := names(m.Visible()); len(got) != 1 || got[0] != "nx-bff-web-payment-dev1@dev1" {
+		t.Errorf("Unexpected search results: %v", got)
+	}
+
+	m.HandleKey(Key{Code: KeyRune, Rune: '/'})
+	m.HandleKey(Key{Code: KeyEscape})
+	if len(m.Visible()) != 5 {
+		t.Errorf("Escape should clear the search, got %d artifacts", len(m.Visible()))
+	}
+
+	typeKeys(m, "S")
+	if m.Source != models.SourceInventory || len(m.Visible()) != 3 {
+		t.Errorf("Expected inventory source filter, got %s with %d artifacts", m.Source, len(m.Visible()))
+	}
+	typeKeys(m, "L")
+	if m.Layer != "bff" || len(m.Visible()) != 2 {
+		t.Errorf("Expected bff layer filter, got %s with %d artifacts", m.Layer, len(m.Visible()))
+	}
+	typeKeys(m, "SSLL")
+	if m.Source != "" || m.Layer != "" {
+		t.Errorf("Filters should cycle back to all, got source %q layer %q", m.Source, m.Layer)
+	}
+
+	typeKeys(m, "EE")
+	if got := names(m.Visible()); len(got) != 1 || got[0] != "nx-bff-test-service@sit1" {
+		t.Errorf("Unexpected environment filter results: %v", got)
+	}
+}
+
+func TestModel_Pins(t *testing.T) {
+	m := NewModel(testArtifacts(), nil)
+
+	typeKeys(m, "G")
+	if action := typeKeys(m, "p"); action != ActionPin {
+		t.Errorf("Expected pin action, got %v", action)
+	}
+	if first := m.Visible()[0]; first.Environment != "sit1" {
+		t.Errorf("Pinned artifacts should be listed first, got %s@%s", first.Name, first.Environment)
+	}
+
+	typeKeys(m, "P")
+	if len(m.Visible()) != 1 {
+		t.Errorf("Expected only the pinned artifact, got %d", len(m.Visible()))
+	}
+
+	screen := m.Render(100, 10, "detail")
+	if !strings.Contains(screen, ">* nx-bff-test-service") || !strings.Contains(screen, "pinned: on") {
+		t.Errorf("Render missing the pinned selection:\n%s", screen)
+	}
+}
+
+func TestModel_PrepareKeys(t *testing.T) {
+	m := NewModel(testArtifacts(), nil)
+
+	m.ShowPane("diff", "+replicaCount: 5")
+	if action := m.HandleKey(Key{Code: KeyEnter}); action != ActionNone {
+		t.Errorf("Expected enter not to prepare, got %v", action)
+	}
+	if screen := m.Render(100, 10, "detail"); strings.Contains(screen, "replicaCount") {
+		t.Errorf("Expected enter to show the detail again:\n%s", screen)
+	}
+	if action := typeKeys(m, "t"); action != ActionPrepare {
+		t.Errorf("Expected prepare action, got %v", action)
+	}
+	if action := typeKeys(m, "T"); action != ActionOverwrite {
+		t.Errorf("Expected overwrite action, got %v", action)
+	}
+}
+
+func TestWorkspace_PrepareAndDiff(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	w := NewWorkspace(baseDir)
+
+	artifacts, err := w.Artifacts()
+	if err != nil {
+		t.Fatalf("Artifacts failed: %v", err)
+	}
+
+	var chart models.SandboxArtifact
+	for _, artifact := range artifacts {
+		if artifact.Source == models.SourceInventory && artifact.Environment == "" {
+			t.Errorf("Inventory artifact %s has no environment", artifact.Name)
+		}
+		if artifact.Name == "nx-bff-test-service" && artifact.Environment == "dev1" {
+			chart = artifact
+		}
+	}
+
+	detail, err := w.Detail(chart)
+	if err != nil || !strings.Contains(detail, "── values.yaml ──") {
+		t.Errorf("Detail missing values (err %v):\n%s", err, detail)
+	}
+
+	if _, err := w.Diff(chart); err == nil {
+		t.Error("Expected diff before prepare to fail")
+	}
+
+	copied, err := w.Prepare(chart, false)
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if len(copied) != 2 || filepath.Base(filepath.Dir(copied[0])) != "nx-bff-test-service-dev1" {
+		t.Errorf("Unexpected prepared files: %v", copied)
+	}
+
+	target, _ := w.EditTarget(chart)
+	if target != filepath.Join(w.TestDir(chart), "values.yaml") {
+		t.Errorf("Expected the prepared values to be edited, got %s", target)
+	}
+	os.WriteFile(target, []byte("replicaCount: 5\n"), 0644)
+
+	text, err := w.Diff(chart)
+	if err != nil || !strings.Contains(text, "+replicaCount: 5") {
+		t.Errorf("Unexpected diff (err %v):\n%s", err, text)
+	}
+
+	// Preparing again keeps the edits unless asked to overwrite them
+	if _, err := w.Prepare(chart, false); err == nil {
+		t.Error("Expected prepare to refuse to discard edits")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "replicaCount: 5\n" {
+		t.Errorf("Expected the edits to survive, got %q", data)
+	}
+	if _, err := w.Prepare(chart, true); err != nil {
+		t.Fatalf("Prepare with overwrite failed: %v", err)
+	}
+	if text, _ := w.Diff(chart); text != "" {
+		t.Errorf("Expected the overwritten copy to match, got:\n%s", text)
+	}
+	if _, err := w.Prepare(chart, false); err != nil {
+		t.Errorf("Expected an unchanged copy to be prepared again, got %v", err)
+	}
+}
+
+func TestWorkspace_Pins(t *testing.T) {
+	w := NewWorkspace(t.TempDir())
+
+	pins, err := w.Pins()
+	if err != nil || len(pins) != 0 {
+		t.Fatalf("Expected no pins, got %v (err %v)", pins, err)
+	}
+
+	pins["inventory:dev1:nx-bff-web-payment-dev1"] = true
+	if err := w.SavePins(pins); err != nil {
+		t.Fatalf("SavePins failed: %v", err)
+	}
+
+	loaded, _ := w.Pins()
+	if !loaded["inventory:dev1:nx-bff-web-payment-dev1"] {
+		t.Errorf("Pins not persisted: %v", loaded)
+	}
+}
@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Match reports whether every character of pattern appears in text in order,
+// ignoring case, and scores the match. Higher scores rank first: consecutive
+// characters and characters starting a word ('-', '/', '_' or '.' before them)
+// score more, and an empty pattern matches everything with score zero.
+func Match(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+
+	score := 0
+	pi := 0
+	prev := -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if unicode.IsSpace(p[pi]) {
+			pi++
+			ti--
+			continue
+		}
+		if t[ti] != p[pi] {
+			continue
+		}
+
+		score++
+		if ti == prev+1 {
+			score += 5
+		}
+		if ti == 0 || strings.ContainsRune("-/_.", t[ti-1]) {
+			score += 3
+		}
+		prev = ti
+		pi++
+	}
+
+	for pi < len(p) && unicode.IsSpace(p[pi]) {
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+
+	// Prefer shorter texts when patterns match equally well
+	return score*100 - len(t), true
+}
@@ -0,0 +1,116 @@
+package browser
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a non-printable key
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyBackspace
+	KeyEscape
+	KeyTab
+	KeyCtrlC
+	KeyCtrlU
+	KeyUnknown
+)
+
+// Key is a key press read from the terminal
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ReadKey reads one key press from a terminal in raw mode, decoding the
+// common VT100/xterm escape sequences
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 127, 8:
+		return Key{Code: KeyBackspace}, nil
+	case '\t':
+		return Key{Code: KeyTab}, nil
+	case 3:
+		return Key{Code: KeyCtrlC}, nil
+	case 21:
+		return Key{Code: KeyCtrlU}, nil
+	case 27:
+		return readEscape(r)
+	}
+
+	if b < 32 {
+		return Key{Code: KeyUnknown}, nil
+	}
+	if b < utf8.RuneSelf {
+		return Key{Code: KeyRune, Rune: rune(b)}, nil
+	}
+
+	if err := r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{Code: KeyRune, Rune: ch}, nil
+}
+
+// readEscape decodes the rest of an escape sequence. A lone ESC is only
+// recognised when no further input is buffered.
+func readEscape(r *bufio.Reader) (Key, error) {
+	if r.Buffered() == 0 {
+		return Key{Code: KeyEscape}, nil
+	}
+
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		return Key{Code: KeyEscape}, nil
+	}
+
+	var seq []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		seq = append(seq, c)
+		if c >= 0x40 && c <= 0x7e {
+			break
+		}
+	}
+
+	switch string(seq) {
+	case "A":
+		return Key{Code: KeyUp}, nil
+	case "B":
+		return Key{Code: KeyDown}, nil
+	case "H", "1~", "7~":
+		return Key{Code: KeyHome}, nil
+	case "F", "4~", "8~":
+		return Key{Code: KeyEnd}, nil
+	case "5~":
+		return Key{Code: KeyPageUp}, nil
+	case "6~":
+		return Key{Code: KeyPageDown}, nil
+	}
+	return Key{Code: KeyUnknown}, nil
+}
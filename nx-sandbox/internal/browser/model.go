@@ -0,0 +1,494 @@
+package browser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// Action is work the browser loop performs outside the model
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionQuit
+	ActionPin
+	ActionPrepare
+	ActionOverwrite
+	ActionDiff
+	ActionEdit
+	ActionReload
+)
+
+// Help lists the key bindings shown at the bottom of the screen
+const Help = "↑/↓ move  / search  L layer  E env  S source  P pinned  p pin  t prepare  T overwrite  d diff  e edit  r reload  q quit"
+
+// ANSI styles, dropped when colour is disabled
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleGreen   = "\x1b[32m"
+	styleCyan    = "\x1b[36m"
+)
+
+// Model is the browser state: the artifacts, the active filters and search,
+// and the selection. It performs no I/O so it can be driven by tests.
+type Model struct {
+	Query       string
+	Layer       string
+	Environment string
+	Source      models.ArtifactSource
+	PinnedOnly  bool
+	Searching   bool
+	Status      string
+	Color       bool
+
+	artifacts []models.SandboxArtifact
+	pins      map[string]bool
+	visible   []models.SandboxArtifact
+	cursor    int
+	offset    int
+
+	pane      string
+	paneTitle string
+	paneTop   int
+}
+
+// NewModel creates a model over artifacts with the given pinned keys
+func NewModel(artifacts []models.SandboxArtifact, pins map[string]bool) *Model {
+	if pins == nil {
+		pins = make(map[string]bool)
+	}
+	m := &Model{pins: pins}
+	m.SetArtifacts(artifacts)
+	return m
+}
+
+// SetArtifacts replaces the artifacts, keeping the selection when possible
+func (m *Model) SetArtifacts(artifacts []models.SandboxArtifact) {
+	selected, ok := m.Selected()
+	m.artifacts = artifacts
+	m.refresh()
+
+	if ok {
+		for i, artifact := range m.visible {
+			if ArtifactKey(artifact) == ArtifactKey(selected) {
+				m.cursor = i
+			}
+		}
+	}
+}
+
+// Pins returns the pinned artifact keys
+func (m *Model) Pins() map[string]bool {
+	return m.pins
+}
+
+// Visible returns the artifacts passing the filters, pinned ones first
+func (m *Model) Visible() []models.SandboxArtifact {
+	return m.visible
+}
+
+// Selected returns the artifact under the cursor
+func (m *Model) Selected() (models.SandboxArtifact, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return models.SandboxArtifact{}, false
+	}
+	return m.visible[m.cursor], true
+}
+
+// ShowPane replaces the detail pane with text, such as a diff, until the
+// selection changes
+func (m *Model) ShowPane(title, text string) {
+	m.paneTitle = title
+	m.pane = text
+	m.paneTop = 0
+}
+
+// HandleKey updates the model for a key press and returns the action the
+// loop should perform
+func (m *Model) HandleKey(k Key) Action {
+	if k.Code == KeyCtrlC {
+		return ActionQuit
+	}
+
+	if m.Searching {
+		switch k.Code {
+		case KeyEnter:
+			m.Searching = false
+		case KeyEscape:
+			m.Searching = false
+			m.setQuery("")
+		case KeyBackspace:
+			if m.Query != "" {
+				_, size := utf8.DecodeLastRuneInString(m.Query)
+				m.setQuery(m.Query[:len(m.Query)-size])
+			}
+		case KeyCtrlU:
+			m.setQuery("")
+		case KeyUp, KeyDown, KeyPageUp, KeyPageDown:
+			m.move(k)
+		case KeyRune:
+			m.setQuery(m.Query + string(k.Rune))
+		}
+		return ActionNone
+	}
+
+	switch k.Code {
+	case KeyUp, KeyDown, KeyPageUp, KeyPageDown, KeyHome, KeyEnd:
+		m.move(k)
+		return ActionNone
+	case KeyEscape, KeyEnter:
+		m.clearPane()
+		return ActionNone
+	case KeyRune:
+	default:
+		return ActionNone
+	}
+
+	m.Status = ""
+	switch k.Rune {
+	case 'q':
+		return ActionQuit
+	case 'j':
+		m.move(Key{Code: KeyDown})
+	case 'k':
+		m.move(Key{Code: KeyUp})
+	case 'g':
+		m.move(Key{Code: KeyHome})
+	case 'G':
+		m.move(Key{Code: KeyEnd})
+	case 'J':
+		m.paneTop++
+	case 'K':
+		if m.paneTop > 0 {
+			m.paneTop--
+		}
+	case '/':
+		m.Searching = true
+	case 'L':
+		m.Layer = next(m.layers(), m.Layer)
+		m.refresh()
+	case 'E':
+		m.Environment = next(m.environments(), m.Environment)
+		m.refresh()
+	case 'S':
+		sources := []string{string(models.SourceInventory), string(models.SourceEnvironment)}
+		m.Source = models.ArtifactSource(next(sources, string(m.Source)))
+		m.refresh()
+	case 'P':
+		m.PinnedOnly = !m.PinnedOnly
+		m.refresh()
+	case 'p':
+		if artifact, ok := m.Selected(); ok {
+			key := ArtifactKey(artifact)
+			if m.pins[key] {
+				delete(m.pins, key)
+			} else {
+				m.pins[key] = true
+			}
+			m.SetArtifacts(m.artifacts)
+			return ActionPin
+		}
+	case 't':
+		return ActionPrepare
+	case 'T':
+		return ActionOverwrite
+	case 'd':
+		if m.paneTitle == "diff" {
+			m.clearPane()
+			return ActionNone
+		}
+		return ActionDiff
+	case 'e':
+		return ActionEdit
+	case 'r':
+		return ActionReload
+	}
+	return ActionNone
+}
+
+// Render draws the screen: a header with the search and filters, the
+// artifact list on the left, the detail pane on the right and a status line
+func (m *Model) Render(width, height int, detail string) string {
+	if width < 40 {
+		width = 40
+	}
+	if height < 6 {
+		height = 6
+	}
+
+	listWidth := width * 2 / 5
+	if listWidth < 30 {
+		listWidth = 30
+	}
+	paneWidth := width - listWidth - 3
+	bodyHeight := height - 3
+
+	var lines []string
+
+	search := m.Query
+	if m.Searching {
+		search += "_"
+	}
+	header := fmt.Sprintf(" nx-sandbox browse │ /%s │ layer: %s  env: %s  source: %s  pinned: %s │ %d/%d",
+		search, orAll(m.Layer), orAll(m.Environment), orAll(string(m.Source)), onOff(m.PinnedOnly),
+		len(m.visible), len(m.artifacts))
+	lines = append(lines, m.style(styleBold, fit(header, width)))
+	lines = append(lines, strings.Repeat("─", listWidth)+"─┬─"+strings.Repeat("─", paneWidth))
+
+	// Keep the cursor on screen
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+bodyHeight {
+		m.offset = m.cursor - bodyHeight + 1
+	}
+
+	if m.pane != "" {
+		detail = fmt.Sprintf("── %s ──\n%s", m.paneTitle, m.pane)
+	}
+	detailLines := strings.Split(strings.ReplaceAll(detail, "\t", "    "), "\n")
+	if m.paneTop > len(detailLines)-1 {
+		m.paneTop = max(len(detailLines)-1, 0)
+	}
+	detailLines = detailLines[m.paneTop:]
+
+	for row := 0; row < bodyHeight; row++ {
+		left := ""
+		if i := m.offset + row; i < len(m.visible) {
+			left = m.row(m.visible[i], i == m.cursor, listWidth)
+		} else {
+			left = strings.Repeat(" ", listWidth)
+		}
+
+		right := ""
+		if row < len(detailLines) {
+			right = fit(detailLines[row], paneWidth)
+			if m.paneTitle == "diff" {
+				right = m.diffLine(right)
+			}
+		}
+		lines = append(lines, left+" │ "+right)
+	}
+
+	footer := Help
+	if m.Searching {
+		footer = "type to search  ↑/↓ move  enter done  esc clear"
+	}
+	if m.Status != "" {
+		footer = m.Status
+	}
+	lines = append(lines, m.style(styleDim, fit(" "+footer, width)))
+
+	return strings.Join(lines, "\r\n")
+}
+
+// Helper methods
+
+func (m *Model) row(artifact models.SandboxArtifact, selected bool, width int) string {
+	marker := " "
+	if m.pins[ArtifactKey(artifact)] {
+		marker = "*"
+	}
+	cursor := " "
+	if selected {
+		cursor = ">"
+	}
+
+	suffix := fmt.Sprintf(" %-4s %-6s", artifact.Layer, artifact.Environment)
+	nameWidth := width - 4 - utf8.RuneCountInString(suffix)
+	text := fmt.Sprintf("%s%s %s%s", cursor, marker, pad(fit(artifact.Name, nameWidth), nameWidth), suffix)
+	text = pad(fit(text, width), width)
+
+	if selected {
+		return m.style(styleReverse, text)
+	}
+	return text
+}
+
+// diffLine colours added, removed and hunk header lines of a diff
+func (m *Model) diffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return m.style(styleBold, line)
+	case strings.HasPrefix(line, "@@"):
+		return m.style(styleCyan, line)
+	case strings.HasPrefix(line, "+"):
+		return m.style(styleGreen, line)
+	case strings.HasPrefix(line, "-"):
+		return m.style(styleRed, line)
+	}
+	return line
+}
+
+func (m *Model) style(style, text string) string {
+	if !m.Color {
+		return text
+	}
+	return style + text + styleReset
+}
+
+func (m *Model) setQuery(query string) {
+	m.Query = query
+	m.cursor = 0
+	m.refresh()
+}
+
+func (m *Model) move(k Key) {
+	page := 10
+	switch k.Code {
+	case KeyUp:
+		m.cursor--
+	case KeyDown:
+		m.cursor++
+	case KeyPageUp:
+		m.cursor -= page
+	case KeyPageDown:
+		m.cursor += page
+	case KeyHome:
+		m.cursor = 0
+	case KeyEnd:
+		m.cursor = len(m.visible) - 1
+	}
+	m.clamp()
+	m.clearPane()
+}
+
+func (m *Model) clamp() {
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m *Model) clearPane() {
+	m.pane = ""
+	m.paneTitle = ""
+	m.paneTop = 0
+}
+
+// refresh recomputes the visible artifacts from the filters and search
+func (m *Model) refresh() {
+	type scored struct {
+		artifact models.SandboxArtifact
+		score    int
+		pinned   bool
+	}
+
+	var matches []scored
+	for _, artifact := range m.artifacts {
+		if m.Layer != "" && artifact.Layer != m.Layer {
+			continue
+		}
+		if m.Environment != "" && artifact.Environment != m.Environment {
+			continue
+		}
+		if m.Source != "" && artifact.Source != m.Source {
+			continue
+		}
+		pinned := m.pins[ArtifactKey(artifact)]
+		if m.PinnedOnly && !pinned {
+			continue
+		}
+		score, ok := Match(m.Query, artifact.Name)
+		if !ok {
+			continue
+		}
+		matches = append(matches, scored{artifact: artifact, score: score, pinned: pinned})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].pinned != matches[j].pinned {
+			return matches[i].pinned
+		}
+		return matches[i].score > matches[j].score
+	})
+
+	m.visible = m.visible[:0]
+	for _, match := range matches {
+		m.visible = append(m.visible, match.artifact)
+	}
+	m.clamp()
+	m.clearPane()
+}
+
+func (m *Model) layers() []string {
+	return distinct(m.artifacts, func(a models.SandboxArtifact) string { return a.Layer })
+}
+
+func (m *Model) environments() []string {
+	return distinct(m.artifacts, func(a models.SandboxArtifact) string { return a.Environment })
+}
+
+// distinct returns the sorted non-empty values of field across artifacts
+func distinct(artifacts []models.SandboxArtifact, field func(models.SandboxArtifact) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, artifact := range artifacts {
+		if value := field(artifact); value != "" && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// next cycles through "" (all) followed by options
+func next(options []string, current string) string {
+	if current == "" {
+		if len(options) == 0 {
+			return ""
+		}
+		return options[0]
+	}
+	for i, option := range options {
+		if option == current && i+1 < len(options) {
+			return options[i+1]
+		}
+	}
+	return ""
+}
+
+// fit truncates text to width runes
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
+
+// pad right-pads text with spaces to width runes
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+func orAll(value string) string {
+	if value == "" {
+		return "all"
+	}
+	return value
+}
+
+func onOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
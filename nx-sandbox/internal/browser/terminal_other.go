@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package browser
+
+import (
+	"fmt"
+	"os"
+)
+
+// Terminal is unavailable on this platform
+type Terminal struct{}
+
+// OpenTerminal reports that the browser is not supported on this platform
+func OpenTerminal(in, out *os.File) (*Terminal, error) {
+	return nil, fmt.Errorf("browse is only supported on Unix terminals")
+}
+
+func (t *Terminal) Size() (int, int)      { return 80, 24 }
+func (t *Terminal) Draw(screen string)    {}
+func (t *Terminal) ReadKey() (Key, error) { return Key{}, fmt.Errorf("no terminal") }
+func (t *Terminal) Suspend() error        { return nil }
+func (t *Terminal) Resume() error         { return nil }
+func (t *Terminal) Close() error          { return nil }
@@ -0,0 +1,117 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package browser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Terminal control sequences
+const (
+	enterAltScreen = "\x1b[?1049h"
+	leaveAltScreen = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	cursorHome     = "\x1b[H"
+	clearToEnd     = "\x1b[J"
+	clearLine      = "\x1b[K"
+)
+
+// Terminal is a raw-mode terminal on the alternate screen
+type Terminal struct {
+	in     *os.File
+	out    *os.File
+	reader *bufio.Reader
+	saved  *unix.Termios
+}
+
+// OpenTerminal switches in to raw mode and out to the alternate screen.
+// Close restores both.
+func OpenTerminal(in, out *os.File) (*Terminal, error) {
+	if _, err := unix.IoctlGetTermios(int(in.Fd()), ioctlGetTermios); err != nil {
+		return nil, fmt.Errorf("browse needs an interactive terminal")
+	}
+
+	t := &Terminal{in: in, out: out, reader: bufio.NewReader(in)}
+	if err := t.Resume(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Size returns the terminal width and height, defaulting to 80x24
+func (t *Terminal) Size() (int, int) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return 80, 24
+	}
+	return int(ws.Col), int(ws.Row)
+}
+
+// Draw replaces the screen with lines separated by "\r\n"
+func (t *Terminal) Draw(screen string) {
+	var b []byte
+	b = append(b, cursorHome...)
+	for i, line := range strings.Split(screen, "\r\n") {
+		if i > 0 {
+			b = append(b, '\r', '\n')
+		}
+		b = append(b, line...)
+		b = append(b, clearLine...)
+	}
+	b = append(b, clearToEnd...)
+	t.out.Write(b)
+}
+
+// ReadKey blocks until a key is pressed
+func (t *Terminal) ReadKey() (Key, error) {
+	return ReadKey(t.reader)
+}
+
+// Suspend restores the terminal so another program can use it
+func (t *Terminal) Suspend() error {
+	if t.saved == nil {
+		return nil
+	}
+	fmt.Fprint(t.out, showCursor+leaveAltScreen)
+	err := unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, t.saved)
+	t.saved = nil
+	return err
+}
+
+// Resume puts the terminal back in raw mode on the alternate screen
+func (t *Terminal) Resume() error {
+	fd := int(t.in.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return fmt.Errorf("failed to read terminal settings: %w", err)
+	}
+
+	raw := *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	t.saved = saved
+	t.reader.Reset(t.in)
+
+	fmt.Fprint(t.out, enterAltScreen+hideCursor)
+	return nil
+}
+
+// Close restores the terminal
+func (t *Terminal) Close() error {
+	return t.Suspend()
+}
@@ -0,0 +1,275 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/diff"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/sandbox"
+)
+
+// PinsFileName is the pinned artifacts store inside .nx-sandbox
+const PinsFileName = "pins.json"
+
+// Workspace defines the file operations behind the browser key bindings
+type Workspace interface {
+	Artifacts() ([]models.SandboxArtifact, error)
+	Files(artifact models.SandboxArtifact) []string
+	Detail(artifact models.SandboxArtifact) (string, error)
+	EditTarget(artifact models.SandboxArtifact) (string, error)
+	Prepare(artifact models.SandboxArtifact, overwrite bool) ([]string, error)
+	Diff(artifact models.SandboxArtifact) (string, error)
+	TestDir(artifact models.SandboxArtifact) string
+	Pins() (map[string]bool, error)
+	SavePins(pins map[string]bool) error
+}
+
+// DefaultWorkspace resolves an artifact's inventory entry and chart, and
+// prepares copies of them under test-artifacts/ for local testing
+type DefaultWorkspace struct {
+	baseDir string
+}
+
+// NewWorkspace creates a new workspace for a sandbox root
+func NewWorkspace(baseDir string) Workspace {
+	return &DefaultWorkspace{
+		baseDir: baseDir,
+	}
+}
+
+// PinsPath returns the location of the pinned artifacts store
+func PinsPath(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), PinsFileName)
+}
+
+// ArtifactKey identifies an artifact across sources and environments
+func ArtifactKey(artifact models.SandboxArtifact) string {
+	return fmt.Sprintf("%s:%s:%s", artifact.Source, artifact.Environment, artifact.Name)
+}
+
+// Artifacts lists every artifact, taking the environment of inventory
+// entries from their infrastructure.environment field
+func (w *DefaultWorkspace) Artifacts() ([]models.SandboxArtifact, error) {
+	artifacts, err := sandbox.NewSandboxManager(w.baseDir).ListArtifacts(models.ArtifactFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, artifact := range artifacts {
+		if artifact.Source != models.SourceInventory || !artifact.HasInventory {
+			continue
+		}
+		if entry, err := inventory.Load(filepath.Join(artifact.Path, layout.InventoryFileName)); err == nil {
+			artifacts[i].Environment = entry.Environment()
+		}
+	}
+	return artifacts, nil
+}
+
+// EditTarget returns the file to open in an editor: the prepared copy of the
+// artifact's values or inventory when it exists, otherwise the original
+func (w *DefaultWorkspace) EditTarget(artifact models.SandboxArtifact) (string, error) {
+	files := w.Files(artifact)
+	if len(files) == 0 {
+		return "", fmt.Errorf("no inventory or chart files found for %s", artifact.Name)
+	}
+
+	primary := files[0]
+	if artifact.Source == models.SourceEnvironment {
+		primary = files[len(files)-1]
+	}
+
+	prepared := filepath.Join(w.TestDir(artifact), filepath.Base(primary))
+	if _, err := os.Stat(prepared); err == nil {
+		return prepared, nil
+	}
+	return primary, nil
+}
+
+// Files returns the inventory entry and chart files of an artifact that exist
+func (w *DefaultWorkspace) Files(artifact models.SandboxArtifact) []string {
+	var files []string
+
+	inventoryPath := filepath.Join(artifact.Path, layout.InventoryFileName)
+	if artifact.Source == models.SourceEnvironment {
+		inventoryPath, _ = inventory.Find(w.baseDir, artifact.Name, artifact.Environment)
+	}
+	if inventoryPath != "" {
+		if _, err := os.Stat(inventoryPath); err == nil {
+			files = append(files, inventoryPath)
+		}
+	}
+
+	chartDir := artifact.Path
+	if artifact.Source == models.SourceInventory {
+		chartDir = ""
+		if artifact.Environment != "" {
+			chartDir, _ = helm.FindChart(w.baseDir, artifact.Environment, artifact.Name)
+		}
+	}
+	if chartDir != "" {
+		for _, name := range []string{helm.ChartFileName, helm.ValuesFileName} {
+			path := filepath.Join(chartDir, name)
+			if _, err := os.Stat(path); err == nil {
+				files = append(files, path)
+			}
+		}
+	}
+
+	return files
+}
+
+// Detail describes an artifact followed by its inventory entry and values
+func (w *DefaultWorkspace) Detail(artifact models.SandboxArtifact) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Name:        %s\n", artifact.Name)
+	fmt.Fprintf(&b, "Layer:       %s\n", artifact.Layer)
+	fmt.Fprintf(&b, "Source:      %s\n", artifact.Source)
+	fmt.Fprintf(&b, "Environment: %s\n", orNone(artifact.Environment))
+	fmt.Fprintf(&b, "Path:        %s\n", artifact.Path)
+
+	testDir := w.TestDir(artifact)
+	if _, err := os.Stat(testDir); err == nil {
+		fmt.Fprintf(&b, "Prepared:    %s\n", testDir)
+	} else {
+		fmt.Fprintf(&b, "Prepared:    no\n")
+	}
+
+	for _, path := range w.Files(artifact) {
+		if filepath.Base(path) == helm.ChartFileName {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return b.String(), err
+		}
+		fmt.Fprintf(&b, "\n── %s ──\n%s", filepath.Base(path), data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String(), nil
+}
+
+// TestDir returns the directory an artifact is prepared in
+func (w *DefaultWorkspace) TestDir(artifact models.SandboxArtifact) string {
+	return filepath.Join(w.baseDir, "test-artifacts", layout.EnvironmentArtifactName(artifact.Name, artifact.Environment))
+}
+
+// Prepare copies an artifact's files into its test directory. A prepared
+// copy that differs from the artifact's files is only replaced when
+// overwrite is set, so edits made in it are not silently discarded.
+func (w *DefaultWorkspace) Prepare(artifact models.SandboxArtifact, overwrite bool) ([]string, error) {
+	files := w.Files(artifact)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no inventory or chart files found for %s", artifact.Name)
+	}
+
+	testDir := w.TestDir(artifact)
+	if !overwrite {
+		if text, err := w.Diff(artifact); err == nil && text != "" {
+			return nil, fmt.Errorf("the prepared copy in %s differs from %s; overwrite it to discard the changes", testDir, artifact.Name)
+		}
+	}
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", testDir, err)
+	}
+
+	var copied []string
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return copied, err
+		}
+		target := filepath.Join(testDir, filepath.Base(path))
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return copied, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		copied = append(copied, target)
+	}
+
+	return copied, nil
+}
+
+// Diff shows how the prepared copies differ from the artifact's files
+func (w *DefaultWorkspace) Diff(artifact models.SandboxArtifact) (string, error) {
+	testDir := w.TestDir(artifact)
+	if _, err := os.Stat(testDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s has not been prepared for testing", artifact.Name)
+	}
+
+	var b strings.Builder
+	for _, path := range w.Files(artifact) {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		copyPath := filepath.Join(testDir, filepath.Base(path))
+		prepared, err := os.ReadFile(copyPath)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		b.WriteString(diff.Unified(path, copyPath, original, prepared))
+	}
+
+	return b.String(), nil
+}
+
+// Pins returns the keys of pinned artifacts
+func (w *DefaultWorkspace) Pins() (map[string]bool, error) {
+	pins := make(map[string]bool)
+
+	data, err := os.ReadFile(PinsPath(w.baseDir))
+	if os.IsNotExist(err) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pins: %w", err)
+	}
+
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse pins: %w", err)
+	}
+	for _, key := range keys {
+		pins[key] = true
+	}
+	return pins, nil
+}
+
+// SavePins stores the keys of pinned artifacts
+func (w *DefaultWorkspace) SavePins(pins map[string]bool) error {
+	keys := []string{}
+	for key, pinned := range pins {
+		if pinned {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	path := PinsPath(w.baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,358 @@
+package capacity
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/promote"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/validate"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+)
+
+// bytesPerGiB converts memory to the unit it is priced in
+const bytesPerGiB = 1 << 30
+
+// Estimator defines the interface for estimating the capacity of the charts
+type Estimator interface {
+	Estimate(env string) (*models.CapacityReport, error)
+	Delta(env, description string, files map[string][]byte) (*models.CapacityDelta, error)
+}
+
+// DefaultEstimator sizes every chart from its values.yaml: replicaCount or
+// the autoscaling bounds, times the CPU and memory requests and limits,
+// priced with the unit-price table of the sandbox configuration
+type DefaultEstimator struct {
+	baseDir string
+}
+
+// NewEstimator creates a new capacity estimator for a sandbox root
+func NewEstimator(baseDir string) Estimator {
+	return &DefaultEstimator{
+		baseDir: baseDir,
+	}
+}
+
+// Estimate aggregates the capacity of every service by layer and
+// environment. An empty env covers every environment.
+func (e *DefaultEstimator) Estimate(env string) (*models.CapacityReport, error) {
+	return e.estimate(env, nil)
+}
+
+// Delta compares the capacity with the given files replacing those on disk.
+// Files map paths to their new content; nil content removes the file.
+func (e *DefaultEstimator) Delta(env, description string, files map[string][]byte) (*models.CapacityDelta, error) {
+	before, err := e.estimate(env, nil)
+	if err != nil {
+		return nil, err
+	}
+	after, err := e.estimate(env, files)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &models.CapacityDelta{
+		Description: description,
+		Currency:    after.Currency,
+		Changes:     []models.CapacityChange{},
+		Layers:      []models.LayerCapacityChange{},
+		Before:      before.Total,
+		After:       after.Total,
+	}
+
+	services := make(map[string]*models.CapacityChange)
+	var keys []string
+	change := func(s models.ServiceCapacity) *models.CapacityChange {
+		key := s.Environment + "/" + s.Layer + "/" + s.Service
+		if c, ok := services[key]; ok {
+			return c
+		}
+		services[key] = &models.CapacityChange{Environment: s.Environment, Layer: s.Layer, Service: s.Service}
+		keys = append(keys, key)
+		return services[key]
+	}
+	for _, s := range before.Services {
+		figures := s.CapacityFigures
+		change(s).Before = &figures
+	}
+	for _, s := range after.Services {
+		figures := s.CapacityFigures
+		change(s).After = &figures
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c := services[key]
+		if c.Before == nil || c.After == nil || *c.Before != *c.After {
+			delta.Changes = append(delta.Changes, *c)
+		}
+	}
+
+	layers := make(map[string]*models.LayerCapacityChange)
+	var layerKeys []string
+	layerChange := func(l models.LayerCapacity) *models.LayerCapacityChange {
+		key := l.Environment + "/" + l.Layer
+		if c, ok := layers[key]; ok {
+			return c
+		}
+		layers[key] = &models.LayerCapacityChange{Environment: l.Environment, Layer: l.Layer}
+		layerKeys = append(layerKeys, key)
+		return layers[key]
+	}
+	for _, l := range before.Layers {
+		layerChange(l).Before = l.CapacityFigures
+	}
+	for _, l := range after.Layers {
+		layerChange(l).After = l.CapacityFigures
+	}
+	sort.Strings(layerKeys)
+	for _, key := range layerKeys {
+		if c := layers[key]; c.Before != c.After {
+			delta.Layers = append(delta.Layers, *c)
+		}
+	}
+
+	return delta, nil
+}
+
+// PullRequestFiles returns the content of an open pull request's files on
+// its branch, keyed by their path in the sandbox
+func PullRequestFiles(baseDir string, pr *models.PullRequest) (map[string][]byte, error) {
+	if pr.State != models.PullRequestOpen {
+		return nil, fmt.Errorf("pull request #%d is %s", pr.ID, pr.State)
+	}
+	repoDir := filepath.Join(layout.ReposDir(baseDir), pr.Repo)
+	repo, err := gitrepo.Open(repoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, file := range pr.Files {
+		data, err := repo.FileAt(pr.Branch, file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		files[filepath.Join(repoDir, file)] = data
+	}
+	return files, nil
+}
+
+// PromotionFiles returns the files a promotion plan would write
+func PromotionFiles(plan *promote.Plan) map[string][]byte {
+	files := make(map[string][]byte)
+	for _, change := range plan.Changes {
+		files[change.Path] = change.After
+	}
+	return files
+}
+
+// Service sizes a single values.yaml. Unreadable quantities are counted as
+// zero and reported as warnings.
+func Service(data []byte, prices config.UnitPrices, hoursPerMonth float64) (models.CapacityFigures, []string, error) {
+	doc, err := yamldoc.Parse(data)
+	if err != nil {
+		return models.CapacityFigures{}, nil, err
+	}
+
+	var warnings []string
+	integer := func(path string, fallback int) int {
+		value := doc.GetString(path)
+		if value == "" {
+			return fallback
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s is not a replica count: %q", path, value))
+			return fallback
+		}
+		return n
+	}
+	quantity := func(path string) float64 {
+		value := doc.GetString(path)
+		if value == "" {
+			return 0
+		}
+		q, err := helm.ParseQuantity(value)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", path, err))
+			return 0
+		}
+		return q
+	}
+
+	var f models.CapacityFigures
+	f.MinReplicas = integer("replicaCount", 1)
+	f.MaxReplicas = f.MinReplicas
+	if doc.GetString("autoscaling.enabled") == "true" {
+		f.MinReplicas = integer("autoscaling.minReplicas", f.MinReplicas)
+		f.MaxReplicas = integer("autoscaling.maxReplicas", f.MinReplicas)
+		if f.MaxReplicas < f.MinReplicas {
+			f.MaxReplicas = f.MinReplicas
+		}
+	}
+
+	// A missing limit is unbounded in Kubernetes; the request is the best
+	// estimate we have. A missing request defaults to the limit.
+	cpuRequest, cpuLimit := requestAndLimit(quantity("resources.requests.cpu"), quantity("resources.limits.cpu"))
+	memRequest, memLimit := requestAndLimit(quantity("resources.requests.memory"), quantity("resources.limits.memory"))
+
+	f.MinCPU = float64(f.MinReplicas) * cpuRequest
+	f.MaxCPU = float64(f.MaxReplicas) * cpuLimit
+	f.MinMemory = float64(f.MinReplicas) * memRequest
+	f.MaxMemory = float64(f.MaxReplicas) * memLimit
+	f.MinCost = cost(f.MinCPU, f.MinMemory, prices, hoursPerMonth)
+	f.MaxCost = cost(f.MaxCPU, f.MaxMemory, prices, hoursPerMonth)
+	return f, warnings, nil
+}
+
+// Helper methods
+
+func (e *DefaultEstimator) estimate(env string, files map[string][]byte) (*models.CapacityReport, error) {
+	cfg, err := config.Load(e.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if env != "" && !cfg.HasEnvironment(env) {
+		return nil, fmt.Errorf("unknown environment '%s' (expected one of %s)", env, strings.Join(cfg.Environments, ", "))
+	}
+
+	paths, err := e.valuesFiles(env, files)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CapacityReport{
+		Currency:     cfg.Capacity.Currency,
+		Services:     []models.ServiceCapacity{},
+		Layers:       []models.LayerCapacity{},
+		Environments: []models.LayerCapacity{},
+	}
+	layers := make(map[string]*models.LayerCapacity)
+	envs := make(map[string]*models.LayerCapacity)
+
+	for _, path := range paths {
+		data, overridden := files[path]
+		if !overridden {
+			if data, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+		} else if data == nil {
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		service := models.ServiceCapacity{
+			Environment: strings.TrimPrefix(filepath.Base(filepath.Dir(filepath.Dir(dir))), layout.EnvironmentRepoPrefix),
+			Layer:       filepath.Base(filepath.Dir(dir)),
+			Service:     filepath.Base(dir),
+		}
+		figures, warnings, err := Service(data, cfg.UnitPrices(service.Environment), cfg.Capacity.HoursPerMonth)
+		if err != nil {
+			service.Warnings = []string{fmt.Sprintf("invalid values.yaml: %v", err)}
+		}
+		service.CapacityFigures = figures
+		service.Warnings = append(service.Warnings, warnings...)
+		report.Services = append(report.Services, service)
+
+		key := service.Environment + "/" + service.Layer
+		if layers[key] == nil {
+			layers[key] = &models.LayerCapacity{Environment: service.Environment, Layer: service.Layer}
+		}
+		if envs[service.Environment] == nil {
+			envs[service.Environment] = &models.LayerCapacity{Environment: service.Environment}
+		}
+		for _, group := range []*models.LayerCapacity{layers[key], envs[service.Environment]} {
+			group.Services++
+			group.Add(figures)
+		}
+		report.Total.Add(figures)
+	}
+
+	order := environmentOrder(cfg)
+	for _, layer := range layers {
+		report.Layers = append(report.Layers, *layer)
+	}
+	sort.Slice(report.Layers, func(i, j int) bool {
+		a, b := report.Layers[i], report.Layers[j]
+		if a.Environment != b.Environment {
+			return order(a.Environment) < order(b.Environment)
+		}
+		return a.Layer < b.Layer
+	})
+	for _, group := range envs {
+		report.Environments = append(report.Environments, *group)
+	}
+	sort.Slice(report.Environments, func(i, j int) bool {
+		return order(report.Environments[i].Environment) < order(report.Environments[j].Environment)
+	})
+
+	return report, nil
+}
+
+// valuesFiles lists the chart values files of env, including files that
+// only exist in the overrides, sorted by environment order and path
+func (e *DefaultEstimator) valuesFiles(env string, files map[string][]byte) ([]string, error) {
+	repo := layout.EnvironmentRepoPrefix + "*"
+	if env != "" {
+		repo = filepath.Base(layout.EnvironmentRepo(e.baseDir, env))
+	}
+	matches, err := filepath.Glob(filepath.Join(layout.ReposDir(e.baseDir), repo, "*", "*", helm.ValuesFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, path := range matches {
+		if validate.Classify(e.baseDir, path) == validate.KindValues {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for path := range files {
+		if seen[path] || validate.Classify(e.baseDir, path) != validate.KindValues {
+			continue
+		}
+		if env != "" && filepath.Dir(filepath.Dir(filepath.Dir(path))) != layout.EnvironmentRepo(e.baseDir, env) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// environmentOrder ranks environments in promotion order, unknown ones last
+func environmentOrder(cfg *config.Config) func(env string) int {
+	return func(env string) int {
+		for i, e := range cfg.Environments {
+			if e == env {
+				return i
+			}
+		}
+		return len(cfg.Environments)
+	}
+}
+
+func requestAndLimit(request, limit float64) (float64, float64) {
+	if request == 0 {
+		request = limit
+	}
+	if limit == 0 {
+		limit = request
+	}
+	return request, limit
+}
+
+func cost(cpu, memory float64, prices config.UnitPrices, hoursPerMonth float64) float64 {
+	return (cpu*prices.CPU + memory/bytesPerGiB*prices.Memory) * hoursPerMonth
+}
@@ -0,0 +1,181 @@
+package capacity
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/promote"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/pullrequest"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+func setupTestEnv(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	return baseDir
+}
+
+func valuesFile(baseDir, env string) string {
+	return filepath.Join(layout.ChartDir(baseDir, env, "bff", "nx-bff-test-service"), "values.yaml")
+}
+
+func replace(t *testing.T, path, old, new string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), old) {
+		t.Fatalf("%s does not contain %q: %v", path, old, err)
+	}
+	os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0644)
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestEstimate(t *testing.T) {
+	baseDir := setupTestEnv(t)
+
+	report, err := NewEstimator(baseDir).Estimate("prod1")
+	if err != nil {
+		t.Fatalf("Estimate failed: %v", err)
+	}
+
+	// Seven layers with one service each: 2-10 replicas of 100m-500m CPU and 128Mi-512Mi memory
+	if len(report.Services) != 7 || len(report.Layers) != 7 || len(report.Environments) != 1 {
+		t.Fatalf("Expected 7 services in 7 layers of prod1, got %+v", report)
+	}
+	total := report.Total
+	if total.MinReplicas != 14 || total.MaxReplicas != 70 || !near(total.MinCPU, 1.4) || !near(total.MaxCPU, 35) {
+		t.Errorf("Unexpected total %+v", total)
+	}
+	if !near(total.MinMemory, 14*128*(1<<20)) || !near(total.MaxMemory, 35*(1<<30)) {
+		t.Errorf("Unexpected memory %+v", total)
+	}
+	if expected := (35*0.04048 + 35*0.004445) * 730; !near(total.MaxCost, expected) || report.Currency != "USD" {
+		t.Errorf("Expected a max cost of %.2f USD, got %.2f %s", expected, total.MaxCost, report.Currency)
+	}
+	if env := report.Environments[0]; env.Environment != "prod1" || env.Services != 7 || env.CapacityFigures != total {
+		t.Errorf("Unexpected environment total %+v", env)
+	}
+
+	all, err := NewEstimator(baseDir).Estimate("")
+	if err != nil {
+		t.Fatalf("Estimate failed: %v", err)
+	}
+	if len(all.Environments) != 4 || all.Environments[0].Environment != "dev1" || all.Environments[3].Environment != "prod1" {
+		t.Errorf("Expected the environments in promotion order, got %+v", all.Environments)
+	}
+
+	if _, err := NewEstimator(baseDir).Estimate("qa9"); err == nil {
+		t.Error("Expected an unknown environment to be rejected")
+	}
+}
+
+func TestEstimate_EnvironmentPrices(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	os.MkdirAll(filepath.Dir(config.Path(baseDir)), 0755)
+	os.WriteFile(config.Path(baseDir), []byte("capacity:\n  currency: EUR\n  hours_per_month: 100\n  environments:\n    prod1:\n      cpu: 1\n      memory: 1\n"), 0644)
+
+	report, err := NewEstimator(baseDir).Estimate("prod1")
+	if err != nil {
+		t.Fatalf("Estimate failed: %v", err)
+	}
+	if report.Currency != "EUR" || !near(report.Total.MaxCost, (35+35)*100) {
+		t.Errorf("Expected prod1 prices to apply, got %.2f %s", report.Total.MaxCost, report.Currency)
+	}
+}
+
+func TestService(t *testing.T) {
+	prices := config.UnitPrices{CPU: 1, Memory: 1}
+
+	figures, warnings, err := Service([]byte("resources:\n  requests:\n    cpu: 250m\n    memory: 1Gi\n"), prices, 1)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("Service failed: %v %v", err, warnings)
+	}
+	if figures.MinReplicas != 1 || figures.MaxReplicas != 1 || !near(figures.MaxCPU, 0.25) || !near(figures.MaxCost, 1.25) {
+		t.Errorf("Expected a single replica sized by its requests, got %+v", figures)
+	}
+
+	figures, warnings, err = Service([]byte("replicaCount: 2\nresources:\n  limits:\n    cpu: lots\n    memory: 2Gi\n"), prices, 1)
+	if err != nil {
+		t.Fatalf("Service failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "resources.limits.cpu") {
+		t.Errorf("Expected a warning for the CPU limit, got %v", warnings)
+	}
+	if figures.MaxCPU != 0 || !near(figures.MinMemory, 4*(1<<30)) {
+		t.Errorf("Unexpected figures %+v", figures)
+	}
+}
+
+func TestDelta_Promotion(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	replace(t, valuesFile(baseDir, "uat1"), "maxReplicas: 10", "maxReplicas: 20")
+
+	plan, err := promote.NewPromoter(baseDir).Plan(promote.Request{Service: "nx-bff-test-service", From: "uat1", To: "prod1"})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	delta, err := NewEstimator(baseDir).Delta("", "promotion", PromotionFiles(plan))
+	if err != nil {
+		t.Fatalf("Delta failed: %v", err)
+	}
+
+	if len(delta.Changes) != 1 || len(delta.Layers) != 1 {
+		t.Fatalf("Expected one changed service, got %+v", delta)
+	}
+	change := delta.Changes[0]
+	if change.Environment != "prod1" || change.Service != "nx-bff-test-service" || change.Before.MaxReplicas != 10 || change.After.MaxReplicas != 20 {
+		t.Errorf("Unexpected change %+v", change)
+	}
+	if !near(delta.After.MaxCPU-delta.Before.MaxCPU, 5) {
+		t.Errorf("Expected 5 more cores at most, got %+v -> %+v", delta.Before, delta.After)
+	}
+
+	removed, err := NewEstimator(baseDir).Delta("prod1", "removal", map[string][]byte{valuesFile(baseDir, "prod1"): nil})
+	if err != nil {
+		t.Fatalf("Delta failed: %v", err)
+	}
+	if len(removed.Changes) != 1 || removed.Changes[0].After != nil || removed.After.MaxReplicas != 60 {
+		t.Errorf("Expected the removed service to be reported, got %+v", removed)
+	}
+}
+
+func TestPullRequestFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	baseDir := setupTestEnv(t)
+	if _, err := gitrepo.Init(layout.EnvironmentRepo(baseDir, "prod1")); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	values := valuesFile(baseDir, "prod1")
+	replace(t, values, "cpu: 500m", "cpu: \"2\"")
+	prs, err := pullrequest.NewManager(baseDir).Propose("Raise the CPU limit", "alice", []string{values})
+	if err != nil || len(prs) != 1 {
+		t.Fatalf("Propose failed: %v", err)
+	}
+
+	files, err := PullRequestFiles(baseDir, &prs[0])
+	if err != nil {
+		t.Fatalf("PullRequestFiles failed: %v", err)
+	}
+	delta, err := NewEstimator(baseDir).Delta("prod1", prs[0].Title, files)
+	if err != nil {
+		t.Fatalf("Delta failed: %v", err)
+	}
+	if len(delta.Changes) != 1 || !near(delta.Changes[0].After.MaxCPU, 20) || !near(delta.Changes[0].Before.MaxCPU, 5) {
+		t.Errorf("Expected the branch CPU limit to be used, got %+v", delta.Changes)
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the sandbox configuration file inside .nx-sandbox
+const FileName = "config.yaml"
+
+// Config holds the sandbox configuration loaded from .nx-sandbox/config.yaml
+type Config struct {
+	// Environments lists the sandbox environments in promotion order
+	Environments []string        `yaml:"environments"`
+	Approvers    ApproverConfig  `yaml:"approvers"`
+	Promotion    PromotionConfig `yaml:"promotion"`
+	AWS          AWSConfig       `yaml:"aws"`
+	Capacity     CapacityConfig  `yaml:"capacity"`
+}
+
+// ApproverConfig lists the teams allowed to approve infrastructure creation
+type ApproverConfig struct {
+	// Teams maps an approver team to its members
+	Teams map[string][]string `yaml:"teams"`
+}
+
+// PromotionConfig controls how charts are promoted between environments
+type PromotionConfig struct {
+	// Preserve lists values paths that keep the target environment's value
+	Preserve []string `yaml:"preserve"`
+
+	// Environments holds the substitution settings of each environment
+	Environments map[string]EnvironmentSettings `yaml:"environments"`
+}
+
+// EnvironmentSettings are the environment-specific values rewritten on promotion
+type EnvironmentSettings struct {
+	HostDomain string `yaml:"host_domain"`
+	Namespace  string `yaml:"namespace"`
+	// ImageTag pins image.tag in the environment; empty keeps the promoted tag
+	ImageTag string `yaml:"image_tag"`
+}
+
+// AWSConfig points infrastructure commands at an AWS-compatible endpoint
+type AWSConfig struct {
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+}
+
+// CapacityConfig is the unit-price table used to cost the capacity of the charts
+type CapacityConfig struct {
+	Currency      string  `yaml:"currency"`
+	HoursPerMonth float64 `yaml:"hours_per_month"`
+	// Prices apply to every environment without its own entry in Environments
+	Prices       UnitPrices            `yaml:"prices"`
+	Environments map[string]UnitPrices `yaml:"environments"`
+}
+
+// UnitPrices are the hourly prices of a CPU core and a GiB of memory
+type UnitPrices struct {
+	CPU    float64 `yaml:"cpu"`
+	Memory float64 `yaml:"memory"`
+}
+
+// DefaultAWSEndpoint is the LocalStack endpoint started by config/docker-compose.yml
+const DefaultAWSEndpoint = "http://localhost:4566"
+
+// DefaultAWSRegion is the region used by the LocalStack init scripts
+const DefaultAWSRegion = "us-east-1"
+
+// Path returns the location of the configuration file for a sandbox root
+func Path(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), FileName)
+}
+
+// Default returns the configuration used when no file is present. The
+// approver teams have no members: approvers must be configured explicitly.
+func Default() *Config {
+	return &Config{
+		Environments: append([]string(nil), models.DefaultEnvironments...),
+		Approvers: ApproverConfig{
+			Teams: map[string][]string{
+				"platform-engineers": {},
+				"devx-team":          {},
+				"sre-team":           {},
+			},
+		},
+		AWS: AWSConfig{
+			Endpoint: DefaultAWSEndpoint,
+			Region:   DefaultAWSRegion,
+		},
+		Capacity: CapacityConfig{
+			Currency:      "USD",
+			HoursPerMonth: 730,
+			// AWS Fargate on-demand prices in us-east-1
+			Prices: UnitPrices{CPU: 0.04048, Memory: 0.004445},
+		},
+	}
+}
+
+// Load reads the configuration for a sandbox root, applying defaults for
+// anything the file leaves unset
+func Load(baseDir string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(Path(baseDir))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// Decoding over the defaults keeps every setting the file omits
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", Path(baseDir), err)
+	}
+
+	if len(cfg.Environments) == 0 {
+		return nil, fmt.Errorf("%s: environments must not be empty", Path(baseDir))
+	}
+
+	return cfg, nil
+}
+
+// ApproverTeam returns the first approver team (in name order) that user belongs to
+func (c *Config) ApproverTeam(user string) (string, bool) {
+	var teams []string
+	for team := range c.Approvers.Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	for _, team := range teams {
+		for _, member := range c.Approvers.Teams[team] {
+			if member == user {
+				return team, true
+			}
+		}
+	}
+	return "", false
+}
+
+// HasEnvironment reports whether env is one of the configured environments
+func (c *Config) HasEnvironment(env string) bool {
+	for _, e := range c.Environments {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvironmentSettings returns the promotion settings of env, defaulting the
+// host domain to <env>.nexus.britishairways.com and the namespace to nexus-<env>
+func (c *Config) EnvironmentSettings(env string) EnvironmentSettings {
+	settings := c.Promotion.Environments[env]
+	if settings.HostDomain == "" {
+		settings.HostDomain = env + ".nexus.britishairways.com"
+	}
+	if settings.Namespace == "" {
+		settings.Namespace = "nexus-" + env
+	}
+	return settings
+}
+
+// NextEnvironment returns the environment that follows env in promotion order
+func (c *Config) NextEnvironment(env string) (string, bool) {
+	for i, e := range c.Environments {
+		if e == env && i+1 < len(c.Environments) {
+			return c.Environments[i+1], true
+		}
+	}
+	return "", false
+}
+
+// UnitPrices returns the prices of env, falling back to the default prices
+// for any price the environment does not set
+func (c *Config) UnitPrices(env string) UnitPrices {
+	prices := c.Capacity.Environments[env]
+	if prices.CPU == 0 {
+		prices.CPU = c.Capacity.Prices.CPU
+	}
+	if prices.Memory == 0 {
+		prices.Memory = c.Capacity.Prices.Memory
+	}
+	return prices
+}
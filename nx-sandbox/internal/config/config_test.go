@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(cfg.Environments) != 4 || cfg.Environments[0] != "dev1" {
+		t.Errorf("Unexpected default environments: %v", cfg.Environments)
+	}
+	if _, ok := cfg.Approvers.Teams["platform-engineers"]; !ok {
+		t.Error("Expected default approver teams")
+	}
+	if cfg.AWS.Endpoint != DefaultAWSEndpoint || cfg.AWS.Region != DefaultAWSRegion {
+		t.Errorf("Unexpected default AWS settings: %+v", cfg.AWS)
+	}
+	if cfg.Capacity.Currency != "USD" || cfg.UnitPrices("prod1") != cfg.Capacity.Prices {
+		t.Errorf("Unexpected default capacity settings: %+v", cfg.Capacity)
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	baseDir := t.TempDir()
+	os.MkdirAll(filepath.Join(baseDir, ".nx-sandbox"), 0755)
+	os.WriteFile(Path(baseDir), []byte("environments: [dev1, prod1]\napprovers:\n  teams:\n    sre-team: [carol]\ncapacity:\n  environments:\n    prod1:\n      cpu: 0.1\n"), 0644)
+
+	cfg, err := Load(baseDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !cfg.HasEnvironment("prod1") || cfg.HasEnvironment("sit1") {
+		t.Errorf("Unexpected environments: %v", cfg.Environments)
+	}
+	if team, ok := cfg.ApproverTeam("carol"); !ok || team != "sre-team" {
+		t.Errorf("Expected carol in sre-team, got %q", team)
+	}
+	if prices := cfg.UnitPrices("prod1"); prices.CPU != 0.1 || prices.Memory != cfg.Capacity.Prices.Memory {
+		t.Errorf("Expected the prod1 CPU price to fall back to the default memory price, got %+v", prices)
+	}
+	if cfg.Capacity.HoursPerMonth != 730 {
+		t.Errorf("Expected the default hours per month to be kept, got %v", cfg.Capacity.HoursPerMonth)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	baseDir := t.TempDir()
+	os.MkdirAll(filepath.Join(baseDir, ".nx-sandbox"), 0755)
+	os.WriteFile(Path(baseDir), []byte("environments: []\n"), 0644)
+
+	if _, err := Load(baseDir); err == nil {
+		t.Error("Expected empty environments to be rejected")
+	}
+}
@@ -0,0 +1,50 @@
+package diff
+
+import "testing"
+
+func TestUnified_Equal(t *testing.T) {
+	if got := Unified("a", "b", []byte("x\n"), []byte("x\n")); got != "" {
+		t.Errorf("Expected empty diff, got %q", got)
+	}
+}
+
+func TestUnified_Change(t *testing.T) {
+	a := []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	b := []byte("one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight\nnine\nten\neleven\n")
+
+	want := `--- a/values.yaml
++++ b/values.yaml
+@@ -2,9 +2,10 @@
+ two
+ three
+ four
+-five
++FIVE
+ six
+ seven
+ eight
+ nine
+ ten
++eleven
+`
+	if got := Unified("a/values.yaml", "b/values.yaml", a, b); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+}
+
+func TestUnified_NewFile(t *testing.T) {
+	want := "--- /dev/null\n+++ b/new.yaml\n@@ -0,0 +1,2 @@\n+a\n+b\n"
+	if got := Unified("/dev/null", "b/new.yaml", nil, []byte("a\nb\n")); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	a := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	b := []byte("X\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\nY\n")
+
+	want := "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+X\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+Y\n"
+	if got := Unified("a", "b", a, b); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+}
@@ -0,0 +1,242 @@
+package envvars
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/secrets"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretRefPrefix marks a value as a reference to a Kubernetes secret,
+// written as secretRef:<secret-name>/<key>
+const SecretRefPrefix = "secretRef:"
+
+// section is the values.yaml key holding environment variables
+const section = "env"
+
+var keyPattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// secretRefValue is the values.yaml form of a secret reference
+type secretRefValue struct {
+	SecretRef models.SecretRef `yaml:"secretRef"`
+}
+
+// Manager defines the interface for managing artifact environment variables
+type Manager interface {
+	List(artifact, env string) ([]models.EnvVar, error)
+	Set(artifact, env string, vars []models.EnvVar) (string, error)
+	Unset(artifact, env string, keys []string) (string, error)
+}
+
+// DefaultManager edits the env section of values.yaml in the environment repositories
+type DefaultManager struct {
+	baseDir string
+}
+
+// NewManager creates a new environment variable manager
+func NewManager(baseDir string) Manager {
+	return &DefaultManager{
+		baseDir: baseDir,
+	}
+}
+
+// List returns the environment variables of an artifact, sorted by name
+func (m *DefaultManager) List(artifact, env string) ([]models.EnvVar, error) {
+	doc, err := m.load(artifact, env)
+	if err != nil {
+		return nil, err
+	}
+
+	node := doc.Get(section)
+	if node == nil || node.Tag == "!!null" {
+		return nil, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: env must be a mapping of NAME: value", doc.Path)
+	}
+
+	var vars []models.EnvVar
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		v := models.EnvVar{Name: node.Content[i].Value}
+		value := node.Content[i+1]
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			v.Value = value.Value
+		case yaml.MappingNode:
+			var ref secretRefValue
+			if err := value.Decode(&ref); err != nil || ref.SecretRef.Name == "" {
+				return nil, fmt.Errorf("%s: env.%s must be a value or a secretRef", doc.Path, v.Name)
+			}
+			v.SecretRef = &models.SecretRef{Name: ref.SecretRef.Name, Key: ref.SecretRef.Key}
+		default:
+			return nil, fmt.Errorf("%s: env.%s must be a value or a secretRef", doc.Path, v.Name)
+		}
+		vars = append(vars, v)
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars, nil
+}
+
+// Set validates and writes environment variables. Nothing is written if any
+// variable is invalid.
+func (m *DefaultManager) Set(artifact, env string, vars []models.EnvVar) (string, error) {
+	if len(vars) == 0 {
+		return "", fmt.Errorf("no variables to set")
+	}
+	for _, v := range vars {
+		if err := Validate(v); err != nil {
+			return "", err
+		}
+	}
+
+	doc, err := m.load(artifact, env)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range vars {
+		var value interface{} = v.Value
+		if v.SecretRef != nil {
+			value = secretRefValue{SecretRef: *v.SecretRef}
+		}
+		if err := doc.Set(section+"."+v.Name, value); err != nil {
+			return "", err
+		}
+	}
+
+	return doc.Path, doc.Save()
+}
+
+// Unset removes environment variables. Unknown names are reported as an error.
+func (m *DefaultManager) Unset(artifact, env string, keys []string) (string, error) {
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no variables to unset")
+	}
+
+	doc, err := m.load(artifact, env)
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range keys {
+		if !doc.Delete(section + "." + key) {
+			return "", fmt.Errorf("variable '%s' is not set", key)
+		}
+	}
+
+	return doc.Path, doc.Save()
+}
+
+// ValidateKey checks an environment variable name
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid variable name '%s': use uppercase letters, digits and underscores, not starting with a digit", key)
+	}
+	return nil
+}
+
+// Validate checks a variable's name and refuses plain values that look like secrets
+func Validate(v models.EnvVar) error {
+	if err := ValidateKey(v.Name); err != nil {
+		return err
+	}
+
+	if v.SecretRef != nil {
+		if v.SecretRef.Name == "" || v.SecretRef.Key == "" {
+			return fmt.Errorf("%s: secret reference needs both a secret name and a key", v.Name)
+		}
+		return nil
+	}
+
+	if reason, ok := secrets.LooksLikeSecret(v.Name, v.Value); ok {
+		return fmt.Errorf("%s: %s; store it in a secret and use %s<secret-name>/<key>", v.Name, reason, SecretRefPrefix)
+	}
+	return nil
+}
+
+// ParseAssignment parses KEY=VALUE, where VALUE may be secretRef:<secret-name>/<key>
+func ParseAssignment(assignment string) (models.EnvVar, error) {
+	key, value, ok := strings.Cut(assignment, "=")
+	if !ok {
+		return models.EnvVar{}, fmt.Errorf("invalid assignment '%s': expected KEY=VALUE", assignment)
+	}
+	return newEnvVar(strings.TrimSpace(key), value)
+}
+
+// ParseDotenv parses a dotenv file. Blank lines, comments and an optional
+// "export " prefix are supported; values may be single or double quoted.
+func ParseDotenv(r io.Reader) ([]models.EnvVar, error) {
+	var vars []models.EnvVar
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		v, err := newEnvVar(strings.TrimSpace(key), value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		vars = append(vars, v)
+	}
+
+	return vars, scanner.Err()
+}
+
+// Helper methods
+
+func (m *DefaultManager) load(artifact, env string) (*yamldoc.Document, error) {
+	path, err := helm.ValuesPath(m.baseDir, env, artifact)
+	if err != nil {
+		return nil, err
+	}
+	return yamldoc.Load(path)
+}
+
+func newEnvVar(key, value string) (models.EnvVar, error) {
+	v := models.EnvVar{Name: key}
+
+	if ref, ok := strings.CutPrefix(value, SecretRefPrefix); ok {
+		name, secretKey, found := strings.Cut(ref, "/")
+		if !found || name == "" || secretKey == "" {
+			return v, fmt.Errorf("invalid secret reference for %s: expected %s<secret-name>/<key>", key, SecretRefPrefix)
+		}
+		v.SecretRef = &models.SecretRef{Name: name, Key: secretKey}
+		return v, nil
+	}
+
+	v.Value = value
+	return v, nil
+}
@@ -0,0 +1,207 @@
+package gitrepo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBranch is the branch created by Init and targeted by pull requests
+const DefaultBranch = "main"
+
+// Fallback identity used when git has no user configured
+const (
+	defaultUserName  = "nx-sandbox"
+	defaultUserEmail = "nx-sandbox@localhost"
+)
+
+// Repo is a local Git repository driven through the git CLI
+type Repo struct {
+	Dir string
+}
+
+// IsRepo reports whether dir is the root of a Git repository
+func IsRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// Open returns the repository rooted at dir
+func Open(dir string) (*Repo, error) {
+	if !IsRepo(dir) {
+		return nil, fmt.Errorf("%s is not a git repository (run: nx-sandbox repos init)", dir)
+	}
+	return &Repo{Dir: dir}, nil
+}
+
+// Init creates a repository in dir on DefaultBranch and commits its current contents
+func Init(dir string) (*Repo, error) {
+	if IsRepo(dir) {
+		return nil, fmt.Errorf("%s is already a git repository", dir)
+	}
+
+	r := &Repo{Dir: dir}
+	if _, err := r.run("init", "--quiet", "--initial-branch", DefaultBranch); err != nil {
+		return nil, err
+	}
+	if _, err := r.run("add", "--all"); err != nil {
+		return nil, err
+	}
+	if err := r.commit("Initial import of "+filepath.Base(dir), true); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CurrentBranch returns the checked out branch
+func (r *Repo) CurrentBranch() (string, error) {
+	return r.run("rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// HasBranch reports whether a local branch exists
+func (r *Repo) HasBranch(name string) bool {
+	_, err := r.run("rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// CommitToBranch commits paths on a new branch created from the current one,
+// then checks the original branch out again. The working tree of the original
+// branch no longer contains the committed changes.
+func (r *Repo) CommitToBranch(branch, message string, paths []string) (string, error) {
+	base, err := r.CurrentBranch()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.run("checkout", "--quiet", "-b", branch); err != nil {
+		return "", err
+	}
+
+	args := append([]string{"add", "--all", "--"}, paths...)
+	_, err = r.run(args...)
+	if err == nil {
+		err = r.commit(message, false)
+	}
+	if err != nil {
+		r.run("checkout", "--quiet", base)
+		r.run("branch", "-D", branch)
+		return "", err
+	}
+
+	sha, err := r.run("rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if _, err := r.run("checkout", "--quiet", base); err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
+// Diff returns the changes a branch introduces relative to base
+func (r *Repo) Diff(base, branch string) (string, error) {
+	return r.run("diff", base+"..."+branch)
+}
+
+// ChangedFiles lists the files a branch changes relative to base
+func (r *Repo) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := r.run("diff", "--name-only", base+"..."+branch)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// FileAt returns the content of path at ref, or os.ErrNotExist when the
+// file does not exist there
+func (r *Repo) FileAt(ref, path string) ([]byte, error) {
+	spec := ref + ":" + filepath.ToSlash(path)
+	if _, err := r.run("cat-file", "-e", spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", spec, os.ErrNotExist)
+	}
+
+	cmd := exec.Command("git", "show", spec)
+	cmd.Dir = r.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show: %w", err)
+	}
+	return out, nil
+}
+
+// Merge merges branch into base with a merge commit, aborting on conflicts
+func (r *Repo) Merge(base, branch, message string) (string, error) {
+	current, err := r.CurrentBranch()
+	if err != nil {
+		return "", err
+	}
+	if current != base {
+		if _, err := r.run("checkout", "--quiet", base); err != nil {
+			return "", err
+		}
+		defer r.run("checkout", "--quiet", current)
+	}
+
+	args := append(r.identity(), "merge", "--no-ff", "--quiet", "-m", message, branch)
+	if _, err := r.run(args...); err != nil {
+		r.run("merge", "--abort")
+		return "", err
+	}
+	return r.run("rev-parse", "HEAD")
+}
+
+// Helper methods
+
+func (r *Repo) commit(message string, allowEmpty bool) error {
+	args := append(r.identity(), "commit", "--quiet", "-m", message)
+	if allowEmpty {
+		args = append(args, "--allow-empty")
+	} else if _, err := r.run("diff", "--cached", "--quiet"); err == nil {
+		return fmt.Errorf("nothing to commit")
+	}
+	_, err := r.run(args...)
+	return err
+}
+
+// identity supplies a committer when git has none configured
+func (r *Repo) identity() []string {
+	if name, _ := r.run("config", "user.name"); name != "" {
+		if email, _ := r.run("config", "user.email"); email != "" {
+			return nil
+		}
+	}
+	return []string{"-c", "user.name=" + defaultUserName, "-c", "user.email=" + defaultUserEmail}
+}
+
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", subcommand(args), msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// subcommand returns the git subcommand of args, skipping -c options
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
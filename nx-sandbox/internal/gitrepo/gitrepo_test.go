@@ -0,0 +1,157 @@
+package gitrepo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupTestRepo initializes a repository holding values.yaml
+func setupTestRepo(t *testing.T) *Repo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("replicaCount: 1\n"), 0644)
+
+	repo, err := Init(dir)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return repo
+}
+
+// write replaces a file in the working tree of repo
+func write(t *testing.T, repo *Repo, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo.Dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	if branch, err := repo.CurrentBranch(); err != nil || branch != DefaultBranch {
+		t.Errorf("Expected branch %s, got %q (%v)", DefaultBranch, branch, err)
+	}
+	if _, err := Init(repo.Dir); err == nil {
+		t.Error("Expected a second Init to fail")
+	}
+	if _, err := Open(t.TempDir()); err == nil || !strings.Contains(err.Error(), "nx-sandbox repos init") {
+		t.Errorf("Expected Open outside a repository to fail with a hint, got %v", err)
+	}
+}
+
+func TestCommitToBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+	write(t, repo, "values.yaml", "replicaCount: 2\n")
+	write(t, repo, "Chart.yaml", "name: nx\n")
+	write(t, repo, "untracked.txt", "left alone\n")
+
+	sha, err := repo.CommitToBranch("scale", "Scale up", []string{"values.yaml", "Chart.yaml"})
+	if err != nil {
+		t.Fatalf("CommitToBranch failed: %v", err)
+	}
+	if len(sha) != 40 {
+		t.Errorf("Expected a commit SHA, got %q", sha)
+	}
+	if branch, _ := repo.CurrentBranch(); branch != DefaultBranch {
+		t.Errorf("Expected %s to be checked out again, got %s", DefaultBranch, branch)
+	}
+	if !repo.HasBranch("scale") || repo.HasBranch("missing") {
+		t.Error("Expected only the committed branch to exist")
+	}
+
+	// The committed changes leave the working tree of the base branch
+	if data, _ := os.ReadFile(filepath.Join(repo.Dir, "values.yaml")); string(data) != "replicaCount: 1\n" {
+		t.Errorf("Expected the base branch content, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Dir, "untracked.txt")); err != nil {
+		t.Error("Expected paths outside the commit to stay in the working tree")
+	}
+
+	files, err := repo.ChangedFiles(DefaultBranch, "scale")
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	if strings.Join(files, ",") != "Chart.yaml,values.yaml" {
+		t.Errorf("Unexpected changed files: %v", files)
+	}
+	if files, _ := repo.ChangedFiles(DefaultBranch, DefaultBranch); files != nil {
+		t.Errorf("Expected no changed files, got %v", files)
+	}
+
+	diff, err := repo.Diff(DefaultBranch, "scale")
+	if err != nil || !strings.Contains(diff, "-replicaCount: 1\n+replicaCount: 2") {
+		t.Errorf("Unexpected diff (%v):\n%s", err, diff)
+	}
+
+	if _, err := repo.CommitToBranch("empty", "Nothing", []string{"values.yaml"}); err == nil {
+		t.Error("Expected a commit without changes to fail")
+	}
+	if repo.HasBranch("empty") {
+		t.Error("Expected the branch of a failed commit to be removed")
+	}
+}
+
+func TestFileAt(t *testing.T) {
+	repo := setupTestRepo(t)
+	write(t, repo, "Chart.yaml", "name: nx\n\n")
+	if _, err := repo.CommitToBranch("chart", "Add chart", []string{"Chart.yaml"}); err != nil {
+		t.Fatalf("CommitToBranch failed: %v", err)
+	}
+
+	// Content is returned as stored, trailing newlines included
+	data, err := repo.FileAt("chart", "Chart.yaml")
+	if err != nil || string(data) != "name: nx\n\n" {
+		t.Errorf("Unexpected content %q (%v)", data, err)
+	}
+
+	for _, tt := range []struct{ ref, path string }{
+		{DefaultBranch, "Chart.yaml"},
+		{"chart", "missing.yaml"},
+		{"missing-branch", "values.yaml"},
+	} {
+		if _, err := repo.FileAt(tt.ref, tt.path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected os.ErrNotExist for %s:%s, got %v", tt.ref, tt.path, err)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	repo := setupTestRepo(t)
+	write(t, repo, "values.yaml", "replicaCount: 2\n")
+	if _, err := repo.CommitToBranch("two", "Two replicas", []string{"values.yaml"}); err != nil {
+		t.Fatalf("CommitToBranch failed: %v", err)
+	}
+	write(t, repo, "values.yaml", "replicaCount: 3\n")
+	if _, err := repo.CommitToBranch("three", "Three replicas", []string{"values.yaml"}); err != nil {
+		t.Fatalf("CommitToBranch failed: %v", err)
+	}
+
+	head, err := repo.Merge(DefaultBranch, "two", "Merge two")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if data, _ := repo.FileAt(DefaultBranch, "values.yaml"); string(data) != "replicaCount: 2\n" {
+		t.Errorf("Expected the merged content, got %q", data)
+	}
+
+	// Both branches change the same line, so the second merge conflicts
+	if _, err := repo.Merge(DefaultBranch, "three", "Merge three"); err == nil {
+		t.Fatal("Expected a conflicting merge to fail")
+	}
+	if _, err := os.Stat(filepath.Join(repo.Dir, ".git", "MERGE_HEAD")); !os.IsNotExist(err) {
+		t.Error("Expected the conflicting merge to be aborted")
+	}
+	if after, _ := repo.run("rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected %s to stay at %s, got %s", DefaultBranch, head, after)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo.Dir, "values.yaml")); string(data) != "replicaCount: 2\n" {
+		t.Errorf("Expected a clean working tree after the abort, got %q", data)
+	}
+}
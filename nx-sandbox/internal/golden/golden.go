@@ -0,0 +1,260 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/diff"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateEnv makes Assert write golden files instead of comparing with them
+// when set to a non-empty value, e.g. NX_SANDBOX_UPDATE_GOLDEN=1 go test ./...
+const UpdateEnv = "NX_SANDBOX_UPDATE_GOLDEN"
+
+// Timestamp replaces every timestamp in normalized output
+const Timestamp = "<TIMESTAMP>"
+
+// timestampPattern matches RFC 3339 timestamps and the output of time.Time.String
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2}| [+-]\d{4} [A-Z]+)?`)
+
+// T is the subset of testing.TB used by Assert
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Normalize makes generated output comparable across runs. Line endings
+// become \n and timestamps become Timestamp. JSON and YAML, recognised by the
+// extension of name, are re-encoded with mapping keys sorted, so only a change
+// of content, not of key order, is a difference; sequence order is kept.
+// Output that does not parse is normalized as text.
+func Normalize(name string, data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		if normalized, err := normalizeJSON(data); err == nil {
+			data = normalized
+		}
+	case ".yaml", ".yml":
+		if normalized, err := normalizeYAML(data); err == nil {
+			data = normalized
+		}
+	}
+	return timestampPattern.ReplaceAll(data, []byte(Timestamp))
+}
+
+// Verify compares generated files, keyed by slash-separated name, with the
+// golden files under dir. Golden files under dir that are not generated are
+// reported as stale.
+func Verify(dir string, files map[string][]byte) ([]models.GoldenFile, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := []models.GoldenFile{}
+	for _, name := range names {
+		result, _, err := compare(dir, name, files[name])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	existing, err := goldenFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range existing {
+		if _, ok := files[name]; !ok {
+			results = append(results, models.GoldenFile{
+				Name:   name,
+				Path:   filepath.Join(dir, filepath.FromSlash(name)),
+				Status: models.GoldenStale,
+			})
+		}
+	}
+	return results, nil
+}
+
+// Update rewrites the golden files under dir from the generated files and
+// removes stale ones. It returns the comparison made before the update.
+func Update(dir string, files map[string][]byte) ([]models.GoldenFile, error) {
+	results, err := Verify(dir, files)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		switch result.Status {
+		case models.GoldenMissing, models.GoldenMismatch:
+			if err := write(result.Path, Normalize(result.Name, files[result.Name])); err != nil {
+				return nil, err
+			}
+		case models.GoldenStale:
+			if err := os.Remove(result.Path); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", result.Path, err)
+			}
+		}
+	}
+	return results, nil
+}
+
+// Assert compares got with the golden file at path, normalizing both, and
+// fails t with a diff when they differ. With UpdateEnv set the golden file
+// is written instead.
+func Assert(t T, path string, got []byte) {
+	t.Helper()
+	result, normalized, err := compare(filepath.Dir(path), filepath.Base(path), got)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		if result.Status != models.GoldenMatch {
+			if err := write(path, normalized); err != nil {
+				t.Fatalf("Failed to update golden file: %v", err)
+			}
+		}
+		return
+	}
+
+	switch result.Status {
+	case models.GoldenMissing:
+		t.Fatalf("Golden file %s does not exist; run with %s=1 to create it", path, UpdateEnv)
+	case models.GoldenMismatch:
+		t.Errorf("Output does not match %s (run with %s=1 to update it):\n%s", path, UpdateEnv, result.Diff)
+	}
+}
+
+// Helper methods
+
+// compare normalizes got and compares it with the golden file name under dir
+func compare(dir, name string, got []byte) (models.GoldenFile, []byte, error) {
+	result := models.GoldenFile{Name: name, Path: filepath.Join(dir, filepath.FromSlash(name)), Status: models.GoldenMatch}
+	normalized := Normalize(name, got)
+
+	want, err := os.ReadFile(result.Path)
+	if os.IsNotExist(err) {
+		result.Status = models.GoldenMissing
+		result.Diff = diff.Unified("/dev/null", "generated/"+name, nil, normalized)
+		return result, normalized, nil
+	}
+	if err != nil {
+		return result, nil, err
+	}
+
+	if d := diff.Unified("golden/"+name, "generated/"+name, Normalize(name, want), normalized); d != "" {
+		result.Status = models.GoldenMismatch
+		result.Diff = d
+	}
+	return result, normalized, nil
+}
+
+// goldenFiles lists the files under dir as slash-separated names
+func goldenFiles(dir string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && path == dir {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(names)
+	return names, err
+}
+
+func write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func normalizeJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeYAML sorts the mapping keys of every document, keeping comments
+// and scalar styles
+func normalizeYAML(data []byte) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		sortKeys(&doc)
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func sortKeys(node *yaml.Node) {
+	for _, child := range node.Content {
+		sortKeys(child)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i][0].Value < pairs[j][0].Value
+	})
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
@@ -0,0 +1,130 @@
+package golden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// recorder is a T that records failures instead of failing the test
+type recorder struct {
+	errors []string
+	fatal  string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestNormalize(t *testing.T) {
+	text := Normalize("run.log", []byte("started 2026-10-19T08:30:00.123Z\r\nfinished 2026-10-19 08:31:02 +0000 UTC\r\n"))
+	if string(text) != "started <TIMESTAMP>\nfinished <TIMESTAMP>\n" {
+		t.Errorf("Unexpected text normalization: %q", text)
+	}
+
+	a := Normalize("run.json", []byte(`{"b": 1, "a": {"d": [3, 1], "c": "2026-10-19T08:30:00+01:00"}}`))
+	b := Normalize("run.json", []byte("{\n\t\"a\": {\"c\": \"2026-01-01T00:00:00Z\", \"d\": [3, 1]},\n\t\"b\": 1\n}"))
+	if string(a) != string(b) || !strings.HasPrefix(string(a), "{\n  \"a\": {\n    \"c\": \"<TIMESTAMP>\"") {
+		t.Errorf("Expected JSON key order and timestamps to be normalized, got:\n%s\n%s", a, b)
+	}
+
+	y := Normalize("values.yml", []byte("# header\n\n# the zone\nzone: eu\nimage:\n  tag: \"1.0\"\n  repository: nx\nhosts: [b, a]\n---\nb: 1\na: 2\n"))
+	want := "# header\n\nhosts: [b, a]\nimage:\n  repository: nx\n  tag: \"1.0\"\n# the zone\nzone: eu\n---\na: 2\nb: 1\n"
+	if string(y) != want {
+		t.Errorf("Expected YAML keys sorted with comments and styles kept, got:\n%s", y)
+	}
+
+	if invalid := Normalize("broken.yaml", []byte("a: [\r\n")); string(invalid) != "a: [\n" {
+		t.Errorf("Expected invalid YAML to be normalized as text, got %q", invalid)
+	}
+}
+
+func TestVerifyUpdate(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "dev1"), 0755)
+	os.WriteFile(filepath.Join(dir, "dev1", "values.yaml"), []byte("replicaCount: 1\nimage: nx\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "dev1", "Chart.yaml"), []byte("name: nx\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "old.yaml"), []byte("x: 1\n"), 0644)
+
+	files := map[string][]byte{
+		"dev1/values.yaml": []byte("image: nx\nreplicaCount: 2\n"),
+		"dev1/Chart.yaml":  []byte("name: nx\n"),
+		"prod1/Chart.yaml": []byte("name: nx\n"),
+	}
+	results, err := Verify(dir, files)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	statuses := map[string]models.GoldenStatus{}
+	for _, result := range results {
+		statuses[result.Name] = result.Status
+	}
+	expected := map[string]models.GoldenStatus{
+		"dev1/Chart.yaml":  models.GoldenMatch,
+		"dev1/values.yaml": models.GoldenMismatch,
+		"prod1/Chart.yaml": models.GoldenMissing,
+		"old.yaml":         models.GoldenStale,
+	}
+	if fmt.Sprint(statuses) != fmt.Sprint(expected) {
+		t.Fatalf("Expected %v, got %v", expected, statuses)
+	}
+	if d := results[1].Diff; !strings.Contains(d, "--- golden/dev1/values.yaml") || !strings.Contains(d, "-replicaCount: 1\n+replicaCount: 2\n") || strings.Contains(d, "-image") {
+		t.Errorf("Expected a diff of the replica count only, got:\n%s", d)
+	}
+
+	if _, err := Update(dir, files); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.yaml")); !os.IsNotExist(err) {
+		t.Error("Expected the stale golden file to be removed")
+	}
+	results, err = Verify(dir, files)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Status != models.GoldenMatch {
+			t.Errorf("Expected %s to match after the update, got %s", result.Name, result.Status)
+		}
+	}
+}
+
+func TestAssert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	t.Setenv(UpdateEnv, "")
+
+	r := &recorder{}
+	Assert(r, path, []byte(`{"ok": true}`))
+	if !strings.Contains(r.fatal, "does not exist") {
+		t.Errorf("Expected a missing golden file to be fatal, got %q", r.fatal)
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	Assert(&recorder{}, path, []byte(`{"ok": true, "at": "2026-10-19T08:30:00Z"}`))
+	if data, _ := os.ReadFile(path); string(data) != "{\n  \"at\": \"<TIMESTAMP>\",\n  \"ok\": true\n}\n" {
+		t.Errorf("Expected the normalized output to be written, got:\n%s", data)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	r = &recorder{}
+	Assert(r, path, []byte(`{"at": "2027-01-01T00:00:00Z", "ok": true}`))
+	if len(r.errors) != 0 || r.fatal != "" {
+		t.Errorf("Expected a match, got %v %q", r.errors, r.fatal)
+	}
+
+	Assert(r, path, []byte(`{"at": "2027-01-01T00:00:00Z", "ok": false}`))
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "-  \"ok\": true\n+  \"ok\": false") {
+		t.Errorf("Expected a diff, got %v", r.errors)
+	}
+}
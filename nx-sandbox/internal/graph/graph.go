@@ -0,0 +1,305 @@
+package graph
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/infra"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// externalValues is the external section of an artifact's Helm values
+type externalValues struct {
+	External struct {
+		Redis struct {
+			Enabled  bool   `yaml:"enabled"`
+			Endpoint string `yaml:"endpoint"`
+		} `yaml:"redis"`
+		DynamoDB struct {
+			Enabled   bool   `yaml:"enabled"`
+			TableName string `yaml:"table_name"`
+		} `yaml:"dynamodb"`
+	} `yaml:"external"`
+}
+
+// Builder defines the interface for building the dependency graph
+type Builder interface {
+	Build(env string) (*models.DependencyGraph, error)
+}
+
+// DefaultBuilder reads the enabled inventory components and the external
+// section of the Helm values under repos/
+type DefaultBuilder struct {
+	baseDir string
+}
+
+// NewBuilder creates a new graph builder for a sandbox root
+func NewBuilder(baseDir string) Builder {
+	return &DefaultBuilder{
+		baseDir: baseDir,
+	}
+}
+
+// Build links every artifact with a dependency to its components and each
+// component to the resource behind it: artifact → component → resource.
+// Resources are named as infra apply names them, so artifacts sharing a Redis
+// cluster_id or a DynamoDB table share the resource node. A Helm Redis
+// endpoint is matched to the cluster whose inventory records it. An empty
+// env builds the graph of every environment.
+func (b *DefaultBuilder) Build(env string) (*models.DependencyGraph, error) {
+	g := newBuilder()
+
+	entries, err := inventory.List(b.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Redis endpoints recorded by infra apply, by host
+	clusters := make(map[string]string)
+	for _, entry := range entries {
+		if redis := entry.Inventory.Components.Redis; redis.Enabled && redis.Endpoint != "" {
+			clusters[endpointHost(redis.Endpoint)] = infra.CacheClusterID(entry)
+		}
+	}
+
+	for _, entry := range entries {
+		if env != "" && entry.Environment() != env {
+			continue
+		}
+		components := entry.Inventory.Components
+		link := func(component, resourceType, resource string) {
+			g.link(entry.Name(), entry.Environment(), component, resourceType, resource, models.GraphSourceInventory)
+		}
+
+		if components.ServiceAccount.Enabled {
+			link("service_account", "iam-role", infra.RoleName(entry))
+		}
+		if components.Redis.Enabled {
+			link("redis", "redis", infra.CacheClusterID(entry))
+		}
+		if components.Dynamo.Enabled {
+			link("dynamo", "dynamodb", infra.TableName(entry))
+		}
+		if components.RDS.Enabled {
+			link("rds", "rds", infra.DBInstanceID(entry))
+		}
+		if components.ECR.Enabled {
+			link("ecr", "ecr", infra.RepositoryName(entry))
+		}
+	}
+
+	valuesFiles, err := b.valuesFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range valuesFiles {
+		chartEnv := strings.TrimPrefix(filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(path)))), layout.EnvironmentRepoPrefix)
+		if env != "" && chartEnv != env {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var values externalValues
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		service := filepath.Base(filepath.Dir(path))
+		artifact := layout.EnvironmentArtifactName(service, chartEnv)
+		external := values.External
+		if external.Redis.Enabled && external.Redis.Endpoint != "" {
+			host := endpointHost(external.Redis.Endpoint)
+			resource := host
+			if cluster, ok := clusters[host]; ok {
+				resource = cluster
+			}
+			g.link(artifact, chartEnv, "redis", "redis", resource, models.GraphSourceHelm)
+		}
+		if external.DynamoDB.Enabled && external.DynamoDB.TableName != "" {
+			g.link(artifact, chartEnv, "dynamo", "dynamodb", external.DynamoDB.TableName, models.GraphSourceHelm)
+		}
+	}
+
+	return g.graph(), nil
+}
+
+// ResourceID returns the node ID of a resource
+func ResourceID(resourceType, name string) string {
+	return resourceType + "/" + name
+}
+
+// FindResource resolves a resource given by ID, such as redis/payments, or
+// by name when only one resource has it
+func FindResource(g *models.DependencyGraph, resource string) (*models.GraphNode, error) {
+	var matches []*models.GraphNode
+	for i, node := range g.Nodes {
+		if node.Kind != models.GraphResource {
+			continue
+		}
+		if node.ID == resource {
+			return &g.Nodes[i], nil
+		}
+		if node.Name == resource {
+			matches = append(matches, &g.Nodes[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("resource '%s' not found", resource)
+	case 1:
+		return matches[0], nil
+	}
+	var ids []string
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+	return nil, fmt.Errorf("resource '%s' is ambiguous: use one of %s", resource, strings.Join(ids, ", "))
+}
+
+// Impact lists the artifacts that depend on a resource, sorted by artifact
+func Impact(g *models.DependencyGraph, resourceID string) []models.GraphImpact {
+	nodes := make(map[string]models.GraphNode)
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+
+	impacts := []models.GraphImpact{}
+	for _, toResource := range g.Edges {
+		if toResource.To != resourceID {
+			continue
+		}
+		component := nodes[toResource.From]
+		for _, toComponent := range g.Edges {
+			if toComponent.To != component.ID {
+				continue
+			}
+			artifact := nodes[toComponent.From]
+			impacts = append(impacts, models.GraphImpact{
+				Artifact:    artifact.Name,
+				Environment: artifact.Environment,
+				Component:   component.Type,
+				Sources:     toResource.Sources,
+			})
+		}
+	}
+
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].Artifact != impacts[j].Artifact {
+			return impacts[i].Artifact < impacts[j].Artifact
+		}
+		return impacts[i].Component < impacts[j].Component
+	})
+	return impacts
+}
+
+// Helper methods
+
+// builder accumulates nodes and edges without duplicates
+type builder struct {
+	nodes map[string]models.GraphNode
+	edges map[[2]string]*models.GraphEdge
+}
+
+func newBuilder() *builder {
+	return &builder{
+		nodes: make(map[string]models.GraphNode),
+		edges: make(map[[2]string]*models.GraphEdge),
+	}
+}
+
+// link adds artifact → component → resource
+func (b *builder) link(artifact, env, component, resourceType, resource, source string) {
+	artifactID := "artifact/" + artifact
+	b.nodes[artifactID] = models.GraphNode{ID: artifactID, Kind: models.GraphArtifact, Name: artifact, Environment: env}
+
+	componentID := "component/" + artifact + "/" + component
+	b.nodes[componentID] = models.GraphNode{
+		ID:          componentID,
+		Kind:        models.GraphComponent,
+		Name:        artifact + "/" + component,
+		Type:        component,
+		Environment: env,
+	}
+
+	resourceID := ResourceID(resourceType, resource)
+	b.nodes[resourceID] = models.GraphNode{ID: resourceID, Kind: models.GraphResource, Name: resource, Type: resourceType}
+
+	b.edge(artifactID, componentID, source)
+	b.edge(componentID, resourceID, source)
+}
+
+func (b *builder) edge(from, to, source string) {
+	key := [2]string{from, to}
+	edge, ok := b.edges[key]
+	if !ok {
+		edge = &models.GraphEdge{From: from, To: to}
+		b.edges[key] = edge
+	}
+	for _, existing := range edge.Sources {
+		if existing == source {
+			return
+		}
+	}
+	edge.Sources = append(edge.Sources, source)
+}
+
+// graph returns the nodes by kind and ID and the edges by endpoints
+func (b *builder) graph() *models.DependencyGraph {
+	g := &models.DependencyGraph{Nodes: []models.GraphNode{}, Edges: []models.GraphEdge{}}
+	for _, node := range b.nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	for _, edge := range b.edges {
+		g.Edges = append(g.Edges, *edge)
+	}
+
+	rank := map[models.GraphNodeKind]int{models.GraphArtifact: 0, models.GraphComponent: 1, models.GraphResource: 2}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Kind != g.Nodes[j].Kind {
+			return rank[g.Nodes[i].Kind] < rank[g.Nodes[j].Kind]
+		}
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// valuesFiles lists the values.yaml of every chart in a known layer
+func (b *DefaultBuilder) valuesFiles() ([]string, error) {
+	var files []string
+	for _, layer := range models.KnownLayers {
+		pattern := filepath.Join(layout.ReposDir(b.baseDir), layout.EnvironmentRepoPrefix+"*", layer, "*", helm.ValuesFileName)
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// endpointHost strips the port from host[:port]
+func endpointHost(endpoint string) string {
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return endpoint
+}
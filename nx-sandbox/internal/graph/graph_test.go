@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+func setInventory(t *testing.T, baseDir, artifact, env string, values map[string]interface{}) {
+	t.Helper()
+	entry, err := inventory.Open(baseDir, artifact, env)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for path, value := range values {
+		if err := entry.Set(path, value); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := entry.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+}
+
+// setupTestEnv seeds a sandbox where two inventories share a Redis cluster
+// and a chart uses the same cluster and a DynamoDB table through its values
+func setupTestEnv(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	setInventory(t, baseDir, "nx-bff-web-payment", "dev1", map[string]interface{}{
+		"components.redis.enabled":     true,
+		"components.redis.cluster_id":  "payments",
+		"components.redis.endpoint":    "payments.abc.cache.amazonaws.com:6379",
+		"components.dynamo.enabled":    true,
+		"components.dynamo.table_name": "payments",
+	})
+	setInventory(t, baseDir, "nx-bff-web-offer-seat", "dev1", map[string]interface{}{
+		"components.redis.enabled":    true,
+		"components.redis.cluster_id": "payments",
+	})
+	setInventory(t, baseDir, "nx-bff-web-offer-seat", "sit1", map[string]interface{}{
+		"components.ecr.enabled": true,
+	})
+
+	values := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service"), "values.yaml")
+	os.WriteFile(values, []byte(`external:
+  redis:
+    enabled: true
+    endpoint: payments.abc.cache.amazonaws.com:6379
+  dynamodb:
+    enabled: true
+    table_name: orders
+`), 0644)
+	return baseDir
+}
+
+func TestBuild_SharedResources(t *testing.T) {
+	baseDir := setupTestEnv(t)
+
+	g, err := NewBuilder(baseDir).Build("dev1")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	impacts := Impact(g, ResourceID("redis", "payments"))
+	if len(impacts) != 3 {
+		t.Fatalf("Expected 3 artifacts on redis/payments, got %+v", impacts)
+	}
+	if impacts[0].Artifact != "nx-bff-test-service-dev1" || impacts[0].Sources[0] != models.GraphSourceHelm {
+		t.Errorf("Expected the chart's endpoint to resolve to the cluster, got %+v", impacts[0])
+	}
+
+	for _, node := range g.Nodes {
+		if node.Environment != "" && node.Environment != "dev1" {
+			t.Errorf("Expected only dev1 nodes, got %+v", node)
+		}
+	}
+}
+
+func TestFindResource(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	g, err := NewBuilder(baseDir).Build("")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if node, err := FindResource(g, "orders"); err != nil || node.ID != "dynamodb/orders" {
+		t.Errorf("Expected orders to resolve to dynamodb/orders, got %+v: %v", node, err)
+	}
+	if _, err := FindResource(g, "payments"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Expected payments to be ambiguous, got %v", err)
+	}
+	if node, err := FindResource(g, "dynamodb/payments"); err != nil || len(Impact(g, node.ID)) != 1 {
+		t.Errorf("Expected dynamodb/payments with one dependent, got %+v: %v", node, err)
+	}
+	if _, err := FindResource(g, "missing"); err == nil {
+		t.Error("Expected an unknown resource to fail")
+	}
+}
+
+func TestRender(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	g, err := NewBuilder(baseDir).Build("sit1")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	dot := DOT(g)
+	for _, expected := range []string{
+		`"artifact/nx-bff-web-offer-seat-sit1" [label="nx-bff-web-offer-seat-sit1", shape=box];`,
+		`"component/nx-bff-web-offer-seat-sit1/ecr" -> "ecr/nx-bff-web-offer-seat-sit1";`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("Expected DOT output to contain %s, got:\n%s", expected, dot)
+		}
+	}
+
+	expected := `flowchart LR
+  n0["nx-bff-web-offer-seat-sit1"]
+  n1(["ecr"])
+  n2[("ecr/nx-bff-web-offer-seat-sit1")]
+  n0 --> n1
+  n1 --> n2
+`
+	if mermaid := Mermaid(g); mermaid != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, mermaid)
+	}
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// DOT renders the graph in Graphviz format, artifacts on the left and
+// resources on the right
+func DOT(g *models.DependencyGraph) string {
+	shapes := map[models.GraphNodeKind]string{
+		models.GraphArtifact:  "box",
+		models.GraphComponent: "ellipse",
+		models.GraphResource:  "cylinder",
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", node.ID, label(node), shapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		style := ""
+		if !hasSource(edge, models.GraphSourceInventory) {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "  %q -> %q%s;\n", edge.From, edge.To, style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart
+func Mermaid(g *models.DependencyGraph) string {
+	ids := make(map[string]string)
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.ID] = id
+
+		text := strings.ReplaceAll(label(node), `"`, "#quot;")
+		switch node.Kind {
+		case models.GraphArtifact:
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, text)
+		case models.GraphComponent:
+			fmt.Fprintf(&b, "  %s([\"%s\"])\n", id, text)
+		default:
+			fmt.Fprintf(&b, "  %s[(\"%s\")]\n", id, text)
+		}
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if !hasSource(edge, models.GraphSourceInventory) {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+	return b.String()
+}
+
+// Helper methods
+
+func label(node models.GraphNode) string {
+	switch node.Kind {
+	case models.GraphComponent:
+		return node.Type
+	case models.GraphResource:
+		return node.ID
+	}
+	return node.Name
+}
+
+func hasSource(edge models.GraphEdge, source string) bool {
+	for _, s := range edge.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+const (
+	// ChartFileName is the Helm chart metadata file
+	ChartFileName = "Chart.yaml"
+
+	// ValuesFileName is the Helm values file
+	ValuesFileName = "values.yaml"
+)
+
+// FindChart resolves the chart directory of an artifact in an environment repository.
+// The artifact may be given with or without its environment suffix.
+func FindChart(baseDir, env, artifact string) (string, error) {
+	name := strings.TrimSuffix(artifact, "-"+env)
+
+	var candidates []string
+	if layer, ok := layout.LayerOf(name); ok {
+		candidates = append(candidates, layer)
+	}
+	candidates = append(candidates, models.KnownLayers...)
+
+	for _, layer := range candidates {
+		dir := layout.ChartDir(baseDir, env, layer, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("chart for '%s' not found in %s", name, filepath.Base(layout.EnvironmentRepo(baseDir, env)))
+}
+
+// ValuesPath returns the values.yaml path of an artifact in an environment
+func ValuesPath(baseDir, env, artifact string) (string, error) {
+	dir, err := FindChart(baseDir, env, artifact)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ValuesFileName), nil
+}
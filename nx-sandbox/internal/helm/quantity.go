@@ -0,0 +1,48 @@
+package helm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// quantitySuffixes maps Kubernetes quantity suffixes to their multipliers,
+// longest first so "Mi" is matched before "M"
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"m", 1e-3},
+	{"k", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+}
+
+// ParseQuantity parses a Kubernetes resource quantity such as "500m" CPU or
+// "512Mi" memory into base units (cores or bytes)
+func ParseQuantity(value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return 0, fmt.Errorf("empty quantity")
+	}
+
+	multiplier := 1.0
+	for _, q := range quantitySuffixes {
+		if strings.HasSuffix(s, q.suffix) {
+			s = strings.TrimSuffix(s, q.suffix)
+			multiplier = q.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid quantity %q", value)
+	}
+	return number * multiplier, nil
+}
@@ -0,0 +1,335 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/awsclient"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// notFoundCodes are the error codes each service returns for a missing resource
+var notFoundCodes = []string{
+	"NoSuchEntity",
+	"CacheClusterNotFound",
+	"ResourceNotFoundException",
+	"DBInstanceNotFound",
+	"RepositoryNotFoundException",
+}
+
+// componentNames lists every inventory component in schema order
+var componentNames = []string{"service_account", "redis", "dynamo", "rds", "ecr"}
+
+// DriftDetector defines the interface for comparing inventories with provisioned resources
+type DriftDetector interface {
+	Detect(artifact, env string) ([]models.DriftReport, error)
+}
+
+// DefaultDriftDetector looks up each inventory component on an AWS-compatible endpoint
+type DefaultDriftDetector struct {
+	baseDir string
+	clients *awsclient.Clients
+}
+
+// NewDriftDetector creates a new drift detector for a sandbox root
+func NewDriftDetector(baseDir string, opts awsclient.Options) DriftDetector {
+	return &DefaultDriftDetector{
+		baseDir: baseDir,
+		clients: awsclient.New(opts),
+	}
+}
+
+// Detect compares inventories with the resources found on the endpoint. An
+// empty artifact checks every artifact and an empty env checks every
+// environment. A report is returned for each inventory checked.
+func (d *DefaultDriftDetector) Detect(artifact, env string) ([]models.DriftReport, error) {
+	entries, err := d.selectEntries(artifact, env)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var reports []models.DriftReport
+	for _, entry := range entries {
+		report, err := d.check(ctx, entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", entry.Name(), err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Helper methods
+
+func (d *DefaultDriftDetector) selectEntries(artifact, env string) ([]*inventory.Entry, error) {
+	if artifact != "" && env != "" {
+		entry, err := inventory.Open(d.baseDir, artifact, env)
+		if err != nil {
+			return nil, err
+		}
+		return []*inventory.Entry{entry}, nil
+	}
+
+	entries, err := inventory.List(d.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*inventory.Entry
+	for _, entry := range entries {
+		if env != "" && entry.Environment() != env {
+			continue
+		}
+		if artifact != "" && entry.Name() != artifact && entry.Name() != layout.EnvironmentArtifactName(artifact, entry.Environment()) {
+			continue
+		}
+		selected = append(selected, entry)
+	}
+
+	if artifact != "" && len(selected) == 0 {
+		return nil, fmt.Errorf("inventory for artifact '%s' not found", artifact)
+	}
+	return selected, nil
+}
+
+func (d *DefaultDriftDetector) check(ctx context.Context, entry *inventory.Entry) (models.DriftReport, error) {
+	report := models.DriftReport{
+		Artifact:    entry.Name(),
+		Environment: entry.Environment(),
+		Path:        entry.Path,
+	}
+
+	enabled := make(map[string]bool)
+	for _, component := range entry.Inventory.Components.EnabledComponents() {
+		enabled[component] = true
+	}
+
+	missing, present := 0, 0
+	for _, component := range componentNames {
+		var name string
+		var found bool
+		var mismatches []string
+		var err error
+
+		switch component {
+		case "service_account":
+			name = RoleName(entry)
+			found, mismatches, err = d.checkServiceAccount(ctx, entry, name)
+		case "redis":
+			name = CacheClusterID(entry)
+			found, mismatches, err = d.checkRedis(ctx, entry, name)
+		case "dynamo":
+			name = TableName(entry)
+			found, mismatches, err = d.checkDynamo(ctx, entry, name)
+		case "rds":
+			name = DBInstanceID(entry)
+			found, mismatches, err = d.checkRDS(ctx, entry, name)
+		case "ecr":
+			name = RepositoryName(entry)
+			found, mismatches, err = d.checkECR(ctx, entry, name)
+		}
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", component, err)
+		}
+
+		switch {
+		case enabled[component] && !found:
+			missing++
+			report.Items = append(report.Items, models.DriftItem{
+				Component: component,
+				Resource:  name,
+				Kind:      models.DriftMissing,
+				Detail:    "component is enabled but the resource does not exist",
+			})
+		case !enabled[component] && found:
+			report.Items = append(report.Items, models.DriftItem{
+				Component: component,
+				Resource:  name,
+				Kind:      models.DriftExtra,
+				Detail:    "resource exists but the component is not enabled",
+			})
+		case found:
+			present++
+			for _, detail := range mismatches {
+				report.Items = append(report.Items, models.DriftItem{
+					Component: component,
+					Resource:  name,
+					Kind:      models.DriftMismatch,
+					Detail:    detail,
+				})
+			}
+		}
+	}
+
+	deployed := entry.Inventory.Infrastructure.Deployed
+	switch {
+	case deployed && missing > 0:
+		report.Items = append(report.Items, models.DriftItem{
+			Component: "infrastructure",
+			Resource:  "infrastructure.deployed",
+			Kind:      models.DriftMismatch,
+			Detail:    fmt.Sprintf("marked deployed but %d enabled component(s) are missing", missing),
+		})
+	case !deployed && len(enabled) > 0 && present == len(enabled):
+		report.Items = append(report.Items, models.DriftItem{
+			Component: "infrastructure",
+			Resource:  "infrastructure.deployed",
+			Kind:      models.DriftMismatch,
+			Detail:    "every enabled component exists but deployed is false",
+		})
+	}
+
+	return report, nil
+}
+
+func (d *DefaultDriftDetector) checkServiceAccount(ctx context.Context, entry *inventory.Entry, name string) (bool, []string, error) {
+	out, err := d.clients.IAM.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+	if found, err := lookupResult(err); !found {
+		return false, nil, err
+	}
+
+	var mismatches []string
+	recorded := entry.Inventory.Components.ServiceAccount.RoleARN
+	mismatches = appendMismatch(mismatches, "role_arn", recorded, aws.ToString(out.Role.Arn))
+	return true, mismatches, nil
+}
+
+func (d *DefaultDriftDetector) checkRedis(ctx context.Context, entry *inventory.Entry, id string) (bool, []string, error) {
+	out, err := d.clients.ElastiCache.DescribeCacheClusters(ctx, &elasticache.DescribeCacheClustersInput{
+		CacheClusterId:    aws.String(id),
+		ShowCacheNodeInfo: aws.Bool(true),
+	})
+	if found, err := lookupResult(err); !found {
+		return false, nil, err
+	}
+	if len(out.CacheClusters) == 0 {
+		return false, nil, nil
+	}
+
+	cluster := out.CacheClusters[0]
+	var endpoint string
+	for _, node := range cluster.CacheNodes {
+		if node.Endpoint != nil {
+			endpoint = formatEndpoint(node.Endpoint.Address, node.Endpoint.Port)
+			break
+		}
+	}
+
+	var mismatches []string
+	if engine := aws.ToString(cluster.Engine); engine != "" && engine != "redis" {
+		mismatches = append(mismatches, fmt.Sprintf("engine is %s, expected redis", engine))
+	}
+	mismatches = appendMismatch(mismatches, "endpoint", entry.Inventory.Components.Redis.Endpoint, endpoint)
+	return true, mismatches, nil
+}
+
+func (d *DefaultDriftDetector) checkDynamo(ctx context.Context, entry *inventory.Entry, name string) (bool, []string, error) {
+	out, err := d.clients.DynamoDB.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(name)})
+	if found, err := lookupResult(err); !found {
+		return false, nil, err
+	}
+
+	var partitionKey, sortKey string
+	for _, key := range out.Table.KeySchema {
+		if key.KeyType == dynamotypes.KeyTypeHash {
+			partitionKey = aws.ToString(key.AttributeName)
+		} else {
+			sortKey = aws.ToString(key.AttributeName)
+		}
+	}
+
+	dynamo := entry.Inventory.Components.Dynamo
+	expectedPartition := dynamo.PartitionKey
+	if expectedPartition == "" {
+		expectedPartition = DefaultPartitionKey
+	}
+
+	var mismatches []string
+	if partitionKey != expectedPartition {
+		mismatches = append(mismatches, fmt.Sprintf("partition_key is %s, inventory declares %s", partitionKey, expectedPartition))
+	}
+	if sortKey != dynamo.SortKey {
+		mismatches = append(mismatches, fmt.Sprintf("sort_key is %s, inventory declares %s", orNone(sortKey), orNone(dynamo.SortKey)))
+	}
+	mismatches = appendMismatch(mismatches, "table_arn", dynamo.TableARN, aws.ToString(out.Table.TableArn))
+	return true, mismatches, nil
+}
+
+func (d *DefaultDriftDetector) checkRDS(ctx context.Context, entry *inventory.Entry, id string) (bool, []string, error) {
+	out, err := d.clients.RDS.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(id)})
+	if found, err := lookupResult(err); !found {
+		return false, nil, err
+	}
+	if len(out.DBInstances) == 0 {
+		return false, nil, nil
+	}
+
+	instance := out.DBInstances[0]
+	var endpoint string
+	if instance.Endpoint != nil {
+		endpoint = formatEndpoint(instance.Endpoint.Address, instance.Endpoint.Port)
+	}
+
+	component := entry.Inventory.Components.RDS
+	var mismatches []string
+	mismatches = appendMismatch(mismatches, "instance_class", component.InstanceClass, aws.ToString(instance.DBInstanceClass))
+	mismatches = appendMismatch(mismatches, "engine", component.Engine, aws.ToString(instance.Engine))
+	mismatches = appendMismatch(mismatches, "endpoint", component.Endpoint, endpoint)
+	return true, mismatches, nil
+}
+
+func (d *DefaultDriftDetector) checkECR(ctx context.Context, entry *inventory.Entry, name string) (bool, []string, error) {
+	out, err := d.clients.ECR.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{RepositoryNames: []string{name}})
+	if found, err := lookupResult(err); !found {
+		return false, nil, err
+	}
+	if len(out.Repositories) == 0 {
+		return false, nil, nil
+	}
+
+	var mismatches []string
+	recorded := entry.Inventory.Components.ECR.RepositoryURI
+	mismatches = appendMismatch(mismatches, "repository_uri", recorded, aws.ToString(out.Repositories[0].RepositoryUri))
+	return true, mismatches, nil
+}
+
+// lookupResult reports whether a describe call found its resource. Not-found
+// errors are not returned as errors.
+func lookupResult(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	for _, code := range notFoundCodes {
+		if isErrorCode(err, code) {
+			return false, nil
+		}
+	}
+	return false, err
+}
+
+// appendMismatch records a difference when the inventory declares a value
+// that the provisioned resource does not have
+func appendMismatch(mismatches []string, field, declared, actual string) []string {
+	if declared == "" || declared == actual {
+		return mismatches
+	}
+	return append(mismatches, fmt.Sprintf("%s is %s, inventory declares %s", field, orNone(actual), declared))
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
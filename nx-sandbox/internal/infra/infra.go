@@ -0,0 +1,406 @@
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/awsclient"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	cachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	// DefaultRedisNodeType is used for Redis clusters
+	DefaultRedisNodeType = "cache.t3.micro"
+	// DefaultRDSInstanceClass is used when the inventory leaves instance_class empty
+	DefaultRDSInstanceClass = "db.t3.micro"
+	// DefaultRDSEngine is used when the inventory leaves engine empty
+	DefaultRDSEngine = "postgres"
+	// DefaultPartitionKey is used when the inventory leaves partition_key empty
+	DefaultPartitionKey = "id"
+
+	rdsMasterUsername = "nxadmin"
+	rdsStorageGB      = 20
+)
+
+// assumeRolePolicy lets EKS service accounts assume the artifact's role
+const assumeRolePolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"eks.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+
+// Provisioner defines the interface for creating an artifact's infrastructure
+type Provisioner interface {
+	Apply(artifact, env string) (*models.ProvisionResult, error)
+}
+
+// DefaultProvisioner creates inventory components through the AWS APIs of a
+// configurable endpoint, normally LocalStack
+type DefaultProvisioner struct {
+	baseDir string
+	opts    awsclient.Options
+	clients *awsclient.Clients
+}
+
+// NewProvisioner creates a new provisioner for a sandbox root
+func NewProvisioner(baseDir string, opts awsclient.Options) Provisioner {
+	return &DefaultProvisioner{
+		baseDir: baseDir,
+		opts:    opts,
+		clients: awsclient.New(opts),
+	}
+}
+
+// Apply creates the enabled components of an approved inventory and writes
+// the resulting endpoints back into it. Resources that already exist are
+// reused, so Apply can be re-run safely.
+func (p *DefaultProvisioner) Apply(artifact, env string) (*models.ProvisionResult, error) {
+	entry, err := inventory.Open(p.baseDir, artifact, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.Inventory.Infrastructure.Enabled {
+		return nil, fmt.Errorf("infrastructure for '%s' in %s is not approved (run: nx-sandbox approve %s --env %s)", entry.Name(), env, artifact, env)
+	}
+
+	components := entry.Inventory.Components.EnabledComponents()
+	if len(components) == 0 {
+		return nil, fmt.Errorf("'%s' in %s has no enabled components", entry.Name(), env)
+	}
+
+	result := &models.ProvisionResult{
+		Artifact:    entry.Name(),
+		Environment: env,
+		Endpoint:    p.opts.Endpoint,
+		Path:        entry.Path,
+	}
+
+	ctx := context.Background()
+	tags := resourceTags(entry)
+	for _, component := range components {
+		var resource models.ProvisionedResource
+		var updates map[string]interface{}
+
+		switch component {
+		case "service_account":
+			resource, updates, err = p.applyServiceAccount(ctx, entry, tags)
+		case "redis":
+			resource, updates, err = p.applyRedis(ctx, entry, tags)
+		case "dynamo":
+			resource, updates, err = p.applyDynamo(ctx, entry, tags)
+		case "rds":
+			resource, updates, err = p.applyRDS(ctx, entry, tags)
+		case "ecr":
+			resource, updates, err = p.applyECR(ctx, entry, tags)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision %s: %w", component, err)
+		}
+
+		for path, value := range updates {
+			if err := entry.Set(path, value); err != nil {
+				return nil, err
+			}
+		}
+		result.Resources = append(result.Resources, resource)
+	}
+
+	if err := entry.Set("infrastructure.deployed", true); err != nil {
+		return nil, err
+	}
+	if err := entry.Save(); err != nil {
+		return nil, fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	return result, nil
+}
+
+// Helper methods
+
+func (p *DefaultProvisioner) applyServiceAccount(ctx context.Context, entry *inventory.Entry, tags map[string]string) (models.ProvisionedResource, map[string]interface{}, error) {
+	name := RoleName(entry)
+	resource := models.ProvisionedResource{Component: "service_account", Name: name}
+
+	var iamTags []iamtypes.Tag
+	for _, key := range tagKeys {
+		iamTags = append(iamTags, iamtypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	out, err := p.clients.IAM.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 aws.String(name),
+		AssumeRolePolicyDocument: aws.String(assumeRolePolicy),
+		Tags:                     iamTags,
+	})
+	var role *iamtypes.Role
+	switch {
+	case err == nil:
+		resource.Created = true
+		role = out.Role
+	case isErrorCode(err, "EntityAlreadyExists"):
+		existing, err := p.clients.IAM.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+		if err != nil {
+			return resource, nil, err
+		}
+		role = existing.Role
+	default:
+		return resource, nil, err
+	}
+
+	resource.ARN = aws.ToString(role.Arn)
+	return resource, map[string]interface{}{
+		"components.service_account.name":     name,
+		"components.service_account.role_arn": resource.ARN,
+	}, nil
+}
+
+func (p *DefaultProvisioner) applyRedis(ctx context.Context, entry *inventory.Entry, tags map[string]string) (models.ProvisionedResource, map[string]interface{}, error) {
+	id := CacheClusterID(entry)
+	resource := models.ProvisionedResource{Component: "redis", Name: id}
+
+	var cacheTags []cachetypes.Tag
+	for _, key := range tagKeys {
+		cacheTags = append(cacheTags, cachetypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	_, err := p.clients.ElastiCache.CreateCacheCluster(ctx, &elasticache.CreateCacheClusterInput{
+		CacheClusterId: aws.String(id),
+		Engine:         aws.String("redis"),
+		CacheNodeType:  aws.String(DefaultRedisNodeType),
+		NumCacheNodes:  aws.Int32(1),
+		Tags:           cacheTags,
+	})
+	switch {
+	case err == nil:
+		resource.Created = true
+	case isErrorCode(err, "CacheClusterAlreadyExists"):
+	default:
+		return resource, nil, err
+	}
+
+	// Node endpoints are only returned when describing the cluster
+	described, err := p.clients.ElastiCache.DescribeCacheClusters(ctx, &elasticache.DescribeCacheClustersInput{
+		CacheClusterId:    aws.String(id),
+		ShowCacheNodeInfo: aws.Bool(true),
+	})
+	if err != nil {
+		return resource, nil, err
+	}
+	for _, cluster := range described.CacheClusters {
+		resource.ARN = aws.ToString(cluster.ARN)
+		for _, node := range cluster.CacheNodes {
+			if node.Endpoint != nil && resource.Endpoint == "" {
+				resource.Endpoint = formatEndpoint(node.Endpoint.Address, node.Endpoint.Port)
+			}
+		}
+	}
+
+	return resource, map[string]interface{}{
+		"components.redis.cluster_id": id,
+		"components.redis.endpoint":   resource.Endpoint,
+	}, nil
+}
+
+func (p *DefaultProvisioner) applyDynamo(ctx context.Context, entry *inventory.Entry, tags map[string]string) (models.ProvisionedResource, map[string]interface{}, error) {
+	dynamo := entry.Inventory.Components.Dynamo
+	name := TableName(entry)
+	partitionKey := dynamo.PartitionKey
+	if partitionKey == "" {
+		partitionKey = DefaultPartitionKey
+	}
+	resource := models.ProvisionedResource{Component: "dynamo", Name: name}
+
+	attributes := []dynamotypes.AttributeDefinition{
+		{AttributeName: aws.String(partitionKey), AttributeType: dynamotypes.ScalarAttributeTypeS},
+	}
+	keySchema := []dynamotypes.KeySchemaElement{
+		{AttributeName: aws.String(partitionKey), KeyType: dynamotypes.KeyTypeHash},
+	}
+	if dynamo.SortKey != "" {
+		attributes = append(attributes, dynamotypes.AttributeDefinition{AttributeName: aws.String(dynamo.SortKey), AttributeType: dynamotypes.ScalarAttributeTypeS})
+		keySchema = append(keySchema, dynamotypes.KeySchemaElement{AttributeName: aws.String(dynamo.SortKey), KeyType: dynamotypes.KeyTypeRange})
+	}
+
+	var dynamoTags []dynamotypes.Tag
+	for _, key := range tagKeys {
+		dynamoTags = append(dynamoTags, dynamotypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	out, err := p.clients.DynamoDB.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String(name),
+		AttributeDefinitions: attributes,
+		KeySchema:            keySchema,
+		BillingMode:          dynamotypes.BillingModePayPerRequest,
+		Tags:                 dynamoTags,
+	})
+	switch {
+	case err == nil:
+		resource.Created = true
+		resource.ARN = aws.ToString(out.TableDescription.TableArn)
+	case isErrorCode(err, "ResourceInUseException"):
+		existing, err := p.clients.DynamoDB.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(name)})
+		if err != nil {
+			return resource, nil, err
+		}
+		resource.ARN = aws.ToString(existing.Table.TableArn)
+	default:
+		return resource, nil, err
+	}
+
+	return resource, map[string]interface{}{
+		"components.dynamo.table_name":    name,
+		"components.dynamo.partition_key": partitionKey,
+		"components.dynamo.table_arn":     resource.ARN,
+	}, nil
+}
+
+func (p *DefaultProvisioner) applyRDS(ctx context.Context, entry *inventory.Entry, tags map[string]string) (models.ProvisionedResource, map[string]interface{}, error) {
+	component := entry.Inventory.Components.RDS
+	id := DBInstanceID(entry)
+	class := component.InstanceClass
+	if class == "" {
+		class = DefaultRDSInstanceClass
+	}
+	engine := component.Engine
+	if engine == "" {
+		engine = DefaultRDSEngine
+	}
+	resource := models.ProvisionedResource{Component: "rds", Name: id}
+
+	var rdsTags []rdstypes.Tag
+	for _, key := range tagKeys {
+		rdsTags = append(rdsTags, rdstypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	// The master password is throwaway: sandbox databases are recreated rather than recovered
+	password, err := generatePassword()
+	if err != nil {
+		return resource, nil, err
+	}
+
+	out, err := p.clients.RDS.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+		DBInstanceIdentifier: aws.String(id),
+		DBInstanceClass:      aws.String(class),
+		Engine:               aws.String(engine),
+		MasterUsername:       aws.String(rdsMasterUsername),
+		MasterUserPassword:   aws.String(password),
+		AllocatedStorage:     aws.Int32(rdsStorageGB),
+		Tags:                 rdsTags,
+	})
+	var instance *rdstypes.DBInstance
+	switch {
+	case err == nil:
+		resource.Created = true
+		instance = out.DBInstance
+	case isErrorCode(err, "DBInstanceAlreadyExists"):
+		existing, err := p.clients.RDS.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(id)})
+		if err != nil {
+			return resource, nil, err
+		}
+		if len(existing.DBInstances) > 0 {
+			instance = &existing.DBInstances[0]
+		}
+	default:
+		return resource, nil, err
+	}
+
+	if instance != nil {
+		resource.ARN = aws.ToString(instance.DBInstanceArn)
+		if instance.Endpoint != nil {
+			resource.Endpoint = formatEndpoint(instance.Endpoint.Address, instance.Endpoint.Port)
+		}
+	}
+
+	return resource, map[string]interface{}{
+		"components.rds.instance_class": class,
+		"components.rds.engine":         engine,
+		"components.rds.endpoint":       resource.Endpoint,
+	}, nil
+}
+
+func (p *DefaultProvisioner) applyECR(ctx context.Context, entry *inventory.Entry, tags map[string]string) (models.ProvisionedResource, map[string]interface{}, error) {
+	name := RepositoryName(entry)
+	resource := models.ProvisionedResource{Component: "ecr", Name: name}
+
+	var ecrTags []ecrtypes.Tag
+	for _, key := range tagKeys {
+		ecrTags = append(ecrTags, ecrtypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	out, err := p.clients.ECR.CreateRepository(ctx, &ecr.CreateRepositoryInput{
+		RepositoryName: aws.String(name),
+		Tags:           ecrTags,
+	})
+	var repository *ecrtypes.Repository
+	switch {
+	case err == nil:
+		resource.Created = true
+		repository = out.Repository
+	case isErrorCode(err, "RepositoryAlreadyExistsException"):
+		existing, err := p.clients.ECR.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{RepositoryNames: []string{name}})
+		if err != nil {
+			return resource, nil, err
+		}
+		if len(existing.Repositories) > 0 {
+			repository = &existing.Repositories[0]
+		}
+	default:
+		return resource, nil, err
+	}
+
+	if repository != nil {
+		resource.ARN = aws.ToString(repository.RepositoryArn)
+		resource.Endpoint = aws.ToString(repository.RepositoryUri)
+	}
+
+	return resource, map[string]interface{}{
+		"components.ecr.repository_name": name,
+		"components.ecr.repository_uri":  resource.Endpoint,
+	}, nil
+}
+
+// tagKeys fixes the order tags are sent in
+var tagKeys = []string{"ManagedBy", "Artifact", "Environment"}
+
+func resourceTags(entry *inventory.Entry) map[string]string {
+	return map[string]string{
+		"ManagedBy":   "nx-sandbox",
+		"Artifact":    entry.Name(),
+		"Environment": entry.Environment(),
+	}
+}
+
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+func formatEndpoint(address *string, port *int32) string {
+	if address == nil {
+		return ""
+	}
+	if port == nil {
+		return *address
+	}
+	return fmt.Sprintf("%s:%d", *address, *port)
+}
+
+func generatePassword() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
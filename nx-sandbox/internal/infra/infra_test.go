@@ -0,0 +1,234 @@
+package infra
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/awsclient"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/awsfake"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+const testInventory = `schema_version: "1.0"
+
+artifact_metadata:
+  artifact_name: "nx-bff-web-payment-dev1"
+  layer: "bff"
+
+infrastructure:
+  enabled: true
+  deployed: false
+  component: "service_account"
+  environment: "dev1"
+
+components:
+  service_account:
+    name: "sa-nx-bff-web-payment-dev1"
+    namespace: "nexus-dev1"
+    enabled: true
+  redis:
+    name: ""
+    cluster_id: ""
+    endpoint: ""
+    enabled: true
+  dynamo:
+    table_name: "nx-payment-sessions"
+    partition_key: "session_id"
+    sort_key: "created_at"
+    enabled: true
+  rds:
+    instance_class: ""
+    engine: ""
+    enabled: true
+  ecr:
+    repository_name: "nx-bff-web-payment-dev1"
+    image_tag: "latest"
+    enabled: true
+`
+
+// setupTestEnv creates a sandbox with one approved inventory and a fake AWS endpoint
+func setupTestEnv(t *testing.T, content string) (string, *awsfake.Server) {
+	tmpDir := t.TempDir()
+
+	artifactDir := filepath.Join(tmpDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1")
+	os.MkdirAll(artifactDir, 0755)
+	os.WriteFile(filepath.Join(artifactDir, "nx-app-inventory.yaml"), []byte(content), 0644)
+
+	server := awsfake.NewServer()
+	t.Cleanup(server.Close)
+
+	return tmpDir, server
+}
+
+func testOptions(server *awsfake.Server) awsclient.Options {
+	return awsclient.Options{Endpoint: server.URL, Region: awsfake.Region}
+}
+
+func TestApply(t *testing.T) {
+	baseDir, server := setupTestEnv(t, testInventory)
+	provisioner := NewProvisioner(baseDir, testOptions(server))
+
+	result, err := provisioner.Apply("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(result.Resources) != 5 {
+		t.Fatalf("Expected 5 resources, got %d", len(result.Resources))
+	}
+	for _, resource := range result.Resources {
+		if !resource.Created {
+			t.Errorf("Expected %s to be created", resource.Component)
+		}
+	}
+
+	table, ok := server.Tables()["nx-payment-sessions"]
+	if !ok || table.PartitionKey != "session_id" || table.SortKey != "created_at" {
+		t.Errorf("Unexpected table: %+v", table)
+	}
+	if roles := server.Roles(); len(roles) != 1 || roles[0] != "sa-nx-bff-web-payment-dev1" {
+		t.Errorf("Unexpected roles: %v", roles)
+	}
+	if _, ok := server.CacheClusters()["redis-nx-bff-web-payment-dev1"]; !ok {
+		t.Error("Expected Redis cluster to be created")
+	}
+	if instance := server.DBInstances()["db-nx-bff-web-payment-dev1"]; instance.Engine != DefaultRDSEngine {
+		t.Errorf("Expected %s instance, got %+v", DefaultRDSEngine, instance)
+	}
+
+	entry, err := inventory.Load(result.Path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	components := entry.Inventory.Components
+	if !entry.Inventory.Infrastructure.Deployed {
+		t.Error("Expected infrastructure.deployed to be true")
+	}
+	if components.Redis.Endpoint != "redis-nx-bff-web-payment-dev1.cache.localhost:6379" {
+		t.Errorf("Unexpected Redis endpoint: %s", components.Redis.Endpoint)
+	}
+	if components.RDS.Endpoint == "" || components.ECR.RepositoryURI == "" || components.ServiceAccount.RoleARN == "" {
+		t.Errorf("Expected endpoints to be written back: %+v", components)
+	}
+}
+
+func TestApply_Idempotent(t *testing.T) {
+	baseDir, server := setupTestEnv(t, testInventory)
+	server.AddRepository("nx-bff-web-payment-dev1")
+	provisioner := NewProvisioner(baseDir, testOptions(server))
+
+	if _, err := provisioner.Apply("nx-bff-web-payment", "dev1"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	result, err := provisioner.Apply("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Second apply failed: %v", err)
+	}
+
+	for _, resource := range result.Resources {
+		if resource.Created {
+			t.Errorf("Expected existing %s to be reused", resource.Component)
+		}
+	}
+}
+
+func TestApply_NotApproved(t *testing.T) {
+	baseDir, server := setupTestEnv(t, testInventory)
+	path := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1", "nx-app-inventory.yaml")
+	entry, _ := inventory.Load(path)
+	entry.Set("infrastructure.enabled", false)
+	entry.Save()
+
+	provisioner := NewProvisioner(baseDir, testOptions(server))
+	if _, err := provisioner.Apply("nx-bff-web-payment", "dev1"); err == nil {
+		t.Error("Expected unapproved inventory to be rejected")
+	}
+	if len(server.Roles()) != 0 {
+		t.Error("No resources should be created before approval")
+	}
+}
+
+func TestDetect_InSync(t *testing.T) {
+	baseDir, server := setupTestEnv(t, testInventory)
+	if _, err := NewProvisioner(baseDir, testOptions(server)).Apply("nx-bff-web-payment", "dev1"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	reports, err := NewDriftDetector(baseDir, testOptions(server)).Detect("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(reports))
+	}
+	if reports[0].HasDrift() {
+		t.Errorf("Expected no drift, got %+v", reports[0].Items)
+	}
+}
+
+func TestDetect_Missing(t *testing.T) {
+	deployed := strings.Replace(testInventory, "deployed: false", "deployed: true", 1)
+	baseDir, server := setupTestEnv(t, deployed)
+	server.AddRole("sa-nx-bff-web-payment-dev1")
+
+	reports, err := NewDriftDetector(baseDir, testOptions(server)).Detect("", "")
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+
+	kinds := make(map[string]models.DriftKind)
+	for _, item := range reports[0].Items {
+		kinds[item.Component] = item.Kind
+	}
+	for _, component := range []string{"redis", "dynamo", "rds", "ecr"} {
+		if kinds[component] != models.DriftMissing {
+			t.Errorf("Expected %s to be missing, got %q", component, kinds[component])
+		}
+	}
+	if _, ok := kinds["service_account"]; ok {
+		t.Error("Existing role should not be reported")
+	}
+	if kinds["infrastructure"] != models.DriftMismatch {
+		t.Error("Expected infrastructure.deployed to be reported")
+	}
+}
+
+func TestDetect_ExtraAndMismatch(t *testing.T) {
+	content := strings.Replace(testInventory, "    engine: \"\"\n    enabled: true", "    engine: \"\"\n    enabled: false", 1)
+	baseDir, server := setupTestEnv(t, content)
+	server.AddDBInstance(awsfake.DBInstance{ID: "db-nx-bff-web-payment-dev1", Class: "db.t3.micro", Engine: "postgres"})
+	server.AddTable(awsfake.Table{Name: "nx-payment-sessions", PartitionKey: "id"})
+
+	reports, err := NewDriftDetector(baseDir, testOptions(server)).Detect("nx-bff-web-payment-dev1", "")
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+
+	var extra, mismatch []string
+	for _, item := range reports[0].Items {
+		switch item.Kind {
+		case models.DriftExtra:
+			extra = append(extra, item.Component)
+		case models.DriftMismatch:
+			mismatch = append(mismatch, item.Detail)
+		}
+	}
+	if strings.Join(extra, ",") != "rds" {
+		t.Errorf("Expected rds to be extra, got %v", extra)
+	}
+	if len(mismatch) != 2 {
+		t.Errorf("Expected partition and sort key mismatches, got %v", mismatch)
+	}
+}
+
+func TestDetect_UnknownArtifact(t *testing.T) {
+	baseDir, server := setupTestEnv(t, testInventory)
+
+	if _, err := NewDriftDetector(baseDir, testOptions(server)).Detect("nx-bff-missing", ""); err == nil {
+		t.Error("Expected unknown artifact to fail")
+	}
+}
@@ -0,0 +1,44 @@
+package infra
+
+import "github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+
+// RoleName returns the IAM role backing an artifact's service account
+func RoleName(entry *inventory.Entry) string {
+	if name := entry.Inventory.Components.ServiceAccount.Name; name != "" {
+		return name
+	}
+	return "sa-" + entry.Name()
+}
+
+// CacheClusterID returns the ElastiCache cluster of an artifact's Redis component
+func CacheClusterID(entry *inventory.Entry) string {
+	redis := entry.Inventory.Components.Redis
+	if redis.ClusterID != "" {
+		return redis.ClusterID
+	}
+	if redis.Name != "" {
+		return redis.Name
+	}
+	return "redis-" + entry.Name()
+}
+
+// TableName returns the DynamoDB table of an artifact's Dynamo component
+func TableName(entry *inventory.Entry) string {
+	if name := entry.Inventory.Components.Dynamo.TableName; name != "" {
+		return name
+	}
+	return entry.Name()
+}
+
+// DBInstanceID returns the RDS instance of an artifact's RDS component
+func DBInstanceID(entry *inventory.Entry) string {
+	return "db-" + entry.Name()
+}
+
+// RepositoryName returns the ECR repository of an artifact's ECR component
+func RepositoryName(entry *inventory.Entry) string {
+	if name := entry.Inventory.Components.ECR.RepositoryName; name != "" {
+		return name
+	}
+	return entry.Name()
+}
@@ -0,0 +1,124 @@
+package inventory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+)
+
+// Entry is a loaded nx-app-inventory.yaml together with its editable document
+type Entry struct {
+	Path      string
+	Layer     string
+	Inventory models.AppInventory
+	doc       *yamldoc.Document
+}
+
+// Load reads an inventory file
+func Load(path string) (*Entry, error) {
+	doc, err := yamldoc.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Path:  path,
+		Layer: filepath.Base(filepath.Dir(filepath.Dir(path))),
+		doc:   doc,
+	}
+	if err := doc.Decode(&entry.Inventory); err != nil {
+		return nil, fmt.Errorf("invalid inventory %s: %w", path, err)
+	}
+
+	return entry, nil
+}
+
+// Find resolves the inventory file of an artifact in an environment.
+// The artifact may be given with or without its environment suffix.
+func Find(baseDir, artifact, env string) (string, error) {
+	name := layout.EnvironmentArtifactName(artifact, env)
+
+	var candidates []string
+	if layer, ok := layout.LayerOf(name); ok {
+		candidates = append(candidates, layer)
+	}
+	candidates = append(candidates, models.KnownLayers...)
+
+	for _, layer := range candidates {
+		path := layout.InventoryFile(baseDir, layer, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	if env == "" {
+		return "", fmt.Errorf("inventory for artifact '%s' not found", artifact)
+	}
+	return "", fmt.Errorf("inventory for artifact '%s' in %s not found", artifact, env)
+}
+
+// Open finds and loads the inventory of an artifact in an environment
+func Open(baseDir, artifact, env string) (*Entry, error) {
+	path, err := Find(baseDir, artifact, env)
+	if err != nil {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// List loads every inventory in the sandbox, sorted by path
+func List(baseDir string) ([]*Entry, error) {
+	pattern := filepath.Join(layout.InventoryRoot(baseDir), "*", "*", layout.InventoryFileName)
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	var entries []*Entry
+	for _, path := range matches {
+		entry, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Name returns the artifact name, falling back to the directory name
+func (e *Entry) Name() string {
+	if e.Inventory.ArtifactMetadata.ArtifactName != "" {
+		return e.Inventory.ArtifactMetadata.ArtifactName
+	}
+	return filepath.Base(filepath.Dir(e.Path))
+}
+
+// Environment returns the environment the inventory entry belongs to
+func (e *Entry) Environment() string {
+	return e.Inventory.Infrastructure.Environment
+}
+
+// Set updates a dot-separated field and refreshes the typed inventory
+func (e *Entry) Set(path string, value interface{}) error {
+	if err := e.doc.Set(path, value); err != nil {
+		return err
+	}
+	return e.doc.Decode(&e.Inventory)
+}
+
+// Save writes the inventory back to disk
+func (e *Entry) Save() error {
+	return e.doc.Save()
+}
+
+// Bytes renders the inventory as it would be saved
+func (e *Entry) Bytes() ([]byte, error) {
+	return e.doc.Bytes()
+}
@@ -0,0 +1,126 @@
+package layout
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+const (
+	// InventoryFileName is the file holding an artifact's inventory entry
+	InventoryFileName = "nx-app-inventory.yaml"
+
+	// EnvironmentRepoPrefix prefixes every Helm environment repository
+	EnvironmentRepoPrefix = "nx-bolt-environment-"
+
+	// StateDirName is the directory holding sandbox-managed state
+	StateDirName = ".nx-sandbox"
+
+	// InfrastructureRepoName is the Terraform repository
+	InfrastructureRepoName = "nexus-infrastructure"
+
+	// MeshDirName is the Kuma mesh chart of each environment repository
+	MeshDirName = "kuma-resources-default-mesh"
+
+	// AuditLogFileName is the append-only audit log inside the state directory
+	AuditLogFileName = "audit.jsonl"
+)
+
+// ReposDir returns the directory holding the mirrored repositories
+func ReposDir(baseDir string) string {
+	return filepath.Join(baseDir, "repos")
+}
+
+// StateDir returns the directory holding sandbox-managed state
+func StateDir(baseDir string) string {
+	return filepath.Join(baseDir, StateDirName)
+}
+
+// AuditLogFile returns the append-only audit log. It lives in the state
+// directory but is never captured or restored by snapshots.
+func AuditLogFile(baseDir string) string {
+	return filepath.Join(StateDir(baseDir), AuditLogFileName)
+}
+
+// InventoryRoot returns the nx-artifacts directory of the inventory repository
+func InventoryRoot(baseDir string) string {
+	return filepath.Join(ReposDir(baseDir), "nx-artifacts-inventory", "nx-artifacts")
+}
+
+// InventoryDir returns the directory holding an artifact's inventory entry
+func InventoryDir(baseDir, layer, artifactName string) string {
+	return filepath.Join(InventoryRoot(baseDir), layer, artifactName)
+}
+
+// InventoryFile returns the path of an artifact's nx-app-inventory.yaml
+func InventoryFile(baseDir, layer, artifactName string) string {
+	return filepath.Join(InventoryDir(baseDir, layer, artifactName), InventoryFileName)
+}
+
+// EnvironmentRepo returns the Helm repository directory for an environment
+func EnvironmentRepo(baseDir, env string) string {
+	return filepath.Join(ReposDir(baseDir), EnvironmentRepoPrefix+env)
+}
+
+// ChartDir returns the Helm chart directory of a service in an environment
+func ChartDir(baseDir, env, layer, service string) string {
+	return filepath.Join(EnvironmentRepo(baseDir, env), layer, service)
+}
+
+// MeshValuesFile returns the values.yaml of an environment's Kuma mesh chart
+func MeshValuesFile(baseDir, env string) string {
+	return filepath.Join(EnvironmentRepo(baseDir, env), MeshDirName, "values.yaml")
+}
+
+// InfrastructureRepo returns the Terraform repository directory
+func InfrastructureRepo(baseDir string) string {
+	return filepath.Join(ReposDir(baseDir), InfrastructureRepoName)
+}
+
+// TerraformComponentDir returns the directory of a Terraform component
+func TerraformComponentDir(baseDir, name string) string {
+	return filepath.Join(InfrastructureRepo(baseDir), "components", name)
+}
+
+// TerraformModulesDir returns the directory of the shared Terraform modules
+func TerraformModulesDir(baseDir string) string {
+	return filepath.Join(InfrastructureRepo(baseDir), "modules")
+}
+
+// LayerOf extracts the layer from an artifact name of the form nx-<layer>-<service>
+func LayerOf(name string) (string, bool) {
+	parts := strings.SplitN(name, "-", 3)
+	if len(parts) < 3 || parts[0] != "nx" {
+		return "", false
+	}
+
+	for _, layer := range models.KnownLayers {
+		if parts[1] == layer {
+			return layer, true
+		}
+	}
+
+	return "", false
+}
+
+// EnvironmentArtifactName returns the per-environment inventory name of an artifact.
+// Names that already carry the environment suffix are returned unchanged.
+func EnvironmentArtifactName(name, env string) string {
+	if env == "" || strings.HasSuffix(name, "-"+env) {
+		return name
+	}
+	return name + "-" + env
+}
+
+// SecretsAllowlistFile returns the allowlist of known mock credentials used
+// by the secret scanner
+func SecretsAllowlistFile(baseDir string) string {
+	return filepath.Join(baseDir, "config", "secrets-allowlist.yaml")
+}
+
+// PoliciesFile returns the policy-as-code rules evaluated by policy check
+// and validate
+func PoliciesFile(baseDir string) string {
+	return filepath.Join(baseDir, "config", "policies.yaml")
+}
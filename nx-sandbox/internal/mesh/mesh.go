@@ -0,0 +1,305 @@
+package mesh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+)
+
+// section is the mesh values key holding external services
+const section = "externalServices"
+
+const (
+	// RedisPort is used when a Redis endpoint does not name a port
+	RedisPort = 6379
+
+	// DynamoPort is the HTTPS port of the DynamoDB regional endpoint
+	DynamoPort = 443
+)
+
+// Protocols lists the protocols an external service may use
+var Protocols = []string{"tcp", "http", "http2", "grpc"}
+
+var (
+	namePattern  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9._]*[a-z0-9])?$`)
+	labelPattern = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9]*[A-Za-z0-9])?$`)
+)
+
+// Manager defines the interface for managing the mesh external services
+type Manager interface {
+	List(env string) ([]models.ExternalService, error)
+	Add(artifact, env string) (string, []models.ExternalService, error)
+	Remove(artifact, env string) (string, []models.ExternalService, error)
+}
+
+// DefaultManager edits externalServices in the kuma-resources-default-mesh
+// values of the environment repositories
+type DefaultManager struct {
+	baseDir string
+}
+
+// NewManager creates a new mesh manager
+func NewManager(baseDir string) Manager {
+	return &DefaultManager{
+		baseDir: baseDir,
+	}
+}
+
+// List returns the external services of an environment in file order
+func (m *DefaultManager) List(env string) ([]models.ExternalService, error) {
+	_, values, err := m.load(env)
+	if err != nil {
+		return nil, err
+	}
+	return values.ExternalServices, nil
+}
+
+// Add generates the external services an artifact's enabled components need
+// and writes them, replacing entries with the same name. Nothing is written
+// if the resulting list is invalid.
+func (m *DefaultManager) Add(artifact, env string) (string, []models.ExternalService, error) {
+	entry, err := inventory.Open(m.baseDir, artifact, env)
+	if err != nil {
+		return "", nil, err
+	}
+	cfg, err := config.Load(m.baseDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	generated, err := Generate(entry.Inventory, cfg.AWS.Region)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(generated) == 0 {
+		return "", nil, fmt.Errorf("artifact '%s' has no redis or dynamo component enabled in %s", artifact, env)
+	}
+
+	doc, values, err := m.load(env)
+	if err != nil {
+		return "", nil, err
+	}
+
+	services := values.ExternalServices
+	for _, service := range generated {
+		replaced := false
+		for i := range services {
+			if services[i].Name == service.Name {
+				services[i] = service
+				replaced = true
+			}
+		}
+		if !replaced {
+			services = append(services, service)
+		}
+	}
+
+	if err := save(doc, services); err != nil {
+		return "", nil, err
+	}
+	return doc.Path, generated, nil
+}
+
+// Remove deletes the external services generated for an artifact, or the
+// entry named artifact. Nothing matching is reported as an error.
+func (m *DefaultManager) Remove(artifact, env string) (string, []models.ExternalService, error) {
+	doc, values, err := m.load(env)
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := layout.EnvironmentArtifactName(artifact, env)
+	var kept, removed []models.ExternalService
+	for _, service := range values.ExternalServices {
+		if service.Name == artifact || service.Artifact == name {
+			removed = append(removed, service)
+			continue
+		}
+		kept = append(kept, service)
+	}
+	if len(removed) == 0 {
+		return "", nil, fmt.Errorf("no external services for '%s' in %s", artifact, env)
+	}
+
+	if err := save(doc, kept); err != nil {
+		return "", nil, err
+	}
+	return doc.Path, removed, nil
+}
+
+// Generate returns the external services for the enabled Redis and DynamoDB
+// components of an inventory. Redis uses the recorded endpoint, or the
+// cluster's ElastiCache host before infrastructure is applied; DynamoDB uses
+// the regional endpoint.
+func Generate(inv models.AppInventory, region string) ([]models.ExternalService, error) {
+	artifact := inv.ArtifactMetadata.ArtifactName
+	components := inv.Components
+	var services []models.ExternalService
+
+	if components.Redis.Enabled {
+		host, port, err := splitEndpoint(components.Redis.Endpoint, RedisPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis endpoint for '%s': %w", artifact, err)
+		}
+		if host == "" {
+			cluster := components.Redis.ClusterID
+			if cluster == "" {
+				cluster = components.Redis.Name
+			}
+			if cluster == "" {
+				return nil, fmt.Errorf("redis for '%s' has no endpoint or cluster_id", artifact)
+			}
+			host = cluster + ".cache.amazonaws.com"
+		}
+		services = append(services, models.ExternalService{
+			Name:      artifact + "-redis",
+			Artifact:  artifact,
+			Component: "redis",
+			Protocol:  "tcp",
+			Host:      host,
+			Port:      port,
+			TLS:       true,
+		})
+	}
+
+	if components.Dynamo.Enabled {
+		services = append(services, models.ExternalService{
+			Name:      artifact + "-dynamo",
+			Artifact:  artifact,
+			Component: "dynamo",
+			Protocol:  "tcp",
+			Host:      fmt.Sprintf("dynamodb.%s.amazonaws.com", region),
+			Port:      DynamoPort,
+			TLS:       true,
+		})
+	}
+
+	return services, nil
+}
+
+// Check validates a list of external services: names must be unique DNS
+// names, hosts a hostname or IP address without a port, and ports in range
+func Check(services []models.ExternalService) []models.MeshProblem {
+	var problems []models.MeshProblem
+	add := func(index int, field, format string, args ...interface{}) {
+		problems = append(problems, models.MeshProblem{Index: index, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	seen := make(map[string]int)
+	for i, service := range services {
+		switch {
+		case service.Name == "":
+			add(i, "name", "name is required")
+		case len(service.Name) > 253 || !namePattern.MatchString(service.Name):
+			add(i, "name", "name %q must be lowercase letters, digits, '-', '.' or '_'", service.Name)
+		}
+		if first, ok := seen[service.Name]; ok && service.Name != "" {
+			add(i, "name", "duplicate name %q (first used by entry %d)", service.Name, first)
+		} else {
+			seen[service.Name] = i
+		}
+
+		if err := checkHost(service.Host); err != nil {
+			add(i, "host", "%v", err)
+		}
+		if service.Port < 1 || service.Port > 65535 {
+			add(i, "port", "port %d must be between 1 and 65535", service.Port)
+		}
+		if !isProtocol(service.Protocol) {
+			add(i, "protocol", "unknown protocol %q (expected one of %s)", service.Protocol, strings.Join(Protocols, ", "))
+		}
+	}
+	return problems
+}
+
+// Helper methods
+
+func (m *DefaultManager) load(env string) (*yamldoc.Document, *models.MeshValues, error) {
+	path := layout.MeshValuesFile(m.baseDir, env)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("mesh values for %s not found at %s", env, path)
+	}
+
+	doc, err := yamldoc.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var values models.MeshValues
+	if err := doc.Decode(&values); err != nil {
+		return nil, nil, fmt.Errorf("invalid mesh values %s: %w", path, err)
+	}
+	return doc, &values, nil
+}
+
+func save(doc *yamldoc.Document, services []models.ExternalService) error {
+	if problems := Check(services); len(problems) > 0 {
+		var messages []string
+		for _, problem := range problems {
+			messages = append(messages, fmt.Sprintf("%s[%d].%s: %s", section, problem.Index, problem.Field, problem.Message))
+		}
+		return fmt.Errorf("invalid external services:\n  %s", strings.Join(messages, "\n  "))
+	}
+
+	if services == nil {
+		services = []models.ExternalService{}
+	}
+	if err := doc.Set(section, services); err != nil {
+		return err
+	}
+	return doc.Save()
+}
+
+// splitEndpoint splits host[:port], using defaultPort when no port is given
+func splitEndpoint(endpoint string, defaultPort int) (string, int, error) {
+	if endpoint == "" {
+		return "", defaultPort, nil
+	}
+	host, portText, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint, defaultPort, nil
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil {
+		return "", 0, fmt.Errorf("port %q is not a number", portText)
+	}
+	return host, port, nil
+}
+
+func checkHost(host string) error {
+	if host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if strings.Contains(host, ":") {
+		return fmt.Errorf("host %q must not include a port", host)
+	}
+	if len(host) > 253 {
+		return fmt.Errorf("host %q is longer than 253 characters", host)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) > 63 || !labelPattern.MatchString(label) {
+			return fmt.Errorf("host %q is not a valid hostname", host)
+		}
+	}
+	return nil
+}
+
+func isProtocol(protocol string) bool {
+	for _, known := range Protocols {
+		if protocol == known {
+			return true
+		}
+	}
+	return false
+}
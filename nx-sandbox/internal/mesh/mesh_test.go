@@ -0,0 +1,167 @@
+package mesh
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/golden"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+// setupTestEnv seeds a sandbox and enables redis and dynamo for nx-bff-web-payment in dev1
+func setupTestEnv(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	entry, err := inventory.Open(baseDir, "nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for path, value := range map[string]interface{}{
+		"components.redis.cluster_id": "payments",
+		"components.redis.enabled":    true,
+		"components.dynamo.enabled":   true,
+	} {
+		if err := entry.Set(path, value); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := entry.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	return baseDir
+}
+
+func TestAddListRemove(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	manager := NewManager(baseDir)
+
+	path, added, err := manager.Add("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if path != layout.MeshValuesFile(baseDir, "dev1") {
+		t.Errorf("Expected the dev1 mesh values, got %s", path)
+	}
+	if len(added) != 2 {
+		t.Fatalf("Expected redis and dynamo entries, got %+v", added)
+	}
+	if added[0].Host != "payments.cache.amazonaws.com" || added[0].Port != RedisPort {
+		t.Errorf("Unexpected redis entry %+v", added[0])
+	}
+	if added[1].Host != "dynamodb.us-east-1.amazonaws.com" || added[1].Port != DynamoPort {
+		t.Errorf("Unexpected dynamo entry %+v", added[1])
+	}
+
+	// Adding again replaces the entries instead of duplicating them
+	if _, _, err := manager.Add("nx-bff-web-payment-dev1", "dev1"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	services, err := manager.List("dev1")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(services) != 2 || services[0].Artifact != "nx-bff-web-payment-dev1" {
+		t.Errorf("Expected 2 entries for the artifact, got %+v", services)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# Kuma Service Mesh Configuration\n") {
+		t.Errorf("Expected the header comment to be kept, got:\n%s", data)
+	}
+
+	_, removed, err := manager.Remove("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Expected 2 removed entries, got %+v", removed)
+	}
+	if services, _ := manager.List("dev1"); len(services) != 0 {
+		t.Errorf("Expected no entries left, got %+v", services)
+	}
+	if _, _, err := manager.Remove("nx-bff-web-payment", "dev1"); err == nil {
+		t.Error("Expected removing a missing artifact to fail")
+	}
+}
+
+func TestAdd_NoComponents(t *testing.T) {
+	baseDir := setupTestEnv(t)
+
+	if _, _, err := NewManager(baseDir).Add("nx-bff-web-payment", "sit1"); err == nil {
+		t.Error("Expected an artifact without redis or dynamo to fail")
+	}
+}
+
+func TestAdd_RefusesInvalidList(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	path := layout.MeshValuesFile(baseDir, "dev1")
+	original := "externalServices:\n  - name: legacy\n    protocol: tcp\n    host: legacy.example.com:443\n    port: 443\n"
+	os.WriteFile(path, []byte(original), 0644)
+
+	_, _, err := NewManager(baseDir).Add("nx-bff-web-payment", "dev1")
+	if err == nil || !strings.Contains(err.Error(), "externalServices[0].host") {
+		t.Fatalf("Expected the invalid host to be reported, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("Expected nothing to be written, got:\n%s", data)
+	}
+}
+
+func TestGenerate_RedisEndpoint(t *testing.T) {
+	inv := models.AppInventory{ArtifactMetadata: models.ArtifactMetadata{ArtifactName: "nx-bb-orders-sit1"}}
+	inv.Components.Redis = models.RedisComponent{Enabled: true, Endpoint: "orders.abc123.cache.local:6380"}
+
+	services, err := Generate(inv, "eu-west-1")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(services) != 1 || services[0].Host != "orders.abc123.cache.local" || services[0].Port != 6380 ||
+		services[0].Name != "nx-bb-orders-sit1-redis" {
+		t.Errorf("Unexpected services %+v", services)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	valid := models.ExternalService{Name: "orders", Protocol: "tcp", Host: "orders.example.com", Port: 443}
+	for name, test := range map[string]struct {
+		service models.ExternalService
+		field   string
+	}{
+		"ip host":           {models.ExternalService{Name: "ip", Protocol: "http", Host: "10.0.0.1", Port: 80}, ""},
+		"duplicate name":    {valid, "name"},
+		"uppercase name":    {models.ExternalService{Name: "Orders", Protocol: "tcp", Host: "a.example.com", Port: 1}, "name"},
+		"host with port":    {models.ExternalService{Name: "a", Protocol: "tcp", Host: "a.example.com:443", Port: 443}, "host"},
+		"invalid host":      {models.ExternalService{Name: "b", Protocol: "tcp", Host: "bad_host.example.com", Port: 443}, "host"},
+		"port out of range": {models.ExternalService{Name: "c", Protocol: "tcp", Host: "c.example.com", Port: 70000}, "port"},
+		"unknown protocol":  {models.ExternalService{Name: "d", Protocol: "udp", Host: "d.example.com", Port: 53}, "protocol"},
+	} {
+		problems := Check([]models.ExternalService{valid, test.service})
+		switch {
+		case test.field == "" && len(problems) != 0:
+			t.Errorf("%s: expected no problems, got %+v", name, problems)
+		case test.field != "" && (len(problems) != 1 || problems[0].Index != 1 || problems[0].Field != test.field):
+			t.Errorf("%s: expected a %s problem in entry 1, got %+v", name, test.field, problems)
+		}
+	}
+}
+
+func TestAdd_Golden(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	path, _, err := NewManager(baseDir).Add("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read mesh values: %v", err)
+	}
+	golden.Assert(t, filepath.Join("testdata", "golden", "dev1-values.yaml"), data)
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Architecture selects how approved infrastructure is provisioned
+type Architecture string
+
+const (
+	ArchitectureCentralized Architecture = "centralized"
+	ArchitectureIndividual  Architecture = "individual"
+)
+
+// ApprovalRecord records an approved infrastructure creation
+type ApprovalRecord struct {
+	ID           string       `json:"id"`
+	Artifact     string       `json:"artifact"`
+	Environment  string       `json:"environment"`
+	Architecture Architecture `json:"architecture"`
+	Approver     string       `json:"approver"`
+	Team         string       `json:"team"`
+	Components   []string     `json:"components"`
+	ApprovedAt   time.Time    `json:"approved_at"`
+}
+
+// PendingApproval is an inventory entry that requests components but has
+// not been approved for infrastructure creation
+type PendingApproval struct {
+	Artifact    string   `json:"artifact"`
+	Environment string   `json:"environment"`
+	Components  []string `json:"components"`
+	Path        string   `json:"path"`
+}
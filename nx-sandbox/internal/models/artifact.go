@@ -10,21 +10,27 @@ const (
 	SourceEnvironment ArtifactSource = "environment"
 )
 
+// KnownLayers lists the Nexus layers recognised by the sandbox
+var KnownLayers = []string{"al", "bal", "bb", "bc", "bff", "ch", "tc", "xp"}
+
+// DefaultEnvironments lists the sandbox environments in promotion order
+var DefaultEnvironments = []string{"dev1", "sit1", "uat1", "prod1"}
+
 // SandboxArtifact represents an artifact available for testing
 type SandboxArtifact struct {
-	Name         string
-	Layer        string
-	Path         string
-	Source       ArtifactSource
-	Environment  string
-	HasChart     bool
-	HasInventory bool
-	LastModified time.Time
+	Name         string         `json:"name"`
+	Layer        string         `json:"layer"`
+	Path         string         `json:"path"`
+	Source       ArtifactSource `json:"source"`
+	Environment  string         `json:"environment,omitempty"`
+	HasChart     bool           `json:"has_chart"`
+	HasInventory bool           `json:"has_inventory"`
+	LastModified time.Time      `json:"last_modified"`
 }
 
 // ArtifactFilter represents filtering options for artifact listing
 type ArtifactFilter struct {
-	Source      ArtifactSource
-	Layer       string
-	Environment string
+	Source      ArtifactSource `json:"source,omitempty"`
+	Layer       string         `json:"layer,omitempty"`
+	Environment string         `json:"environment,omitempty"`
 }
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// AuditResult is the outcome of an audited operation
+type AuditResult string
+
+const (
+	AuditSuccess AuditResult = "success"
+	AuditFailure AuditResult = "failure"
+)
+
+// AuditChange is a path an operation created, modified or removed. Hashes are
+// SHA-256 of the file, or of the sorted file hashes for a directory created or
+// removed as a whole; an empty hash means the path did not exist.
+type AuditChange struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// AuditEntry records one mutating operation
+type AuditEntry struct {
+	Time       time.Time     `json:"time"`
+	Operation  string        `json:"operation"`
+	Args       []string      `json:"args,omitempty"`
+	User       string        `json:"user"`
+	Paths      []AuditChange `json:"paths"`
+	DurationMS int64         `json:"duration_ms"`
+	Result     AuditResult   `json:"result"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// AuditFilter selects audit entries. Zero values match everything.
+type AuditFilter struct {
+	// Artifact matches entries naming the artifact in an argument or a path
+	Artifact string
+	// Operation matches the operation or, for a command group such as
+	// "env", any of its subcommands
+	Operation string
+	Since     time.Time
+	Until     time.Time
+}
@@ -0,0 +1,84 @@
+package models
+
+// CapacityFigures are the resources and monthly cost of a service or a group
+// of services: the minimum at the lowest replica count with the requests, the
+// maximum at the highest replica count with the limits. CPU is in cores and
+// memory in bytes.
+type CapacityFigures struct {
+	MinReplicas int     `json:"min_replicas"`
+	MaxReplicas int     `json:"max_replicas"`
+	MinCPU      float64 `json:"min_cpu"`
+	MaxCPU      float64 `json:"max_cpu"`
+	MinMemory   float64 `json:"min_memory"`
+	MaxMemory   float64 `json:"max_memory"`
+	MinCost     float64 `json:"min_cost"`
+	MaxCost     float64 `json:"max_cost"`
+}
+
+// Add accumulates other into f
+func (f *CapacityFigures) Add(other CapacityFigures) {
+	f.MinReplicas += other.MinReplicas
+	f.MaxReplicas += other.MaxReplicas
+	f.MinCPU += other.MinCPU
+	f.MaxCPU += other.MaxCPU
+	f.MinMemory += other.MinMemory
+	f.MaxMemory += other.MaxMemory
+	f.MinCost += other.MinCost
+	f.MaxCost += other.MaxCost
+}
+
+// ServiceCapacity is the capacity of a service in one environment
+type ServiceCapacity struct {
+	Environment string `json:"environment"`
+	Layer       string `json:"layer"`
+	Service     string `json:"service"`
+	CapacityFigures
+	// Warnings report values that could not be read and were counted as zero
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// LayerCapacity is the capacity of a layer in one environment, or of a
+// whole environment when Layer is empty
+type LayerCapacity struct {
+	Environment string `json:"environment"`
+	Layer       string `json:"layer,omitempty"`
+	Services    int    `json:"services"`
+	CapacityFigures
+}
+
+// CapacityReport is the estimated capacity of the sandbox charts
+type CapacityReport struct {
+	Currency     string            `json:"currency"`
+	Services     []ServiceCapacity `json:"services"`
+	Layers       []LayerCapacity   `json:"layers"`
+	Environments []LayerCapacity   `json:"environments"`
+	Total        CapacityFigures   `json:"total"`
+}
+
+// CapacityChange is a service whose capacity a change alters. Before is nil
+// for a new service and After for a removed one.
+type CapacityChange struct {
+	Environment string           `json:"environment"`
+	Layer       string           `json:"layer"`
+	Service     string           `json:"service"`
+	Before      *CapacityFigures `json:"before"`
+	After       *CapacityFigures `json:"after"`
+}
+
+// LayerCapacityChange is the capacity of a layer before and after a change
+type LayerCapacityChange struct {
+	Environment string          `json:"environment"`
+	Layer       string          `json:"layer"`
+	Before      CapacityFigures `json:"before"`
+	After       CapacityFigures `json:"after"`
+}
+
+// CapacityDelta is the capacity a pending change would add or remove
+type CapacityDelta struct {
+	Description string                `json:"description"`
+	Currency    string                `json:"currency"`
+	Changes     []CapacityChange      `json:"changes"`
+	Layers      []LayerCapacityChange `json:"layers"`
+	Before      CapacityFigures       `json:"before"`
+	After       CapacityFigures       `json:"after"`
+}
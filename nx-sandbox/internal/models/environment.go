@@ -4,19 +4,30 @@ import "time"
 
 // SandboxEnvironment represents the state of the sandbox environment
 type SandboxEnvironment struct {
-	TestArtifactsDir    string
-	LocalArtifactsDir   string
-	TotalArtifacts      int
-	TestArtifactsCount  int
-	LocalArtifactsCount int
-	DiskUsage           int64 // in bytes
-	LastCleanup         time.Time
+	TestArtifactsDir    string    `json:"test_artifacts_dir"`
+	LocalArtifactsDir   string    `json:"local_artifacts_dir"`
+	TotalArtifacts      int       `json:"total_artifacts"`
+	TestArtifactsCount  int       `json:"test_artifacts_count"`
+	LocalArtifactsCount int       `json:"local_artifacts_count"`
+	DiskUsage           int64     `json:"disk_usage"` // in bytes
+	SnapshotCount       int       `json:"snapshot_count"`
+	SnapshotDiskUsage   int64     `json:"snapshot_disk_usage"` // in bytes
+	LastCleanup         time.Time `json:"last_cleanup"`
 }
 
 // SandboxStatus represents the overall status of the sandbox
 type SandboxStatus struct {
-	Environment     SandboxEnvironment
-	IsHealthy       bool
-	Issues          []string
-	Recommendations []string
+	Environment      SandboxEnvironment `json:"environment"`
+	IsHealthy        bool               `json:"is_healthy"`
+	Issues           []string           `json:"issues"`
+	Recommendations  []string           `json:"recommendations"`
+	PendingApprovals []PendingApproval  `json:"pending_approvals"`
+}
+
+// CleanupCandidate is an artifact directory old enough to be removed by a cleanup
+type CleanupCandidate struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"` // in bytes
+	ModTime time.Time `json:"mod_time"`
+	MaxAge  string    `json:"max_age"`
 }
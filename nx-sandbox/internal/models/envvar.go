@@ -0,0 +1,14 @@
+package models
+
+// SecretRef points an environment variable at a key in a Kubernetes secret
+type SecretRef struct {
+	Name string `yaml:"name" json:"name"`
+	Key  string `yaml:"key" json:"key"`
+}
+
+// EnvVar is an entry in the env section of an artifact's Helm values
+type EnvVar struct {
+	Name      string     `json:"name"`
+	Value     string     `json:"value,omitempty"`
+	SecretRef *SecretRef `json:"secret_ref,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+// GoldenStatus is how generated output compares with its golden file
+type GoldenStatus string
+
+const (
+	GoldenMatch    GoldenStatus = "match"
+	GoldenMismatch GoldenStatus = "mismatch"
+	// GoldenMissing output has no golden file yet
+	GoldenMissing GoldenStatus = "missing"
+	// GoldenStale golden files are no longer generated
+	GoldenStale GoldenStatus = "stale"
+)
+
+// GoldenFile compares one generated file with its golden file. Diff is a
+// unified diff from the golden file to the normalized output.
+type GoldenFile struct {
+	Name   string       `json:"name"`
+	Path   string       `json:"path"`
+	Status GoldenStatus `json:"status"`
+	Diff   string       `json:"diff,omitempty"`
+}
+
+// GoldenCaseResult is the comparison of every file a golden case generates
+type GoldenCaseResult struct {
+	Name  string       `json:"name"`
+	Files []GoldenFile `json:"files"`
+}
+
+// GoldenReport is the result of verifying or updating golden cases
+type GoldenReport struct {
+	Cases []GoldenCaseResult `json:"cases"`
+}
+
+// Failures counts the files that do not match their golden file
+func (r *GoldenReport) Failures() int {
+	count := 0
+	for _, c := range r.Cases {
+		for _, file := range c.Files {
+			if file.Status != GoldenMatch {
+				count++
+			}
+		}
+	}
+	return count
+}
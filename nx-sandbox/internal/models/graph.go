@@ -0,0 +1,50 @@
+package models
+
+// GraphNodeKind classifies the nodes of the dependency graph
+type GraphNodeKind string
+
+const (
+	GraphArtifact  GraphNodeKind = "artifact"
+	GraphComponent GraphNodeKind = "component"
+	GraphResource  GraphNodeKind = "resource"
+)
+
+// Sources an edge of the dependency graph can be read from
+const (
+	GraphSourceInventory = "inventory"
+	GraphSourceHelm      = "helm"
+)
+
+// GraphNode is an artifact, one of its components or a shared resource.
+// Type is the component (redis, dynamo, ...) or resource type (redis,
+// dynamodb, ...).
+type GraphNode struct {
+	ID          string        `json:"id"`
+	Kind        GraphNodeKind `json:"kind"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type,omitempty"`
+	Environment string        `json:"environment,omitempty"`
+}
+
+// GraphEdge links an artifact to a component or a component to a resource.
+// Sources lists where the dependency was declared.
+type GraphEdge struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Sources []string `json:"sources"`
+}
+
+// DependencyGraph links artifacts to their components and the resources
+// behind them
+type DependencyGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphImpact is an artifact that depends on a resource
+type GraphImpact struct {
+	Artifact    string   `json:"artifact"`
+	Environment string   `json:"environment,omitempty"`
+	Component   string   `json:"component"`
+	Sources     []string `json:"sources"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// ProvisionedResource is an AWS resource created or found for an inventory component
+type ProvisionedResource struct {
+	Component string `json:"component"`
+	Name      string `json:"name"`
+	ARN       string `json:"arn,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	Created   bool   `json:"created"`
+}
+
+// ProvisionResult is the outcome of applying an inventory's components
+type ProvisionResult struct {
+	Artifact    string                `json:"artifact"`
+	Environment string                `json:"environment"`
+	Endpoint    string                `json:"endpoint"`
+	Resources   []ProvisionedResource `json:"resources"`
+	Path        string                `json:"path"`
+}
+
+// DriftKind classifies a difference between an inventory and the provisioned resources
+type DriftKind string
+
+const (
+	// DriftMissing is an enabled component whose resource does not exist
+	DriftMissing DriftKind = "missing"
+	// DriftExtra is a resource that exists for a component that is not enabled
+	DriftExtra DriftKind = "extra"
+	// DriftMismatch is a resource whose settings differ from the inventory
+	DriftMismatch DriftKind = "mismatch"
+)
+
+// DriftItem is a single difference found for an inventory component
+type DriftItem struct {
+	Component string    `json:"component"`
+	Resource  string    `json:"resource"`
+	Kind      DriftKind `json:"kind"`
+	Detail    string    `json:"detail"`
+}
+
+// DriftReport lists the drift found for one artifact in one environment
+type DriftReport struct {
+	Artifact    string      `json:"artifact"`
+	Environment string      `json:"environment"`
+	Path        string      `json:"path"`
+	Items       []DriftItem `json:"items"`
+}
+
+// HasDrift reports whether any differences were found
+func (r DriftReport) HasDrift() bool {
+	return len(r.Items) > 0
+}
@@ -0,0 +1,98 @@
+package models
+
+// AppInventory represents an nx-app-inventory.yaml entry
+type AppInventory struct {
+	SchemaVersion    string               `yaml:"schema_version" json:"schema_version"`
+	ArtifactMetadata ArtifactMetadata     `yaml:"artifact_metadata" json:"artifact_metadata"`
+	Infrastructure   InfrastructureStatus `yaml:"infrastructure" json:"infrastructure"`
+	Components       InventoryComponents  `yaml:"components" json:"components"`
+}
+
+// ArtifactMetadata identifies an artifact and its owner
+type ArtifactMetadata struct {
+	ArtifactName string `yaml:"artifact_name" json:"artifact_name"`
+	Layer        string `yaml:"layer" json:"layer"`
+	Domain       string `yaml:"domain" json:"domain"`
+	Service      string `yaml:"service" json:"service"`
+	Description  string `yaml:"description" json:"description"`
+	Owner        string `yaml:"owner" json:"owner"`
+}
+
+// InfrastructureStatus tracks whether infrastructure is approved and deployed
+type InfrastructureStatus struct {
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	Deployed    bool   `yaml:"deployed" json:"deployed"`
+	Component   string `yaml:"component" json:"component"`
+	Environment string `yaml:"environment" json:"environment"`
+}
+
+// InventoryComponents holds the AWS components an artifact can request
+type InventoryComponents struct {
+	ServiceAccount ServiceAccountComponent `yaml:"service_account" json:"service_account"`
+	Redis          RedisComponent          `yaml:"redis" json:"redis"`
+	Dynamo         DynamoComponent         `yaml:"dynamo" json:"dynamo"`
+	RDS            RDSComponent            `yaml:"rds" json:"rds"`
+	ECR            ECRComponent            `yaml:"ecr" json:"ecr"`
+}
+
+// ServiceAccountComponent configures the artifact's IAM-backed service account
+type ServiceAccountComponent struct {
+	Name      string `yaml:"name" json:"name"`
+	Namespace string `yaml:"namespace" json:"namespace"`
+	RoleARN   string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+}
+
+// RedisComponent configures an ElastiCache Redis cluster
+type RedisComponent struct {
+	Name      string `yaml:"name" json:"name"`
+	ClusterID string `yaml:"cluster_id" json:"cluster_id"`
+	Endpoint  string `yaml:"endpoint" json:"endpoint"`
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+}
+
+// DynamoComponent configures a DynamoDB table
+type DynamoComponent struct {
+	TableName    string `yaml:"table_name" json:"table_name"`
+	PartitionKey string `yaml:"partition_key" json:"partition_key"`
+	SortKey      string `yaml:"sort_key" json:"sort_key"`
+	TableARN     string `yaml:"table_arn,omitempty" json:"table_arn,omitempty"`
+	Enabled      bool   `yaml:"enabled" json:"enabled"`
+}
+
+// RDSComponent configures an RDS instance
+type RDSComponent struct {
+	InstanceClass string `yaml:"instance_class" json:"instance_class"`
+	Engine        string `yaml:"engine" json:"engine"`
+	Endpoint      string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	Enabled       bool   `yaml:"enabled" json:"enabled"`
+}
+
+// ECRComponent configures an ECR repository
+type ECRComponent struct {
+	RepositoryName string `yaml:"repository_name" json:"repository_name"`
+	ImageTag       string `yaml:"image_tag" json:"image_tag"`
+	RepositoryURI  string `yaml:"repository_uri,omitempty" json:"repository_uri,omitempty"`
+	Enabled        bool   `yaml:"enabled" json:"enabled"`
+}
+
+// EnabledComponents returns the names of the enabled components in schema order
+func (c InventoryComponents) EnabledComponents() []string {
+	var enabled []string
+	if c.ServiceAccount.Enabled {
+		enabled = append(enabled, "service_account")
+	}
+	if c.Redis.Enabled {
+		enabled = append(enabled, "redis")
+	}
+	if c.Dynamo.Enabled {
+		enabled = append(enabled, "dynamo")
+	}
+	if c.RDS.Enabled {
+		enabled = append(enabled, "rds")
+	}
+	if c.ECR.Enabled {
+		enabled = append(enabled, "ecr")
+	}
+	return enabled
+}
@@ -0,0 +1,26 @@
+package models
+
+// MeshValues is the values.yaml of an environment's kuma-resources-default-mesh chart
+type MeshValues struct {
+	ExternalServices []ExternalService `yaml:"externalServices" json:"externalServices"`
+}
+
+// ExternalService is a Kuma ExternalService letting mesh traffic leave for a
+// host outside the mesh. Entries generated from an inventory name the
+// artifact and component they were generated for.
+type ExternalService struct {
+	Name      string `yaml:"name" json:"name"`
+	Artifact  string `yaml:"artifact,omitempty" json:"artifact,omitempty"`
+	Component string `yaml:"component,omitempty" json:"component,omitempty"`
+	Protocol  string `yaml:"protocol" json:"protocol"`
+	Host      string `yaml:"host" json:"host"`
+	Port      int    `yaml:"port" json:"port"`
+	TLS       bool   `yaml:"tls" json:"tls"`
+}
+
+// MeshProblem is an invalid field of an external service entry
+type MeshProblem struct {
+	Index   int    `json:"index"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
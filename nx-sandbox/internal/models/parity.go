@@ -0,0 +1,40 @@
+package models
+
+// ChartVersion is the chart of a service in one environment
+type ChartVersion struct {
+	Environment string `json:"environment"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"app_version"`
+}
+
+// LayerParity records the environments a layer directory exists in
+type LayerParity struct {
+	Layer        string   `json:"layer"`
+	Environments []string `json:"environments"`
+	Missing      []string `json:"missing"`
+}
+
+// ServiceParity compares a service's charts across the environments
+type ServiceParity struct {
+	Layer        string         `json:"layer"`
+	Service      string         `json:"service"`
+	Environments []string       `json:"environments"`
+	Missing      []string       `json:"missing"`
+	Charts       []ChartVersion `json:"charts"`
+	// VersionDrift and AppVersionDrift are set when the present charts disagree
+	VersionDrift    bool `json:"version_drift"`
+	AppVersionDrift bool `json:"app_version_drift"`
+	// Skipped lists environments missing between two environments that have
+	// the service, in promotion order
+	Skipped []string `json:"skipped"`
+}
+
+// ParityReport compares the nx-bolt-environment-* repositories
+type ParityReport struct {
+	// Environments lists the environments with a repository, in promotion
+	// order followed by any not in the promotion chain
+	Environments []string        `json:"environments"`
+	Layers       []LayerParity   `json:"layers"`
+	Services     []ServiceParity `json:"services"`
+	InParity     bool            `json:"in_parity"`
+}
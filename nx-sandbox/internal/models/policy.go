@@ -0,0 +1,52 @@
+package models
+
+// PolicyTarget selects the files a policy is evaluated against
+type PolicyTarget string
+
+const (
+	// PolicyTargetInventory evaluates a policy for every inventory
+	PolicyTargetInventory PolicyTarget = "inventory"
+	// PolicyTargetValues evaluates a policy for the values.yaml of every chart
+	PolicyTargetValues PolicyTarget = "values"
+)
+
+// Policy is a declarative rule: for every file of its target where When
+// holds, Require must hold
+type Policy struct {
+	Name        string       `yaml:"name" json:"name"`
+	Description string       `yaml:"description,omitempty" json:"description,omitempty"`
+	Severity    Severity     `yaml:"severity,omitempty" json:"severity"`
+	Target      PolicyTarget `yaml:"target" json:"target"`
+	When        string       `yaml:"when,omitempty" json:"when,omitempty"`
+	Require     string       `yaml:"require" json:"require"`
+	Message     string       `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// PolicyViolation is a file that does not satisfy a policy
+type PolicyViolation struct {
+	Policy      string   `json:"policy"`
+	Severity    Severity `json:"severity"`
+	Artifact    string   `json:"artifact"`
+	Environment string   `json:"environment,omitempty"`
+	Path        string   `json:"path"`
+	Line        int      `json:"line,omitempty"`
+	Message     string   `json:"message"`
+}
+
+// PolicyReport is the result of evaluating the policies
+type PolicyReport struct {
+	Policies   []Policy          `json:"policies"`
+	Checked    []string          `json:"checked"`
+	Violations []PolicyViolation `json:"violations"`
+}
+
+// Errors counts the violations with error severity
+func (r *PolicyReport) Errors() int {
+	count := 0
+	for _, violation := range r.Violations {
+		if violation.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
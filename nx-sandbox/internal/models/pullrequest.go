@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PullRequestState is the lifecycle state of a simulated pull request
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestMerged PullRequestState = "merged"
+)
+
+// PullRequest is a simulated pull request against a local repository under repos/
+type PullRequest struct {
+	ID        int              `json:"id"`
+	Title     string           `json:"title"`
+	Repo      string           `json:"repo"`
+	Branch    string           `json:"branch"`
+	Base      string           `json:"base"`
+	Author    string           `json:"author"`
+	State     PullRequestState `json:"state"`
+	Commit    string           `json:"commit"`
+	Files     []string         `json:"files"`
+	CreatedAt time.Time        `json:"created_at"`
+	MergedAt  *time.Time       `json:"merged_at,omitempty"`
+	MergedBy  string           `json:"merged_by,omitempty"`
+}
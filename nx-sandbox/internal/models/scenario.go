@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// ScenarioStepResult records a step of a scenario: the command it ran or the
+// workflow it dispatched, its output and the expectations it missed
+type ScenarioStepResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration_ns"`
+	Output   string        `json:"output,omitempty"`
+	Failures []string      `json:"failures,omitempty"`
+}
+
+// ScenarioResult records a scenario run in its own sandbox root. Error is set
+// when the scenario could not be loaded or set up; Failures lists the missed
+// expectations of the final state.
+type ScenarioResult struct {
+	Name     string               `json:"name"`
+	Path     string               `json:"path"`
+	Passed   bool                 `json:"passed"`
+	Duration time.Duration        `json:"duration_ns"`
+	Error    string               `json:"error,omitempty"`
+	Steps    []ScenarioStepResult `json:"steps"`
+	Failures []string             `json:"failures,omitempty"`
+}
+
+// ScenarioReport is the result of running a set of scenarios
+type ScenarioReport struct {
+	Scenarios []ScenarioResult `json:"scenarios"`
+	Duration  time.Duration    `json:"duration_ns"`
+}
+
+// Failed counts the scenarios that did not pass
+func (r *ScenarioReport) Failed() int {
+	count := 0
+	for _, scenario := range r.Scenarios {
+		if !scenario.Passed {
+			count++
+		}
+	}
+	return count
+}
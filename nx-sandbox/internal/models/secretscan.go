@@ -0,0 +1,21 @@
+package models
+
+// SecretFinding is a suspected secret in a sandbox file. The secret itself is
+// never recorded; Snippet shows only its first characters.
+type SecretFinding struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Snippet string `json:"snippet"`
+	// Fingerprint identifies the finding across runs without revealing it
+	Fingerprint string `json:"fingerprint"`
+}
+
+// SecretScanReport is the result of scanning the sandbox for secrets
+type SecretScanReport struct {
+	Scanned  int             `json:"scanned"`
+	Allowed  int             `json:"allowed"`
+	Findings []SecretFinding `json:"findings"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"os"
+	"time"
+)
+
+// SnapshotFile is a file, directory or symlink captured by a snapshot.
+// Paths are slash-separated and relative to the sandbox root.
+type SnapshotFile struct {
+	Path    string      `json:"path"`
+	Mode    os.FileMode `json:"mode"`
+	Size    int64       `json:"size,omitempty"`
+	ModTime time.Time   `json:"mod_time"`
+	Hash    string      `json:"hash,omitempty"`
+	Link    string      `json:"link,omitempty"`
+}
+
+// Snapshot is the manifest of a saved sandbox state
+type Snapshot struct {
+	Name      string         `json:"name"`
+	CreatedAt time.Time      `json:"created_at"`
+	Roots     []string       `json:"roots"`
+	Files     []SnapshotFile `json:"files"`
+}
+
+// SnapshotSummary describes a snapshot in listings
+type SnapshotSummary struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     int       `json:"files"`
+	Size      int64     `json:"size"`
+}
+
+// SnapshotUsage reports the disk space used by the snapshot store
+type SnapshotUsage struct {
+	Snapshots int   `json:"snapshots"`
+	Objects   int   `json:"objects"`
+	DiskUsage int64 `json:"disk_usage"`
+}
+
+// RestoreResult reports what a snapshot restore changed
+type RestoreResult struct {
+	Name      string `json:"name"`
+	Written   int    `json:"written"`
+	Removed   int    `json:"removed"`
+	Unchanged int    `json:"unchanged"`
+}
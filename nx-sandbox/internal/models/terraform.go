@@ -0,0 +1,48 @@
+package models
+
+// PlanAction is the summarized action Terraform plans for a resource
+type PlanAction string
+
+const (
+	PlanCreate  PlanAction = "create"
+	PlanUpdate  PlanAction = "update"
+	PlanDelete  PlanAction = "delete"
+	PlanReplace PlanAction = "replace"
+)
+
+// PlanResourceChange is a planned change to a single resource
+type PlanResourceChange struct {
+	Address     string     `json:"address"`
+	Type        string     `json:"type"`
+	Action      PlanAction `json:"action"`
+	Artifact    string     `json:"artifact,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	// Destructive marks deletes and replaces in a protected environment
+	Destructive bool `json:"destructive"`
+}
+
+// PlanTypeSummary counts the planned changes for one resource type
+type PlanTypeSummary struct {
+	Type    string `json:"type"`
+	Create  int    `json:"create"`
+	Update  int    `json:"update"`
+	Delete  int    `json:"delete"`
+	Replace int    `json:"replace"`
+}
+
+// PlanSummary is a condensed view of a `terraform show -json` plan
+type PlanSummary struct {
+	Types   []PlanTypeSummary    `json:"types"`
+	Changes []PlanResourceChange `json:"changes"`
+}
+
+// DestructiveChanges returns the changes flagged as destructive
+func (s PlanSummary) DestructiveChanges() []PlanResourceChange {
+	var destructive []PlanResourceChange
+	for _, change := range s.Changes {
+		if change.Destructive {
+			destructive = append(destructive, change)
+		}
+	}
+	return destructive
+}
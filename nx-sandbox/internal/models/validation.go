@@ -0,0 +1,40 @@
+package models
+
+// Severity ranks a validation issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a problem found in a sandbox file
+type ValidationIssue struct {
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport is the result of validating a set of files
+type ValidationReport struct {
+	Checked []string          `json:"checked"`
+	Issues  []ValidationIssue `json:"issues"`
+}
+
+// Errors counts the issues with error severity
+func (r *ValidationReport) Errors() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// Valid reports whether the validation found no errors
+func (r *ValidationReport) Valid() bool {
+	return r.Errors() == 0
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// WorkflowConclusion is the outcome of a workflow run, job or step
+type WorkflowConclusion string
+
+const (
+	ConclusionSuccess WorkflowConclusion = "success"
+	ConclusionFailure WorkflowConclusion = "failure"
+	ConclusionSkipped WorkflowConclusion = "skipped"
+)
+
+// WorkflowInput is a workflow_dispatch input declared by a workflow
+type WorkflowInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required"`
+	Type        string   `json:"type,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	Default     string   `json:"default,omitempty"`
+}
+
+// WorkflowSummary describes a github-simulator workflow
+type WorkflowSummary struct {
+	Name   string          `json:"name"`
+	Title  string          `json:"title"`
+	Path   string          `json:"path"`
+	Events []string        `json:"events"`
+	Jobs   []string        `json:"jobs"`
+	Inputs []WorkflowInput `json:"inputs"`
+}
+
+// WorkflowRun records a simulated run of a github-simulator workflow
+type WorkflowRun struct {
+	ID         int                `json:"id"`
+	Workflow   string             `json:"workflow"`
+	Event      string             `json:"event"`
+	Inputs     map[string]string  `json:"inputs"`
+	Actor      string             `json:"actor"`
+	Conclusion WorkflowConclusion `json:"conclusion"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt time.Time          `json:"finished_at"`
+	Jobs       []JobRun           `json:"jobs"`
+	// DryRun runs worked on a copy of repos/ and are not recorded; Changes
+	// lists what they would have changed
+	DryRun  bool                 `json:"dry_run,omitempty"`
+	Changes []WorkflowFileChange `json:"changes,omitempty"`
+}
+
+// FileChangeKind is how a dry run would have changed a file
+type FileChangeKind string
+
+const (
+	FileAdded    FileChangeKind = "added"
+	FileModified FileChangeKind = "modified"
+	FileDeleted  FileChangeKind = "deleted"
+)
+
+// WorkflowFileChange is a file a dry run would have changed, with its
+// unified diff. Path is relative to the sandbox root.
+type WorkflowFileChange struct {
+	Path   string         `json:"path"`
+	Change FileChangeKind `json:"change"`
+	Diff   string         `json:"diff"`
+}
+
+// JobRun records a job of a workflow run
+type JobRun struct {
+	ID         string             `json:"id"`
+	Conclusion WorkflowConclusion `json:"conclusion"`
+	Outputs    map[string]string  `json:"outputs,omitempty"`
+	Steps      []StepRun          `json:"steps"`
+}
+
+// StepRun records a step of a job, with its combined output
+type StepRun struct {
+	Name       string             `json:"name"`
+	ID         string             `json:"id,omitempty"`
+	Uses       string             `json:"uses,omitempty"`
+	Conclusion WorkflowConclusion `json:"conclusion"`
+	Message    string             `json:"message,omitempty"`
+	Outputs    map[string]string  `json:"outputs,omitempty"`
+	Log        string             `json:"log,omitempty"`
+}
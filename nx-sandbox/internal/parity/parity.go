@@ -0,0 +1,215 @@
+package parity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// chartMetadata is the part of Chart.yaml the comparison reads
+type chartMetadata struct {
+	Version    string `yaml:"version"`
+	AppVersion string `yaml:"appVersion"`
+}
+
+// Comparer defines the interface for comparing the environment repositories
+type Comparer interface {
+	Compare(layer string) (*models.ParityReport, error)
+}
+
+// DefaultComparer compares the layers and charts of the nx-bolt-environment-*
+// repositories under repos/
+type DefaultComparer struct {
+	baseDir string
+}
+
+// NewComparer creates a new comparer for a sandbox root
+func NewComparer(baseDir string) Comparer {
+	return &DefaultComparer{
+		baseDir: baseDir,
+	}
+}
+
+// Compare reports which layers and services each environment has, the
+// charts whose version or appVersion differ, and the services that skip an
+// environment of the promotion chain. An empty layer compares every layer.
+func (c *DefaultComparer) Compare(layer string) (*models.ParityReport, error) {
+	cfg, err := config.Load(c.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	envs, err := c.environments(cfg.Environments)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ParityReport{
+		Environments: envs,
+		Layers:       []models.LayerParity{},
+		Services:     []models.ServiceParity{},
+		InParity:     true,
+	}
+
+	for _, known := range models.KnownLayers {
+		if layer != "" && layer != known {
+			continue
+		}
+
+		layerParity := models.LayerParity{Layer: known, Environments: []string{}, Missing: []string{}}
+		charts := make(map[string][]models.ChartVersion)
+		for _, env := range envs {
+			dir := filepath.Join(layout.EnvironmentRepo(c.baseDir, env), known)
+			entries, err := os.ReadDir(dir)
+			if os.IsNotExist(err) {
+				layerParity.Missing = append(layerParity.Missing, env)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+			}
+			layerParity.Environments = append(layerParity.Environments, env)
+
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				chart, err := readChart(filepath.Join(dir, entry.Name(), helm.ChartFileName))
+				if err != nil {
+					return nil, err
+				}
+				chart.Environment = env
+				charts[entry.Name()] = append(charts[entry.Name()], chart)
+			}
+		}
+
+		if len(layerParity.Environments) == 0 {
+			continue
+		}
+		if len(layerParity.Missing) > 0 {
+			report.InParity = false
+		}
+		report.Layers = append(report.Layers, layerParity)
+
+		var services []string
+		for service := range charts {
+			services = append(services, service)
+		}
+		sort.Strings(services)
+		for _, service := range services {
+			serviceParity := compareService(known, service, charts[service], envs, cfg.Environments)
+			if len(serviceParity.Missing) > 0 || serviceParity.VersionDrift || serviceParity.AppVersionDrift {
+				report.InParity = false
+			}
+			report.Services = append(report.Services, serviceParity)
+		}
+	}
+
+	return report, nil
+}
+
+// Helper methods
+
+// environments returns the environments with a repository: those in the
+// promotion chain first, in order, then the others by name
+func (c *DefaultComparer) environments(chain []string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(layout.ReposDir(c.baseDir), layout.EnvironmentRepoPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			found[strings.TrimPrefix(filepath.Base(match), layout.EnvironmentRepoPrefix)] = true
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no %s* repositories found in %s", layout.EnvironmentRepoPrefix, layout.ReposDir(c.baseDir))
+	}
+
+	var envs, others []string
+	for _, env := range chain {
+		if found[env] {
+			envs = append(envs, env)
+			delete(found, env)
+		}
+	}
+	for env := range found {
+		others = append(others, env)
+	}
+	sort.Strings(others)
+	return append(envs, others...), nil
+}
+
+func compareService(layer, service string, charts []models.ChartVersion, envs, chain []string) models.ServiceParity {
+	parity := models.ServiceParity{
+		Layer:        layer,
+		Service:      service,
+		Environments: []string{},
+		Missing:      []string{},
+		Charts:       charts,
+		Skipped:      []string{},
+	}
+
+	present := make(map[string]bool)
+	for _, chart := range charts {
+		present[chart.Environment] = true
+		parity.Environments = append(parity.Environments, chart.Environment)
+		if chart.Version != charts[0].Version {
+			parity.VersionDrift = true
+		}
+		if chart.AppVersion != charts[0].AppVersion {
+			parity.AppVersionDrift = true
+		}
+	}
+	for _, env := range envs {
+		if !present[env] {
+			parity.Missing = append(parity.Missing, env)
+		}
+	}
+
+	// An environment is skipped when the service exists both before and
+	// after it in the promotion chain
+	first, last := -1, -1
+	for i, env := range chain {
+		if present[env] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	for i := first + 1; first >= 0 && i < last; i++ {
+		if !present[chain[i]] {
+			parity.Skipped = append(parity.Skipped, chain[i])
+		}
+	}
+
+	return parity
+}
+
+// readChart reads the version and appVersion of a chart. Services without
+// a Chart.yaml are reported with empty versions.
+func readChart(path string) (models.ChartVersion, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return models.ChartVersion{}, nil
+	}
+	if err != nil {
+		return models.ChartVersion{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var metadata chartMetadata
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return models.ChartVersion{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return models.ChartVersion{Version: metadata.Version, AppVersion: metadata.AppVersion}, nil
+}
@@ -0,0 +1,98 @@
+package parity
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+func seedSandbox(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	return baseDir
+}
+
+func findService(report *models.ParityReport, service string) *models.ServiceParity {
+	for i := range report.Services {
+		if report.Services[i].Service == service {
+			return &report.Services[i]
+		}
+	}
+	return nil
+}
+
+func TestCompare_SeededSandbox(t *testing.T) {
+	baseDir := seedSandbox(t)
+
+	report, err := NewComparer(baseDir).Compare("")
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if !report.InParity {
+		t.Errorf("Expected seeded environments to be in parity, got %+v", report)
+	}
+	if !reflect.DeepEqual(report.Environments, []string{"dev1", "sit1", "uat1", "prod1"}) {
+		t.Errorf("Expected environments in promotion order, got %v", report.Environments)
+	}
+	if len(report.Layers) != 7 || len(report.Services) != 7 {
+		t.Errorf("Expected 7 layers and services, got %d and %d", len(report.Layers), len(report.Services))
+	}
+}
+
+func TestCompare_Differences(t *testing.T) {
+	baseDir := seedSandbox(t)
+	chart := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service"), "Chart.yaml")
+	os.WriteFile(chart, []byte("apiVersion: v2\nname: nx-bff-test-service\nversion: 1.1.0\nappVersion: \"1.1\"\n"), 0644)
+	os.RemoveAll(layout.ChartDir(baseDir, "sit1", "tc", "nx-tc-test-service"))
+	os.RemoveAll(filepath.Join(layout.EnvironmentRepo(baseDir, "prod1"), "al"))
+
+	comparer := NewComparer(baseDir)
+	report, err := comparer.Compare("")
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if report.InParity {
+		t.Error("Expected the environments not to be in parity")
+	}
+
+	bff := findService(report, "nx-bff-test-service")
+	if bff == nil || !bff.VersionDrift || !bff.AppVersionDrift || len(bff.Missing) != 0 {
+		t.Errorf("Expected version and appVersion drift for nx-bff-test-service, got %+v", bff)
+	}
+
+	tc := findService(report, "nx-tc-test-service")
+	if tc == nil || !reflect.DeepEqual(tc.Skipped, []string{"sit1"}) || !reflect.DeepEqual(tc.Missing, []string{"sit1"}) {
+		t.Errorf("Expected nx-tc-test-service to skip sit1, got %+v", tc)
+	}
+
+	// Missing from the end of the chain is not yet promoted, not skipped
+	al := findService(report, "nx-al-test-service")
+	if al == nil || len(al.Skipped) != 0 || !reflect.DeepEqual(al.Missing, []string{"prod1"}) {
+		t.Errorf("Expected nx-al-test-service to be missing from prod1 only, got %+v", al)
+	}
+	if report.Layers[0].Layer != "al" || !reflect.DeepEqual(report.Layers[0].Missing, []string{"prod1"}) {
+		t.Errorf("Expected layer al to be missing from prod1, got %+v", report.Layers[0])
+	}
+
+	filtered, err := comparer.Compare("tc")
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(filtered.Layers) != 1 || len(filtered.Services) != 1 {
+		t.Errorf("Expected only the tc layer, got %+v", filtered)
+	}
+}
+
+func TestCompare_NoRepositories(t *testing.T) {
+	if _, err := NewComparer(t.TempDir()).Compare(""); err == nil {
+		t.Error("Expected a sandbox without environment repositories to fail")
+	}
+}
@@ -0,0 +1,997 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+)
+
+// Expressions are small boolean formulas over the variables of a policy
+// target:
+//
+//	inventory.components.rds.enabled && !(artifact.layer in ["bal", "bc"])
+//	quantity(values.resources.limits.cpu) <= 2
+//	matches(chart.version, "^1\\.")
+//
+// Operators are ||, &&, !, ==, !=, <, <=, >, >= and in; functions are len,
+// matches and quantity. Fields are reached with . or ["key"] and list items
+// with [n]. Expressions are type-checked against the typed model when a
+// policy is loaded, so misspelt fields fail early. Missing values are null,
+// which is false in conditions and never ordered.
+
+// typeKind is the static type of an expression
+type typeKind int
+
+const (
+	typeAny typeKind = iota
+	typeNull
+	typeBool
+	typeNumber
+	typeString
+	typeList
+	typeObject
+)
+
+var typeNames = map[typeKind]string{
+	typeAny:    "any",
+	typeNull:   "null",
+	typeBool:   "bool",
+	typeNumber: "number",
+	typeString: "string",
+	typeList:   "list",
+	typeObject: "object",
+}
+
+// exprType describes a value of the model; fields are set for objects and
+// elem for lists
+type exprType struct {
+	kind   typeKind
+	elem   *exprType
+	fields map[string]*exprType
+}
+
+var (
+	anyType    = &exprType{kind: typeAny}
+	nullType   = &exprType{kind: typeNull}
+	boolType   = &exprType{kind: typeBool}
+	numberType = &exprType{kind: typeNumber}
+	stringType = &exprType{kind: typeString}
+)
+
+func (t *exprType) String() string {
+	return typeNames[t.kind]
+}
+
+// typeOf derives the expression type of a Go model type from its yaml tags
+func typeOf(t reflect.Type) *exprType {
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolType
+	case reflect.String:
+		return stringType
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return numberType
+	case reflect.Slice, reflect.Array:
+		return &exprType{kind: typeList, elem: typeOf(t.Elem())}
+	case reflect.Pointer:
+		return typeOf(t.Elem())
+	case reflect.Struct:
+		fields := make(map[string]*exprType)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fields[name] = typeOf(field.Type)
+		}
+		return &exprType{kind: typeObject, fields: fields}
+	}
+	return anyType
+}
+
+// node is a parsed expression
+type node interface {
+	check(vars map[string]*exprType) (*exprType, error)
+	eval(vars map[string]interface{}) (interface{}, error)
+	String() string
+}
+
+// parseExpr parses and type-checks an expression against the variables of
+// a target
+func parseExpr(source string, vars map[string]*exprType) (node, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at column %d", tok.text, tok.pos+1)
+	}
+
+	t, err := expr.check(vars)
+	if err != nil {
+		return nil, err
+	}
+	if t.kind != typeBool && t.kind != typeAny {
+		return nil, fmt.Errorf("expression is a %s, expected a condition", t)
+	}
+	return expr, nil
+}
+
+// evalCondition evaluates an expression as a condition; null is false
+func evalCondition(expr node, vars map[string]interface{}) (bool, error) {
+	value, err := expr.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return truth(value, expr)
+}
+
+// paths returns the field paths an expression reads, as variable name
+// followed by keys, in source order
+func paths(expr node) [][]string {
+	var out [][]string
+	var visit func(n node)
+	visit = func(n node) {
+		if keys, ok := staticPath(n); ok {
+			out = append(out, keys)
+			return
+		}
+		switch n := n.(type) {
+		case *accessNode:
+			visit(n.target)
+			visit(n.key)
+		case *listNode:
+			for _, item := range n.items {
+				visit(item)
+			}
+		case *unaryNode:
+			visit(n.operand)
+		case *binaryNode:
+			visit(n.left)
+			visit(n.right)
+		case *callNode:
+			for _, arg := range n.args {
+				visit(arg)
+			}
+		}
+	}
+	visit(expr)
+	return out
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func lex(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, source[start:i], start})
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, source[start:i], start})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(source) && rune(source[i]) != c {
+				if source[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(source) {
+				return nil, fmt.Errorf("unterminated string at column %d", start+1)
+			}
+			i++
+			text := source[start:i]
+			if c == '\'' {
+				text = `"` + strings.ReplaceAll(strings.ReplaceAll(text[1:len(text)-1], `"`, `\"`), `\'`, `'`) + `"`
+			}
+			value, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at column %d: %w", start+1, err)
+			}
+			tokens = append(tokens, token{tokenString, value, start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, token{tokenOperator, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at column %d", c, i+1)
+			}
+		}
+	}
+	return append(tokens, token{tokenEOF, "end of expression", len(source)}), nil
+}
+
+// Parser
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) accept(text string) bool {
+	if tok := p.peek(); (tok.kind == tokenOperator || tok.kind == tokenIdent) && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at column %d, got %q", text, tok.pos+1, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.accept(op) {
+			right, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &binaryNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	expr, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			tok := p.next()
+			if tok.kind != tokenIdent {
+				return nil, fmt.Errorf("expected a field name at column %d, got %q", tok.pos+1, tok.text)
+			}
+			expr = &accessNode{target: expr, key: &literalNode{value: tok.text}}
+		case p.accept("["):
+			key, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			expr = &accessNode{target: expr, key: key, indexed: true}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *parser) parseAtom() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at column %d", tok.text, tok.pos+1)
+		}
+		return &literalNode{value: value}, nil
+	case tokenString:
+		return &literalNode{value: tok.text}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.accept("(") {
+			return p.parseCall(tok)
+		}
+		return &variableNode{name: tok.text}, nil
+	case tokenOperator:
+		switch tok.text {
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		case "[":
+			list := &listNode{}
+			for !p.accept("]") {
+				if len(list.items) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+			}
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at column %d", tok.text, tok.pos+1)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	call := &callNode{name: name.text}
+	for !p.accept(")") {
+		if len(call.args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+	return call, nil
+}
+
+// Nodes
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) check(map[string]*exprType) (*exprType, error) {
+	switch n.value.(type) {
+	case bool:
+		return boolType, nil
+	case float64:
+		return numberType, nil
+	case string:
+		return stringType, nil
+	}
+	return nullType, nil
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *literalNode) String() string {
+	switch value := n.value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(n.value)
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) check(vars map[string]*exprType) (*exprType, error) {
+	t, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %q (expected one of %s)", n.name, strings.Join(sortedKeys(vars), ", "))
+	}
+	return t, nil
+}
+
+func (n *variableNode) eval(vars map[string]interface{}) (interface{}, error) {
+	return vars[n.name], nil
+}
+
+func (n *variableNode) String() string {
+	return n.name
+}
+
+// accessNode reads a field with .name or ["name"], or a list item with [n]
+type accessNode struct {
+	target  node
+	key     node
+	indexed bool
+}
+
+func (n *accessNode) check(vars map[string]*exprType) (*exprType, error) {
+	target, err := n.target.check(vars)
+	if err != nil {
+		return nil, err
+	}
+	key, err := n.key.check(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch target.kind {
+	case typeAny:
+		return anyType, nil
+	case typeObject:
+		literal, ok := n.key.(*literalNode)
+		name, isString := literalValue(literal).(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("%s: fields of %s must be named with a string", n, n.target)
+		}
+		field, ok := target.fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %q (expected one of %s)", n.target, name, strings.Join(sortedKeys(target.fields), ", "))
+		}
+		return field, nil
+	case typeList:
+		if key.kind != typeNumber && key.kind != typeAny {
+			return nil, fmt.Errorf("%s: list index must be a number, got %s", n, key)
+		}
+		return target.elem, nil
+	}
+	return nil, fmt.Errorf("%s: cannot read a field of a %s", n, target)
+}
+
+func (n *accessNode) eval(vars map[string]interface{}) (interface{}, error) {
+	target, err := n.target.eval(vars)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	key, err := n.key.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch target := target.(type) {
+	case map[string]interface{}:
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: field name must be a string", n)
+		}
+		return target[name], nil
+	case []interface{}:
+		index, ok := key.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: list index must be a number", n)
+		}
+		if index < 0 || int(index) >= len(target) {
+			return nil, nil
+		}
+		return target[int(index)], nil
+	}
+	return nil, fmt.Errorf("%s: %s is a %s, not an object", n, n.target, kindOf(target))
+}
+
+func (n *accessNode) String() string {
+	if literal, ok := n.key.(*literalNode); ok && !n.indexed {
+		return fmt.Sprintf("%s.%s", n.target, literal.value)
+	}
+	return fmt.Sprintf("%s[%s]", n.target, n.key)
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) check(vars map[string]*exprType) (*exprType, error) {
+	var elem *exprType
+	for _, item := range n.items {
+		t, err := item.check(vars)
+		if err != nil {
+			return nil, err
+		}
+		if elem == nil {
+			elem = t
+		} else if elem.kind != t.kind {
+			elem = anyType
+		}
+	}
+	if elem == nil {
+		elem = anyType
+	}
+	return &exprType{kind: typeList, elem: elem}, nil
+}
+
+func (n *listNode) eval(vars map[string]interface{}) (interface{}, error) {
+	list := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+func (n *listNode) String() string {
+	items := make([]string, len(n.items))
+	for i, item := range n.items {
+		items[i] = item.String()
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// unaryNode negates a condition
+type unaryNode struct {
+	operand node
+}
+
+func (n *unaryNode) check(vars map[string]*exprType) (*exprType, error) {
+	t, err := n.operand.check(vars)
+	if err != nil {
+		return nil, err
+	}
+	if !isCondition(t) {
+		return nil, fmt.Errorf("%s: cannot negate a %s", n, t)
+	}
+	return boolType, nil
+}
+
+func (n *unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := evalCondition(n.operand, vars)
+	return !value, err
+}
+
+func (n *unaryNode) String() string {
+	return "!" + n.operand.String()
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) check(vars map[string]*exprType) (*exprType, error) {
+	left, err := n.left.check(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.check(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		if !isCondition(left) || !isCondition(right) {
+			return nil, fmt.Errorf("%s: %s needs two conditions, got %s and %s", n, n.op, left, right)
+		}
+	case "==", "!=":
+		if !compatible(left, right) {
+			return nil, fmt.Errorf("%s: cannot compare a %s with a %s", n, left, right)
+		}
+	case "<", "<=", ">", ">=":
+		if !ordered(left) || !ordered(right) || !compatible(left, right) {
+			return nil, fmt.Errorf("%s: cannot order a %s and a %s", n, left, right)
+		}
+	case "in":
+		switch right.kind {
+		case typeAny:
+		case typeList:
+			if !compatible(left, right.elem) {
+				return nil, fmt.Errorf("%s: a %s is never in a list of %s", n, left, right.elem)
+			}
+		default:
+			return nil, fmt.Errorf("%s: in needs a list, got a %s", n, right)
+		}
+	}
+	return boolType, nil
+}
+
+func (n *binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	if n.op == "&&" || n.op == "||" {
+		left, err := evalCondition(n.left, vars)
+		if err != nil {
+			return nil, err
+		}
+		if (n.op == "&&" && !left) || (n.op == "||" && left) {
+			return left, nil
+		}
+		return evalCondition(n.right, vars)
+	}
+
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "in":
+		if right == nil {
+			return false, nil
+		}
+		list, ok := right.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is a %s, not a list", n, n.right, kindOf(right))
+		}
+		for _, item := range list {
+			if reflect.DeepEqual(left, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if left == nil || right == nil {
+		return false, nil
+	}
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot order a number and a %s", n, kindOf(right))
+		}
+		cmp = compareNumbers(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot order a string and a %s", n, kindOf(right))
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("%s: cannot order a %s", n, kindOf(left))
+	}
+
+	switch n.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+func (n *binaryNode) String() string {
+	return fmt.Sprintf("%s %s %s", n.left, n.op, n.right)
+}
+
+type callNode struct {
+	name    string
+	args    []node
+	pattern *regexp.Regexp
+}
+
+func (n *callNode) check(vars map[string]*exprType) (*exprType, error) {
+	types := make([]*exprType, len(n.args))
+	for i, arg := range n.args {
+		t, err := arg.check(vars)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+	}
+
+	switch n.name {
+	case "len":
+		if len(types) != 1 {
+			return nil, fmt.Errorf("%s: len takes one argument", n)
+		}
+		switch types[0].kind {
+		case typeAny, typeString, typeList, typeObject:
+			return numberType, nil
+		}
+		return nil, fmt.Errorf("%s: len of a %s", n, types[0])
+	case "matches":
+		if len(types) != 2 {
+			return nil, fmt.Errorf("%s: matches takes a value and a pattern", n)
+		}
+		if types[0].kind != typeString && types[0].kind != typeAny {
+			return nil, fmt.Errorf("%s: matches needs a string, got a %s", n, types[0])
+		}
+		literal, ok := n.args[1].(*literalNode)
+		pattern, isString := literalValue(literal).(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("%s: the pattern must be a string literal", n)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n, err)
+		}
+		n.pattern = re
+		return boolType, nil
+	case "quantity":
+		if len(types) != 1 {
+			return nil, fmt.Errorf("%s: quantity takes one argument", n)
+		}
+		switch types[0].kind {
+		case typeAny, typeString, typeNumber:
+			return numberType, nil
+		}
+		return nil, fmt.Errorf("%s: quantity of a %s", n, types[0])
+	}
+	return nil, fmt.Errorf("unknown function %q (expected len, matches or quantity)", n.name)
+}
+
+func (n *callNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.args[0].eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.name {
+	case "len":
+		switch value := value.(type) {
+		case nil:
+			return float64(0), nil
+		case string:
+			return float64(len([]rune(value))), nil
+		case []interface{}:
+			return float64(len(value)), nil
+		case map[string]interface{}:
+			return float64(len(value)), nil
+		}
+	case "matches":
+		switch value := value.(type) {
+		case nil:
+			return false, nil
+		case string:
+			return n.pattern.MatchString(value), nil
+		}
+	case "quantity":
+		switch value := value.(type) {
+		case nil, float64:
+			return value, nil
+		case string:
+			quantity, err := helm.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", n, err)
+			}
+			return quantity, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: unexpected %s argument", n, kindOf(value))
+}
+
+func (n *callNode) String() string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", n.name, strings.Join(args, ", "))
+}
+
+// Helper methods
+
+// staticPath returns the variable and constant keys of a field access such
+// as values.ingress.hosts[0].host
+func staticPath(n node) ([]string, bool) {
+	switch n := n.(type) {
+	case *variableNode:
+		return []string{n.name}, true
+	case *accessNode:
+		keys, ok := staticPath(n.target)
+		literal, isLiteral := n.key.(*literalNode)
+		if !ok || !isLiteral {
+			return nil, false
+		}
+		switch key := literal.value.(type) {
+		case string:
+			return append(keys, key), true
+		case float64:
+			return append(keys, strconv.Itoa(int(key))), true
+		}
+	}
+	return nil, false
+}
+
+func literalValue(n *literalNode) interface{} {
+	if n == nil {
+		return nil
+	}
+	return n.value
+}
+
+func truth(value interface{}, expr node) (bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return value, nil
+	}
+	return false, fmt.Errorf("%s is a %s, not a condition", expr, kindOf(value))
+}
+
+func isCondition(t *exprType) bool {
+	return t.kind == typeBool || t.kind == typeAny || t.kind == typeNull
+}
+
+func ordered(t *exprType) bool {
+	return t.kind == typeNumber || t.kind == typeString || t.kind == typeAny || t.kind == typeNull
+}
+
+func compatible(a, b *exprType) bool {
+	switch {
+	case a.kind == typeAny || b.kind == typeAny, a.kind == typeNull || b.kind == typeNull:
+		return true
+	}
+	return a.kind == b.kind
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// kindOf names the kind of an evaluated value
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// normalize converts decoded YAML into the values expressions work on:
+// numbers become float64 and maps have string keys
+func normalize(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			out[k] = normalize(v)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			out[fmt.Sprint(k)] = normalize(v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, v := range value {
+			out[i] = normalize(v)
+		}
+		return out
+	}
+	return value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
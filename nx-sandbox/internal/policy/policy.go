@@ -0,0 +1,412 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/validate"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+	"gopkg.in/yaml.v3"
+)
+
+// Artifact is the artifact variable of both targets. For values the
+// domain, service and owner come from the matching inventory, if any.
+type Artifact struct {
+	Name        string `yaml:"name"`
+	Layer       string `yaml:"layer"`
+	Environment string `yaml:"environment"`
+	Domain      string `yaml:"domain"`
+	Service     string `yaml:"service"`
+	Owner       string `yaml:"owner"`
+}
+
+// Chart is the chart variable of the values target
+type Chart struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	AppVersion string `yaml:"appVersion"`
+}
+
+// targetVariables are the typed variables each target's expressions see.
+// teams are the approver teams and environments the configured environments.
+var targetVariables = map[models.PolicyTarget]map[string]*exprType{
+	models.PolicyTargetInventory: {
+		"inventory":    typeOf(reflect.TypeOf(models.AppInventory{})),
+		"artifact":     typeOf(reflect.TypeOf(Artifact{})),
+		"teams":        typeOf(reflect.TypeOf([]string{})),
+		"environments": typeOf(reflect.TypeOf([]string{})),
+	},
+	models.PolicyTargetValues: {
+		"values":       anyType,
+		"chart":        typeOf(reflect.TypeOf(Chart{})),
+		"artifact":     typeOf(reflect.TypeOf(Artifact{})),
+		"teams":        typeOf(reflect.TypeOf([]string{})),
+		"environments": typeOf(reflect.TypeOf([]string{})),
+	},
+}
+
+// inventoryArtifactFields locate the artifact fields of an inventory
+var inventoryArtifactFields = map[string]string{
+	"artifact.name":        "artifact_metadata.artifact_name",
+	"artifact.layer":       "artifact_metadata.layer",
+	"artifact.environment": "infrastructure.environment",
+	"artifact.domain":      "artifact_metadata.domain",
+	"artifact.service":     "artifact_metadata.service",
+	"artifact.owner":       "artifact_metadata.owner",
+}
+
+// Set is a loaded policy file whose expressions have been type-checked
+type Set struct {
+	Policies []models.Policy
+	compiled []compiledPolicy
+}
+
+type compiledPolicy struct {
+	policy  models.Policy
+	when    node
+	require node
+}
+
+// Load reads and type-checks a policy file
+func Load(path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+	set, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policies %s: %w", path, err)
+	}
+	return set, nil
+}
+
+// LoadDefault reads config/policies.yaml of a sandbox root; a sandbox
+// without the file has no policies
+func LoadDefault(baseDir string) (*Set, error) {
+	path := layout.PoliciesFile(baseDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &Set{}, nil
+	}
+	return Load(path)
+}
+
+// Parse type-checks the policies of a policy file
+func Parse(data []byte) (*Set, error) {
+	var file struct {
+		Policies []models.Policy `yaml:"policies"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	set := &Set{}
+	seen := make(map[string]bool)
+	for i, policy := range file.Policies {
+		if policy.Name == "" {
+			return nil, fmt.Errorf("policy %d has no name", i+1)
+		}
+		if seen[policy.Name] {
+			return nil, fmt.Errorf("duplicate policy %q", policy.Name)
+		}
+		seen[policy.Name] = true
+
+		compiled, err := compile(policy)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", policy.Name, err)
+		}
+		set.Policies = append(set.Policies, compiled.policy)
+		set.compiled = append(set.compiled, compiled)
+	}
+	return set, nil
+}
+
+// Checker defines the interface for evaluating policies over the sandbox
+type Checker interface {
+	Check() (*models.PolicyReport, error)
+	CheckFiles(paths []string) (*models.PolicyReport, error)
+}
+
+// DefaultChecker evaluates a policy set over the inventories and chart
+// values the validator knows about
+type DefaultChecker struct {
+	baseDir string
+	set     *Set
+}
+
+// NewChecker creates a new policy checker for a sandbox root
+func NewChecker(baseDir string, set *Set) Checker {
+	return &DefaultChecker{
+		baseDir: baseDir,
+		set:     set,
+	}
+}
+
+// Check evaluates the policies over every inventory and chart values file
+func (c *DefaultChecker) Check() (*models.PolicyReport, error) {
+	files, err := validate.Files(c.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return c.CheckFiles(files)
+}
+
+// CheckFiles evaluates the policies over the given files, skipping files
+// that are not an inventory or chart values, are missing or do not parse;
+// the validator reports those.
+func (c *DefaultChecker) CheckFiles(paths []string) (*models.PolicyReport, error) {
+	cfg, err := config.Load(c.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]interface{}, 0, len(cfg.Approvers.Teams))
+	for _, team := range sortedKeys(cfg.Approvers.Teams) {
+		teams = append(teams, team)
+	}
+	environments := make([]interface{}, 0, len(cfg.Environments))
+	for _, env := range cfg.Environments {
+		environments = append(environments, env)
+	}
+
+	report := &models.PolicyReport{
+		Policies:   c.set.Policies,
+		Checked:    []string{},
+		Violations: []models.PolicyViolation{},
+	}
+	if report.Policies == nil {
+		report.Policies = []models.Policy{}
+	}
+
+	for _, path := range paths {
+		var target models.PolicyTarget
+		switch validate.Classify(c.baseDir, path) {
+		case validate.KindInventory:
+			target = models.PolicyTargetInventory
+		case validate.KindValues:
+			target = models.PolicyTargetValues
+		default:
+			continue
+		}
+
+		doc, err := yamldoc.Load(path)
+		if err != nil {
+			continue
+		}
+		s, ok := c.subject(target, path, doc)
+		if !ok {
+			continue
+		}
+		s.vars["teams"] = teams
+		s.vars["environments"] = environments
+
+		report.Checked = append(report.Checked, path)
+		report.Violations = append(report.Violations, c.set.evaluate(target, s)...)
+	}
+
+	return report, nil
+}
+
+// Issues converts violations to validation issues, with the rule
+// policy/<name>
+func Issues(report *models.PolicyReport) []models.ValidationIssue {
+	issues := make([]models.ValidationIssue, 0, len(report.Violations))
+	for _, violation := range report.Violations {
+		issues = append(issues, models.ValidationIssue{
+			Path:     violation.Path,
+			Line:     violation.Line,
+			Rule:     "policy/" + violation.Policy,
+			Severity: violation.Severity,
+			Message:  violation.Message,
+		})
+	}
+	return issues
+}
+
+// Helper methods
+
+func compile(policy models.Policy) (compiledPolicy, error) {
+	if policy.Severity == "" {
+		policy.Severity = models.SeverityError
+	}
+	if policy.Severity != models.SeverityError && policy.Severity != models.SeverityWarning {
+		return compiledPolicy{}, fmt.Errorf("unknown severity %q (expected error or warning)", policy.Severity)
+	}
+	vars, ok := targetVariables[policy.Target]
+	if !ok {
+		return compiledPolicy{}, fmt.Errorf("unknown target %q (expected inventory or values)", policy.Target)
+	}
+	if strings.TrimSpace(policy.Require) == "" {
+		return compiledPolicy{}, fmt.Errorf("require is missing")
+	}
+
+	compiled := compiledPolicy{policy: policy}
+	var err error
+	if strings.TrimSpace(policy.When) != "" {
+		if compiled.when, err = parseExpr(policy.When, vars); err != nil {
+			return compiledPolicy{}, fmt.Errorf("when: %w", err)
+		}
+	}
+	if compiled.require, err = parseExpr(policy.Require, vars); err != nil {
+		return compiledPolicy{}, fmt.Errorf("require: %w", err)
+	}
+	return compiled, nil
+}
+
+// subject is a file a policy is evaluated against
+type subject struct {
+	path     string
+	doc      *yamldoc.Document
+	artifact Artifact
+	vars     map[string]interface{}
+}
+
+func (c *DefaultChecker) subject(target models.PolicyTarget, path string, doc *yamldoc.Document) (*subject, bool) {
+	s := &subject{path: path, doc: doc, vars: make(map[string]interface{})}
+
+	switch target {
+	case models.PolicyTargetInventory:
+		var inv models.AppInventory
+		if err := doc.Decode(&inv); err != nil {
+			return nil, false
+		}
+		meta := inv.ArtifactMetadata
+		s.artifact = Artifact{
+			Name:        meta.ArtifactName,
+			Layer:       filepath.Base(filepath.Dir(filepath.Dir(path))),
+			Environment: inv.Infrastructure.Environment,
+			Domain:      meta.Domain,
+			Service:     meta.Service,
+			Owner:       meta.Owner,
+		}
+		if s.artifact.Name == "" {
+			s.artifact.Name = filepath.Base(filepath.Dir(path))
+		}
+		s.vars["inventory"] = toValue(inv)
+
+	case models.PolicyTargetValues:
+		var values interface{}
+		if err := doc.Decode(&values); err != nil {
+			return nil, false
+		}
+		dir := filepath.Dir(path)
+		env := strings.TrimPrefix(filepath.Base(filepath.Dir(filepath.Dir(dir))), layout.EnvironmentRepoPrefix)
+		s.artifact = Artifact{
+			Name:        filepath.Base(dir),
+			Layer:       filepath.Base(filepath.Dir(dir)),
+			Environment: env,
+		}
+		if entry, err := inventory.Open(c.baseDir, s.artifact.Name, env); err == nil {
+			meta := entry.Inventory.ArtifactMetadata
+			s.artifact.Domain, s.artifact.Service, s.artifact.Owner = meta.Domain, meta.Service, meta.Owner
+		}
+
+		var chart Chart
+		if data, err := os.ReadFile(filepath.Join(dir, helm.ChartFileName)); err == nil {
+			yaml.Unmarshal(data, &chart)
+		}
+		s.vars["values"] = normalize(values)
+		s.vars["chart"] = toValue(chart)
+	}
+
+	s.vars["artifact"] = toValue(s.artifact)
+	return s, true
+}
+
+// evaluate returns the violations of a subject. A policy that cannot be
+// evaluated, for example because a value has the wrong type, is reported
+// as a violation too.
+func (set *Set) evaluate(target models.PolicyTarget, s *subject) []models.PolicyViolation {
+	var violations []models.PolicyViolation
+	for _, compiled := range set.compiled {
+		policy := compiled.policy
+		if policy.Target != target {
+			continue
+		}
+
+		message := ""
+		if compiled.when != nil {
+			applies, err := evalCondition(compiled.when, s.vars)
+			if err != nil {
+				message = fmt.Sprintf("cannot evaluate when: %v", err)
+			} else if !applies {
+				continue
+			}
+		}
+		if message == "" {
+			satisfied, err := evalCondition(compiled.require, s.vars)
+			switch {
+			case err != nil:
+				message = fmt.Sprintf("cannot evaluate require: %v", err)
+			case satisfied:
+				continue
+			case policy.Message != "":
+				message = policy.Message
+			case policy.Description != "":
+				message = policy.Description
+			default:
+				message = fmt.Sprintf("requires %s", policy.Require)
+			}
+		}
+
+		violations = append(violations, models.PolicyViolation{
+			Policy:      policy.Name,
+			Severity:    policy.Severity,
+			Artifact:    s.artifact.Name,
+			Environment: s.artifact.Environment,
+			Path:        s.path,
+			Line:        s.line(target, compiled),
+			Message:     message,
+		})
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Line < violations[j].Line })
+	return violations
+}
+
+// line locates a violation at the first document field the requirement
+// reads, falling back to the condition and then the nearest existing parent
+func (s *subject) line(target models.PolicyTarget, compiled compiledPolicy) int {
+	root := string(target)
+	exprs := []node{compiled.require}
+	if compiled.when != nil {
+		exprs = append(exprs, compiled.when)
+	}
+	for _, expr := range exprs {
+		for _, keys := range paths(expr) {
+			if field, ok := inventoryArtifactFields[strings.Join(keys, ".")]; ok && target == models.PolicyTargetInventory {
+				keys = append([]string{root}, strings.Split(field, ".")...)
+			}
+			if keys[0] != root || len(keys) < 2 {
+				continue
+			}
+			for i := len(keys); i > 1; i-- {
+				if node := s.doc.GetKeys(keys[1:i]); node != nil {
+					return node.Line
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// toValue converts a typed model to the values expressions work on, keyed
+// by its yaml field names
+func toValue(model interface{}) interface{} {
+	data, err := yaml.Marshal(model)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return normalize(value)
+}
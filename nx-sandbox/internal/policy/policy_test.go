@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+const testPolicies = `policies:
+  - name: prod-autoscaling
+    description: prod1 services must autoscale
+    target: values
+    when: artifact.environment == "prod1"
+    require: values.autoscaling.enabled == true
+  - name: rds-layers
+    target: inventory
+    when: inventory.components.rds.enabled
+    require: artifact.layer in ["bal", "bc"]
+    message: rds is only allowed in the bal and bc layers
+  - name: known-owner
+    severity: warning
+    target: inventory
+    require: artifact.owner in teams
+`
+
+func setupTestEnv(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(layout.PoliciesFile(baseDir)), 0755)
+	if err := os.WriteFile(layout.PoliciesFile(baseDir), []byte(testPolicies), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return baseDir
+}
+
+func TestExpressions(t *testing.T) {
+	vars := map[string]interface{}{
+		"values": normalize(map[string]interface{}{
+			"replicaCount": 3,
+			"image":        map[string]interface{}{"tag": "1.4.2"},
+			"resources":    map[string]interface{}{"limits": map[string]interface{}{"cpu": "500m"}},
+			"ingress": map[string]interface{}{
+				"hosts":       []interface{}{map[string]interface{}{"host": "svc.prod1.example.com"}},
+				"annotations": map[string]interface{}{"nginx.ingress.kubernetes.io/ssl-redirect": "true"},
+			},
+		}),
+		"artifact": toValue(Artifact{Name: "nx-bff-svc", Layer: "bff", Environment: "prod1"}),
+		"teams":    []interface{}{"devx-team"},
+	}
+	types := targetVariables[models.PolicyTargetValues]
+
+	tests := map[string]bool{
+		`values.replicaCount >= 2 && values.replicaCount < 5`:                              true,
+		`quantity(values.resources.limits.cpu) <= 0.5`:                                     true,
+		`matches(values.image.tag, "^1\\.")`:                                               true,
+		`values.ingress.hosts[0].host == "svc.prod1.example.com"`:                          true,
+		`values.ingress.annotations["nginx.ingress.kubernetes.io/ssl-redirect"] == 'true'`: true,
+		`artifact.layer in ["bal", "bc"]`:                                                  false,
+		`!(artifact.layer in ["bal", "bc"]) || artifact.owner in teams`:                    true,
+		`values.autoscaling.enabled`:                                                       false,
+		`values.autoscaling.maxReplicas > 1`:                                               false,
+		`values.missing == null && len(values.ingress.hosts) == 1`:                         true,
+	}
+	for source, expected := range tests {
+		expr, err := parseExpr(source, types)
+		if err != nil {
+			t.Errorf("parseExpr(%s) failed: %v", source, err)
+			continue
+		}
+		if got, err := evalCondition(expr, vars); err != nil || got != expected {
+			t.Errorf("Expected %s to be %v, got %v: %v", source, expected, got, err)
+		}
+	}
+
+	if expr, _ := parseExpr(`values.image.tag.major == 1`, types); expr != nil {
+		if _, err := evalCondition(expr, vars); err == nil {
+			t.Error("Expected reading a field of a string to fail")
+		}
+	}
+}
+
+func TestParse_TypeErrors(t *testing.T) {
+	for source, expected := range map[string]string{
+		`inventory.componets.rds.enabled`:            `no field "componets"`,
+		`inventory.components.rds.enabled == "true"`: "cannot compare a bool with a string",
+		`artifact.owner`:                             "expected a condition",
+		`artifact.layer in "bal"`:                    "in needs a list",
+		`values.replicaCount > 1`:                    `unknown variable "values"`,
+		`len(inventory.components.rds.enabled) > 0`:  "len of a bool",
+		`matches(artifact.name, "[")`:                "missing closing ]",
+		`artifact.name == "a" &&`:                    "unexpected",
+		`inventory.infrastructure.deployed && 1`:     "needs two conditions",
+		`size(artifact.name) > 1`:                    `unknown function "size"`,
+	} {
+		policies := "policies:\n  - name: test\n    target: inventory\n    require: '" + strings.ReplaceAll(source, "'", "''") + "'\n"
+		if _, err := Parse([]byte(policies)); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %s to fail with %q, got %v", source, expected, err)
+		}
+	}
+
+	for policies, expected := range map[string]string{
+		"policies:\n  - target: values\n    require: 'true'\n":                                                                    "has no name",
+		"policies:\n  - name: a\n    target: chart\n    require: 'true'\n":                                                        `unknown target "chart"`,
+		"policies:\n  - name: a\n    target: values\n    severity: info\n    require: 'true'\n":                                   `unknown severity "info"`,
+		"policies:\n  - name: a\n    target: values\n    require: 'true'\n  - name: a\n    target: values\n    require: 'true'\n": "duplicate",
+	} {
+		if _, err := Parse([]byte(policies)); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q to fail with %q, got %v", policies, expected, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	baseDir := setupTestEnv(t)
+
+	values := filepath.Join(layout.ChartDir(baseDir, "prod1", "bff", "nx-bff-test-service"), "values.yaml")
+	data, _ := os.ReadFile(values)
+	os.WriteFile(values, []byte(strings.Replace(string(data), "autoscaling:\n  enabled: true", "autoscaling:\n  enabled: false", 1)), 0644)
+
+	entry, err := inventory.Open(baseDir, "nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	entry.Set("components.rds.enabled", true)
+	entry.Set("artifact_metadata.owner", "nobody")
+	if err := entry.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	set, err := LoadDefault(baseDir)
+	if err != nil {
+		t.Fatalf("LoadDefault failed: %v", err)
+	}
+	report, err := NewChecker(baseDir, set).Check()
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	if len(report.Violations) != 3 || report.Errors() != 2 {
+		t.Fatalf("Expected 3 violations with 2 errors, got %+v", report.Violations)
+	}
+	byPolicy := make(map[string]models.PolicyViolation)
+	for _, violation := range report.Violations {
+		byPolicy[violation.Policy] = violation
+	}
+
+	autoscaling := byPolicy["prod-autoscaling"]
+	if autoscaling.Artifact != "nx-bff-test-service" || autoscaling.Environment != "prod1" ||
+		autoscaling.Message != "prod1 services must autoscale" || autoscaling.Line == 0 {
+		t.Errorf("Unexpected autoscaling violation %+v", autoscaling)
+	}
+	if rds := byPolicy["rds-layers"]; rds.Artifact != "nx-bff-web-payment-dev1" || rds.Message != "rds is only allowed in the bal and bc layers" {
+		t.Errorf("Unexpected rds violation %+v", rds)
+	}
+	if owner := byPolicy["known-owner"]; owner.Severity != models.SeverityWarning || owner.Line == 0 {
+		t.Errorf("Unexpected owner violation %+v", owner)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	values := filepath.Join(layout.ChartDir(baseDir, "prod1", "bff", "nx-bff-test-service"), "values.yaml")
+
+	report, err := NewValidator(baseDir).ValidateFiles([]string{values})
+	if err != nil {
+		t.Fatalf("ValidateFiles failed: %v", err)
+	}
+	if !report.Valid() || len(report.Issues) != 0 {
+		t.Errorf("Expected seeded values to pass, got %+v", report.Issues)
+	}
+
+	os.WriteFile(layout.PoliciesFile(baseDir), []byte("policies:\n  - name: broken\n    target: values\n    require: values.x ==\n"), 0644)
+	report, err = NewValidator(baseDir).ValidateFiles([]string{values})
+	if err != nil {
+		t.Fatalf("ValidateFiles failed: %v", err)
+	}
+	if report.Valid() || report.Issues[0].Rule != "policy-file" {
+		t.Errorf("Expected the broken policy file to be reported, got %+v", report.Issues)
+	}
+}
@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"sort"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/validate"
+)
+
+// Validator runs the file validator and adds the violations of the
+// sandbox policies to its report. The policy file is read on every run so
+// long-running commands pick up edits; an invalid policy file is reported
+// as an issue of that file.
+type Validator struct {
+	baseDir   string
+	validator validate.Validator
+}
+
+// NewValidator creates a validator that also enforces config/policies.yaml
+func NewValidator(baseDir string) validate.Validator {
+	return &Validator{
+		baseDir:   baseDir,
+		validator: validate.NewValidator(baseDir),
+	}
+}
+
+// Validate checks every inventory and chart, including the policies
+func (v *Validator) Validate() (*models.ValidationReport, error) {
+	report, err := v.validator.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return v.withPolicies(report)
+}
+
+// ValidateFiles checks the given files, including the policies
+func (v *Validator) ValidateFiles(paths []string) (*models.ValidationReport, error) {
+	report, err := v.validator.ValidateFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	return v.withPolicies(report)
+}
+
+// Helper methods
+
+func (v *Validator) withPolicies(report *models.ValidationReport) (*models.ValidationReport, error) {
+	set, err := LoadDefault(v.baseDir)
+	if err != nil {
+		report.Issues = append(report.Issues, models.ValidationIssue{
+			Path:     layout.PoliciesFile(v.baseDir),
+			Rule:     "policy-file",
+			Severity: models.SeverityError,
+			Message:  err.Error(),
+		})
+		return report, nil
+	}
+	if len(set.Policies) == 0 {
+		return report, nil
+	}
+
+	policies, err := NewChecker(v.baseDir, set).CheckFiles(report.Checked)
+	if err != nil {
+		return nil, err
+	}
+	report.Issues = append(report.Issues, Issues(policies)...)
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Path != report.Issues[j].Path {
+			return report.Issues[i].Path < report.Issues[j].Path
+		}
+		return report.Issues[i].Line < report.Issues[j].Line
+	})
+	return report, nil
+}
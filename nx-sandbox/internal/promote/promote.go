@@ -0,0 +1,234 @@
+package promote
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+	"gopkg.in/yaml.v3"
+)
+
+// Request describes a promotion of a service between environments
+type Request struct {
+	Service string
+	From    string
+	To      string
+}
+
+// FileChange is a file the promotion would create or modify.
+// Before is nil for files that do not exist in the target yet.
+type FileChange struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// Plan is the set of changes a promotion would make
+type Plan struct {
+	Request   Request
+	SourceDir string
+	TargetDir string
+	Changes   []FileChange
+}
+
+// Promoter defines the interface for promoting charts between environments
+type Promoter interface {
+	Plan(req Request) (*Plan, error)
+	Apply(plan *Plan) error
+}
+
+// DefaultPromoter copies charts between nx-bolt-environment repositories,
+// rewriting environment-specific values from the sandbox configuration
+type DefaultPromoter struct {
+	baseDir string
+}
+
+// NewPromoter creates a new promoter for a sandbox root
+func NewPromoter(baseDir string) Promoter {
+	return &DefaultPromoter{
+		baseDir: baseDir,
+	}
+}
+
+// Plan computes the changes needed to promote a service without writing anything
+func (p *DefaultPromoter) Plan(req Request) (*Plan, error) {
+	cfg, err := config.Load(p.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.HasEnvironment(req.From) {
+		return nil, fmt.Errorf("unknown source environment '%s'", req.From)
+	}
+	if !cfg.HasEnvironment(req.To) {
+		return nil, fmt.Errorf("unknown target environment '%s'", req.To)
+	}
+	if next, ok := cfg.NextEnvironment(req.From); !ok || next != req.To {
+		if !ok {
+			return nil, fmt.Errorf("%s is the last environment in the promotion chain %s", req.From, strings.Join(cfg.Environments, " → "))
+		}
+		return nil, fmt.Errorf("%s must be promoted to %s, not %s (promotion chain: %s)", req.From, next, req.To, strings.Join(cfg.Environments, " → "))
+	}
+
+	sourceDir, err := helm.FindChart(p.baseDir, req.From, req.Service)
+	if err != nil {
+		return nil, err
+	}
+	layer := filepath.Base(filepath.Dir(sourceDir))
+	targetDir := layout.ChartDir(p.baseDir, req.To, layer, filepath.Base(sourceDir))
+
+	plan := &Plan{
+		Request:   req,
+		SourceDir: sourceDir,
+		TargetDir: targetDir,
+	}
+
+	var files []string
+	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source chart: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, source := range files {
+		rel, _ := filepath.Rel(sourceDir, source)
+		target := filepath.Join(targetDir, rel)
+
+		before, err := os.ReadFile(target)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		var after []byte
+		if rel == helm.ValuesFileName {
+			after, err = p.promoteValues(cfg, req, source, before)
+		} else {
+			after, err = os.ReadFile(source)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if before != nil && bytes.Equal(before, after) {
+			continue
+		}
+		plan.Changes = append(plan.Changes, FileChange{Path: target, Before: before, After: after})
+	}
+
+	return plan, nil
+}
+
+// Apply writes the planned changes to the target environment
+func (p *DefaultPromoter) Apply(plan *Plan) error {
+	for _, change := range plan.Changes {
+		if err := os.MkdirAll(filepath.Dir(change.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(change.Path), err)
+		}
+		if err := os.WriteFile(change.Path, change.After, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// Helper methods
+
+// promoteValues rewrites the source values for the target environment and
+// carries over target-only keys and configured preserved paths
+func (p *DefaultPromoter) promoteValues(cfg *config.Config, req Request, sourcePath string, targetData []byte) ([]byte, error) {
+	doc, err := yamldoc.Load(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	from := cfg.EnvironmentSettings(req.From)
+	to := cfg.EnvironmentSettings(req.To)
+	replacer := strings.NewReplacer(from.HostDomain, to.HostDomain, from.Namespace, to.Namespace)
+
+	type substitution struct {
+		keys  []string
+		value string
+	}
+	var substitutions []substitution
+	doc.Walk(func(keys []string, node *yaml.Node) {
+		if node.Tag != "!!str" {
+			return
+		}
+		if replaced := replacer.Replace(node.Value); replaced != node.Value {
+			substitutions = append(substitutions, substitution{keys, replaced})
+		}
+	})
+	for _, s := range substitutions {
+		if err := doc.SetKeys(s.keys, s.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if to.ImageTag != "" {
+		if err := doc.Set("image.tag", to.ImageTag); err != nil {
+			return nil, err
+		}
+	}
+
+	if targetData != nil {
+		target, err := yamldoc.Parse(targetData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse target values: %w", err)
+		}
+
+		for _, path := range cfg.Promotion.Preserve {
+			if node := target.Get(path); node != nil {
+				if err := doc.Set(path, node); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := keepTargetOnly(nil, target.GetKeys(nil), doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// keepTargetOnly copies mapping keys that exist in the target but not in the promoted values
+func keepTargetOnly(prefix []string, target *yaml.Node, doc *yamldoc.Document) error {
+	if target.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(target.Content); i += 2 {
+		keys := append(append([]string(nil), prefix...), target.Content[i].Value)
+		value := target.Content[i+1]
+
+		existing := doc.GetKeys(keys)
+		if existing == nil {
+			if err := doc.SetKeys(keys, value); err != nil {
+				return err
+			}
+			continue
+		}
+		if existing.Kind == yaml.MappingNode {
+			if err := keepTargetOnly(keys, value, doc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package promote
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sourceValues = `replicaCount: 2
+
+image:
+  repository: nx-registry/web
+  tag: "1.4.0"
+
+ingress:
+  hosts:
+    - host: web.dev1.nexus.britishairways.com
+
+serviceAccount:
+  namespace: nexus-dev1
+`
+
+const targetValues = `replicaCount: 4
+
+image:
+  repository: nx-registry/web
+  tag: "1.3.0"
+
+ingress:
+  hosts:
+    - host: web.sit1.nexus.britishairways.com
+
+serviceAccount:
+  namespace: nexus-sit1
+
+podAnnotations:
+  team: sit-only
+`
+
+// setupTestEnv creates dev1 and sit1 charts and an optional config file
+func setupTestEnv(t *testing.T, cfg string) string {
+	tmpDir := t.TempDir()
+
+	for env, values := range map[string]string{"dev1": sourceValues, "sit1": targetValues} {
+		dir := filepath.Join(tmpDir, "repos", "nx-bolt-environment-"+env, "bff", "nx-bff-web-service")
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: nx-bff-web-service\nversion: 1.0.0\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "values.yaml"), []byte(values), 0644)
+	}
+	os.MkdirAll(filepath.Join(tmpDir, "repos", "nx-bolt-environment-uat1"), 0755)
+
+	if cfg != "" {
+		os.MkdirAll(filepath.Join(tmpDir, ".nx-sandbox"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, ".nx-sandbox", "config.yaml"), []byte(cfg), 0644)
+	}
+
+	return tmpDir
+}
+
+func TestPlan_SubstitutesAndKeepsTargetOnly(t *testing.T) {
+	baseDir := setupTestEnv(t, "")
+	promoter := NewPromoter(baseDir)
+
+	plan, err := promoter.Plan(Request{Service: "nx-bff-web-service", From: "dev1", To: "sit1"})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if len(plan.Changes) != 1 {
+		t.Fatalf("Expected only values.yaml to change, got %d changes", len(plan.Changes))
+	}
+
+	values := string(plan.Changes[0].After)
+	for _, want := range []string{"host: web.sit1.nexus.britishairways.com", "namespace: nexus-sit1", `tag: "1.4.0"`, "replicaCount: 2", "team: sit-only"} {
+		if !strings.Contains(values, want) {
+			t.Errorf("Promoted values missing %q:\n%s", want, values)
+		}
+	}
+
+	// Nothing is written until Apply
+	data, _ := os.ReadFile(plan.Changes[0].Path)
+	if string(data) != targetValues {
+		t.Error("Plan must not modify the target")
+	}
+
+	if err := promoter.Apply(plan); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	data, _ = os.ReadFile(plan.Changes[0].Path)
+	if string(data) != values {
+		t.Error("Apply should write the planned values")
+	}
+}
+
+func TestPlan_PreserveAndImageTag(t *testing.T) {
+	cfg := `promotion:
+  preserve: [replicaCount]
+  environments:
+    sit1:
+      image_tag: stable
+`
+	baseDir := setupTestEnv(t, cfg)
+
+	plan, err := NewPromoter(baseDir).Plan(Request{Service: "nx-bff-web-service", From: "dev1", To: "sit1"})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	values := string(plan.Changes[0].After)
+	if !strings.Contains(values, "replicaCount: 4") {
+		t.Errorf("Expected preserved replicaCount:\n%s", values)
+	}
+	if !strings.Contains(values, `tag: "stable"`) {
+		t.Errorf("Expected pinned image tag:\n%s", values)
+	}
+}
+
+func TestPlan_NewTargetChart(t *testing.T) {
+	baseDir := setupTestEnv(t, "")
+	os.RemoveAll(filepath.Join(baseDir, "repos", "nx-bolt-environment-sit1", "bff"))
+
+	plan, err := NewPromoter(baseDir).Plan(Request{Service: "nx-bff-web-service", From: "dev1", To: "sit1"})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if len(plan.Changes) != 2 {
+		t.Fatalf("Expected Chart.yaml and values.yaml to be created, got %d", len(plan.Changes))
+	}
+	for _, change := range plan.Changes {
+		if change.Before != nil {
+			t.Errorf("Expected %s to be new", change.Path)
+		}
+	}
+}
+
+func TestPlan_EnforcesOrder(t *testing.T) {
+	baseDir := setupTestEnv(t, "")
+	promoter := NewPromoter(baseDir)
+
+	cases := []Request{
+		{Service: "nx-bff-web-service", From: "dev1", To: "uat1"},
+		{Service: "nx-bff-web-service", From: "sit1", To: "dev1"},
+		{Service: "nx-bff-web-service", From: "prod1", To: "dev1"},
+		{Service: "nx-bff-web-service", From: "dev1", To: "qa9"},
+	}
+	for _, req := range cases {
+		if _, err := promoter.Plan(req); err == nil {
+			t.Errorf("Expected %s → %s to be rejected", req.From, req.To)
+		}
+	}
+}
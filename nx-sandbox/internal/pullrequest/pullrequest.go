@@ -0,0 +1,254 @@
+package pullrequest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// StoreFileName is the pull request store inside .nx-sandbox
+const StoreFileName = "pulls.json"
+
+// BranchPrefix prefixes every branch created for a pull request
+const BranchPrefix = "nx-sandbox/"
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Manager defines the interface for simulated pull requests
+type Manager interface {
+	Propose(title, author string, paths []string) ([]models.PullRequest, error)
+	List() ([]models.PullRequest, error)
+	Get(id int) (*models.PullRequest, error)
+	Diff(pr *models.PullRequest) (string, error)
+	Merge(id int, user string) (*models.PullRequest, error)
+}
+
+// DefaultManager commits changes to branches of the Git repositories under
+// repos/ and keeps pull request metadata in .nx-sandbox/pulls.json
+type DefaultManager struct {
+	baseDir string
+	now     func() time.Time
+}
+
+// NewManager creates a new pull request manager for a sandbox root
+func NewManager(baseDir string) Manager {
+	return &DefaultManager{
+		baseDir: baseDir,
+		now:     time.Now,
+	}
+}
+
+// StorePath returns the location of the pull request store
+func StorePath(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), StoreFileName)
+}
+
+// Propose commits changed paths to a new branch and opens a pull request for
+// each Git repository they belong to. Paths outside repos/ or in repositories
+// that have not been initialized are left as plain working tree changes.
+func (m *DefaultManager) Propose(title, author string, paths []string) ([]models.PullRequest, error) {
+	byRepo := make(map[string][]string)
+	for _, path := range paths {
+		repo, rel, ok := m.repoOf(path)
+		if !ok {
+			continue
+		}
+		byRepo[repo] = append(byRepo[repo], rel)
+	}
+	if len(byRepo) == 0 {
+		return nil, nil
+	}
+
+	prs, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	nextID := 1
+	for _, pr := range prs {
+		if pr.ID >= nextID {
+			nextID = pr.ID + 1
+		}
+	}
+
+	var repos []string
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var opened []models.PullRequest
+	for _, repo := range repos {
+		r, err := gitrepo.Open(filepath.Join(layout.ReposDir(m.baseDir), repo))
+		if err != nil {
+			return opened, err
+		}
+		base, err := r.CurrentBranch()
+		if err != nil {
+			return opened, err
+		}
+
+		pr := models.PullRequest{
+			ID:        nextID,
+			Title:     title,
+			Repo:      repo,
+			Branch:    fmt.Sprintf("%spr-%d-%s", BranchPrefix, nextID, slug(title)),
+			Base:      base,
+			Author:    author,
+			State:     models.PullRequestOpen,
+			Files:     byRepo[repo],
+			CreatedAt: m.now().UTC(),
+		}
+
+		pr.Commit, err = r.CommitToBranch(pr.Branch, title, pr.Files)
+		if err != nil {
+			return opened, fmt.Errorf("failed to commit to %s: %w", repo, err)
+		}
+
+		prs = append(prs, pr)
+		if err := m.save(prs); err != nil {
+			return opened, err
+		}
+		opened = append(opened, pr)
+		nextID++
+	}
+
+	return opened, nil
+}
+
+// List returns every pull request, oldest first
+func (m *DefaultManager) List() ([]models.PullRequest, error) {
+	data, err := os.ReadFile(StorePath(m.baseDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pull request store: %w", err)
+	}
+
+	var prs []models.PullRequest
+	if err := json.Unmarshal(data, &prs); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request store: %w", err)
+	}
+	return prs, nil
+}
+
+// Get returns a pull request by ID
+func (m *DefaultManager) Get(id int) (*models.PullRequest, error) {
+	prs, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range prs {
+		if prs[i].ID == id {
+			return &prs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("pull request #%d not found", id)
+}
+
+// Diff returns the changes a pull request introduces
+func (m *DefaultManager) Diff(pr *models.PullRequest) (string, error) {
+	r, err := gitrepo.Open(filepath.Join(layout.ReposDir(m.baseDir), pr.Repo))
+	if err != nil {
+		return "", err
+	}
+	return r.Diff(pr.Base, pr.Branch)
+}
+
+// Merge merges an open pull request into its base branch
+func (m *DefaultManager) Merge(id int, user string) (*models.PullRequest, error) {
+	prs, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var pr *models.PullRequest
+	for i := range prs {
+		if prs[i].ID == id {
+			pr = &prs[i]
+		}
+	}
+	if pr == nil {
+		return nil, fmt.Errorf("pull request #%d not found", id)
+	}
+	if pr.State != models.PullRequestOpen {
+		return nil, fmt.Errorf("pull request #%d is already %s", id, pr.State)
+	}
+
+	r, err := gitrepo.Open(filepath.Join(layout.ReposDir(m.baseDir), pr.Repo))
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Merge pull request #%d from %s\n\n%s", pr.ID, pr.Branch, pr.Title)
+	if _, err := r.Merge(pr.Base, pr.Branch, message); err != nil {
+		return nil, fmt.Errorf("failed to merge pull request #%d: %w", id, err)
+	}
+
+	now := m.now().UTC()
+	pr.State = models.PullRequestMerged
+	pr.MergedAt = &now
+	pr.MergedBy = user
+	if err := m.save(prs); err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// Helper methods
+
+// repoOf returns the Git repository under repos/ that contains path, and
+// path relative to that repository
+func (m *DefaultManager) repoOf(path string) (string, string, bool) {
+	reposDir, err := filepath.Abs(layout.ReposDir(m.baseDir))
+	if err != nil {
+		return "", "", false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", false
+	}
+
+	rel, err := filepath.Rel(reposDir, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	if len(parts) < 2 || !gitrepo.IsRepo(filepath.Join(reposDir, parts[0])) {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func (m *DefaultManager) save(prs []models.PullRequest) error {
+	path := StorePath(m.baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(prs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// slug turns a title into a short branch-safe name
+func slug(title string) string {
+	s := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(s) > 40 {
+		s = strings.TrimRight(s[:40], "-")
+	}
+	return s
+}
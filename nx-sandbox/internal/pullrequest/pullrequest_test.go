@@ -0,0 +1,111 @@
+package pullrequest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// setupTestEnv creates a sandbox with an initialized inventory repository and
+// a plain environment repository
+func setupTestEnv(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tmpDir := t.TempDir()
+
+	inventoryRepo := filepath.Join(tmpDir, "repos", "nx-artifacts-inventory")
+	os.MkdirAll(filepath.Join(inventoryRepo, "nx-artifacts", "bff"), 0755)
+	os.WriteFile(filepath.Join(inventoryRepo, "nx-artifacts", "bff", "inventory.yaml"), []byte("enabled: false\n"), 0644)
+	if _, err := gitrepo.Init(inventoryRepo); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	envRepo := filepath.Join(tmpDir, "repos", "nx-bolt-environment-dev1")
+	os.MkdirAll(envRepo, 0755)
+	os.WriteFile(filepath.Join(envRepo, "values.yaml"), []byte("replicaCount: 1\n"), 0644)
+
+	return tmpDir
+}
+
+func TestProposeAndMerge(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	manager := NewManager(baseDir)
+
+	inventoryFile := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "inventory.yaml")
+	valuesFile := filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", "values.yaml")
+	os.WriteFile(inventoryFile, []byte("enabled: true\n"), 0644)
+	os.WriteFile(valuesFile, []byte("replicaCount: 3\n"), 0644)
+
+	prs, err := manager.Propose("Approve infrastructure for nx-bff-web-payment", "alice", []string{inventoryFile, valuesFile})
+	if err != nil {
+		t.Fatalf("Propose failed: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request for the initialized repository, got %d", len(prs))
+	}
+	pr := prs[0]
+	if pr.ID != 1 || pr.Repo != "nx-artifacts-inventory" || pr.Base != gitrepo.DefaultBranch {
+		t.Errorf("Unexpected pull request: %+v", pr)
+	}
+	if pr.Branch != "nx-sandbox/pr-1-approve-infrastructure-for-nx-bff-web-pa" {
+		t.Errorf("Unexpected branch: %s", pr.Branch)
+	}
+
+	if data, _ := os.ReadFile(inventoryFile); string(data) != "enabled: false\n" {
+		t.Errorf("Base branch should be unchanged until merge, got %q", data)
+	}
+	if data, _ := os.ReadFile(valuesFile); string(data) != "replicaCount: 3\n" {
+		t.Error("Changes outside git repositories should stay in the working tree")
+	}
+
+	diff, err := manager.Diff(&pr)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+enabled: true") {
+		t.Errorf("Diff missing change:\n%s", diff)
+	}
+
+	merged, err := manager.Merge(pr.ID, "bob")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if merged.State != models.PullRequestMerged || merged.MergedBy != "bob" || merged.MergedAt == nil {
+		t.Errorf("Unexpected merged pull request: %+v", merged)
+	}
+	if data, _ := os.ReadFile(inventoryFile); string(data) != "enabled: true\n" {
+		t.Errorf("Merge should apply the change, got %q", data)
+	}
+
+	if _, err := manager.Merge(pr.ID, "bob"); err == nil {
+		t.Error("Expected merging twice to fail")
+	}
+}
+
+func TestPropose_NothingChanged(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	manager := NewManager(baseDir)
+
+	inventoryFile := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "inventory.yaml")
+	if _, err := manager.Propose("No-op", "alice", []string{inventoryFile}); err == nil {
+		t.Error("Expected proposing an unchanged file to fail")
+	}
+
+	prs, _ := manager.List()
+	if len(prs) != 0 {
+		t.Errorf("Expected no pull requests, got %d", len(prs))
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	if _, err := NewManager(setupTestEnv(t)).Get(42); err == nil {
+		t.Error("Expected unknown pull request to fail")
+	}
+}
@@ -17,6 +17,7 @@ type SandboxManager interface {
 	ArtifactLister
 	GetStatus() (*models.SandboxStatus, error)
 	Clean() error
+	CleanPlan() ([]models.CleanupCandidate, error)
 	CloneArtifact(org, repo string, prepareTesting bool) error
 }
 
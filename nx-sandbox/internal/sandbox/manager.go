@@ -7,7 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/approval"
 	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/snapshot"
 )
 
 // DefaultSandboxManager implements the SandboxManager interface
@@ -98,6 +100,14 @@ func (m *DefaultSandboxManager) GetStatus() (*models.SandboxStatus, error) {
 
 	env.DiskUsage = testUsage + localUsage
 
+	// Measure the snapshot store
+	snapshots, err := snapshot.NewStore(m.baseDir).Usage()
+	if err != nil {
+		return nil, err
+	}
+	env.SnapshotCount = snapshots.Snapshots
+	env.SnapshotDiskUsage = snapshots.DiskUsage
+
 	// Get last cleanup time
 	lastCleanup, err := m.getLastCleanupTime()
 	if err == nil {
@@ -116,10 +126,22 @@ func (m *DefaultSandboxManager) GetStatus() (*models.SandboxStatus, error) {
 		status.IsHealthy = false
 	}
 
+	if env.SnapshotDiskUsage > 1024*1024*1024 { // > 1GB
+		status.Issues = append(status.Issues, "Snapshots use over 1GB - delete unused snapshots")
+	}
+
 	if env.TestArtifactsCount > 50 {
 		status.Issues = append(status.Issues, "Many test artifacts - consider cleanup")
 	}
 
+	// Collect inventories waiting for infrastructure approval
+	pending, err := approval.NewApprover(m.baseDir).Pending()
+	if err != nil {
+		status.Issues = append(status.Issues, fmt.Sprintf("Unable to read inventories: %v", err))
+		status.IsHealthy = false
+	}
+	status.PendingApprovals = pending
+
 	if len(status.Issues) == 0 {
 		status.Recommendations = append(status.Recommendations, "Sandbox is in good condition")
 	} else {
@@ -131,21 +153,39 @@ func (m *DefaultSandboxManager) GetStatus() (*models.SandboxStatus, error) {
 
 // Clean implements SandboxManager interface
 func (m *DefaultSandboxManager) Clean() error {
+	candidates, err := m.CleanPlan()
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if err := os.RemoveAll(candidate.Path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", candidate.Path, err)
+		}
+	}
+
+	// Update cleanup timestamp
+	return m.updateLastCleanupTime()
+}
+
+// CleanPlan implements SandboxManager interface
+func (m *DefaultSandboxManager) CleanPlan() ([]models.CleanupCandidate, error) {
 	testDir := filepath.Join(m.baseDir, "test-artifacts")
 	localDir := filepath.Join(m.baseDir, "local-artifacts")
 
-	// Clean test artifacts older than 7 days
-	if err := m.cleanOldArtifacts(testDir, 7*24*time.Hour); err != nil {
-		return fmt.Errorf("failed to clean test artifacts: %w", err)
+	// Test artifacts older than 7 days
+	candidates, err := m.findOldArtifacts(testDir, 7*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan test artifacts: %w", err)
 	}
 
-	// Clean local artifacts older than 30 days
-	if err := m.cleanOldArtifacts(localDir, 30*24*time.Hour); err != nil {
-		return fmt.Errorf("failed to clean local artifacts: %w", err)
+	// Local artifacts older than 30 days
+	local, err := m.findOldArtifacts(localDir, 30*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan local artifacts: %w", err)
 	}
 
-	// Update cleanup timestamp
-	return m.updateLastCleanupTime()
+	return append(candidates, local...), nil
 }
 
 // CloneArtifact implements SandboxManager interface
@@ -161,9 +201,7 @@ func (m *DefaultSandboxManager) scanInventoryArtifacts(filter models.ArtifactFil
 
 	inventoryDir := filepath.Join(m.baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts")
 
-	layers := []string{"al", "bal", "bb", "bc", "bff", "ch", "tc", "xp"}
-
-	for _, layer := range layers {
+	for _, layer := range models.KnownLayers {
 		if filter.Layer != "" && filter.Layer != layer {
 			continue
 		}
@@ -236,23 +274,14 @@ func (m *DefaultSandboxManager) scanEnvironmentArtifacts(filter models.ArtifactF
 			continue
 		}
 
-		// Scan layers in environment
-		entries, err := os.ReadDir(envDir)
-		if err != nil {
-			continue
-		}
-
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-
-			layer := entry.Name()
+		// Scan layers in environment. Other directories, such as the Kuma
+		// mesh chart, are not layers.
+		for _, layer := range models.KnownLayers {
 			if filter.Layer != "" && filter.Layer != layer {
 				continue
 			}
 
-			layerDir := filepath.Join(envDir, entry.Name())
+			layerDir := filepath.Join(envDir, layer)
 
 			// Scan services in layer
 			serviceEntries, err := os.ReadDir(layerDir)
@@ -331,12 +360,13 @@ func (m *DefaultSandboxManager) calculateDiskUsage(dir string) (int64, error) {
 	return size, err
 }
 
-func (m *DefaultSandboxManager) cleanOldArtifacts(dir string, maxAge time.Duration) error {
+func (m *DefaultSandboxManager) findOldArtifacts(dir string, maxAge time.Duration) ([]models.CleanupCandidate, error) {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return nil
+		return nil, nil
 	}
 
-	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	var candidates []models.CleanupCandidate
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -344,13 +374,24 @@ func (m *DefaultSandboxManager) cleanOldArtifacts(dir string, maxAge time.Durati
 		if info.IsDir() && path != dir {
 			// Check if directory is older than maxAge
 			if time.Since(info.ModTime()) > maxAge {
-				fmt.Printf("Removing old artifact: %s\n", path)
-				return os.RemoveAll(path)
+				size, err := m.calculateDiskUsage(path)
+				if err != nil {
+					return err
+				}
+				candidates = append(candidates, models.CleanupCandidate{
+					Path:    path,
+					Size:    size,
+					ModTime: info.ModTime(),
+					MaxAge:  fmt.Sprintf("%dd", int(maxAge.Hours()/24)),
+				})
+				return filepath.SkipDir
 			}
 		}
 
 		return nil
 	})
+
+	return candidates, err
 }
 
 func (m *DefaultSandboxManager) getLastCleanupTime() (time.Time, error) {
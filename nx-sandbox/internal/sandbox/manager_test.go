@@ -4,8 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
 	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
 )
 
 // setupTestEnv creates test directory structure
@@ -108,6 +111,39 @@ func TestListArtifacts_FilterByLayer(t *testing.T) {
 	}
 }
 
+func TestListArtifacts_SeededSandbox(t *testing.T) {
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	manager := NewSandboxManager(baseDir)
+
+	inventoryArtifacts, err := manager.ListArtifacts(models.ArtifactFilter{Source: models.SourceInventory})
+	if err != nil {
+		t.Fatalf("ListArtifacts failed: %v", err)
+	}
+	if len(inventoryArtifacts) != 6 {
+		t.Errorf("Expected 6 seeded inventory artifacts, got %d", len(inventoryArtifacts))
+	}
+
+	// The mesh chart and repository metadata are not layers
+	os.MkdirAll(filepath.Join(layout.EnvironmentRepo(baseDir, "dev1"), layout.MeshDirName, "templates"), 0755)
+	os.MkdirAll(filepath.Join(layout.EnvironmentRepo(baseDir, "dev1"), ".git", "refs"), 0755)
+
+	charts, err := manager.ListArtifacts(models.ArtifactFilter{Source: models.SourceEnvironment, Environment: "dev1"})
+	if err != nil {
+		t.Fatalf("ListArtifacts failed: %v", err)
+	}
+	if len(charts) != 7 {
+		t.Errorf("Expected one seeded chart per layer in dev1, got %d", len(charts))
+	}
+	for _, artifact := range charts {
+		if !artifact.HasChart {
+			t.Errorf("Expected seeded chart for %s", artifact.Name)
+		}
+	}
+}
+
 func TestGetStatus(t *testing.T) {
 	baseDir := setupTestEnv(t)
 	manager := NewSandboxManager(baseDir)
@@ -165,6 +201,40 @@ func TestClean_EmptyDirectories(t *testing.T) {
 	}
 }
 
+func TestCleanPlan_OldArtifacts(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	manager := NewSandboxManager(baseDir)
+
+	oldDir := filepath.Join(baseDir, "test-artifacts", "nx-bff-old-dev1")
+	freshDir := filepath.Join(baseDir, "test-artifacts", "nx-bff-fresh-dev1")
+	os.MkdirAll(oldDir, 0755)
+	os.MkdirAll(freshDir, 0755)
+	os.WriteFile(filepath.Join(oldDir, "values.yaml"), []byte("replicaCount: 1\n"), 0644)
+	old := time.Now().Add(-8 * 24 * time.Hour)
+	os.Chtimes(oldDir, old, old)
+
+	candidates, err := manager.CleanPlan()
+	if err != nil {
+		t.Fatalf("CleanPlan failed: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Path != oldDir || candidates[0].Size != 16 {
+		t.Fatalf("Expected only the old artifact to be planned, got %+v", candidates)
+	}
+	if _, err := os.Stat(oldDir); err != nil {
+		t.Error("CleanPlan should not remove anything")
+	}
+
+	if err := manager.Clean(); err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if _, err := os.Stat(oldDir); !os.IsNotExist(err) {
+		t.Error("Expected the old artifact to be removed")
+	}
+	if _, err := os.Stat(freshDir); err != nil {
+		t.Error("Expected the fresh artifact to be kept")
+	}
+}
+
 // Benchmark tests
 func BenchmarkListArtifacts(b *testing.B) {
 	tmpDir := b.TempDir()
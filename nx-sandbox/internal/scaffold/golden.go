@@ -0,0 +1,96 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"gopkg.in/yaml.v3"
+)
+
+// GoldenCase is an artifact rendered with the scaffold templates, including
+// the overrides in .nx-sandbox/templates, and compared with golden files
+type GoldenCase struct {
+	Name         string   `yaml:"name"`
+	Artifact     string   `yaml:"artifact"`
+	Layer        string   `yaml:"layer"`
+	Domain       string   `yaml:"domain"`
+	Service      string   `yaml:"service"`
+	Description  string   `yaml:"description"`
+	Owner        string   `yaml:"owner"`
+	Environments []string `yaml:"environments"`
+}
+
+// goldenCasesFile is the layout of .nx-sandbox/golden.yaml
+type goldenCasesFile struct {
+	Cases []GoldenCase `yaml:"cases"`
+}
+
+// GoldenCasesPath returns the file declaring the golden cases of a sandbox root
+func GoldenCasesPath(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), "golden.yaml")
+}
+
+// GoldenDir returns the directory holding the golden files of a case
+func GoldenDir(baseDir, name string) string {
+	return filepath.Join(layout.StateDir(baseDir), "golden", name)
+}
+
+// LoadGoldenCases reads the golden cases of a sandbox root. A missing file
+// declares no cases.
+func LoadGoldenCases(baseDir string) ([]GoldenCase, error) {
+	path := GoldenCasesPath(baseDir)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file goldenCasesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range file.Cases {
+		switch {
+		case c.Name == "" || strings.ContainsAny(c.Name, `/\`) || c.Name == "." || c.Name == "..":
+			return nil, fmt.Errorf("%s: invalid case name '%s'", path, c.Name)
+		case seen[c.Name]:
+			return nil, fmt.Errorf("%s: case '%s' declared more than once", path, c.Name)
+		case len(c.Environments) == 0:
+			return nil, fmt.Errorf("%s: case '%s' has no environments", path, c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return file.Cases, nil
+}
+
+// RenderGoldenCase renders every template of a case, keyed by
+// <environment>/<file name>
+func RenderGoldenCase(baseDir string, c GoldenCase) (map[string][]byte, error) {
+	req := ArtifactRequest{
+		Name:        c.Artifact,
+		Layer:       c.Layer,
+		Domain:      c.Domain,
+		Service:     c.Service,
+		Description: c.Description,
+		Owner:       c.Owner,
+	}
+
+	files := make(map[string][]byte)
+	for _, env := range c.Environments {
+		for _, tmpl := range []string{InventoryTemplate, ChartTemplate, ValuesTemplate} {
+			content, err := Render(baseDir, tmpl, req, env)
+			if err != nil {
+				return nil, fmt.Errorf("case '%s': %w", c.Name, err)
+			}
+			files[env+"/"+strings.TrimSuffix(tmpl, ".tmpl")] = content
+		}
+	}
+	return files, nil
+}
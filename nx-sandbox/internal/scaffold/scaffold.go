@@ -0,0 +1,272 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Templates rendered for each environment of an artifact
+const (
+	InventoryTemplate = "nx-app-inventory.yaml.tmpl"
+	ChartTemplate     = "Chart.yaml.tmpl"
+	ValuesTemplate    = "values.yaml.tmpl"
+)
+
+var artifactNamePattern = regexp.MustCompile(`^nx-(al|bal|bb|bc|bff|ch|tc|xp)-[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ArtifactRequest describes a new artifact to scaffold
+type ArtifactRequest struct {
+	Name         string
+	Layer        string
+	Domain       string
+	Service      string
+	Description  string
+	Owner        string
+	Environments []string
+}
+
+// templateData is the data passed to every scaffold template
+type templateData struct {
+	Name         string
+	ArtifactName string
+	Layer        string
+	Domain       string
+	Service      string
+	Description  string
+	Owner        string
+	Environment  string
+}
+
+// plannedFile is a rendered file waiting to be written
+type plannedFile struct {
+	path    string
+	content []byte
+}
+
+// Scaffolder defines the interface for creating new artifacts
+type Scaffolder interface {
+	Create(req ArtifactRequest) ([]string, error)
+}
+
+// DefaultScaffolder renders artifacts from embedded templates, preferring
+// overrides found in .nx-sandbox/templates under the sandbox root
+type DefaultScaffolder struct {
+	baseDir string
+}
+
+// NewScaffolder creates a new scaffolder
+func NewScaffolder(baseDir string) Scaffolder {
+	return &DefaultScaffolder{
+		baseDir: baseDir,
+	}
+}
+
+// TemplateOverrideDir returns the directory searched for template overrides
+func TemplateOverrideDir(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), "templates")
+}
+
+// ValidateArtifactName checks an artifact name against the nx-<layer>-<service> convention
+func ValidateArtifactName(name string) error {
+	if !artifactNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid artifact name '%s': expected nx-<layer>-<service> in lowercase (layers: al, bal, bb, bc, bff, ch, tc, xp)", name)
+	}
+	return nil
+}
+
+// Render renders one template for an artifact in an environment, applying the
+// same defaults and template overrides as Create
+func Render(baseDir, name string, req ArtifactRequest, env string) ([]byte, error) {
+	if err := applyDefaults(&req); err != nil {
+		return nil, err
+	}
+	s := &DefaultScaffolder{baseDir: baseDir}
+	return s.render(name, newTemplateData(req, env))
+}
+
+// RenderBuiltin renders one template like Render but with the built-in
+// templates only, ignoring overrides in .nx-sandbox/templates
+func RenderBuiltin(name string, req ArtifactRequest, env string) ([]byte, error) {
+	if err := applyDefaults(&req); err != nil {
+		return nil, err
+	}
+	source, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return nil, err
+	}
+	return execute(name, source, newTemplateData(req, env))
+}
+
+// Create renders the inventory entry and environment charts for an artifact.
+// Nothing is written if any target file already exists.
+func (s *DefaultScaffolder) Create(req ArtifactRequest) ([]string, error) {
+	if err := s.normalize(&req); err != nil {
+		return nil, err
+	}
+
+	files, err := s.plan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file.path); err == nil {
+			return nil, fmt.Errorf("refusing to overwrite existing file: %s", file.path)
+		}
+	}
+
+	var created []string
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.path), 0755); err != nil {
+			return created, fmt.Errorf("failed to create directory for %s: %w", file.path, err)
+		}
+		if err := os.WriteFile(file.path, file.content, 0644); err != nil {
+			return created, fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+		created = append(created, file.path)
+	}
+
+	return created, nil
+}
+
+// Helper methods
+
+func (s *DefaultScaffolder) normalize(req *ArtifactRequest) error {
+	if err := applyDefaults(req); err != nil {
+		return err
+	}
+
+	if len(req.Environments) == 0 {
+		return fmt.Errorf("at least one environment is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, env := range req.Environments {
+		if env == "" {
+			return fmt.Errorf("empty environment name")
+		}
+		if seen[env] {
+			return fmt.Errorf("environment '%s' listed more than once", env)
+		}
+		seen[env] = true
+
+		if _, err := os.Stat(layout.EnvironmentRepo(s.baseDir, env)); os.IsNotExist(err) {
+			return fmt.Errorf("environment repository for '%s' not found", env)
+		}
+	}
+
+	return nil
+}
+
+func (s *DefaultScaffolder) plan(req ArtifactRequest) ([]plannedFile, error) {
+	var files []plannedFile
+
+	for _, env := range req.Environments {
+		data := newTemplateData(req, env)
+
+		targets := []struct {
+			template string
+			path     string
+		}{
+			{InventoryTemplate, layout.InventoryFile(s.baseDir, req.Layer, data.ArtifactName)},
+			{ChartTemplate, filepath.Join(layout.ChartDir(s.baseDir, env, req.Layer, req.Name), "Chart.yaml")},
+			{ValuesTemplate, filepath.Join(layout.ChartDir(s.baseDir, env, req.Layer, req.Name), "values.yaml")},
+		}
+
+		for _, target := range targets {
+			content, err := s.render(target.template, data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, plannedFile{path: target.path, content: content})
+		}
+	}
+
+	return files, nil
+}
+
+// applyDefaults validates the artifact name and fills in fields derived from it
+func applyDefaults(req *ArtifactRequest) error {
+	if err := ValidateArtifactName(req.Name); err != nil {
+		return err
+	}
+
+	nameLayer, _ := layout.LayerOf(req.Name)
+	if req.Layer == "" {
+		req.Layer = nameLayer
+	} else if req.Layer != nameLayer {
+		return fmt.Errorf("layer '%s' does not match artifact name '%s'", req.Layer, req.Name)
+	}
+
+	if req.Service == "" {
+		req.Service = strings.TrimPrefix(req.Name, "nx-"+req.Layer+"-")
+	}
+	if req.Domain == "" {
+		req.Domain = "web"
+	}
+	if req.Owner == "" {
+		req.Owner = "devx-team"
+	}
+	return nil
+}
+
+func newTemplateData(req ArtifactRequest, env string) templateData {
+	data := templateData{
+		Name:         req.Name,
+		ArtifactName: layout.EnvironmentArtifactName(req.Name, env),
+		Layer:        req.Layer,
+		Domain:       req.Domain,
+		Service:      req.Service,
+		Description:  req.Description,
+		Owner:        req.Owner,
+		Environment:  env,
+	}
+	if data.Description == "" {
+		data.Description = fmt.Sprintf("Nexus %s for %s layer in %s", req.Service, req.Layer, env)
+	}
+	return data
+}
+
+func (s *DefaultScaffolder) render(name string, data templateData) ([]byte, error) {
+	source, err := s.loadTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	return execute(name, source, data)
+}
+
+func execute(name string, source []byte, data templateData) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *DefaultScaffolder) loadTemplate(name string) ([]byte, error) {
+	override := filepath.Join(TemplateOverrideDir(s.baseDir), name)
+	if data, err := os.ReadFile(override); err == nil {
+		return data, nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read template override %s: %w", override, err)
+	}
+
+	return defaultTemplates.ReadFile("templates/" + name)
+}
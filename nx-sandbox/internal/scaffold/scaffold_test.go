@@ -0,0 +1,171 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/golden"
+)
+
+// setupTestEnv creates environment repositories for the given environments
+func setupTestEnv(t *testing.T, envs ...string) string {
+	tmpDir := t.TempDir()
+
+	for _, env := range envs {
+		os.MkdirAll(filepath.Join(tmpDir, "repos", "nx-bolt-environment-"+env), 0755)
+	}
+	os.MkdirAll(filepath.Join(tmpDir, "repos", "nx-artifacts-inventory", "nx-artifacts"), 0755)
+
+	return tmpDir
+}
+
+func TestValidateArtifactName(t *testing.T) {
+	valid := []string{"nx-bff-web-payment", "nx-tc-order-creator", "nx-ch-web-checkout"}
+	for _, name := range valid {
+		if err := ValidateArtifactName(name); err != nil {
+			t.Errorf("Expected '%s' to be valid, got %v", name, err)
+		}
+	}
+
+	invalid := []string{"bff-web-payment", "nx-foo-service", "nx-bff-", "nx-bff-Web", "nx-bff-web_payment"}
+	for _, name := range invalid {
+		if err := ValidateArtifactName(name); err == nil {
+			t.Errorf("Expected '%s' to be invalid", name)
+		}
+	}
+}
+
+func TestCreate_RendersAllFiles(t *testing.T) {
+	baseDir := setupTestEnv(t, "dev1", "sit1")
+	scaffolder := NewScaffolder(baseDir)
+
+	created, err := scaffolder.Create(ArtifactRequest{
+		Name:         "nx-bff-web-loyalty",
+		Domain:       "loyalty",
+		Environments: []string{"dev1", "sit1"},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if len(created) != 6 {
+		t.Fatalf("Expected 6 files, got %d: %v", len(created), created)
+	}
+
+	inventory := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-loyalty-sit1", "nx-app-inventory.yaml")
+	data, err := os.ReadFile(inventory)
+	if err != nil {
+		t.Fatalf("Expected inventory file: %v", err)
+	}
+	for _, want := range []string{`artifact_name: "nx-bff-web-loyalty-sit1"`, `service: "web-loyalty"`, `domain: "loyalty"`, `environment: "sit1"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Inventory missing %q", want)
+		}
+	}
+
+	values, err := os.ReadFile(filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", "bff", "nx-bff-web-loyalty", "values.yaml"))
+	if err != nil {
+		t.Fatalf("Expected values file: %v", err)
+	}
+	if !strings.Contains(string(values), "web-loyalty.dev1.nexus.britishairways.com") {
+		t.Error("Values should contain the environment host")
+	}
+}
+
+func TestCreate_RefusesOverwrite(t *testing.T) {
+	baseDir := setupTestEnv(t, "dev1")
+	scaffolder := NewScaffolder(baseDir)
+
+	req := ArtifactRequest{Name: "nx-tc-order-creator", Environments: []string{"dev1"}}
+	if _, err := scaffolder.Create(req); err != nil {
+		t.Fatalf("First create failed: %v", err)
+	}
+
+	if _, err := scaffolder.Create(req); err == nil {
+		t.Error("Expected second create to fail")
+	}
+}
+
+func TestCreate_LayerMismatch(t *testing.T) {
+	baseDir := setupTestEnv(t, "dev1")
+	scaffolder := NewScaffolder(baseDir)
+
+	_, err := scaffolder.Create(ArtifactRequest{Name: "nx-tc-order-creator", Layer: "bff", Environments: []string{"dev1"}})
+	if err == nil {
+		t.Error("Expected layer mismatch error")
+	}
+}
+
+func TestCreate_UnknownEnvironment(t *testing.T) {
+	baseDir := setupTestEnv(t, "dev1")
+	scaffolder := NewScaffolder(baseDir)
+
+	_, err := scaffolder.Create(ArtifactRequest{Name: "nx-tc-order-creator", Environments: []string{"qa9"}})
+	if err == nil {
+		t.Error("Expected missing environment error")
+	}
+}
+
+func TestCreate_TemplateOverride(t *testing.T) {
+	baseDir := setupTestEnv(t, "dev1")
+	overrideDir := TemplateOverrideDir(baseDir)
+	os.MkdirAll(overrideDir, 0755)
+	os.WriteFile(filepath.Join(overrideDir, "Chart.yaml.tmpl"), []byte("name: {{ .Name }}\ncustom: true\n"), 0644)
+
+	scaffolder := NewScaffolder(baseDir)
+	if _, err := scaffolder.Create(ArtifactRequest{Name: "nx-al-flight-status", Environments: []string{"dev1"}}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(baseDir, "repos", "nx-bolt-environment-dev1", "al", "nx-al-flight-status", "Chart.yaml"))
+	if err != nil {
+		t.Fatalf("Expected chart file: %v", err)
+	}
+	if !strings.Contains(string(data), "custom: true") {
+		t.Error("Expected override template to be used")
+	}
+}
+
+func TestLoadGoldenCases(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	if cases, err := LoadGoldenCases(baseDir); err != nil || cases != nil {
+		t.Fatalf("Expected no cases without golden.yaml, got %v %v", cases, err)
+	}
+
+	os.MkdirAll(filepath.Dir(GoldenCasesPath(baseDir)), 0755)
+	for content, want := range map[string]string{
+		"cases:\n  - name: ../x\n    environments: [dev1]\n":                                     "invalid case name",
+		"cases:\n  - name: a\n    environments: [dev1]\n  - name: a\n    environments: [dev1]\n": "more than once",
+		"cases:\n  - name: a\n": "no environments",
+	} {
+		os.WriteFile(GoldenCasesPath(baseDir), []byte(content), 0644)
+		if _, err := LoadGoldenCases(baseDir); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+}
+
+func TestRenderGoldenCase_Golden(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	files, err := RenderGoldenCase(baseDir, GoldenCase{
+		Name:         "loyalty",
+		Artifact:     "nx-bff-web-loyalty",
+		Layer:        "bff",
+		Domain:       "loyalty",
+		Service:      "web-loyalty",
+		Description:  "Loyalty BFF",
+		Owner:        "devx-team",
+		Environments: []string{"dev1", "prod1"},
+	})
+	if err != nil {
+		t.Fatalf("RenderGoldenCase failed: %v", err)
+	}
+	if len(files) != 6 {
+		t.Fatalf("Expected 6 files, got %d", len(files))
+	}
+	for name, content := range files {
+		golden.Assert(t, filepath.Join("testdata", "golden", "loyalty", filepath.FromSlash(name)), content)
+	}
+}
@@ -0,0 +1,119 @@
+package scenario
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// JUnitSuiteName is the name of the test suite in JUnit reports
+const JUnitSuiteName = "nx-sandbox scenarios"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+// WriteJUnit writes a report as JUnit XML with one test case per scenario.
+// Missed expectations are failures; scenarios that could not be loaded or
+// set up are errors. The output of every step is kept in system-out.
+func WriteJUnit(w io.Writer, report *models.ScenarioReport) error {
+	suite := junitTestSuite{
+		Name:  JUnitSuiteName,
+		Tests: len(report.Scenarios),
+		Time:  seconds(report.Duration),
+	}
+	for _, result := range report.Scenarios {
+		tc := junitTestCase{
+			Name:      result.Name,
+			ClassName: strings.TrimSuffix(filepath.ToSlash(result.Path), filepath.Ext(result.Path)),
+			Time:      seconds(result.Duration),
+		}
+
+		var out strings.Builder
+		var failures []string
+		for _, step := range result.Steps {
+			fmt.Fprintf(&out, "=== %s\n%s", step.Name, step.Output)
+			if step.Output != "" && !strings.HasSuffix(step.Output, "\n") {
+				out.WriteString("\n")
+			}
+			for _, failure := range step.Failures {
+				failures = append(failures, step.Name+": "+failure)
+			}
+		}
+		failures = append(failures, result.Failures...)
+		if out.Len() > 0 {
+			tc.SystemOut = &junitText{Text: out.String()}
+		}
+
+		switch {
+		case result.Error != "":
+			suite.Errors++
+			tc.Error = &junitMessage{Message: result.Error, Text: result.Error}
+		case !result.Passed:
+			suite.Failures++
+			tc.Failure = &junitMessage{Message: failures[0], Text: strings.Join(failures, "\n")}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitTestSuites{
+		Name:     JUnitSuiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,483 @@
+package scenario
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/awsfake"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/workflow"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultActor triggers workflow dispatches that do not name an actor
+const DefaultActor = "nx-sandbox-scenario"
+
+// RunOptions controls how scenarios are run
+type RunOptions struct {
+	// Parallel is the number of scenarios run at once; zero uses the number
+	// of CPUs
+	Parallel int
+	// Binary is the nx-sandbox executable used by run: steps; empty uses the
+	// running executable
+	Binary string
+}
+
+// Runner defines the interface for running scenario files
+type Runner interface {
+	Run(paths []string, options RunOptions) (*models.ScenarioReport, error)
+}
+
+// DefaultRunner runs every scenario in a temporary sandbox root of its own,
+// seeded from the scenario and given a copy of the github-simulator workflows
+// of the sandbox it was created for. Commands run as separate nx-sandbox
+// processes in that root and workflows run in-process, so scenarios never
+// share state and can run in parallel.
+type DefaultRunner struct {
+	baseDir string
+	now     func() time.Time
+}
+
+// NewRunner creates a new scenario runner for a sandbox root
+func NewRunner(baseDir string) Runner {
+	return &DefaultRunner{
+		baseDir: baseDir,
+		now:     time.Now,
+	}
+}
+
+// Run runs the scenarios found under paths and reports them in file order. A
+// scenario that fails is not an error; errors are reserved for paths that
+// cannot be read.
+func (r *DefaultRunner) Run(paths []string, options RunOptions) (*models.ScenarioReport, error) {
+	files, err := Files(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no scenarios found in %s", strings.Join(paths, ", "))
+	}
+
+	binary := options.Binary
+	if binary == "" {
+		if binary, err = os.Executable(); err != nil {
+			return nil, fmt.Errorf("failed to locate nx-sandbox: %w", err)
+		}
+	}
+	parallel := options.Parallel
+	if parallel <= 0 {
+		parallel = runtime.NumCPU()
+	}
+
+	start := r.now()
+	report := &models.ScenarioReport{Scenarios: make([]models.ScenarioResult, len(files))}
+
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for worker := 0; worker < parallel && worker < len(files); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				started := r.now()
+				result := r.runFile(files[i], binary)
+				result.Duration = r.now().Sub(started)
+				report.Scenarios[i] = result
+			}
+		}()
+	}
+	for i := range files {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	report.Duration = r.now().Sub(start)
+	return report, nil
+}
+
+// Helper methods
+
+// execution is the state of a single scenario run
+type execution struct {
+	scenario *Scenario
+	root     string
+	binary   string
+	aws      *awsfake.Server
+}
+
+func (r *DefaultRunner) runFile(path, binary string) models.ScenarioResult {
+	result := models.ScenarioResult{
+		Name:  strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path:  path,
+		Steps: []models.ScenarioStepResult{},
+	}
+
+	sc, err := Load(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Name = sc.Name
+
+	root, err := os.MkdirTemp("", "nx-sandbox-scenario-")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer os.RemoveAll(root)
+
+	ex := &execution{scenario: sc, root: root, binary: binary}
+	if sc.AWS {
+		ex.aws = awsfake.NewServer()
+		defer ex.aws.Close()
+	}
+	if err := r.setup(ex); err != nil {
+		result.Error = fmt.Sprintf("failed to set up the sandbox: %v", err)
+		return result
+	}
+
+	for _, step := range sc.Steps {
+		stepStart := r.now()
+		stepResult := ex.runStep(step)
+		stepResult.Duration = r.now().Sub(stepStart)
+		result.Steps = append(result.Steps, stepResult)
+		if !stepResult.Passed {
+			// Later steps depend on earlier ones, so there is nothing to gain
+			// from running them
+			return result
+		}
+	}
+
+	result.Failures = ex.checkState()
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// setup seeds the scenario root, copies the workflows in and writes the
+// scenario files and configuration
+func (r *DefaultRunner) setup(ex *execution) error {
+	sc := ex.scenario
+
+	if err := copyDir(workflow.Dir(r.baseDir), workflow.Dir(ex.root)); err != nil {
+		return fmt.Errorf("failed to copy workflows: %w", err)
+	}
+
+	spec := sc.Seed.Spec
+	if spec == nil {
+		spec = seed.DefaultSpec()
+	}
+	if _, err := seed.NewSeeder(ex.root).Seed(spec, false); err != nil {
+		return err
+	}
+
+	for _, path := range sortedKeys(sc.Seed.Files) {
+		target := filepath.Join(ex.root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, []byte(sc.Seed.Files[path]), 0644); err != nil {
+			return err
+		}
+	}
+
+	cfg := make(map[string]interface{})
+	for key, value := range sc.Config {
+		cfg[key] = value
+	}
+	if ex.aws != nil {
+		aws, _ := cfg["aws"].(map[string]interface{})
+		if aws == nil {
+			aws = make(map[string]interface{})
+		}
+		aws["endpoint"] = ex.aws.URL
+		cfg["aws"] = aws
+	}
+	if len(cfg) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path(ex.root)), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(config.Path(ex.root), data, 0644)
+}
+
+func (ex *execution) runStep(step *Step) models.ScenarioStepResult {
+	result := models.ScenarioStepResult{Name: step.Name}
+	if result.Name == "" {
+		if step.Workflow != "" {
+			result.Name = "workflow " + step.Workflow
+		} else {
+			result.Name = "nx-sandbox " + strings.Join(step.Run, " ")
+		}
+	}
+
+	var failures []string
+	if step.Workflow != "" {
+		result.Output, failures = ex.dispatch(step)
+	} else {
+		result.Output, failures = ex.command(step)
+	}
+
+	for _, text := range step.Expect.OutputContains {
+		if !strings.Contains(result.Output, text) {
+			failures = append(failures, fmt.Sprintf("output does not contain %q", text))
+		}
+	}
+	for _, text := range step.Expect.OutputExcludes {
+		if strings.Contains(result.Output, text) {
+			failures = append(failures, fmt.Sprintf("output contains %q", text))
+		}
+	}
+
+	result.Failures = failures
+	result.Passed = len(failures) == 0
+	return result
+}
+
+// command runs nx-sandbox in the scenario root and checks its exit code
+func (ex *execution) command(step *Step) (string, []string) {
+	cmd := exec.Command(ex.binary, step.Run...)
+	cmd.Dir = ex.root
+	cmd.Env = append(os.Environ(), "NO_COLOR=1")
+	output, err := cmd.CombinedOutput()
+
+	code := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	case err != nil:
+		return string(output), []string{fmt.Sprintf("failed to run nx-sandbox: %v", err)}
+	}
+
+	expected := 0
+	if step.Expect.ExitCode != nil {
+		expected = *step.Expect.ExitCode
+	}
+	if code != expected {
+		return string(output), []string{fmt.Sprintf("exit code %d, expected %d", code, expected)}
+	}
+	return string(output), nil
+}
+
+// dispatch runs a workflow in the scenario root and checks its conclusion
+// and outputs
+func (ex *execution) dispatch(step *Step) (string, []string) {
+	actor := step.Actor
+	if actor == "" {
+		actor = DefaultActor
+	}
+	var output bytes.Buffer
+	run, err := workflow.NewRunner(ex.root).Run(step.Workflow, workflow.RunOptions{
+		Event:  step.Event,
+		Inputs: step.Inputs,
+		Actor:  actor,
+		Output: &output,
+	})
+	if err != nil {
+		return output.String(), []string{fmt.Sprintf("workflow %s did not start: %v", step.Workflow, err)}
+	}
+
+	var failures []string
+	expected := step.Expect.Conclusion
+	if expected == "" {
+		expected = models.ConclusionSuccess
+	}
+	if run.Conclusion != expected {
+		failures = append(failures, fmt.Sprintf("workflow concluded with %s, expected %s", run.Conclusion, expected))
+	}
+
+	for _, key := range sortedKeys(step.Expect.Outputs) {
+		want := step.Expect.Outputs[key]
+		got, ok := runOutput(run, key)
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("workflow has no output %s", key))
+		case got != want:
+			failures = append(failures, fmt.Sprintf("output %s is %q, expected %q", key, got, want))
+		}
+	}
+	return output.String(), failures
+}
+
+// checkState compares the sandbox root and AWS endpoint with the scenario's
+// final expectations
+func (ex *execution) checkState() []string {
+	var failures []string
+	for _, file := range ex.scenario.Expect.Files {
+		failures = append(failures, ex.checkFile(file)...)
+	}
+
+	if expect := ex.scenario.Expect.AWS; expect != nil {
+		missing := func(kind string, want, have []string) {
+			for _, name := range want {
+				if !contains(have, name) {
+					failures = append(failures, fmt.Sprintf("%s %s does not exist", kind, name))
+				}
+			}
+		}
+		missing("IAM role", expect.Roles, ex.aws.Roles())
+		missing("ECR repository", expect.Repositories, ex.aws.Repositories())
+		missing("DynamoDB table", expect.Tables, mapKeys(ex.aws.Tables()))
+		missing("ElastiCache cluster", expect.CacheClusters, mapKeys(ex.aws.CacheClusters()))
+		missing("RDS instance", expect.DBInstances, mapKeys(ex.aws.DBInstances()))
+	}
+	return failures
+}
+
+func (ex *execution) checkFile(file FileExpectation) []string {
+	path := filepath.Join(ex.root, filepath.FromSlash(file.Path))
+	info, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return []string{fmt.Sprintf("%s: %v", file.Path, err)}
+	}
+
+	if file.Exists != nil && !*file.Exists {
+		if err == nil {
+			return []string{fmt.Sprintf("%s exists", file.Path)}
+		}
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("%s does not exist", file.Path)}
+	}
+	if file.Equals == nil && len(file.Contains) == 0 && len(file.Excludes) == 0 && len(file.Values) == 0 {
+		return nil
+	}
+	if info.IsDir() {
+		return []string{fmt.Sprintf("%s is a directory", file.Path)}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", file.Path, err)}
+	}
+
+	var failures []string
+	content := string(data)
+	if file.Equals != nil && content != *file.Equals {
+		failures = append(failures, fmt.Sprintf("%s does not have the expected content", file.Path))
+	}
+	for _, text := range file.Contains {
+		if !strings.Contains(content, text) {
+			failures = append(failures, fmt.Sprintf("%s does not contain %q", file.Path, text))
+		}
+	}
+	for _, text := range file.Excludes {
+		if strings.Contains(content, text) {
+			failures = append(failures, fmt.Sprintf("%s contains %q", file.Path, text))
+		}
+	}
+
+	if len(file.Values) > 0 {
+		doc, err := yamldoc.Parse(data)
+		if err != nil {
+			return append(failures, fmt.Sprintf("%s is not valid YAML: %v", file.Path, err))
+		}
+		for _, path := range sortedKeys(file.Values) {
+			want := file.Values[path]
+			switch node := doc.Get(path); {
+			case node == nil:
+				failures = append(failures, fmt.Sprintf("%s has no %s", file.Path, path))
+			case node.Kind != yaml.ScalarNode:
+				failures = append(failures, fmt.Sprintf("%s: %s is not a scalar", file.Path, path))
+			case node.Value != want:
+				failures = append(failures, fmt.Sprintf("%s: %s is %q, expected %q", file.Path, path, node.Value, want))
+			}
+		}
+	}
+	return failures
+}
+
+// runOutput looks up <job>.<output> or <job>.<step>.<output> in a run
+func runOutput(run *models.WorkflowRun, key string) (string, bool) {
+	parts := strings.Split(key, ".")
+	for _, job := range run.Jobs {
+		if job.ID != parts[0] {
+			continue
+		}
+		if len(parts) == 2 {
+			value, ok := job.Outputs[parts[1]]
+			return value, ok
+		}
+		for _, step := range job.Steps {
+			if step.ID == parts[1] {
+				value, ok := step.Outputs[parts[2]]
+				return value, ok
+			}
+		}
+	}
+	return "", false
+}
+
+// copyDir copies the regular files under src to dst; a missing src is not an error
+func copyDir(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package scenario
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is an end-to-end test: a seed state, a sequence of nx-sandbox
+// commands and workflow dispatches, and the state expected afterwards
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Seed        Seed   `yaml:"seed"`
+	// Config is written to .nx-sandbox/config.yaml
+	Config map[string]interface{} `yaml:"config"`
+	// AWS starts an in-process AWS-compatible endpoint and points the
+	// aws.endpoint setting at it
+	AWS    bool             `yaml:"aws"`
+	Steps  []*Step          `yaml:"steps"`
+	Expect StateExpectation `yaml:"expect"`
+
+	Path string `yaml:"-"`
+}
+
+// Seed is the state of the sandbox root before the first step
+type Seed struct {
+	// Spec is a seed spec as accepted by 'nx-sandbox seed'; empty uses the
+	// built-in spec
+	Spec *seed.Spec `yaml:"spec"`
+	// Files are written after seeding, keyed by path relative to the root
+	Files map[string]string `yaml:"files"`
+}
+
+// Step runs an nx-sandbox command or dispatches a github-simulator workflow
+type Step struct {
+	Name string `yaml:"name"`
+	// Run holds the nx-sandbox arguments, without the executable
+	Run []string `yaml:"run"`
+
+	Workflow string            `yaml:"workflow"`
+	Event    string            `yaml:"event"`
+	Inputs   map[string]string `yaml:"inputs"`
+	Actor    string            `yaml:"actor"`
+
+	Expect StepExpectation `yaml:"expect"`
+}
+
+// StepExpectation describes the outcome of a step. Commands are expected to
+// exit with 0 and workflows to succeed unless stated otherwise.
+type StepExpectation struct {
+	ExitCode       *int                      `yaml:"exit_code"`
+	Conclusion     models.WorkflowConclusion `yaml:"conclusion"`
+	OutputContains []string                  `yaml:"output_contains"`
+	OutputExcludes []string                  `yaml:"output_excludes"`
+	// Outputs are workflow outputs keyed by <job>.<output> for job outputs
+	// and <job>.<step>.<output> for step outputs
+	Outputs map[string]string `yaml:"outputs"`
+}
+
+// StateExpectation describes the sandbox after the last step
+type StateExpectation struct {
+	Files []FileExpectation `yaml:"files"`
+	AWS   *AWSExpectation   `yaml:"aws"`
+}
+
+// FileExpectation describes a file or directory relative to the sandbox root.
+// It is expected to exist unless Exists is false; content checks only apply
+// to files.
+type FileExpectation struct {
+	Path     string   `yaml:"path"`
+	Exists   *bool    `yaml:"exists"`
+	Equals   *string  `yaml:"equals"`
+	Contains []string `yaml:"contains"`
+	Excludes []string `yaml:"excludes"`
+	// Values are scalar values keyed by dot-separated YAML path
+	Values map[string]string `yaml:"values"`
+}
+
+// AWSExpectation lists resources that must exist on the scenario's AWS endpoint
+type AWSExpectation struct {
+	Roles         []string `yaml:"roles"`
+	Repositories  []string `yaml:"repositories"`
+	Tables        []string `yaml:"tables"`
+	CacheClusters []string `yaml:"cache_clusters"`
+	DBInstances   []string `yaml:"db_instances"`
+}
+
+// Load reads and parses a scenario file. Scenarios without a name are named
+// after their file.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	sc.Path = path
+	if sc.Name == "" {
+		sc.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return sc, nil
+}
+
+// Parse parses and validates scenario YAML
+func Parse(data []byte) (*Scenario, error) {
+	var sc Scenario
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&sc); err != nil {
+		return nil, err
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+// Validate checks that every step does exactly one thing and that every path
+// stays inside the sandbox root
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario has no steps")
+	}
+	if s.Seed.Spec != nil {
+		if err := s.Seed.Spec.Validate(); err != nil {
+			return fmt.Errorf("seed: %w", err)
+		}
+	}
+	for path := range s.Seed.Files {
+		if err := checkPath(path); err != nil {
+			return fmt.Errorf("seed: %w", err)
+		}
+	}
+
+	for i, step := range s.Steps {
+		name := fmt.Sprintf("step %d", i+1)
+		if step.Name != "" {
+			name = fmt.Sprintf("step '%s'", step.Name)
+		}
+		switch {
+		case len(step.Run) > 0 && step.Workflow != "":
+			return fmt.Errorf("%s: run and workflow are mutually exclusive", name)
+		case len(step.Run) == 0 && step.Workflow == "":
+			return fmt.Errorf("%s: either run or workflow is required", name)
+		case len(step.Run) > 0 && (step.Event != "" || len(step.Inputs) > 0 || step.Actor != ""):
+			return fmt.Errorf("%s: event, inputs and actor only apply to workflows", name)
+		case len(step.Run) > 0 && (step.Expect.Conclusion != "" || len(step.Expect.Outputs) > 0):
+			return fmt.Errorf("%s: conclusion and outputs only apply to workflows", name)
+		case step.Workflow != "" && step.Expect.ExitCode != nil:
+			return fmt.Errorf("%s: exit_code only applies to commands", name)
+		}
+		for key := range step.Expect.Outputs {
+			if parts := strings.Split(key, "."); len(parts) < 2 || len(parts) > 3 {
+				return fmt.Errorf("%s: output '%s' must be <job>.<output> or <job>.<step>.<output>", name, key)
+			}
+		}
+	}
+
+	for _, file := range s.Expect.Files {
+		if err := checkPath(file.Path); err != nil {
+			return fmt.Errorf("expect: %w", err)
+		}
+		if file.Exists != nil && !*file.Exists && (file.Equals != nil || len(file.Contains) > 0 || len(file.Excludes) > 0 || len(file.Values) > 0) {
+			return fmt.Errorf("expect: %s cannot be both absent and have content", file.Path)
+		}
+	}
+	if s.Expect.AWS != nil && !s.AWS {
+		return fmt.Errorf("expect: aws resources require aws: true")
+	}
+	return nil
+}
+
+// Files expands paths into the scenario files they name. Directories are
+// searched recursively for .yaml and .yml files.
+func Files(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := filepath.Ext(p); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				found = append(found, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		for _, p := range found {
+			add(p)
+		}
+	}
+	return files, nil
+}
+
+// Helper methods
+
+// checkPath rejects paths that are absolute or leave the sandbox root
+func checkPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("empty path")
+	}
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %s must be relative to the sandbox root", path)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/workflow"
+)
+
+const testWorkflow = `name: Register
+on:
+  workflow_dispatch:
+    inputs:
+      artifact_name:
+        required: true
+        type: string
+jobs:
+  register:
+    runs-on: ubuntu-latest
+    outputs:
+      layer: ${{ steps.parse.outputs.layer }}
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          repository: britishairways-nexus/nx-artifacts-inventory
+      - id: parse
+        run: |
+          echo "layer=$(echo ${{ inputs.artifact_name }} | cut -d- -f2)" >> $GITHUB_OUTPUT
+          echo "registered ${{ inputs.artifact_name }}" > registered.txt
+`
+
+// fakeCLI stands in for nx-sandbox in run: steps: it prints its arguments,
+// records them in the sandbox root and exits with the code given to 'exit'
+const fakeCLI = `#!/bin/sh
+echo "args: $*"
+echo "$*" >> cli.log
+if [ "$1" = exit ]; then exit "$2"; fi
+if [ "$1" = config ]; then cat .nx-sandbox/config.yaml; fi
+`
+
+const passingScenario = `name: passing
+config:
+  approvers:
+    teams:
+      platform-engineers: [alice]
+steps:
+  - workflow: register
+    inputs:
+      artifact_name: nx-bff-web-search
+    expect:
+      outputs:
+        register.layer: bff
+        register.parse.layer: bff
+  - run: [approve, nx-bff-web-search]
+    expect:
+      output_contains: ["args: approve nx-bff-web-search"]
+  - run: [exit, "3"]
+    expect:
+      exit_code: 3
+expect:
+  files:
+    - path: repos/nx-artifacts-inventory/registered.txt
+      equals: "registered nx-bff-web-search\n"
+    - path: cli.log
+      contains: [approve]
+      excludes: [reject]
+    - path: .nx-sandbox/config.yaml
+      values:
+        approvers.teams.platform-engineers.0: alice
+    - path: repos/nx-artifacts-inventory/nx-artifacts/bff/nx-bff-web-payment-dev1/nx-app-inventory.yaml
+      values:
+        infrastructure.enabled: "false"
+    - path: repos/nx-bolt-environment-dev1
+      exists: true
+`
+
+const failingScenario = `steps:
+  - run: [exit, "1"]
+  - run: [never-run]
+`
+
+func setupTestEnv(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(workflow.Dir(baseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(workflow.Dir(baseDir), "register.yml"), []byte(testWorkflow), 0644)
+
+	binary := filepath.Join(t.TempDir(), "nx-sandbox")
+	if err := os.WriteFile(binary, []byte(fakeCLI), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return baseDir, binary
+}
+
+func writeScenario(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParse(t *testing.T) {
+	sc, err := Parse([]byte(passingScenario))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if sc.Name != "passing" || len(sc.Steps) != 3 || *sc.Steps[2].Expect.ExitCode != 3 || len(sc.Expect.Files) != 5 {
+		t.Errorf("Unexpected scenario %+v", sc)
+	}
+
+	invalid := map[string]string{
+		"no steps":          "name: empty\n",
+		"run and workflow":  "steps:\n  - run: [list]\n    workflow: register\n",
+		"neither":           "steps:\n  - name: nothing\n",
+		"inputs on run":     "steps:\n  - run: [list]\n    inputs: {a: b}\n",
+		"exit code on flow": "steps:\n  - workflow: register\n    expect: {exit_code: 1}\n",
+		"bad output key":    "steps:\n  - workflow: register\n    expect: {outputs: {layer: bff}}\n",
+		"escaping seed":     "seed:\n  files: {../outside.txt: x}\nsteps:\n  - run: [list]\n",
+		"absolute expect":   "steps:\n  - run: [list]\nexpect:\n  files:\n    - path: /etc/passwd\n",
+		"absent content":    "steps:\n  - run: [list]\nexpect:\n  files:\n    - path: a\n      exists: false\n      contains: [x]\n",
+		"aws without aws":   "steps:\n  - run: [list]\nexpect:\n  aws: {roles: [sa]}\n",
+		"unknown field":     "steps:\n  - run: [list]\n    expects: {}\n",
+		"invalid seed":      "seed:\n  spec: {environments: []}\nsteps:\n  - run: [list]\n",
+	}
+	for name, content := range invalid {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	b := writeScenario(t, dir, "b.yaml", failingScenario)
+	a := writeScenario(t, dir, "nested/a.yml", failingScenario)
+	writeScenario(t, dir, "README.md", "not a scenario")
+
+	files, err := Files([]string{dir, b})
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	if len(files) != 2 || files[0] != b || files[1] != a {
+		t.Errorf("Expected the scenarios sorted and deduplicated, got %v", files)
+	}
+
+	if _, err := Files([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Error("Expected a missing path to be an error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	baseDir, binary := setupTestEnv(t)
+	dir := t.TempDir()
+	writeScenario(t, dir, "1-passing.yaml", passingScenario)
+	writeScenario(t, dir, "2-failing.yaml", failingScenario)
+	writeScenario(t, dir, "3-invalid.yaml", "steps: []\n")
+	writeScenario(t, dir, "4-state.yaml", `aws: true
+seed:
+  files:
+    notes.txt: hello
+steps:
+  - run: [config]
+    expect:
+      output_contains: ["endpoint: http://127.0.0.1"]
+  - workflow: register
+    inputs: {artifact_name: nx-tc-order}
+    expect:
+      outputs: {register.layer: bff}
+`)
+	writeScenario(t, dir, "5-expect.yaml", `aws: true
+seed:
+  files:
+    notes.txt: hello
+steps:
+  - run: [list]
+expect:
+  files:
+    - path: notes.txt
+      contains: [goodbye]
+      values: {a.b: c}
+    - path: cli.log
+      exists: false
+    - path: missing.txt
+  aws:
+    repositories: [nx-bff-web-search-dev1]
+`)
+
+	report, err := NewRunner(baseDir).Run([]string{dir}, RunOptions{Parallel: 2, Binary: binary})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Scenarios) != 5 || report.Failed() != 4 {
+		t.Fatalf("Expected 1 of 5 scenarios to pass, got %+v", report.Scenarios)
+	}
+
+	passing := report.Scenarios[0]
+	if !passing.Passed || passing.Name != "passing" || len(passing.Steps) != 3 {
+		t.Errorf("Expected the passing scenario to pass, got %+v", passing)
+	}
+	if passing.Steps[0].Name != "workflow register" || passing.Steps[1].Name != "nx-sandbox approve nx-bff-web-search" {
+		t.Errorf("Unexpected step names %q and %q", passing.Steps[0].Name, passing.Steps[1].Name)
+	}
+
+	failing := report.Scenarios[1]
+	if failing.Name != "2-failing" || len(failing.Steps) != 1 || failing.Steps[0].Failures[0] != "exit code 1, expected 0" {
+		t.Errorf("Expected the failing scenario to stop at its first step, got %+v", failing)
+	}
+	if invalid := report.Scenarios[2]; invalid.Error == "" || len(invalid.Steps) != 0 {
+		t.Errorf("Expected the invalid scenario to report an error, got %+v", invalid)
+	}
+
+	state := report.Scenarios[3]
+	if !state.Steps[0].Passed || state.Steps[1].Failures[0] != `output register.layer is "tc", expected "bff"` {
+		t.Errorf("Expected the AWS endpoint in the config and a wrong output, got %+v", state.Steps)
+	}
+
+	expected := []string{
+		`notes.txt does not contain "goodbye"`,
+		"notes.txt is not valid YAML: top-level YAML value must be a mapping",
+		"cli.log exists",
+		"missing.txt does not exist",
+		"ECR repository nx-bff-web-search-dev1 does not exist",
+	}
+	if failures := report.Scenarios[4].Failures; strings.Join(failures, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected failures:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(failures, "\n"))
+	}
+
+	if _, err := NewRunner(baseDir).Run([]string{t.TempDir()}, RunOptions{Binary: binary}); err == nil {
+		t.Error("Expected a directory without scenarios to be an error")
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	baseDir, binary := setupTestEnv(t)
+	dir := t.TempDir()
+	writeScenario(t, dir, "passing.yaml", passingScenario)
+	writeScenario(t, dir, "failing.yaml", failingScenario)
+	writeScenario(t, dir, "invalid.yaml", "steps: []\n")
+
+	report, err := NewRunner(baseDir).Run([]string{dir}, RunOptions{Binary: binary})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, report); err != nil {
+		t.Fatalf("WriteJUnit failed: %v", err)
+	}
+
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || doc.Errors != 1 || len(doc.Suites) != 1 || len(doc.Suites[0].Cases) != 3 {
+		t.Fatalf("Unexpected totals in\n%s", buf.String())
+	}
+
+	cases := doc.Suites[0].Cases
+	if cases[0].Name != "failing" || cases[0].Failure == nil || !strings.Contains(cases[0].Failure.Message, "exit code 1") {
+		t.Errorf("Expected the failing scenario to be a failure, got %+v", cases[0])
+	}
+	if cases[1].Error == nil || cases[2].Failure != nil || cases[2].Error != nil {
+		t.Errorf("Expected an error and a pass, got %+v and %+v", cases[1], cases[2])
+	}
+	if cases[2].SystemOut == nil || !strings.Contains(cases[2].SystemOut.Text, "args: approve nx-bff-web-search") {
+		t.Errorf("Expected the step output in system-out, got %+v", cases[2].SystemOut)
+	}
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AllowEntry accepts findings matching all of its non-empty fields. Path is a
+// glob relative to the sandbox root where ** matches any number of
+// directories.
+type AllowEntry struct {
+	Rule   string `yaml:"rule,omitempty"`
+	Path   string `yaml:"path,omitempty"`
+	Value  string `yaml:"value,omitempty"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// Allowlist holds the known non-secret values, such as mock credentials
+type Allowlist struct {
+	Entries []AllowEntry `yaml:"allow"`
+}
+
+// LoadAllowlist reads an allowlist file
+func LoadAllowlist(file string) (*Allowlist, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %w", err)
+	}
+
+	var allowlist Allowlist
+	if err := yaml.Unmarshal(data, &allowlist); err != nil {
+		return nil, fmt.Errorf("failed to parse allowlist %s: %w", file, err)
+	}
+	for i, entry := range allowlist.Entries {
+		if entry.Rule == "" && entry.Path == "" && entry.Value == "" {
+			return nil, fmt.Errorf("allowlist entry %d must set rule, path or value", i+1)
+		}
+		if entry.Path != "" {
+			if _, err := path.Match(strings.ReplaceAll(entry.Path, "**", "*"), ""); err != nil {
+				return nil, fmt.Errorf("allowlist entry %d has an invalid path %q: %w", i+1, entry.Path, err)
+			}
+		}
+	}
+	return &allowlist, nil
+}
+
+// Allows reports whether a finding of rule in file with value is allowlisted
+func (a *Allowlist) Allows(rule, file, value string) bool {
+	for _, entry := range a.Entries {
+		if entry.Rule != "" && entry.Rule != rule {
+			continue
+		}
+		if entry.Value != "" && entry.Value != value {
+			continue
+		}
+		if entry.Path != "" && !matchPath(entry.Path, file) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Helper methods
+
+// matchPath matches a slash-separated path against a glob where ** matches
+// zero or more directories
+func matchPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
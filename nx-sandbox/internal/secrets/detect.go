@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// sensitiveKeyWords mark variable names whose values are treated as secrets.
+// They match anywhere in the name, so DBPASSWORD is as sensitive as DB_PASSWORD.
+var sensitiveKeyWords = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "APIKEY", "PRIVATE_KEY", "CREDENTIAL", "AUTH"}
+
+// valuePatterns recognise well-known credential formats
+var valuePatterns = []struct {
+	rule    string
+	name    string
+	pattern *regexp.Regexp
+}{
+	{RuleAWSAccessKey, "AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{RulePrivateKey, "private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{RuleGitHubToken, "GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{RuleJWT, "JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+	{RuleSlackToken, "Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{RuleURLCredentials, "credentials in URL", regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:[^/\s@]+@`)},
+}
+
+const (
+	// minEntropyLength is the shortest value checked for randomness
+	minEntropyLength = 20
+
+	// entropyThreshold is the Shannon entropy (bits per character) above
+	// which a value is considered random enough to be a secret
+	entropyThreshold = 4.0
+)
+
+// IsSensitiveKey reports whether a variable name suggests a secret value
+func IsSensitiveKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, word := range sensitiveKeyWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchValue returns the name of the credential format value matches, if any
+func MatchValue(value string) (string, bool) {
+	for _, p := range valuePatterns {
+		if p.pattern.MatchString(value) {
+			return p.name, true
+		}
+	}
+	return "", false
+}
+
+// tokenCharset matches strings made only of characters used by encoded tokens
+var tokenCharset = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+
+// IsHighEntropy reports whether value looks like a random token
+func IsHighEntropy(value string) bool {
+	if len(value) < minEntropyLength || !tokenCharset.MatchString(value) {
+		return false
+	}
+	return Entropy(value) >= entropyThreshold
+}
+
+// Entropy returns the Shannon entropy of s in bits per character
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// LooksLikeSecret reports whether an environment variable appears to hold a
+// secret, along with the reason. Any non-empty value under a sensitive name
+// counts, however short or plain, so callers must use a secret reference.
+func LooksLikeSecret(key, value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+	if IsSensitiveKey(key) {
+		return "name suggests a secret", true
+	}
+	if name, ok := MatchValue(value); ok {
+		return "value looks like a " + name, true
+	}
+	if IsHighEntropy(value) {
+		return "value looks like a random token", true
+	}
+	return "", false
+}
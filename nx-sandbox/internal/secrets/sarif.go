@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// SARIF 2.1.0 structures, limited to what code scanning reads
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// SARIFLog is the root of a SARIF file
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of the scanner
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the scanner and its rules
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the scanner component of a SARIF tool
+type SARIFDriver struct {
+	Name  string      `json:"name"`
+	Rules []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a rule the scanner reports
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFMessage is a SARIF text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a single finding
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+// SARIFLocation points at a finding
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a file and region
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation is a file relative to the repository root
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the position of a finding in a file
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIF converts a scan report to a SARIF log for code scanning. Paths stay
+// relative to the sandbox root, which is the repository root in CI.
+func SARIF(report *models.SecretScanReport) *SARIFLog {
+	driver := SARIFDriver{
+		Name:  "nx-sandbox",
+		Rules: make([]SARIFRule, 0, len(Rules)),
+	}
+	for _, rule := range Rules {
+		driver.Rules = append(driver.Rules, SARIFRule{ID: rule.ID, ShortDescription: SARIFMessage{Text: rule.Description}})
+	}
+
+	results := make([]SARIFResult, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		results = append(results, SARIFResult{
+			RuleID:  finding.Rule,
+			Level:   "error",
+			Message: SARIFMessage{Text: finding.Message},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: finding.Path},
+					Region:           SARIFRegion{StartLine: finding.Line, StartColumn: finding.Column},
+				},
+			}},
+			PartialFingerprints: map[string]string{"secretHash/v1": finding.Fingerprint},
+		})
+	}
+
+	return &SARIFLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
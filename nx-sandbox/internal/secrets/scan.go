@@ -0,0 +1,328 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// Rules reported by the scanner
+const (
+	RuleAWSAccessKey        = "aws-access-key"
+	RuleAWSSecretKey        = "aws-secret-key"
+	RulePrivateKey          = "private-key"
+	RuleGitHubToken         = "github-token"
+	RuleJWT                 = "jwt"
+	RuleSlackToken          = "slack-token"
+	RuleURLCredentials      = "url-credentials"
+	RuleHardcodedCredential = "hardcoded-credential"
+	RuleHighEntropy         = "high-entropy"
+)
+
+// Rule describes a scanner rule
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules lists every rule the scanner reports, in report order
+var Rules = []Rule{
+	{RuleAWSAccessKey, "AWS access key ID"},
+	{RuleAWSSecretKey, "AWS secret access key"},
+	{RulePrivateKey, "Private key block"},
+	{RuleGitHubToken, "GitHub token"},
+	{RuleJWT, "JSON web token"},
+	{RuleSlackToken, "Slack token"},
+	{RuleURLCredentials, "Credentials embedded in a URL"},
+	{RuleHardcodedCredential, "Credential assigned to a literal value"},
+	{RuleHighEntropy, "High-entropy string that looks like a random token"},
+}
+
+// ScanRoots are the directories scanned by default, relative to the sandbox root
+var ScanRoots = []string{"repos", "test-artifacts", "local-artifacts"}
+
+// maxFileSize skips files too large to be configuration
+const maxFileSize = 2 << 20
+
+// secretRefPrefix marks a value that references a Kubernetes secret, as
+// written by the env command
+const secretRefPrefix = "secretRef:"
+
+var (
+	awsSecretPattern  = regexp.MustCompile(`(?i)aws_?secret_?(access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)
+	assignmentPattern = regexp.MustCompile(`^\s*(?:-\s+)?["']?([A-Za-z0-9_.-]+)["']?\s*[:=]\s*(.*)$`)
+	tokenPattern      = regexp.MustCompile(`[A-Za-z0-9+/=_-]{20,}`)
+	expressionPattern = regexp.MustCompile(`^[a-z_]+\.[A-Za-z0-9_.\[\]"-]+$`)
+)
+
+// credentialKeyWords extend the sensitive names for configuration files
+var credentialKeyWords = []string{"ACCESS_KEY", "SECRET_KEY", "ACCESSKEY", "SECRETKEY", "CREDENTIALS"}
+
+// minCredentialLength is the shortest literal reported as a hard-coded
+// credential
+const minCredentialLength = 6
+
+// Match is a secret found in a line
+type Match struct {
+	Rule    string
+	Message string
+	Column  int
+	Value   string
+}
+
+// Scanner defines the interface for scanning the sandbox for secrets
+type Scanner interface {
+	Scan(paths []string) (*models.SecretScanReport, error)
+}
+
+// DefaultScanner scans text files line by line, skipping version control
+// directories, binary files and findings the allowlist accepts
+type DefaultScanner struct {
+	baseDir   string
+	allowlist *Allowlist
+}
+
+// NewScanner creates a new secret scanner for a sandbox root. A nil
+// allowlist allows nothing.
+func NewScanner(baseDir string, allowlist *Allowlist) Scanner {
+	if allowlist == nil {
+		allowlist = &Allowlist{}
+	}
+	return &DefaultScanner{
+		baseDir:   baseDir,
+		allowlist: allowlist,
+	}
+}
+
+// Scan scans the given files and directories, or ScanRoots when none are
+// given. Missing roots are skipped.
+func (s *DefaultScanner) Scan(paths []string) (*models.SecretScanReport, error) {
+	if len(paths) == 0 {
+		for _, root := range ScanRoots {
+			paths = append(paths, filepath.Join(s.baseDir, root))
+		}
+	}
+
+	report := &models.SecretScanReport{Findings: []models.SecretFinding{}}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			return s.scanFile(path, report)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+// ScanLine returns the secrets found in a line of text. Each match carries
+// its rule, the 1-based column and the secret value.
+func ScanLine(line string) []Match {
+	var matches []Match
+
+	for _, p := range valuePatterns {
+		for _, loc := range p.pattern.FindAllStringIndex(line, -1) {
+			matches = append(matches, Match{Rule: p.rule, Message: p.name, Column: loc[0] + 1, Value: line[loc[0]:loc[1]]})
+		}
+	}
+
+	if loc := awsSecretPattern.FindStringSubmatchIndex(line); loc != nil {
+		matches = append(matches, Match{Rule: RuleAWSSecretKey, Message: "AWS secret access key", Column: loc[4] + 1, Value: line[loc[4]:loc[5]]})
+	}
+
+	if len(matches) == 0 {
+		if match, ok := hardcodedCredential(line); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	if len(matches) == 0 {
+		for _, loc := range tokenPattern.FindAllStringIndex(line, -1) {
+			if token := line[loc[0]:loc[1]]; IsHighEntropy(token) {
+				matches = append(matches, Match{Rule: RuleHighEntropy, Message: "value looks like a random token", Column: loc[0] + 1, Value: token})
+			}
+		}
+	}
+
+	return matches
+}
+
+// Helper methods
+
+func (s *DefaultScanner) scanFile(path string, report *models.SecretScanReport) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() > maxFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil
+	}
+	report.Scanned++
+
+	rel := filepath.ToSlash(path)
+	if r, err := relativeTo(s.baseDir, path); err == nil && !strings.HasPrefix(r, "..") {
+		rel = filepath.ToSlash(r)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	for number := 1; scanner.Scan(); number++ {
+		for _, match := range ScanLine(scanner.Text()) {
+			if s.allowlist.Allows(match.Rule, rel, match.Value) {
+				report.Allowed++
+				continue
+			}
+			report.Findings = append(report.Findings, models.SecretFinding{
+				Path:        rel,
+				Line:        number,
+				Column:      match.Column,
+				Rule:        match.Rule,
+				Message:     match.Message,
+				Snippet:     redact(match.Value),
+				Fingerprint: fingerprint(match.Rule, rel, match.Value),
+			})
+		}
+	}
+	return scanner.Err()
+}
+
+// hardcodedCredential finds a sensitive key assigned a literal value, as in
+// password: hunter2 or secret_key = "abc". References such as ${VAR},
+// templates, secretRef values and HCL expressions are not literals.
+func hardcodedCredential(line string) (Match, bool) {
+	loc := assignmentPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return Match{}, false
+	}
+	key := line[loc[2]:loc[3]]
+	if !hasKeyWords(key, sensitiveKeyWords) && !hasKeyWords(key, credentialKeyWords) {
+		return Match{}, false
+	}
+
+	raw := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line[loc[4]:loc[5]]), ","))
+	quoted := len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') && raw[len(raw)-1] == raw[0]
+	value := raw
+	if quoted {
+		value = raw[1 : len(raw)-1]
+	} else if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	switch {
+	case value == "", value == "|", value == ">", value == "{", value == "[":
+		return Match{}, false
+	case strings.HasPrefix(value, "$"), strings.Contains(value, "{{"), strings.HasPrefix(value, secretRefPrefix):
+		return Match{}, false
+	case !quoted && expressionPattern.MatchString(value):
+		return Match{}, false
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "null", "~", "none", "changeme", "<redacted>":
+		return Match{}, false
+	}
+	if isPlainSetting(value) {
+		return Match{}, false
+	}
+
+	column := loc[4] + strings.Index(line[loc[4]:], value) + 1
+	return Match{Rule: RuleHardcodedCredential, Message: fmt.Sprintf("%s is assigned a literal credential", key), Column: column, Value: value}, true
+}
+
+// keyWordSplit separates the words of a name: underscores, dashes, dots and
+// camelCase boundaries, so apiToken and API_TOKEN both split into API TOKEN
+var keyWordSplit = regexp.MustCompile(`[^A-Za-z0-9]+|([a-z0-9])([A-Z])|([A-Z])([A-Z][a-z])`)
+
+// hasKeyWords reports whether the words of key contain any of words, each
+// an underscore-separated sequence of whole words. Unlike IsSensitiveKey,
+// AUTH matches AUTH_TOKEN but not AUTHOR, which keeps configuration files
+// from reporting every key that happens to contain a sensitive word.
+func hasKeyWords(key string, words []string) bool {
+	name := "_" + strings.ToUpper(keyWordSplit.ReplaceAllString(key, "${1}${3}_${2}${4}")) + "_"
+	for _, word := range words {
+		if strings.Contains(name, "_"+word+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlainSetting reports whether value is too short or too structured to be
+// a credential: a bool, a number or text with spaces
+func isPlainSetting(value string) bool {
+	if len(value) < minCredentialLength || strings.ContainsAny(value, " \t") {
+		return true
+	}
+	switch strings.ToLower(value) {
+	case "yes", "no", "on", "off", "enabled", "disabled":
+		return true
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// relativeTo returns path relative to base, resolving both so paths given
+// relative to the working directory work with any sandbox root
+func relativeTo(base, path string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absBase, absPath)
+}
+
+// redact keeps the first characters of a secret so findings can be located
+func redact(value string) string {
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", 8)
+}
+
+func fingerprint(rule, path, value string) string {
+	sum := sha256.Sum256([]byte(rule + "\x00" + path + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
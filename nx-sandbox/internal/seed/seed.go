@@ -0,0 +1,254 @@
+package seed
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/pullrequest"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/scaffold"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/terraform"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+)
+
+//go:embed files/*
+var staticFiles embed.FS
+
+// Seeder defines the interface for generating the repos/ tree from a spec
+type Seeder interface {
+	Seed(spec *Spec, reset bool) ([]string, error)
+}
+
+// DefaultSeeder renders inventories and charts with the built-in scaffold
+// templates, so seeded artifacts look like ones made by 'nx-sandbox create'.
+// Template overrides in .nx-sandbox/templates are ignored: the output depends
+// only on the spec.
+type DefaultSeeder struct {
+	baseDir string
+}
+
+// NewSeeder creates a new seeder for a sandbox root
+func NewSeeder(baseDir string) Seeder {
+	return &DefaultSeeder{
+		baseDir: baseDir,
+	}
+}
+
+// plannedFile is a rendered file waiting to be written
+type plannedFile struct {
+	path    string
+	content []byte
+}
+
+// Seed writes every file declared by spec and returns their paths in a stable
+// order. With reset the repos/ directory is removed first, including Git
+// repositories made by 'nx-sandbox repos init', and so is the pull request
+// store whose branches they held; otherwise existing files with different
+// content are never overwritten.
+func (s *DefaultSeeder) Seed(spec *Spec, reset bool) ([]string, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	files, err := s.plan(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if reset {
+		if err := os.RemoveAll(layout.ReposDir(s.baseDir)); err != nil {
+			return nil, fmt.Errorf("failed to reset repositories: %w", err)
+		}
+		if err := os.Remove(pullrequest.StorePath(s.baseDir)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to reset pull requests: %w", err)
+		}
+	} else {
+		for _, file := range files {
+			existing, err := os.ReadFile(file.path)
+			if err == nil && string(existing) != string(file.content) {
+				return nil, fmt.Errorf("refusing to overwrite modified file: %s (use --reset)", file.path)
+			}
+		}
+	}
+
+	var written []string
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.path), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory for %s: %w", file.path, err)
+		}
+		if err := os.WriteFile(file.path, file.content, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+		written = append(written, file.path)
+	}
+
+	return written, nil
+}
+
+// Helper methods
+
+func (s *DefaultSeeder) plan(spec *Spec) ([]plannedFile, error) {
+	var files []plannedFile
+
+	static := func(name, path string) error {
+		content, err := staticFiles.ReadFile("files/" + name)
+		if err != nil {
+			return err
+		}
+		files = append(files, plannedFile{path: path, content: content})
+		return nil
+	}
+
+	for _, component := range spec.Infrastructure.Components {
+		if err := static("provider_aws.tf", filepath.Join(layout.TerraformComponentDir(s.baseDir, component), "provider_aws.tf")); err != nil {
+			return nil, err
+		}
+	}
+
+	// The shared modules called by 'nx-sandbox terraform generate' components
+	if len(spec.Infrastructure.Components) > 0 {
+		modules, err := terraform.Modules()
+		if err != nil {
+			return nil, err
+		}
+		for name, content := range modules {
+			files = append(files, plannedFile{path: filepath.Join(layout.TerraformModulesDir(s.baseDir), filepath.FromSlash(name)), content: content})
+		}
+	}
+
+	if err := static("app-inventory-schema.yaml", filepath.Join(layout.ReposDir(s.baseDir), "nx-artifacts-inventory", "app-inventory-schema.yaml")); err != nil {
+		return nil, err
+	}
+
+	for _, env := range spec.Environments {
+		if err := static("mesh-values.yaml", layout.MeshValuesFile(s.baseDir, env)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, layer := range spec.Layers {
+		for _, svc := range layer.Services {
+			serviceFiles, err := s.planService(spec, layer.Name, svc)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, serviceFiles...)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+	return files, nil
+}
+
+func (s *DefaultSeeder) planService(spec *Spec, layer string, svc ServiceSpec) ([]plannedFile, error) {
+	req := scaffold.ArtifactRequest{
+		Name:        svc.ArtifactName(layer),
+		Layer:       layer,
+		Domain:      svc.Domain,
+		Description: svc.Description,
+		Owner:       svc.Owner,
+	}
+
+	envs := svc.Environments
+	if len(envs) == 0 {
+		envs = spec.Environments
+	}
+
+	var files []plannedFile
+	for _, env := range envs {
+		if svc.Inventory != nil {
+			overrides := map[string]interface{}{}
+			if len(svc.Inventory.Infrastructure) > 0 {
+				overrides["infrastructure"] = svc.Inventory.Infrastructure
+			}
+			if len(svc.Inventory.Components) > 0 {
+				components := map[string]interface{}{}
+				for name, values := range svc.Inventory.Components {
+					components[name] = values
+				}
+				overrides["components"] = components
+			}
+
+			content, err := s.render(scaffold.InventoryTemplate, req, env, overrides)
+			if err != nil {
+				return nil, err
+			}
+			path := layout.InventoryFile(s.baseDir, layer, layout.EnvironmentArtifactName(req.Name, env))
+			files = append(files, plannedFile{path: path, content: content})
+		}
+
+		if svc.Chart != nil {
+			chartDir := layout.ChartDir(s.baseDir, env, layer, req.Name)
+
+			content, err := s.render(scaffold.ChartTemplate, req, env, nil)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, plannedFile{path: filepath.Join(chartDir, "Chart.yaml"), content: content})
+
+			content, err = s.render(scaffold.ValuesTemplate, req, env, svc.Chart.Values, svc.Chart.EnvValues[env])
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, plannedFile{path: filepath.Join(chartDir, "values.yaml"), content: content})
+		}
+	}
+
+	return files, nil
+}
+
+// render renders a scaffold template and applies override maps in order
+func (s *DefaultSeeder) render(template string, req scaffold.ArtifactRequest, env string, overrides ...map[string]interface{}) ([]byte, error) {
+	content, err := scaffold.RenderBuiltin(template, req, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths [][]string
+	var values []interface{}
+	for _, override := range overrides {
+		flatten(nil, override, func(keys []string, value interface{}) {
+			paths = append(paths, keys)
+			values = append(values, value)
+		})
+	}
+	if len(paths) == 0 {
+		return content, nil
+	}
+
+	doc, err := yamldoc.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rendered %s for %s: %w", template, req.Name, err)
+	}
+	for i, keys := range paths {
+		if err := doc.SetKeys(keys, values[i]); err != nil {
+			return nil, fmt.Errorf("%s (%s): %w", req.Name, env, err)
+		}
+	}
+	return doc.Bytes()
+}
+
+// flatten calls fn for every leaf of a nested map, in sorted key order.
+// Lists and scalars are leaves.
+func flatten(prefix []string, values map[string]interface{}, fn func(keys []string, value interface{})) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := append(append([]string(nil), prefix...), key)
+		switch value := values[key].(type) {
+		case map[string]interface{}:
+			flatten(path, value, fn)
+		default:
+			fn(path, value)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+)
+
+const testSpec = `
+environments: [dev1, prod1]
+layers:
+  - name: bff
+    services:
+      - name: web-loyalty
+        chart:
+          values:
+            replicaCount: 3
+          env_values:
+            prod1:
+              replicaCount: 6
+        inventory:
+          components:
+            redis:
+              enabled: true
+`
+
+func TestSeed_DefaultSpec(t *testing.T) {
+	baseDir := t.TempDir()
+
+	written, err := NewSeeder(baseDir).Seed(DefaultSpec(), false)
+	if err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if len(written) != 83 {
+		t.Errorf("Expected 83 files, got %d", len(written))
+	}
+
+	for _, path := range []string{
+		filepath.Join(layout.TerraformComponentDir(baseDir, "tool"), "provider_aws.tf"),
+		filepath.Join(layout.TerraformModulesDir(baseDir), "elasticache-redis", "main.tf"),
+		layout.InventoryFile(baseDir, "ch", "nx-ch-web-checkout-dev1"),
+		filepath.Join(layout.ChartDir(baseDir, "prod1", "xp", "nx-xp-test-service"), "values.yaml"),
+		layout.MeshValuesFile(baseDir, "uat1"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be seeded", path)
+		}
+	}
+}
+
+func TestSeed_Deterministic(t *testing.T) {
+	spec, err := ParseSpec([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+	written, err := NewSeeder(first).Seed(spec, false)
+	if err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if _, err := NewSeeder(second).Seed(spec, false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	for _, path := range written {
+		rel, _ := filepath.Rel(first, path)
+		a, _ := os.ReadFile(path)
+		b, _ := os.ReadFile(filepath.Join(second, rel))
+		if string(a) != string(b) {
+			t.Errorf("%s differs between runs", rel)
+		}
+	}
+}
+
+func TestSeed_Overrides(t *testing.T) {
+	baseDir := t.TempDir()
+	spec, _ := ParseSpec([]byte(testSpec))
+	if _, err := NewSeeder(baseDir).Seed(spec, false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	dev, _ := os.ReadFile(filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-web-loyalty"), "values.yaml"))
+	prod, _ := os.ReadFile(filepath.Join(layout.ChartDir(baseDir, "prod1", "bff", "nx-bff-web-loyalty"), "values.yaml"))
+	if !strings.Contains(string(dev), "replicaCount: 3\n") {
+		t.Errorf("Expected shared values override in dev1:\n%s", dev)
+	}
+	if !strings.Contains(string(prod), "replicaCount: 6\n") {
+		t.Errorf("Expected environment values override in prod1:\n%s", prod)
+	}
+	if !strings.HasPrefix(string(dev), "# British Airways Nexus Service Configuration\n") {
+		t.Error("Overrides should keep the template comments")
+	}
+
+	entry, err := inventory.Open(baseDir, "nx-bff-web-loyalty", "prod1")
+	if err != nil {
+		t.Fatalf("Seeded inventory not found: %v", err)
+	}
+	if !entry.Inventory.Components.Redis.Enabled {
+		t.Error("Expected redis to be enabled in the seeded inventory")
+	}
+}
+
+func TestSeed_ResetAndOverwrite(t *testing.T) {
+	baseDir := t.TempDir()
+	spec, _ := ParseSpec([]byte(testSpec))
+	seeder := NewSeeder(baseDir)
+	seeder.Seed(spec, false)
+
+	valuesFile := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-web-loyalty"), "values.yaml")
+	os.WriteFile(valuesFile, []byte("replicaCount: 9\n"), 0644)
+	stray := filepath.Join(layout.ReposDir(baseDir), "stray.txt")
+	os.WriteFile(stray, []byte("stray"), 0644)
+
+	if _, err := seeder.Seed(spec, false); err == nil {
+		t.Error("Expected seeding over a modified file to fail without reset")
+	}
+
+	if _, err := seeder.Seed(spec, true); err != nil {
+		t.Fatalf("Seed with reset failed: %v", err)
+	}
+	if data, _ := os.ReadFile(valuesFile); string(data) == "replicaCount: 9\n" {
+		t.Error("Reset should regenerate modified files")
+	}
+	if _, err := os.Stat(stray); !os.IsNotExist(err) {
+		t.Error("Reset should remove files not in the spec")
+	}
+
+	// Pull requests point at branches of the removed repositories
+	pulls := filepath.Join(layout.StateDir(baseDir), "pulls.json")
+	os.MkdirAll(layout.StateDir(baseDir), 0755)
+	os.WriteFile(pulls, []byte("[]\n"), 0644)
+	if _, err := seeder.Seed(spec, true); err != nil {
+		t.Fatalf("Seed with reset failed: %v", err)
+	}
+	if _, err := os.Stat(pulls); !os.IsNotExist(err) {
+		t.Error("Reset should remove the pull request store")
+	}
+}
+
+func TestSeed_IgnoresTemplateOverrides(t *testing.T) {
+	spec, _ := ParseSpec([]byte(testSpec))
+
+	plain, customized := t.TempDir(), t.TempDir()
+	overrides := filepath.Join(layout.StateDir(customized), "templates")
+	os.MkdirAll(overrides, 0755)
+	os.WriteFile(filepath.Join(overrides, "Chart.yaml.tmpl"), []byte("name: custom\n"), 0644)
+
+	written, err := NewSeeder(plain).Seed(spec, false)
+	if err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if _, err := NewSeeder(customized).Seed(spec, false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	for _, path := range written {
+		rel, _ := filepath.Rel(plain, path)
+		want, _ := os.ReadFile(path)
+		got, _ := os.ReadFile(filepath.Join(customized, rel))
+		if string(got) != string(want) {
+			t.Errorf("%s depends on the template overrides", rel)
+		}
+	}
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"no environments":     "layers: []",
+		"unknown layer":       "environments: [dev1]\nlayers: [{name: zz, services: [{name: a, chart: {}}]}]",
+		"undeclared env":      "environments: [dev1]\nlayers: [{name: bff, services: [{name: a, environments: [sit1], chart: {}}]}]",
+		"nothing to generate": "environments: [dev1]\nlayers: [{name: bff, services: [{name: a}]}]",
+		"duplicate service":   "environments: [dev1]\nlayers: [{name: bff, services: [{name: a, chart: {}}, {name: a, chart: {}}]}]",
+	}
+
+	for name, source := range cases {
+		if _, err := ParseSpec([]byte(source)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package seed
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultSpec []byte
+
+// Spec declares the repositories generated under repos/
+type Spec struct {
+	Environments   []string           `yaml:"environments"`
+	Infrastructure InfrastructureSpec `yaml:"infrastructure"`
+	Layers         []LayerSpec        `yaml:"layers"`
+}
+
+// InfrastructureSpec lists the Terraform components of nexus-infrastructure
+type InfrastructureSpec struct {
+	Components []string `yaml:"components"`
+}
+
+// LayerSpec groups the services of a Nexus layer
+type LayerSpec struct {
+	Name     string        `yaml:"name"`
+	Services []ServiceSpec `yaml:"services"`
+}
+
+// ServiceSpec is an artifact nx-<layer>-<name>. It gets a Helm chart in each
+// environment repository when Chart is set and an inventory entry per
+// environment when Inventory is set.
+type ServiceSpec struct {
+	Name         string         `yaml:"name"`
+	Domain       string         `yaml:"domain"`
+	Owner        string         `yaml:"owner"`
+	Description  string         `yaml:"description"`
+	Environments []string       `yaml:"environments"`
+	Chart        *ChartSpec     `yaml:"chart"`
+	Inventory    *InventorySpec `yaml:"inventory"`
+}
+
+// ChartSpec overrides values.yaml keys, for every environment and per environment
+type ChartSpec struct {
+	Values    map[string]interface{}            `yaml:"values"`
+	EnvValues map[string]map[string]interface{} `yaml:"env_values"`
+}
+
+// InventorySpec overrides nx-app-inventory.yaml keys under infrastructure and components
+type InventorySpec struct {
+	Infrastructure map[string]interface{}            `yaml:"infrastructure"`
+	Components     map[string]map[string]interface{} `yaml:"components"`
+}
+
+// LoadSpec reads a seed spec from disk
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed spec: %w", err)
+	}
+
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed spec %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// DefaultSpec returns the built-in spec matching the historical mock repositories
+func DefaultSpec() *Spec {
+	spec, err := ParseSpec(defaultSpec)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in seed spec: %v", err))
+	}
+	return spec
+}
+
+// DefaultSpecYAML returns the source of the built-in spec
+func DefaultSpecYAML() []byte {
+	return append([]byte(nil), defaultSpec...)
+}
+
+// ParseSpec parses and validates a seed spec
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks environments, layers and service names
+func (s *Spec) Validate() error {
+	if len(s.Environments) == 0 {
+		return fmt.Errorf("at least one environment is required")
+	}
+
+	envs := make(map[string]bool)
+	for _, env := range s.Environments {
+		if env == "" || envs[env] {
+			return fmt.Errorf("environment '%s' is empty or listed more than once", env)
+		}
+		envs[env] = true
+	}
+
+	artifacts := make(map[string]bool)
+	for _, layer := range s.Layers {
+		if !isKnownLayer(layer.Name) {
+			return fmt.Errorf("unknown layer '%s'", layer.Name)
+		}
+
+		for _, svc := range layer.Services {
+			name := svc.ArtifactName(layer.Name)
+			if artifacts[name] {
+				return fmt.Errorf("service '%s' declared more than once", name)
+			}
+			artifacts[name] = true
+
+			if svc.Chart == nil && svc.Inventory == nil {
+				return fmt.Errorf("service '%s' needs a chart, an inventory or both", name)
+			}
+			for _, env := range svc.Environments {
+				if !envs[env] {
+					return fmt.Errorf("service '%s' uses undeclared environment '%s'", name, env)
+				}
+			}
+			if svc.Chart != nil {
+				for env := range svc.Chart.EnvValues {
+					if !envs[env] {
+						return fmt.Errorf("service '%s' has values for undeclared environment '%s'", name, env)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ArtifactName returns the artifact name of a service in a layer
+func (s ServiceSpec) ArtifactName(layer string) string {
+	return "nx-" + layer + "-" + s.Name
+}
+
+func isKnownLayer(name string) bool {
+	for _, layer := range models.KnownLayers {
+		if layer == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,603 @@
+package server
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/audit"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/policy"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/sandbox"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/validate"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/workflow"
+)
+
+// APIPrefix prefixes every API route
+const APIPrefix = "/api/v1"
+
+// DefaultActor is recorded on workflow runs triggered without an actor
+const DefaultActor = "api"
+
+// maxBodySize bounds request bodies
+const maxBodySize = 1 << 20
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// Options configures the API server
+type Options struct {
+	// Token, when set, must be sent as "Authorization: Bearer <token>"
+	Token string
+	// Logger receives one line per request; nil disables request logging
+	Logger io.Writer
+}
+
+// Server serves a JSON REST API over the sandbox manager, the validator, the
+// policies and the workflow runner. Mutating operations are serialized.
+type Server struct {
+	baseDir   string
+	options   Options
+	manager   sandbox.SandboxManager
+	validator validate.Validator
+	runner    workflow.Runner
+	logger    *log.Logger
+	mu        sync.Mutex
+}
+
+// NewServer creates an API server for a sandbox root
+func NewServer(baseDir string, options Options) *Server {
+	s := &Server{
+		baseDir:   baseDir,
+		options:   options,
+		manager:   sandbox.NewSandboxManager(baseDir),
+		validator: policy.NewValidator(baseDir),
+		runner:    workflow.NewRunner(baseDir),
+	}
+	if options.Logger != nil {
+		s.logger = log.New(options.Logger, "", log.LstdFlags)
+	}
+	return s
+}
+
+// IsLoopback reports whether a listen address only accepts local connections
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// OpenAPI returns the OpenAPI document describing the API
+func OpenAPI() []byte {
+	return openAPIDocument
+}
+
+// Handler returns the HTTP handler with authentication and request logging
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("GET "+APIPrefix+"/artifacts", s.handleListArtifacts)
+	mux.HandleFunc("GET "+APIPrefix+"/artifacts/{name}", s.handleGetArtifact)
+	mux.HandleFunc("GET "+APIPrefix+"/status", s.handleStatus)
+	mux.HandleFunc("POST "+APIPrefix+"/clean", s.handleClean)
+	mux.HandleFunc("POST "+APIPrefix+"/validate", s.handleValidate)
+	mux.HandleFunc("POST "+APIPrefix+"/policy/check", s.handlePolicyCheck)
+	mux.HandleFunc("GET "+APIPrefix+"/workflows", s.handleListWorkflows)
+	mux.HandleFunc("POST "+APIPrefix+"/workflows/{name}/runs", s.handleRunWorkflow)
+	mux.HandleFunc("GET "+APIPrefix+"/runs", s.handleListRuns)
+	mux.HandleFunc("GET "+APIPrefix+"/runs/{id}", s.handleGetRun)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s %s", r.Method, r.URL.Path))
+	})
+
+	return s.logRequests(s.guardRequests(s.authenticate(mux)))
+}
+
+// Helper methods
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIDocument)
+}
+
+func (s *Server) handleListArtifacts(w http.ResponseWriter, r *http.Request) {
+	filter, err := artifactFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	artifacts, err := s.manager.ListArtifacts(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if artifacts == nil {
+		artifacts = []models.SandboxArtifact{}
+	}
+	writeJSON(w, http.StatusOK, artifacts)
+}
+
+// handleGetArtifact returns every artifact with the name, one per source and
+// environment, narrowed by the same filters as the listing
+func (s *Server) handleGetArtifact(w http.ResponseWriter, r *http.Request) {
+	filter, err := artifactFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	artifacts, err := s.manager.ListArtifacts(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	name := r.PathValue("name")
+	matches := []models.SandboxArtifact{}
+	for _, artifact := range artifacts {
+		if artifact.Name == name {
+			matches = append(matches, artifact)
+		}
+	}
+	if len(matches) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("artifact '%s' not found", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.manager.GetStatus()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// cleanResult is the response of POST /clean
+type cleanResult struct {
+	DryRun  bool                      `json:"dry_run"`
+	Removed []models.CleanupCandidate `json:"removed"`
+	Freed   int64                     `json:"freed"`
+}
+
+func (s *Server) handleClean(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := boolParam(r, "dry_run")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates, err := s.manager.CleanPlan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !dryRun {
+		op := audit.Begin(s.baseDir, "clean", []string{}, DefaultActor, "test-artifacts", "local-artifacts")
+		err := s.manager.Clean()
+		s.finishAudit(op, err)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	result := cleanResult{DryRun: dryRun, Removed: []models.CleanupCandidate{}}
+	for _, candidate := range candidates {
+		result.Removed = append(result.Removed, candidate)
+		result.Freed += candidate.Size
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validateRequest is the optional body of POST /validate
+type validateRequest struct {
+	Paths []string `json:"paths"`
+}
+
+// validateResult is the response of POST /validate
+type validateResult struct {
+	*models.ValidationReport
+	Valid bool `json:"valid"`
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var req validateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var report *models.ValidationReport
+	var err error
+	if len(req.Paths) > 0 {
+		var paths []string
+		if paths, err = s.resolvePaths(req.Paths); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		report, err = s.validator.ValidateFiles(paths)
+	} else {
+		report, err = s.validator.Validate()
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, validateResult{ValidationReport: report, Valid: report.Valid()})
+}
+
+// policyResult is the response of POST /policy/check
+type policyResult struct {
+	*models.PolicyReport
+	Passed bool `json:"passed"`
+}
+
+func (s *Server) handlePolicyCheck(w http.ResponseWriter, r *http.Request) {
+	var req validateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	set, err := policy.LoadDefault(s.baseDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	checker := policy.NewChecker(s.baseDir, set)
+
+	var report *models.PolicyReport
+	if len(req.Paths) > 0 {
+		var paths []string
+		if paths, err = s.resolvePaths(req.Paths); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		report, err = checker.CheckFiles(paths)
+	} else {
+		report, err = checker.Check()
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, policyResult{PolicyReport: report, Passed: report.Errors() == 0})
+}
+
+func (s *Server) handleListWorkflows(w http.ResponseWriter, r *http.Request) {
+	workflows, err := s.runner.Workflows()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	summaries := []models.WorkflowSummary{}
+	for _, wf := range workflows {
+		summaries = append(summaries, wf.Summary())
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// runRequest is the body of POST /workflows/{name}/runs
+type runRequest struct {
+	Event  string            `json:"event"`
+	Inputs map[string]string `json:"inputs"`
+	Actor  string            `json:"actor"`
+	DryRun bool              `json:"dry_run"`
+}
+
+func (s *Server) handleRunWorkflow(w http.ResponseWriter, r *http.Request) {
+	var req runRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = DefaultActor
+	}
+
+	wf, err := workflow.Find(s.baseDir, r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if _, _, err := workflow.ResolveInputs(wf, req.Event, req.Inputs); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Dry runs copy repos/, so they wait for mutations but are not audited
+	if req.DryRun {
+		run, err := s.runner.Run(wf.Name, workflow.RunOptions{
+			Event:  req.Event,
+			Inputs: req.Inputs,
+			Actor:  req.Actor,
+			DryRun: true,
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, run)
+		return
+	}
+
+	args := []string{wf.Name}
+	if req.Event != "" {
+		args = append(args, "--event="+req.Event)
+	}
+	for _, name := range sortedKeys(req.Inputs) {
+		args = append(args, fmt.Sprintf("--input=%s=%s", name, req.Inputs[name]))
+	}
+	op := audit.Begin(s.baseDir, "workflow run", args, req.Actor)
+
+	run, err := s.runner.Run(wf.Name, workflow.RunOptions{
+		Event:  req.Event,
+		Inputs: req.Inputs,
+		Actor:  req.Actor,
+	})
+	switch {
+	case err != nil:
+		s.finishAudit(op, err)
+	case run.Conclusion == models.ConclusionFailure:
+		s.finishAudit(op, fmt.Errorf("workflow run #%d failed", run.ID))
+	default:
+		s.finishAudit(op, nil)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, run)
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := s.runner.Runs()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	workflowName := r.URL.Query().Get("workflow")
+	filtered := []models.WorkflowRun{}
+	for _, run := range runs {
+		if workflowName == "" || run.Workflow == workflowName {
+			filtered = append(filtered, run)
+		}
+	}
+	writeJSON(w, http.StatusOK, filtered)
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run id '%s'", r.PathValue("id")))
+		return
+	}
+
+	run, err := s.runner.GetRun(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// finishAudit records an audited operation, logging audit log failures
+// rather than failing the request
+func (s *Server) finishAudit(op *audit.Operation, err error) {
+	if auditErr := op.Finish(err); auditErr != nil && s.logger != nil {
+		s.logger.Printf("failed to update the audit log: %v", auditErr)
+	}
+}
+
+// resolvePaths resolves request paths against the sandbox root. Paths that
+// lead outside it, through .. or a symbolic link, are refused so the API
+// cannot read other files on the host.
+func (s *Server) resolvePaths(paths []string) ([]string, error) {
+	root, err := realPath(s.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		if !filepath.IsAbs(path) {
+			resolved[i] = filepath.Join(s.baseDir, path)
+		} else {
+			resolved[i] = path
+		}
+
+		real, err := realPath(resolved[i])
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, real)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("path %s is outside the sandbox root", path)
+		}
+	}
+	return resolved, nil
+}
+
+// authenticate requires the bearer token on every request when one is configured
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.options.Token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + s.options.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(given, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="nx-sandbox"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// guardRequests rejects requests a web page could forge. Every POST must be
+// JSON, which a browser cannot send cross-origin without a preflight that is
+// never answered. Without a token the server only listens on loopback, so the
+// Host must be a loopback name, which defeats DNS rebinding, and so must the
+// Origin a browser sends.
+func (s *Server) guardRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, errors.New("POST requests must have Content-Type: application/json"))
+				return
+			}
+		}
+
+		if s.options.Token == "" {
+			if !IsLoopback(r.Host) {
+				writeError(w, http.StatusForbidden, fmt.Errorf("host '%s' is not a loopback address", r.Host))
+				return
+			}
+			if origin := r.Header.Get("Origin"); origin != "" {
+				if u, err := url.Parse(origin); err != nil || !IsLoopback(u.Host) {
+					writeError(w, http.StatusForbidden, fmt.Errorf("origin '%s' is not a loopback address", origin))
+					return
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logRequests writes method, path, status, size, duration and client per request
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	if s.logger == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		s.logger.Printf("%s %s %d %dB %s %s", r.Method, r.URL.RequestURI(), recorder.status,
+			recorder.size, time.Since(start).Round(time.Microsecond), r.RemoteAddr)
+	})
+}
+
+// statusRecorder captures the status code and size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(data)
+	r.size += n
+	return n, err
+}
+
+func artifactFilter(r *http.Request) (models.ArtifactFilter, error) {
+	query := r.URL.Query()
+	filter := models.ArtifactFilter{
+		Source:      models.ArtifactSource(query.Get("source")),
+		Layer:       query.Get("layer"),
+		Environment: query.Get("env"),
+	}
+	switch filter.Source {
+	case "", models.SourceInventory, models.SourceEnvironment:
+	default:
+		return filter, fmt.Errorf("invalid source '%s': expected inventory or environment", filter.Source)
+	}
+	return filter, nil
+}
+
+func boolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s '%s': expected true or false", name, value)
+	}
+	return b, nil
+}
+
+// decodeBody decodes an optional JSON body, rejecting unknown fields
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// realPath returns the absolute path with symbolic links resolved when it
+// exists
+func realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		return real, nil
+	}
+	return abs, nil
+}
@@ -0,0 +1,403 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/audit"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/workflow"
+)
+
+const testWorkflow = `name: Echo
+on:
+  workflow_dispatch:
+    inputs:
+      message:
+        required: true
+        type: string
+jobs:
+  echo:
+    runs-on: ubuntu-latest
+    steps:
+      - id: say
+        run: echo "said=${{ inputs.message }}" >> $GITHUB_OUTPUT
+`
+
+func newTestServer(t *testing.T, options Options) (string, http.Handler) {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	return baseDir, NewServer(baseDir, options).Handler()
+}
+
+func do(t *testing.T, handler http.Handler, method, target, body string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Host = "127.0.0.1:8080"
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", method, target, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+func TestArtifacts_Filters(t *testing.T) {
+	_, handler := newTestServer(t, Options{})
+
+	var all, bff, dev1 []models.SandboxArtifact
+	do(t, handler, "GET", "/api/v1/artifacts", "", &all)
+	do(t, handler, "GET", "/api/v1/artifacts?source=inventory&layer=bff", "", &bff)
+	do(t, handler, "GET", "/api/v1/artifacts?source=environment&env=dev1", "", &dev1)
+
+	if len(all) == 0 || len(bff) == 0 || len(dev1) == 0 {
+		t.Fatalf("Expected artifacts, got %d, %d and %d", len(all), len(bff), len(dev1))
+	}
+	if len(bff) >= len(all) {
+		t.Errorf("Expected layer filter to narrow %d artifacts, got %d", len(all), len(bff))
+	}
+	for _, artifact := range bff {
+		if artifact.Layer != "bff" || artifact.Source != models.SourceInventory {
+			t.Errorf("Unexpected artifact %s (%s, %s)", artifact.Name, artifact.Layer, artifact.Source)
+		}
+	}
+	for _, artifact := range dev1 {
+		if artifact.Environment != "dev1" {
+			t.Errorf("Expected dev1 artifact, got %s in %s", artifact.Name, artifact.Environment)
+		}
+	}
+
+	if rec := do(t, handler, "GET", "/api/v1/artifacts?source=bogus", "", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid source, got %d", rec.Code)
+	}
+}
+
+func TestArtifact_Info(t *testing.T) {
+	_, handler := newTestServer(t, Options{})
+
+	var matches []models.SandboxArtifact
+	rec := do(t, handler, "GET", "/api/v1/artifacts/nx-bff-test-service?env=dev1", "", &matches)
+	if rec.Code != http.StatusOK || len(matches) != 1 || matches[0].Name != "nx-bff-test-service" {
+		t.Fatalf("Expected one dev1 artifact, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var errBody map[string]string
+	rec = do(t, handler, "GET", "/api/v1/artifacts/nx-missing", "", &errBody)
+	if rec.Code != http.StatusNotFound || !strings.Contains(errBody["error"], "nx-missing") {
+		t.Errorf("Expected 404 with error, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestClean_DryRun(t *testing.T) {
+	baseDir, handler := newTestServer(t, Options{})
+
+	old := filepath.Join(baseDir, "test-artifacts", "old")
+	if err := os.MkdirAll(old, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(old, "file"), []byte("data"), 0644)
+	stale := time.Now().Add(-8 * 24 * time.Hour)
+	if err := os.Chtimes(old, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	var result cleanResult
+	do(t, handler, "POST", "/api/v1/clean?dry_run=true", "", &result)
+	if !result.DryRun || len(result.Removed) != 1 || result.Freed != 4 {
+		t.Fatalf("Unexpected dry run result: %+v", result)
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Fatalf("Dry run removed %s", old)
+	}
+
+	do(t, handler, "POST", "/api/v1/clean", "", &result)
+	if result.DryRun || len(result.Removed) != 1 {
+		t.Fatalf("Unexpected clean result: %+v", result)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed", old)
+	}
+
+	entries, err := audit.NewLog(baseDir).Entries(models.AuditFilter{Operation: "clean"})
+	if err != nil || len(entries) != 1 || entries[0].User != DefaultActor || len(entries[0].Paths) != 1 {
+		t.Errorf("Expected the clean to be audited, got %+v: %v", entries, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	baseDir, handler := newTestServer(t, Options{})
+
+	var result struct {
+		Checked []string                 `json:"checked"`
+		Issues  []models.ValidationIssue `json:"issues"`
+		Valid   bool                     `json:"valid"`
+	}
+	do(t, handler, "POST", "/api/v1/validate", "", &result)
+	if !result.Valid || len(result.Checked) == 0 {
+		t.Fatalf("Expected seeded sandbox to be valid, got %+v", result)
+	}
+
+	chart := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service"), "Chart.yaml")
+	if err := os.WriteFile(chart, []byte("apiVersion: v2\nname: other\nversion: 1.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rel, _ := filepath.Rel(baseDir, chart)
+	do(t, handler, "POST", "/api/v1/validate", `{"paths": ["`+rel+`"]}`, &result)
+	if result.Valid || len(result.Checked) != 1 || len(result.Issues) == 0 {
+		t.Errorf("Expected invalid chart to be reported, got %+v", result)
+	}
+
+	if rec := do(t, handler, "POST", "/api/v1/validate", `{"path": "x"}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown field, got %d", rec.Code)
+	}
+}
+
+func TestValidate_RejectsPathsOutsideRoot(t *testing.T) {
+	baseDir, handler := newTestServer(t, Options{})
+	outside := filepath.Join(t.TempDir(), "Chart.yaml")
+	os.WriteFile(outside, []byte("host secret\n"), 0644)
+	link := filepath.Join(baseDir, "repos", "link.yaml")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{outside, "../Chart.yaml", "repos/../../Chart.yaml", "repos/link.yaml"} {
+		for _, route := range []string{"/api/v1/validate", "/api/v1/policy/check"} {
+			rec := do(t, handler, "POST", route, `{"paths": ["`+path+`"]}`, nil)
+			if rec.Code != http.StatusBadRequest || strings.Contains(rec.Body.String(), "host secret") {
+				t.Errorf("Expected %s to refuse %s with 400, got %d: %s", route, path, rec.Code, rec.Body)
+			}
+		}
+	}
+
+	inside := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service"), "Chart.yaml")
+	if rec := do(t, handler, "POST", "/api/v1/validate", `{"paths": ["`+inside+`"]}`, nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected an absolute path inside the root to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	baseDir, handler := newTestServer(t, Options{})
+	os.MkdirAll(filepath.Dir(layout.PoliciesFile(baseDir)), 0755)
+	if err := os.WriteFile(layout.PoliciesFile(baseDir), []byte(`policies:
+  - name: dev-single-replica
+    target: values
+    when: artifact.environment == "dev1"
+    require: values.replicaCount == 1
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Violations []models.PolicyViolation `json:"violations"`
+		Passed     bool                     `json:"passed"`
+	}
+	do(t, handler, "POST", "/api/v1/policy/check", "", &result)
+	if result.Passed || len(result.Violations) != 7 {
+		t.Fatalf("Expected a violation per dev1 chart, got %+v", result)
+	}
+
+	var validation struct {
+		Issues []models.ValidationIssue `json:"issues"`
+	}
+	values := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service"), "values.yaml")
+	rel, _ := filepath.Rel(baseDir, values)
+	do(t, handler, "POST", "/api/v1/validate", `{"paths": ["`+rel+`"]}`, &validation)
+	if len(validation.Issues) != 1 || validation.Issues[0].Rule != "policy/dev-single-replica" {
+		t.Errorf("Expected validate to report the policy, got %+v", validation.Issues)
+	}
+}
+
+func TestWorkflowRuns(t *testing.T) {
+	baseDir, handler := newTestServer(t, Options{})
+	if err := os.MkdirAll(workflow.Dir(baseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workflow.Dir(baseDir), "echo.yml"), []byte(testWorkflow), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var workflows []models.WorkflowSummary
+	do(t, handler, "GET", "/api/v1/workflows", "", &workflows)
+	if len(workflows) != 1 || workflows[0].Name != "echo" {
+		t.Fatalf("Expected echo workflow, got %+v", workflows)
+	}
+
+	if rec := do(t, handler, "POST", "/api/v1/workflows/echo/runs", `{}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing input, got %d", rec.Code)
+	}
+	if rec := do(t, handler, "POST", "/api/v1/workflows/missing/runs", `{}`, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown workflow, got %d", rec.Code)
+	}
+
+	var run models.WorkflowRun
+	rec := do(t, handler, "POST", "/api/v1/workflows/echo/runs", `{"inputs": {"message": "hello"}}`, &run)
+	if rec.Code != http.StatusCreated || run.Conclusion != models.ConclusionSuccess {
+		t.Fatalf("Expected successful run, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if run.Actor != DefaultActor || run.Jobs[0].Steps[0].Outputs["said"] != "hello" {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+
+	var dryRun models.WorkflowRun
+	rec = do(t, handler, "POST", "/api/v1/workflows/echo/runs", `{"inputs": {"message": "hi"}, "dry_run": true}`, &dryRun)
+	if rec.Code != http.StatusOK || !dryRun.DryRun || dryRun.Jobs[0].Steps[0].Outputs["said"] != "hi" {
+		t.Errorf("Expected an unrecorded dry run, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var runs []models.WorkflowRun
+	do(t, handler, "GET", "/api/v1/runs?workflow=echo", "", &runs)
+	if len(runs) != 1 {
+		t.Errorf("Expected 1 run, got %d", len(runs))
+	}
+	if rec := do(t, handler, "GET", "/api/v1/runs/1", "", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected run 1, got %d", rec.Code)
+	}
+	if rec := do(t, handler, "GET", "/api/v1/runs/2", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for run 2, got %d", rec.Code)
+	}
+}
+
+func TestToken(t *testing.T) {
+	_, handler := newTestServer(t, Options{Token: "secret"})
+
+	rec := do(t, handler, "GET", "/api/v1/status", "", nil)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with token, got %d", rec.Code)
+	}
+}
+
+func TestGuardRequests(t *testing.T) {
+	baseDir, handler := newTestServer(t, Options{})
+	os.MkdirAll(workflow.Dir(baseDir), 0755)
+	os.WriteFile(filepath.Join(workflow.Dir(baseDir), "echo.yml"), []byte(testWorkflow), 0644)
+
+	send := func(contentType, host, origin string) int {
+		req := httptest.NewRequest("POST", "/api/v1/workflows/echo/runs", strings.NewReader(`{"inputs": {"message": "hi"}}`))
+		req.Host = host
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for name, tt := range map[string]struct {
+		contentType, host, origin string
+		expected                  int
+	}{
+		"text/plain body":    {"text/plain", "127.0.0.1:8080", "", http.StatusUnsupportedMediaType},
+		"no content type":    {"", "127.0.0.1:8080", "", http.StatusUnsupportedMediaType},
+		"foreign origin":     {"application/json", "127.0.0.1:8080", "https://attacker.example", http.StatusForbidden},
+		"null origin":        {"application/json", "127.0.0.1:8080", "null", http.StatusForbidden},
+		"rebound host":       {"application/json", "attacker.example:8080", "", http.StatusForbidden},
+		"loopback origin":    {"application/json; charset=utf-8", "localhost:8080", "http://localhost:3000", http.StatusCreated},
+		"ipv6 loopback host": {"application/json", "[::1]:8080", "", http.StatusCreated},
+	} {
+		if code := send(tt.contentType, tt.host, tt.origin); code != tt.expected {
+			t.Errorf("%s: expected %d, got %d", name, tt.expected, code)
+		}
+	}
+
+	var runs []models.WorkflowRun
+	do(t, handler, "GET", "/api/v1/runs", "", &runs)
+	if len(runs) != 2 {
+		t.Errorf("Expected only the 2 allowed requests to run the workflow, got %d runs", len(runs))
+	}
+
+	// With a token the server may listen on any address under any name
+	_, handler = newTestServer(t, Options{Token: "secret"})
+	req := httptest.NewRequest("POST", "/api/v1/validate", nil)
+	req.Host = "sandbox.internal:8080"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for an authenticated request, got %d", rec.Code)
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	_, handler := newTestServer(t, Options{Logger: &logs})
+
+	do(t, handler, "GET", "/api/v1/artifacts/nx-missing", "", nil)
+	if !strings.Contains(logs.String(), "GET /api/v1/artifacts/nx-missing 404") {
+		t.Errorf("Expected request to be logged, got %q", logs.String())
+	}
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	var doc struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(OpenAPI(), &doc); err != nil {
+		t.Fatalf("Invalid OpenAPI document: %v", err)
+	}
+
+	for _, route := range []string{
+		"GET /openapi.json",
+		"GET /api/v1/artifacts",
+		"GET /api/v1/artifacts/{name}",
+		"GET /api/v1/status",
+		"POST /api/v1/clean",
+		"POST /api/v1/validate",
+		"POST /api/v1/policy/check",
+		"GET /api/v1/workflows",
+		"POST /api/v1/workflows/{name}/runs",
+		"GET /api/v1/runs",
+		"GET /api/v1/runs/{id}",
+	} {
+		method, path, _ := strings.Cut(route, " ")
+		if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("%s is not documented", route)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, expected := range map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		"[::1]":          true,
+		"localhost":      true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+	} {
+		if got := IsLoopback(addr); got != expected {
+			t.Errorf("IsLoopback(%q) = %v, expected %v", addr, got, expected)
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// DirName is the snapshot store inside .nx-sandbox
+const DirName = "snapshots"
+
+// Roots lists the sandbox directories captured by a snapshot, relative to the
+// sandbox root. The snapshot store itself and the append-only audit log are
+// never captured or restored.
+var Roots = []string{"repos", "test-artifacts", "local-artifacts", layout.StateDirName}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Store defines the interface for sandbox snapshots
+type Store interface {
+	Create(name string) (*models.Snapshot, error)
+	List() ([]models.SnapshotSummary, error)
+	Restore(name string) (*models.RestoreResult, error)
+	Delete(name string) (int64, error)
+	Usage() (*models.SnapshotUsage, error)
+}
+
+// DefaultStore keeps gzip-compressed file contents under
+// .nx-sandbox/snapshots/objects, addressed by their SHA-256, and one JSON
+// manifest per snapshot under .nx-sandbox/snapshots/manifests. Files shared
+// between snapshots are stored once.
+type DefaultStore struct {
+	baseDir string
+	now     func() time.Time
+}
+
+// NewStore creates a new snapshot store for a sandbox root
+func NewStore(baseDir string) Store {
+	return &DefaultStore{
+		baseDir: baseDir,
+		now:     time.Now,
+	}
+}
+
+// Dir returns the location of the snapshot store
+func Dir(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), DirName)
+}
+
+// Create captures the current sandbox state under name. Files whose size,
+// mode and modification time match the latest snapshot reuse its hashes, so
+// only changed files are read.
+func (s *DefaultStore) Create(name string) (*models.Snapshot, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	if _, err := os.Stat(s.manifestPath(name)); err == nil {
+		return nil, fmt.Errorf("snapshot '%s' already exists", name)
+	}
+
+	known, err := s.latestFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &models.Snapshot{
+		Name:      name,
+		CreatedAt: s.now().UTC(),
+	}
+
+	for _, root := range Roots {
+		rootDir := filepath.Join(s.baseDir, root)
+		if _, err := os.Stat(rootDir); os.IsNotExist(err) {
+			continue
+		}
+		snap.Roots = append(snap.Roots, root)
+
+		err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if s.excluded(path) {
+				return skip(d)
+			}
+
+			rel, err := s.relPath(path)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			file := models.SnapshotFile{
+				Path:    rel,
+				Mode:    info.Mode(),
+				ModTime: info.ModTime().UTC(),
+			}
+
+			switch {
+			case d.IsDir():
+			case info.Mode()&fs.ModeSymlink != 0:
+				if file.Link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			case info.Mode().IsRegular():
+				file.Size = info.Size()
+				if prev, ok := known[rel]; ok && sameFile(prev, file) && s.hasObject(prev.Hash) {
+					file.Hash = prev.Hash
+				} else if file.Hash, err = s.storeFile(path); err != nil {
+					return err
+				}
+			default:
+				return nil
+			}
+
+			snap.Files = append(snap.Files, file)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture %s: %w", root, err)
+		}
+	}
+
+	if err := s.writeManifest(snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// List returns every snapshot, oldest first
+func (s *DefaultStore) List() ([]models.SnapshotSummary, error) {
+	snaps, err := s.manifests()
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []models.SnapshotSummary
+	for _, snap := range snaps {
+		summary := models.SnapshotSummary{
+			Name:      snap.Name,
+			CreatedAt: snap.CreatedAt,
+		}
+		for _, file := range snap.Files {
+			if file.Mode.IsRegular() {
+				summary.Files++
+				summary.Size += file.Size
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// Restore returns the captured directories to the state saved in a snapshot.
+// Files that still match the snapshot are left untouched.
+func (s *DefaultStore) Restore(name string) (*models.RestoreResult, error) {
+	snap, err := s.readManifest(name)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]models.SnapshotFile, len(snap.Files))
+	for _, file := range snap.Files {
+		wanted[file.Path] = file
+	}
+
+	result := &models.RestoreResult{Name: name}
+
+	// Remove anything the snapshot does not contain
+	for _, root := range Roots {
+		rootDir := filepath.Join(s.baseDir, root)
+		if _, err := os.Stat(rootDir); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if s.excluded(path) {
+				return skip(d)
+			}
+
+			rel, err := s.relPath(path)
+			if err != nil {
+				return err
+			}
+			file, ok := wanted[rel]
+			if ok && d.IsDir() == file.Mode.IsDir() {
+				return nil
+			}
+			if d.IsDir() && strings.HasPrefix(Dir(s.baseDir), path+string(filepath.Separator)) {
+				// Keep the directories leading to the snapshot store
+				return nil
+			}
+
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			result.Removed++
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to clean %s: %w", root, err)
+		}
+	}
+
+	// Directories come before their contents in the manifest
+	for _, file := range snap.Files {
+		path := filepath.Join(s.baseDir, filepath.FromSlash(file.Path))
+		if s.excluded(path) {
+			// Snapshots taken before the audit log was excluded contain it
+			continue
+		}
+
+		switch {
+		case file.Mode.IsDir():
+			if err := os.MkdirAll(path, file.Mode.Perm()|0700); err != nil {
+				return nil, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+			}
+		case file.Mode&fs.ModeSymlink != 0:
+			if target, err := os.Readlink(path); err == nil && target == file.Link {
+				result.Unchanged++
+				continue
+			}
+			os.Remove(path)
+			if err := os.Symlink(file.Link, path); err != nil {
+				return nil, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+			}
+			result.Written++
+		default:
+			if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() && sameFile(file, models.SnapshotFile{
+				Mode:    info.Mode(),
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+			}) {
+				result.Unchanged++
+				continue
+			}
+			if err := s.restoreFile(file, path); err != nil {
+				return nil, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+			}
+			result.Written++
+		}
+	}
+
+	// Restore directory times after their contents were written
+	for _, file := range snap.Files {
+		if file.Mode.IsDir() {
+			path := filepath.Join(s.baseDir, filepath.FromSlash(file.Path))
+			os.Chtimes(path, file.ModTime, file.ModTime)
+		}
+	}
+
+	return result, nil
+}
+
+// Delete removes a snapshot and the objects no other snapshot uses, returning
+// the number of bytes freed
+func (s *DefaultStore) Delete(name string) (int64, error) {
+	if _, err := s.readManifest(name); err != nil {
+		return 0, err
+	}
+	if err := os.Remove(s.manifestPath(name)); err != nil {
+		return 0, fmt.Errorf("failed to delete snapshot '%s': %w", name, err)
+	}
+	return s.collectGarbage()
+}
+
+// Usage reports the number of snapshots and the disk space they use
+func (s *DefaultStore) Usage() (*models.SnapshotUsage, error) {
+	usage := &models.SnapshotUsage{}
+
+	dir := Dir(s.baseDir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return usage, nil
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		usage.DiskUsage += info.Size()
+
+		rel, _ := filepath.Rel(dir, path)
+		switch strings.SplitN(filepath.ToSlash(rel), "/", 2)[0] {
+		case "objects":
+			usage.Objects++
+		case "manifests":
+			usage.Snapshots++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure snapshot store: %w", err)
+	}
+	return usage, nil
+}
+
+// Helper methods
+
+// excluded reports whether path is never captured or restored: the snapshot
+// store and the audit log, which must survive restores
+func (s *DefaultStore) excluded(path string) bool {
+	return path == Dir(s.baseDir) || path == layout.AuditLogFile(s.baseDir)
+}
+
+// skip leaves an excluded entry out of a walk
+func skip(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+func (s *DefaultStore) manifestPath(name string) string {
+	return filepath.Join(Dir(s.baseDir), "manifests", name+".json")
+}
+
+func (s *DefaultStore) objectPath(hash string) string {
+	return filepath.Join(Dir(s.baseDir), "objects", hash[:2], hash[2:])
+}
+
+func (s *DefaultStore) hasObject(hash string) bool {
+	if len(hash) < 3 {
+		return false
+	}
+	_, err := os.Stat(s.objectPath(hash))
+	return err == nil
+}
+
+// relPath returns path relative to the sandbox root, slash-separated
+func (s *DefaultStore) relPath(path string) (string, error) {
+	rel, err := filepath.Rel(s.baseDir, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// storeFile hashes a file and stores its compressed content if new
+func (s *DefaultStore) storeFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if s.hasObject(hash) {
+		return hash, nil
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return "", err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	if err := writeAtomic(s.objectPath(hash), buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to store object: %w", err)
+	}
+	return hash, nil
+}
+
+// restoreFile writes an object back to path with its recorded mode and time
+func (s *DefaultStore) restoreFile(file models.SnapshotFile, path string) error {
+	f, err := os.Open(s.objectPath(file.Hash))
+	if err != nil {
+		return fmt.Errorf("snapshot object missing: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+
+	os.Remove(path)
+	if err := os.WriteFile(path, data, file.Mode.Perm()); err != nil {
+		return err
+	}
+	if err := os.Chmod(path, file.Mode.Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(path, file.ModTime, file.ModTime)
+}
+
+func (s *DefaultStore) readManifest(name string) (*models.Snapshot, error) {
+	data, err := os.ReadFile(s.manifestPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("snapshot '%s' not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot '%s': %w", name, err)
+	}
+
+	var snap models.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot '%s': %w", name, err)
+	}
+	return &snap, nil
+}
+
+func (s *DefaultStore) writeManifest(snap *models.Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(s.manifestPath(snap.Name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot '%s': %w", snap.Name, err)
+	}
+	return nil
+}
+
+// manifests returns every snapshot manifest, oldest first
+func (s *DefaultStore) manifests() ([]*models.Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(Dir(s.baseDir), "manifests"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+
+	var snaps []*models.Snapshot
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		snap, err := s.readManifest(name)
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+
+	sort.SliceStable(snaps, func(i, j int) bool {
+		return snaps[i].CreatedAt.Before(snaps[j].CreatedAt)
+	})
+	return snaps, nil
+}
+
+// latestFiles indexes the files of the most recent snapshot by path
+func (s *DefaultStore) latestFiles() (map[string]models.SnapshotFile, error) {
+	snaps, err := s.manifests()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]models.SnapshotFile)
+	if len(snaps) == 0 {
+		return known, nil
+	}
+	for _, file := range snaps[len(snaps)-1].Files {
+		known[file.Path] = file
+	}
+	return known, nil
+}
+
+// collectGarbage removes objects no snapshot references
+func (s *DefaultStore) collectGarbage() (int64, error) {
+	snaps, err := s.manifests()
+	if err != nil {
+		return 0, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, snap := range snaps {
+		for _, file := range snap.Files {
+			if file.Hash != "" {
+				referenced[file.Hash] = true
+			}
+		}
+	}
+
+	objectsDir := filepath.Join(Dir(s.baseDir), "objects")
+	var freed int64
+	err = filepath.WalkDir(objectsDir, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		hash := filepath.Base(filepath.Dir(path)) + d.Name()
+		if referenced[hash] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		freed += info.Size()
+		return nil
+	})
+	if err != nil {
+		return freed, fmt.Errorf("failed to remove unused objects: %w", err)
+	}
+	return freed, nil
+}
+
+// sameFile reports whether two regular files can be assumed identical
+// without reading them
+func sameFile(a, b models.SnapshotFile) bool {
+	return a.Size == b.Size && a.Mode == b.Mode && a.ModTime.Equal(b.ModTime)
+}
+
+// writeAtomic writes data to a temporary file and renames it into place
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupTestEnv creates a sandbox with a repository, a test artifact and state
+func setupTestEnv(t *testing.T) string {
+	tmpDir := t.TempDir()
+
+	inventoryDir := filepath.Join(tmpDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-test-service-dev1")
+	os.MkdirAll(inventoryDir, 0755)
+	os.WriteFile(filepath.Join(inventoryDir, "nx-app-inventory.yaml"), []byte("enabled: false\n"), 0644)
+
+	os.MkdirAll(filepath.Join(tmpDir, "test-artifacts", "nx-bff-test-service"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "test-artifacts", "nx-bff-test-service", "run.sh"), []byte("#!/bin/sh\n"), 0755)
+
+	os.MkdirAll(filepath.Join(tmpDir, ".nx-sandbox"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, ".nx-sandbox", "approvals.json"), []byte("[]\n"), 0644)
+
+	return tmpDir
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	store := NewStore(baseDir)
+
+	snap, err := store.Create("before")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(snap.Roots) != 3 {
+		t.Errorf("Expected repos, test-artifacts and .nx-sandbox to be captured, got %v", snap.Roots)
+	}
+
+	inventoryFile := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-test-service-dev1", "nx-app-inventory.yaml")
+	os.WriteFile(inventoryFile, []byte("enabled: true\n"), 0644)
+	os.RemoveAll(filepath.Join(baseDir, "test-artifacts"))
+	os.MkdirAll(filepath.Join(baseDir, "local-artifacts", "scratch"), 0755)
+	os.WriteFile(filepath.Join(baseDir, "repos", "stray.txt"), []byte("stray"), 0644)
+
+	result, err := store.Restore("before")
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.Removed != 2 {
+		t.Errorf("Expected local-artifacts and stray.txt to be removed, got %d removals", result.Removed)
+	}
+
+	if data, _ := os.ReadFile(inventoryFile); string(data) != "enabled: false\n" {
+		t.Errorf("Inventory not restored, got %q", data)
+	}
+	info, err := os.Stat(filepath.Join(baseDir, "test-artifacts", "nx-bff-test-service", "run.sh"))
+	if err != nil {
+		t.Fatalf("Deleted file not restored: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("Expected mode 0755, got %v", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, "local-artifacts")); !os.IsNotExist(err) {
+		t.Error("Directories created after the snapshot should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, "repos", "stray.txt")); !os.IsNotExist(err) {
+		t.Error("Files created after the snapshot should be removed")
+	}
+	if _, err := os.Stat(Dir(baseDir)); err != nil {
+		t.Error("Restore must keep the snapshot store")
+	}
+
+	again, err := store.Restore("before")
+	if err != nil {
+		t.Fatalf("Second restore failed: %v", err)
+	}
+	if again.Written != 0 || again.Removed != 0 {
+		t.Errorf("Expected a no-op restore, got %+v", again)
+	}
+}
+
+func TestCreate_SharesObjects(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	store := NewStore(baseDir)
+
+	if _, err := store.Create("one"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	first, _ := store.Usage()
+
+	if _, err := store.Create("two"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second, _ := store.Usage()
+
+	if second.Snapshots != 2 {
+		t.Errorf("Expected 2 snapshots, got %d", second.Snapshots)
+	}
+	if second.Objects != first.Objects {
+		t.Errorf("Unchanged files should not add objects: %d then %d", first.Objects, second.Objects)
+	}
+
+	if _, err := store.Create("one"); err == nil {
+		t.Error("Expected duplicate snapshot name to fail")
+	}
+	if _, err := store.Create("../escape"); err == nil {
+		t.Error("Expected invalid snapshot name to fail")
+	}
+}
+
+func TestDelete_CollectsUnusedObjects(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	store := NewStore(baseDir)
+
+	store.Create("one")
+	os.WriteFile(filepath.Join(baseDir, "repos", "new.txt"), []byte("only in two"), 0644)
+	store.Create("two")
+
+	freed, err := store.Delete("two")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if freed == 0 {
+		t.Error("Expected the object only used by 'two' to be freed")
+	}
+
+	snaps, _ := store.List()
+	if len(snaps) != 1 || snaps[0].Name != "one" {
+		t.Errorf("Unexpected snapshots after delete: %+v", snaps)
+	}
+	if _, err := store.Restore("one"); err != nil {
+		t.Errorf("Remaining snapshot should still restore: %v", err)
+	}
+
+	if _, err := store.Delete("missing"); err == nil {
+		t.Error("Expected deleting an unknown snapshot to fail")
+	}
+}
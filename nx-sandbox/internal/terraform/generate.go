@@ -0,0 +1,360 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/infra"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+)
+
+// ModulesSource is the path from a component directory to the shared modules
+const ModulesSource = "../../modules"
+
+// File is a generated Terraform file
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Component is a generated Terraform component for one artifact in one environment
+type Component struct {
+	Artifact    string
+	Environment string
+	Dir         string
+	Files       []File
+}
+
+// Generator defines the interface for generating Terraform from inventories
+type Generator interface {
+	Generate(artifact, env string) (*Component, error)
+	Write(component *Component) ([]string, error)
+}
+
+// DefaultGenerator renders a nexus-infrastructure component with one module
+// call per enabled inventory component. Output depends only on the inventory
+// and configuration, so regenerating an unchanged inventory is a no-op.
+type DefaultGenerator struct {
+	baseDir string
+}
+
+// NewGenerator creates a new Terraform generator for a sandbox root
+func NewGenerator(baseDir string) Generator {
+	return &DefaultGenerator{
+		baseDir: baseDir,
+	}
+}
+
+// variable is a component input: declared in variables.tf, set in terraform.tfvars
+// and passed to a module
+type variable struct {
+	name        string
+	description string
+	value       string
+	input       string
+	optional    bool
+}
+
+// module is a module call generated for an inventory component
+type module struct {
+	name      string
+	source    string
+	variables []variable
+	outputs   []string
+}
+
+// Generate renders the Terraform component of an artifact without writing it
+func (g *DefaultGenerator) Generate(artifact, env string) (*Component, error) {
+	cfg, err := config.Load(g.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := inventory.Open(g.baseDir, artifact, env)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := modulesFor(entry)
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("'%s' in %s has no enabled components", entry.Name(), env)
+	}
+
+	source, err := filepath.Rel(filepath.Dir(layout.InventoryRoot(g.baseDir)), entry.Path)
+	if err != nil {
+		source = entry.Path
+	}
+	header := fmt.Sprintf("# Generated by nx-sandbox from %s. DO NOT EDIT.\n# Regenerate with: nx-sandbox terraform generate %s --env %s\n\n",
+		filepath.ToSlash(source), entry.Name(), env)
+
+	common := []variable{
+		{name: "artifact_name", description: "Inventory artifact name", value: quote(entry.Name())},
+		{name: "environment", description: "Sandbox environment", value: quote(env)},
+		{name: "owner", description: "Owning team", value: quote(entry.Inventory.ArtifactMetadata.Owner)},
+		{name: "region", description: "AWS region", value: quote(cfg.AWS.Region)},
+	}
+
+	files := []File{
+		{Name: "main.tf", Content: renderMain(modules)},
+		{Name: "variables.tf", Content: renderVariables(common, modules)},
+		{Name: "outputs.tf", Content: renderOutputs(modules)},
+		{Name: "terraform.tfvars", Content: renderTFVars(common, modules)},
+		{Name: "provider_aws.tf", Content: renderProvider()},
+	}
+	for i := range files {
+		files[i].Content = append([]byte(header), files[i].Content...)
+	}
+
+	return &Component{
+		Artifact:    entry.Name(),
+		Environment: env,
+		Dir:         layout.TerraformComponentDir(g.baseDir, entry.Name()),
+		Files:       files,
+	}, nil
+}
+
+// Write saves a generated component, replacing any previous generation
+func (g *DefaultGenerator) Write(component *Component) ([]string, error) {
+	if err := os.MkdirAll(component.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", component.Dir, err)
+	}
+
+	var written []string
+	for _, file := range component.Files {
+		path := filepath.Join(component.Dir, file.Name)
+		if err := os.WriteFile(path, file.Content, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// Helper methods
+
+// modulesFor maps the enabled inventory components to module calls, in schema order
+func modulesFor(entry *inventory.Entry) []module {
+	components := entry.Inventory.Components
+	var modules []module
+
+	if components.ServiceAccount.Enabled {
+		namespace := components.ServiceAccount.Namespace
+		if namespace == "" {
+			namespace = "nexus-" + entry.Environment()
+		}
+		modules = append(modules, module{
+			name:   "service_account",
+			source: "iam-service-account",
+			variables: []variable{
+				{name: "service_account_role_name", input: "role_name", description: "IAM role assumed by the service account", value: quote(infra.RoleName(entry))},
+				{name: "service_account_namespace", input: "namespace", description: "Kubernetes namespace of the service account", value: quote(namespace)},
+			},
+			outputs: []string{"role_arn"},
+		})
+	}
+
+	if components.Redis.Enabled {
+		modules = append(modules, module{
+			name:   "redis",
+			source: "elasticache-redis",
+			variables: []variable{
+				{name: "redis_cluster_id", input: "cluster_id", description: "ElastiCache cluster identifier", value: quote(infra.CacheClusterID(entry))},
+				{name: "redis_node_type", input: "node_type", description: "ElastiCache node type", value: quote(infra.DefaultRedisNodeType)},
+			},
+			outputs: []string{"endpoint"},
+		})
+	}
+
+	if components.Dynamo.Enabled {
+		partitionKey := components.Dynamo.PartitionKey
+		if partitionKey == "" {
+			partitionKey = infra.DefaultPartitionKey
+		}
+		sortKey := variable{name: "dynamo_sort_key", input: "sort_key", description: "DynamoDB sort key, null for a partition key only table", optional: true}
+		if components.Dynamo.SortKey != "" {
+			sortKey.value = quote(components.Dynamo.SortKey)
+		}
+		modules = append(modules, module{
+			name:   "dynamo",
+			source: "dynamodb-table",
+			variables: []variable{
+				{name: "dynamo_table_name", input: "table_name", description: "DynamoDB table name", value: quote(infra.TableName(entry))},
+				{name: "dynamo_partition_key", input: "partition_key", description: "DynamoDB partition key", value: quote(partitionKey)},
+				sortKey,
+			},
+			outputs: []string{"table_arn"},
+		})
+	}
+
+	if components.RDS.Enabled {
+		class := components.RDS.InstanceClass
+		if class == "" {
+			class = infra.DefaultRDSInstanceClass
+		}
+		engine := components.RDS.Engine
+		if engine == "" {
+			engine = infra.DefaultRDSEngine
+		}
+		modules = append(modules, module{
+			name:   "rds",
+			source: "rds-instance",
+			variables: []variable{
+				{name: "rds_identifier", input: "identifier", description: "RDS instance identifier", value: quote(infra.DBInstanceID(entry))},
+				{name: "rds_instance_class", input: "instance_class", description: "RDS instance class", value: quote(class)},
+				{name: "rds_engine", input: "engine", description: "RDS database engine", value: quote(engine)},
+			},
+			outputs: []string{"endpoint"},
+		})
+	}
+
+	if components.ECR.Enabled {
+		modules = append(modules, module{
+			name:   "ecr",
+			source: "ecr-repository",
+			variables: []variable{
+				{name: "ecr_repository_name", input: "repository_name", description: "ECR repository name", value: quote(infra.RepositoryName(entry))},
+			},
+			outputs: []string{"repository_url"},
+		})
+	}
+
+	return modules
+}
+
+func renderMain(modules []module) []byte {
+	w := &hclWriter{}
+
+	w.open("locals")
+	w.open("tags =")
+	w.attributes([]attribute{
+		{"Artifact", "var.artifact_name"},
+		{"Environment", "var.environment"},
+		{"ManagedBy", quote("nx-sandbox")},
+		{"Owner", "var.owner"},
+	})
+	w.close()
+	w.close()
+
+	for _, m := range modules {
+		w.line("")
+		w.open(fmt.Sprintf("module %s", quote(m.name)))
+		w.attributes([]attribute{{"source", quote(ModulesSource + "/" + m.source)}})
+		w.line("")
+
+		var inputs []attribute
+		for _, v := range m.variables {
+			inputs = append(inputs, attribute{v.input, "var." + v.name})
+		}
+		inputs = append(inputs, attribute{"tags", "local.tags"})
+		w.attributes(inputs)
+		w.close()
+	}
+
+	return w.bytes()
+}
+
+func renderVariables(common []variable, modules []module) []byte {
+	w := &hclWriter{}
+
+	all := append([]variable(nil), common...)
+	for _, m := range modules {
+		all = append(all, m.variables...)
+	}
+
+	for i, v := range all {
+		if i > 0 {
+			w.line("")
+		}
+		attrs := []attribute{
+			{"description", quote(v.description)},
+			{"type", "string"},
+		}
+		if v.optional {
+			attrs = append(attrs, attribute{"default", "null"})
+		}
+		w.open(fmt.Sprintf("variable %s", quote(v.name)))
+		w.attributes(attrs)
+		w.close()
+	}
+
+	return w.bytes()
+}
+
+func renderOutputs(modules []module) []byte {
+	w := &hclWriter{}
+
+	first := true
+	for _, m := range modules {
+		for _, output := range m.outputs {
+			if !first {
+				w.line("")
+			}
+			first = false
+
+			w.open(fmt.Sprintf("output %s", quote(m.name+"_"+output)))
+			w.attributes([]attribute{{"value", fmt.Sprintf("module.%s.%s", m.name, output)}})
+			w.close()
+		}
+	}
+
+	return w.bytes()
+}
+
+func renderTFVars(common []variable, modules []module) []byte {
+	w := &hclWriter{}
+
+	var attrs []attribute
+	for _, v := range common {
+		attrs = append(attrs, attribute{v.name, v.value})
+	}
+	w.attributes(attrs)
+
+	for _, m := range modules {
+		attrs = nil
+		for _, v := range m.variables {
+			if v.value != "" {
+				attrs = append(attrs, attribute{v.name, v.value})
+			}
+		}
+		w.line("")
+		w.attributes(attrs)
+	}
+
+	return w.bytes()
+}
+
+func renderProvider() []byte {
+	w := &hclWriter{}
+
+	w.open("terraform")
+	w.open("required_providers")
+	w.open("aws =")
+	w.attributes([]attribute{
+		{"source", quote("hashicorp/aws")},
+		{"version", quote("~> 5.0")},
+	})
+	w.close()
+	w.close()
+	w.close()
+	w.line("")
+
+	// Mirrors components/tool/provider_aws.tf so plans run without AWS credentials
+	w.open(`provider "aws"`)
+	w.attributes([]attribute{
+		{"region", "var.region"},
+		{"skip_credentials_validation", "true"},
+		{"skip_metadata_api_check", "true"},
+		{"skip_requesting_account_id", "true"},
+	})
+	w.line("")
+	w.attributes([]attribute{
+		{"access_key", quote("mock_access_key")},
+		{"secret_key", quote("mock_secret_key")},
+	})
+	w.close()
+
+	return w.bytes()
+}
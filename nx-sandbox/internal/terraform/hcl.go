@@ -0,0 +1,85 @@
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// attribute is a single `name = expression` line in an HCL body
+type attribute struct {
+	name  string
+	value string
+}
+
+// hclWriter renders HCL the way `terraform fmt` lays it out: two-space
+// indentation, aligned equals signs within a group of attributes and a blank
+// line between blocks
+type hclWriter struct {
+	buf    bytes.Buffer
+	indent int
+}
+
+func (w *hclWriter) line(format string, args ...interface{}) {
+	if format == "" {
+		w.buf.WriteString("\n")
+		return
+	}
+	w.buf.WriteString(strings.Repeat("  ", w.indent))
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteString("\n")
+}
+
+func (w *hclWriter) open(header string) {
+	w.line("%s {", header)
+	w.indent++
+}
+
+func (w *hclWriter) close() {
+	w.indent--
+	w.line("}")
+}
+
+// attributes writes a group of attributes with their equals signs aligned
+func (w *hclWriter) attributes(attrs []attribute) {
+	width := 0
+	for _, attr := range attrs {
+		if len(attr.name) > width {
+			width = len(attr.name)
+		}
+	}
+	for _, attr := range attrs {
+		w.line("%-*s = %s", width, attr.name, attr.value)
+	}
+}
+
+func (w *hclWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// quote renders s as an HCL string literal
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			// Escape template sequences so values are taken literally
+			if i+1 < len(s) && s[i+1] == '{' {
+				b.WriteByte(c)
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
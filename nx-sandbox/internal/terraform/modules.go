@@ -0,0 +1,33 @@
+package terraform
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+)
+
+// moduleFiles holds the shared modules called by generated components, one
+// directory per module source
+//
+//go:embed modules
+var moduleFiles embed.FS
+
+// Modules returns the files of the shared modules, keyed by slash-separated
+// path relative to the modules directory, e.g. elasticache-redis/main.tf.
+// They are written to nexus-infrastructure/modules so that every ModulesSource
+// reference in a generated component resolves.
+func Modules() (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(moduleFiles, "modules", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := moduleFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(name, "modules/")] = content
+		return nil
+	})
+	return files, err
+}
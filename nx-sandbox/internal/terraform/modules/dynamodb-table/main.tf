@@ -0,0 +1,31 @@
+# Shared module written by nx-sandbox seed; called by generated components.
+
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+resource "aws_dynamodb_table" "this" {
+  name         = var.table_name
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = var.partition_key
+  range_key    = var.sort_key
+  tags         = var.tags
+
+  attribute {
+    name = var.partition_key
+    type = "S"
+  }
+
+  dynamic "attribute" {
+    for_each = var.sort_key == null ? [] : [var.sort_key]
+    content {
+      name = attribute.value
+      type = "S"
+    }
+  }
+}
@@ -0,0 +1,22 @@
+variable "table_name" {
+  description = "DynamoDB table name"
+  type        = string
+}
+
+variable "partition_key" {
+  description = "DynamoDB partition key"
+  type        = string
+  default     = "id"
+}
+
+variable "sort_key" {
+  description = "DynamoDB sort key, null for a partition key only table"
+  type        = string
+  default     = null
+}
+
+variable "tags" {
+  description = "Tags applied to every resource"
+  type        = map(string)
+  default     = {}
+}
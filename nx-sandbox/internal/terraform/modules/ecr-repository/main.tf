@@ -0,0 +1,15 @@
+# Shared module written by nx-sandbox seed; called by generated components.
+
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+resource "aws_ecr_repository" "this" {
+  name = var.repository_name
+  tags = var.tags
+}
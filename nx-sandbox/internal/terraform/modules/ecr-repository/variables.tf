@@ -0,0 +1,10 @@
+variable "repository_name" {
+  description = "ECR repository name"
+  type        = string
+}
+
+variable "tags" {
+  description = "Tags applied to every resource"
+  type        = map(string)
+  default     = {}
+}
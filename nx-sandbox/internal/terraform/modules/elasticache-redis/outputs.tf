@@ -0,0 +1,3 @@
+output "endpoint" {
+  value = "${aws_elasticache_cluster.this.cache_nodes[0].address}:${aws_elasticache_cluster.this.cache_nodes[0].port}"
+}
@@ -0,0 +1,25 @@
+# Shared module written by nx-sandbox seed; called by generated components.
+
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+resource "aws_iam_role" "this" {
+  name = var.role_name
+  tags = merge(var.tags, { Namespace = var.namespace })
+
+  # Lets EKS service accounts assume the role, as nx-sandbox infra apply does
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Allow"
+      Principal = { Service = "eks.amazonaws.com" }
+      Action    = "sts:AssumeRole"
+    }]
+  })
+}
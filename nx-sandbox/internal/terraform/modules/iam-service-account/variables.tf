@@ -0,0 +1,15 @@
+variable "role_name" {
+  description = "IAM role assumed by the service account"
+  type        = string
+}
+
+variable "namespace" {
+  description = "Kubernetes namespace of the service account"
+  type        = string
+}
+
+variable "tags" {
+  description = "Tags applied to every resource"
+  type        = map(string)
+  default     = {}
+}
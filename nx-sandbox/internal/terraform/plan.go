@@ -0,0 +1,212 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/infra"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/inventory"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// plan is the subset of the `terraform show -json` format the summary reads
+type plan struct {
+	Variables       map[string]planVariable `json:"variables"`
+	ResourceChanges []resourceChange        `json:"resource_changes"`
+}
+
+type planVariable struct {
+	Value interface{} `json:"value"`
+}
+
+type resourceChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Change  struct {
+		Actions []string               `json:"actions"`
+		Before  map[string]interface{} `json:"before"`
+		After   map[string]interface{} `json:"after"`
+	} `json:"change"`
+}
+
+// nameAttributes are the resource attributes that carry a resource's AWS name
+var nameAttributes = []string{"name", "cluster_id", "replication_group_id", "identifier"}
+
+// Summarizer defines the interface for summarizing Terraform plans
+type Summarizer interface {
+	Summarize(data []byte) (*models.PlanSummary, error)
+}
+
+// DefaultSummarizer counts planned changes per resource type and maps each
+// resource back to the inventory artifact that owns it
+type DefaultSummarizer struct {
+	baseDir string
+}
+
+// NewSummarizer creates a new plan summarizer for a sandbox root
+func NewSummarizer(baseDir string) Summarizer {
+	return &DefaultSummarizer{
+		baseDir: baseDir,
+	}
+}
+
+// Summarize parses `terraform show -json` output. Deletes and replaces of
+// resources owned by the last environment in the promotion chain (prod1 by
+// default) are flagged as destructive.
+func (s *DefaultSummarizer) Summarize(data []byte) (*models.PlanSummary, error) {
+	var p plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+
+	cfg, err := config.Load(s.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	protected := cfg.Environments[len(cfg.Environments)-1]
+
+	owners, err := s.ownersByName()
+	if err != nil {
+		return nil, err
+	}
+	planArtifact := stringVariable(p.Variables, "artifact_name")
+	planEnvironment := stringVariable(p.Variables, "environment")
+
+	summary := &models.PlanSummary{}
+	types := make(map[string]*models.PlanTypeSummary)
+
+	for _, rc := range p.ResourceChanges {
+		if rc.Mode == "data" {
+			continue
+		}
+		action, ok := summarizeActions(rc.Change.Actions)
+		if !ok {
+			continue
+		}
+
+		change := models.PlanResourceChange{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Action:  action,
+		}
+
+		// Prefer the state being removed for deletes, the planned state otherwise
+		values := []map[string]interface{}{rc.Change.After, rc.Change.Before}
+		if action == models.PlanDelete {
+			values = []map[string]interface{}{rc.Change.Before}
+		}
+		change.Artifact, change.Environment = owner(values, owners)
+		if change.Artifact == "" {
+			change.Artifact = planArtifact
+		}
+		if change.Environment == "" {
+			change.Environment = planEnvironment
+		}
+
+		change.Destructive = change.Environment == protected && (action == models.PlanDelete || action == models.PlanReplace)
+		summary.Changes = append(summary.Changes, change)
+
+		counts, ok := types[rc.Type]
+		if !ok {
+			counts = &models.PlanTypeSummary{Type: rc.Type}
+			types[rc.Type] = counts
+		}
+		switch action {
+		case models.PlanCreate:
+			counts.Create++
+		case models.PlanUpdate:
+			counts.Update++
+		case models.PlanDelete:
+			counts.Delete++
+		case models.PlanReplace:
+			counts.Replace++
+		}
+	}
+
+	for _, counts := range types {
+		summary.Types = append(summary.Types, *counts)
+	}
+	sort.Slice(summary.Types, func(i, j int) bool { return summary.Types[i].Type < summary.Types[j].Type })
+
+	return summary, nil
+}
+
+// Helper methods
+
+// inventoryOwner identifies the inventory entry a resource name belongs to
+type inventoryOwner struct {
+	artifact    string
+	environment string
+}
+
+// ownersByName indexes every inventory's resource names
+func (s *DefaultSummarizer) ownersByName() (map[string]inventoryOwner, error) {
+	entries, err := inventory.List(s.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]inventoryOwner)
+	for _, entry := range entries {
+		o := inventoryOwner{artifact: entry.Name(), environment: entry.Environment()}
+		for _, name := range []string{
+			infra.RoleName(entry),
+			infra.CacheClusterID(entry),
+			infra.TableName(entry),
+			infra.DBInstanceID(entry),
+			infra.RepositoryName(entry),
+		} {
+			owners[name] = o
+		}
+	}
+	return owners, nil
+}
+
+// summarizeActions maps Terraform's action list to a single action.
+// No-op and read changes report false.
+func summarizeActions(actions []string) (models.PlanAction, bool) {
+	switch {
+	case len(actions) == 2:
+		return models.PlanReplace, true
+	case len(actions) == 1 && actions[0] == "create":
+		return models.PlanCreate, true
+	case len(actions) == 1 && actions[0] == "update":
+		return models.PlanUpdate, true
+	case len(actions) == 1 && actions[0] == "delete":
+		return models.PlanDelete, true
+	}
+	return "", false
+}
+
+// owner finds the artifact and environment of a resource from its tags,
+// falling back to its AWS name
+func owner(values []map[string]interface{}, owners map[string]inventoryOwner) (string, string) {
+	for _, v := range values {
+		for _, key := range []string{"tags", "tags_all"} {
+			tags, _ := v[key].(map[string]interface{})
+			artifact, _ := tags["Artifact"].(string)
+			environment, _ := tags["Environment"].(string)
+			if artifact != "" {
+				return artifact, environment
+			}
+		}
+	}
+
+	for _, v := range values {
+		for _, attr := range nameAttributes {
+			name, _ := v[attr].(string)
+			if o, ok := owners[name]; ok && name != "" {
+				return o.artifact, o.environment
+			}
+		}
+	}
+	return "", ""
+}
+
+func stringVariable(variables map[string]planVariable, name string) string {
+	value, _ := variables[name].Value.(string)
+	return value
+}
@@ -0,0 +1,301 @@
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/golden"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+const testInventory = `schema_version: "1.0"
+artifact_metadata:
+  artifact_name: "nx-bff-web-payment-dev1"
+  layer: "bff"
+  owner: "devx-team"
+infrastructure:
+  enabled: true
+  environment: "dev1"
+components:
+  service_account:
+    name: "sa-nx-bff-web-payment-dev1"
+    enabled: false
+  redis:
+    cluster_id: "nx-payment-cache"
+    enabled: true
+  dynamo:
+    table_name: "nx-payment-sessions"
+    partition_key: "session_id"
+    enabled: true
+`
+
+// setupTestEnv creates a sandbox with one inventory entry
+func setupTestEnv(t *testing.T) string {
+	tmpDir := t.TempDir()
+
+	artifactDir := filepath.Join(tmpDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1")
+	os.MkdirAll(artifactDir, 0755)
+	os.WriteFile(filepath.Join(artifactDir, "nx-app-inventory.yaml"), []byte(testInventory), 0644)
+
+	return tmpDir
+}
+
+func fileContent(component *Component, name string) string {
+	for _, file := range component.Files {
+		if file.Name == name {
+			return string(file.Content)
+		}
+	}
+	return ""
+}
+
+func TestGenerate(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	generator := NewGenerator(baseDir)
+
+	component, err := generator.Generate("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if filepath.Base(component.Dir) != "nx-bff-web-payment-dev1" {
+		t.Errorf("Unexpected component dir: %s", component.Dir)
+	}
+
+	main := fileContent(component, "main.tf")
+	wantModule := `module "redis" {
+  source = "../../modules/elasticache-redis"
+
+  cluster_id = var.redis_cluster_id
+  node_type  = var.redis_node_type
+  tags       = local.tags
+}`
+	if !strings.Contains(main, wantModule) {
+		t.Errorf("main.tf missing redis module:\n%s", main)
+	}
+	if !strings.Contains(main, `source = "../../modules/dynamodb-table"`) {
+		t.Error("main.tf missing dynamo module")
+	}
+	if strings.Contains(main, "iam-service-account") {
+		t.Error("Disabled service account should not be generated")
+	}
+	if !strings.HasPrefix(main, "# Generated by nx-sandbox from nx-artifacts/bff/nx-bff-web-payment-dev1/nx-app-inventory.yaml") {
+		t.Errorf("Unexpected header:\n%s", main)
+	}
+
+	tfvars := fileContent(component, "terraform.tfvars")
+	for _, want := range []string{`redis_cluster_id = "nx-payment-cache"`, `dynamo_partition_key = "session_id"`, `region        = "us-east-1"`} {
+		if !strings.Contains(tfvars, want) {
+			t.Errorf("terraform.tfvars missing %q:\n%s", want, tfvars)
+		}
+	}
+	if strings.Contains(tfvars, "dynamo_sort_key") {
+		t.Error("Empty sort key should be left to its null default")
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	generator := NewGenerator(baseDir)
+
+	first, _ := generator.Generate("nx-bff-web-payment", "dev1")
+	if _, err := generator.Write(first); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	second, _ := generator.Generate("nx-bff-web-payment", "dev1")
+
+	for i, file := range first.Files {
+		if !bytes.Equal(file.Content, second.Files[i].Content) {
+			t.Errorf("%s differs between generations", file.Name)
+		}
+		written, err := os.ReadFile(filepath.Join(first.Dir, file.Name))
+		if err != nil || !bytes.Equal(written, file.Content) {
+			t.Errorf("%s was not written as generated", file.Name)
+		}
+	}
+}
+
+func TestGenerate_ModulesResolve(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	path := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1", "nx-app-inventory.yaml")
+	all := strings.Replace(testInventory, "enabled: false", "enabled: true", 1) + `  rds:
+    enabled: true
+  ecr:
+    enabled: true
+`
+	os.WriteFile(path, []byte(all), 0644)
+
+	modules, err := Modules()
+	if err != nil {
+		t.Fatalf("Modules failed: %v", err)
+	}
+	for name, content := range modules {
+		full := filepath.Join(baseDir, "repos", "nexus-infrastructure", "modules", filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(full), 0755)
+		os.WriteFile(full, content, 0644)
+	}
+
+	component, err := NewGenerator(baseDir).Generate("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	blockPattern := regexp.MustCompile(`(?s)module "([a-z_]+)" \{\n(.*?)\n\}`)
+	attributePattern := regexp.MustCompile(`(?m)^\s+([a-z_]+)\s+=\s+(.+)$`)
+	declared := func(dir, kind, name string) bool {
+		file := map[string]string{"variable": "variables.tf", "output": "outputs.tf"}[kind]
+		data, _ := os.ReadFile(filepath.Join(dir, file))
+		return strings.Contains(string(data), fmt.Sprintf("%s %q {", kind, name))
+	}
+
+	moduleDirs := map[string]string{}
+	blocks := blockPattern.FindAllStringSubmatch(fileContent(component, "main.tf"), -1)
+	if len(blocks) != 5 {
+		t.Fatalf("Expected 5 module calls, got %d", len(blocks))
+	}
+	for _, block := range blocks {
+		for _, attr := range attributePattern.FindAllStringSubmatch(block[2], -1) {
+			if attr[1] == "source" {
+				dir := filepath.Join(component.Dir, filepath.FromSlash(strings.Trim(attr[2], `"`)))
+				if _, err := os.Stat(filepath.Join(dir, "main.tf")); err != nil {
+					t.Fatalf("Module %s source %s does not resolve", block[1], attr[2])
+				}
+				moduleDirs[block[1]] = dir
+				continue
+			}
+			if !declared(moduleDirs[block[1]], "variable", attr[1]) {
+				t.Errorf("Module %s does not declare the variable %s", block[1], attr[1])
+			}
+		}
+	}
+
+	for _, ref := range regexp.MustCompile(`module\.([a-z_]+)\.([a-z_]+)`).FindAllStringSubmatch(fileContent(component, "outputs.tf"), -1) {
+		if !declared(moduleDirs[ref[1]], "output", ref[2]) {
+			t.Errorf("Module %s does not declare the output %s", ref[1], ref[2])
+		}
+	}
+}
+
+// TestModules_MatchRepository keeps the modules committed under repos/ in
+// step with the embedded ones, which are the source of truth. Run
+// 'nx-sandbox seed' after changing internal/terraform/modules.
+func TestModules_MatchRepository(t *testing.T) {
+	dir := filepath.Join("..", "..", "..", "repos", "nexus-infrastructure", "modules")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		t.Skip("no mock repositories next to the module")
+	}
+
+	modules, err := Modules()
+	if err != nil {
+		t.Fatalf("Modules failed: %v", err)
+	}
+	for name, content := range modules {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("Failed to read the committed module file: %v", err)
+		} else if !bytes.Equal(data, content) {
+			t.Errorf("repos/nexus-infrastructure/modules/%s differs from internal/terraform/modules/%s", name, name)
+		}
+	}
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if _, ok := modules[filepath.ToSlash(rel)]; !ok {
+			t.Errorf("repos/nexus-infrastructure/modules/%s is not in internal/terraform/modules", filepath.ToSlash(rel))
+		}
+		return nil
+	})
+}
+
+func TestGenerate_NoComponents(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	path := filepath.Join(baseDir, "repos", "nx-artifacts-inventory", "nx-artifacts", "bff", "nx-bff-web-payment-dev1", "nx-app-inventory.yaml")
+	os.WriteFile(path, []byte(strings.ReplaceAll(testInventory, "enabled: true", "enabled: false")), 0644)
+
+	if _, err := NewGenerator(baseDir).Generate("nx-bff-web-payment", "dev1"); err == nil {
+		t.Error("Expected inventory without components to be rejected")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got := quote(`a "b" ${c}`); got != `"a \"b\" $${c}"` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+}
+
+const testPlan = `{
+  "format_version": "1.2",
+  "variables": {"environment": {"value": "dev1"}},
+  "resource_changes": [
+    {"address": "module.redis.aws_elasticache_cluster.this", "mode": "managed", "type": "aws_elasticache_cluster",
+     "change": {"actions": ["create"], "before": null, "after": {"cluster_id": "nx-payment-cache", "tags": {"Artifact": "nx-bff-web-payment-dev1", "Environment": "dev1"}}}},
+    {"address": "module.dynamo.aws_dynamodb_table.this", "mode": "managed", "type": "aws_dynamodb_table",
+     "change": {"actions": ["delete"], "before": {"name": "nx-payment-sessions"}, "after": null}},
+    {"address": "module.ecr.aws_ecr_repository.this", "mode": "managed", "type": "aws_ecr_repository",
+     "change": {"actions": ["delete", "create"], "before": {"name": "nx-bff-web-payment-prod1"}, "after": {"name": "nx-bff-web-payment-prod1", "tags": {"Artifact": "nx-bff-web-payment-prod1", "Environment": "prod1"}}}},
+    {"address": "module.ecr.aws_ecr_lifecycle_policy.this", "mode": "managed", "type": "aws_ecr_lifecycle_policy",
+     "change": {"actions": ["update"], "before": {}, "after": {}}},
+    {"address": "module.service_account.aws_iam_role.this", "mode": "managed", "type": "aws_iam_role",
+     "change": {"actions": ["no-op"], "before": {}, "after": {}}},
+    {"address": "data.aws_caller_identity.current", "mode": "data", "type": "aws_caller_identity",
+     "change": {"actions": ["read"], "before": null, "after": {}}}
+  ]
+}`
+
+func TestSummarize(t *testing.T) {
+	baseDir := setupTestEnv(t)
+
+	summary, err := NewSummarizer(baseDir).Summarize([]byte(testPlan))
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+
+	if len(summary.Changes) != 4 {
+		t.Fatalf("Expected 4 changes, got %d", len(summary.Changes))
+	}
+	if len(summary.Types) != 4 || summary.Types[0].Type != "aws_dynamodb_table" || summary.Types[0].Delete != 1 {
+		t.Errorf("Unexpected type summary: %+v", summary.Types)
+	}
+
+	byType := make(map[string]models.PlanResourceChange)
+	for _, change := range summary.Changes {
+		byType[change.Type] = change
+	}
+	if change := byType["aws_dynamodb_table"]; change.Artifact != "nx-bff-web-payment-dev1" || change.Destructive {
+		t.Errorf("Expected dev1 table delete mapped by name and not destructive: %+v", change)
+	}
+	if change := byType["aws_ecr_repository"]; change.Action != models.PlanReplace || !change.Destructive {
+		t.Errorf("Expected prod1 replace to be destructive: %+v", change)
+	}
+	if change := byType["aws_ecr_lifecycle_policy"]; change.Environment != "dev1" {
+		t.Errorf("Expected plan environment fallback, got %+v", change)
+	}
+
+	if destructive := summary.DestructiveChanges(); len(destructive) != 1 {
+		t.Errorf("Expected 1 destructive change, got %d", len(destructive))
+	}
+}
+
+func TestSummarize_InvalidJSON(t *testing.T) {
+	if _, err := NewSummarizer(setupTestEnv(t)).Summarize([]byte("not json")); err == nil {
+		t.Error("Expected invalid plan to fail")
+	}
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	baseDir := setupTestEnv(t)
+	component, err := NewGenerator(baseDir).Generate("nx-bff-web-payment", "dev1")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, file := range component.Files {
+		golden.Assert(t, filepath.Join("testdata", "golden", "nx-bff-web-payment-dev1", file.Name), file.Content)
+	}
+}
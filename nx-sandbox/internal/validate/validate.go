@@ -0,0 +1,399 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/helm"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/mesh"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/yamldoc"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind classifies the files the validator understands
+type Kind string
+
+const (
+	KindInventory Kind = "inventory"
+	KindChart     Kind = "chart"
+	KindValues    Kind = "values"
+	KindMesh      Kind = "mesh"
+)
+
+var (
+	semverPattern   = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+	yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+)
+
+// Validator defines the interface for validating inventories, charts and
+// mesh values
+type Validator interface {
+	Validate() (*models.ValidationReport, error)
+	ValidateFiles(paths []string) (*models.ValidationReport, error)
+}
+
+// DefaultValidator checks the inventories, Helm charts and mesh values under repos/
+// against the inventory schema and the sandbox configuration
+type DefaultValidator struct {
+	baseDir string
+}
+
+// NewValidator creates a new validator for a sandbox root
+func NewValidator(baseDir string) Validator {
+	return &DefaultValidator{
+		baseDir: baseDir,
+	}
+}
+
+// Classify returns the kind of a file under repos/, or "" when the validator
+// does not check it
+func Classify(baseDir, path string) Kind {
+	reposDir, err := filepath.Abs(layout.ReposDir(baseDir))
+	if err != nil {
+		return ""
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(reposDir, absPath)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+
+	switch {
+	case len(parts) == 5 && parts[0] == "nx-artifacts-inventory" && parts[1] == "nx-artifacts" &&
+		parts[4] == layout.InventoryFileName:
+		return KindInventory
+	case len(parts) == 3 && strings.HasPrefix(parts[0], layout.EnvironmentRepoPrefix) && parts[1] == layout.MeshDirName &&
+		parts[2] == helm.ValuesFileName:
+		return KindMesh
+	case len(parts) != 4 || !strings.HasPrefix(parts[0], layout.EnvironmentRepoPrefix) || !isKnownLayer(parts[1]):
+		return ""
+	case parts[3] == helm.ChartFileName:
+		return KindChart
+	case parts[3] == helm.ValuesFileName:
+		return KindValues
+	}
+	return ""
+}
+
+// Files lists every file the validator checks, sorted by path
+func Files(baseDir string) ([]string, error) {
+	patterns := []string{
+		filepath.Join(layout.InventoryRoot(baseDir), "*", "*", layout.InventoryFileName),
+		filepath.Join(layout.ReposDir(baseDir), layout.EnvironmentRepoPrefix+"*", "*", "*", helm.ChartFileName),
+		filepath.Join(layout.ReposDir(baseDir), layout.EnvironmentRepoPrefix+"*", "*", "*", helm.ValuesFileName),
+		filepath.Join(layout.ReposDir(baseDir), layout.EnvironmentRepoPrefix+"*", layout.MeshDirName, helm.ValuesFileName),
+	}
+
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			if Classify(baseDir, path) != "" {
+				files = append(files, path)
+			}
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// Validate checks every inventory and chart in the sandbox
+func (v *DefaultValidator) Validate() (*models.ValidationReport, error) {
+	files, err := Files(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return v.ValidateFiles(files)
+}
+
+// ValidateFiles checks the given files, skipping those the validator does
+// not understand. Missing files are ignored so deleted paths can be passed.
+func (v *DefaultValidator) ValidateFiles(paths []string) (*models.ValidationReport, error) {
+	cfg, err := config.Load(v.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ValidationReport{
+		Checked: []string{},
+		Issues:  []models.ValidationIssue{},
+	}
+	for _, path := range paths {
+		kind := Classify(v.baseDir, path)
+		if kind == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		report.Checked = append(report.Checked, path)
+		c := &checker{path: path}
+
+		doc, err := yamldoc.Parse(data)
+		if err != nil {
+			c.add(lineOfError(err), "yaml-syntax", models.SeverityError, "invalid YAML: %v", err)
+			report.Issues = append(report.Issues, c.issues...)
+			continue
+		}
+		c.doc = doc
+
+		switch kind {
+		case KindInventory:
+			c.checkInventory(cfg)
+		case KindChart:
+			c.checkChart()
+		case KindValues:
+			c.checkValues()
+		case KindMesh:
+			c.checkMesh()
+		}
+		sort.SliceStable(c.issues, func(i, j int) bool { return c.issues[i].Line < c.issues[j].Line })
+		report.Issues = append(report.Issues, c.issues...)
+	}
+
+	return report, nil
+}
+
+// Helper methods
+
+// checker collects the issues of a single file
+type checker struct {
+	path   string
+	doc    *yamldoc.Document
+	issues []models.ValidationIssue
+}
+
+func (c *checker) add(line int, rule string, severity models.Severity, format string, args ...interface{}) {
+	c.issues = append(c.issues, models.ValidationIssue{
+		Path:     c.path,
+		Line:     line,
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// line returns the line of the value at path, falling back to its nearest
+// existing parent
+func (c *checker) line(path string) int {
+	keys := strings.Split(path, ".")
+	for i := len(keys); i > 0; i-- {
+		if node := c.doc.GetKeys(keys[:i]); node != nil {
+			return node.Line
+		}
+	}
+	return 0
+}
+
+func (c *checker) require(paths ...string) {
+	for _, path := range paths {
+		if strings.TrimSpace(c.doc.GetString(path)) == "" {
+			c.add(c.line(path), "required", models.SeverityError, "%s is required", path)
+		}
+	}
+}
+
+func (c *checker) checkInventory(cfg *config.Config) {
+	var inv models.AppInventory
+	if err := c.doc.Decode(&inv); err != nil {
+		c.add(lineOfError(err), "inventory-schema", models.SeverityError, "inventory does not match the schema: %v", err)
+		return
+	}
+
+	c.require(
+		"artifact_metadata.artifact_name",
+		"artifact_metadata.layer",
+		"artifact_metadata.domain",
+		"artifact_metadata.service",
+		"artifact_metadata.owner",
+		"infrastructure.environment",
+	)
+
+	dirName := filepath.Base(filepath.Dir(c.path))
+	dirLayer := filepath.Base(filepath.Dir(filepath.Dir(c.path)))
+	meta := inv.ArtifactMetadata
+
+	if meta.ArtifactName != "" && meta.ArtifactName != dirName {
+		c.add(c.line("artifact_metadata.artifact_name"), "inventory-name", models.SeverityError,
+			"artifact_name %q does not match its directory %q", meta.ArtifactName, dirName)
+	}
+	if meta.Layer != "" {
+		if !isKnownLayer(meta.Layer) {
+			c.add(c.line("artifact_metadata.layer"), "inventory-layer", models.SeverityError,
+				"unknown layer %q (expected one of %s)", meta.Layer, strings.Join(models.KnownLayers, ", "))
+		} else if meta.Layer != dirLayer {
+			c.add(c.line("artifact_metadata.layer"), "inventory-layer", models.SeverityError,
+				"layer %q does not match the %s/ directory the inventory is in", meta.Layer, dirLayer)
+		}
+	}
+
+	env := inv.Infrastructure.Environment
+	if env != "" {
+		if !cfg.HasEnvironment(env) {
+			c.add(c.line("infrastructure.environment"), "inventory-environment", models.SeverityError,
+				"unknown environment %q (expected one of %s)", env, strings.Join(cfg.Environments, ", "))
+		} else if !strings.HasSuffix(dirName, "-"+env) {
+			c.add(c.line("infrastructure.environment"), "inventory-environment", models.SeverityWarning,
+				"inventory directory %q does not carry the -%s suffix", dirName, env)
+		}
+	}
+
+	component := inv.Infrastructure.Component
+	if component != "" && !isComponent(component) {
+		c.add(c.line("infrastructure.component"), "inventory-component", models.SeverityError,
+			"unknown component %q", component)
+	}
+	if inv.Infrastructure.Deployed && !inv.Infrastructure.Enabled {
+		c.add(c.line("infrastructure.deployed"), "inventory-deployed", models.SeverityWarning,
+			"infrastructure is deployed but not enabled")
+	}
+
+	components := inv.Components
+	if components.ServiceAccount.Enabled {
+		c.require("components.service_account.name", "components.service_account.namespace")
+	}
+	if components.Redis.Enabled {
+		c.require("components.redis.name", "components.redis.cluster_id")
+	}
+	if components.Dynamo.Enabled {
+		c.require("components.dynamo.table_name", "components.dynamo.partition_key")
+	}
+	if components.RDS.Enabled {
+		c.require("components.rds.instance_class", "components.rds.engine")
+	}
+	if components.ECR.Enabled {
+		c.require("components.ecr.repository_name")
+	}
+}
+
+func (c *checker) checkChart() {
+	c.require("apiVersion", "name", "version")
+
+	name := c.doc.GetString("name")
+	dirName := filepath.Base(filepath.Dir(c.path))
+	if name != "" && name != dirName {
+		c.add(c.line("name"), "chart-name", models.SeverityError,
+			"chart name %q does not match its directory %q", name, dirName)
+	}
+
+	version := c.doc.GetString("version")
+	if version != "" && !semverPattern.MatchString(version) {
+		c.add(c.line("version"), "chart-version", models.SeverityError,
+			"version %q is not a semantic version", version)
+	}
+}
+
+func (c *checker) checkValues() {
+	if replicas := c.doc.GetString("replicaCount"); replicas != "" {
+		if n, err := strconv.Atoi(replicas); err != nil || n < 1 {
+			c.add(c.line("replicaCount"), "values-replicas", models.SeverityError,
+				"replicaCount must be a positive integer, got %q", replicas)
+		}
+	}
+
+	if c.doc.GetString("autoscaling.enabled") == "true" {
+		min, minErr := strconv.Atoi(c.doc.GetString("autoscaling.minReplicas"))
+		max, maxErr := strconv.Atoi(c.doc.GetString("autoscaling.maxReplicas"))
+		switch {
+		case minErr != nil || maxErr != nil:
+			c.add(c.line("autoscaling"), "values-autoscaling", models.SeverityError,
+				"autoscaling needs integer minReplicas and maxReplicas")
+		case min < 1 || min > max:
+			c.add(c.line("autoscaling.minReplicas"), "values-autoscaling", models.SeverityError,
+				"minReplicas %d must be between 1 and maxReplicas %d", min, max)
+		}
+	}
+
+	for _, resource := range []string{"cpu", "memory"} {
+		request := c.quantity("resources.requests." + resource)
+		limit := c.quantity("resources.limits." + resource)
+		if request > 0 && limit > 0 && request > limit {
+			c.add(c.line("resources.requests."+resource), "values-resources", models.SeverityError,
+				"%s request %s exceeds the limit %s", resource,
+				c.doc.GetString("resources.requests."+resource), c.doc.GetString("resources.limits."+resource))
+		}
+	}
+
+	if c.doc.GetString("external.redis.enabled") == "true" && c.doc.GetString("external.redis.endpoint") == "" {
+		c.add(c.line("external.redis.endpoint"), "values-external", models.SeverityWarning,
+			"external.redis is enabled without an endpoint")
+	}
+	if c.doc.GetString("external.dynamodb.enabled") == "true" && c.doc.GetString("external.dynamodb.table_name") == "" {
+		c.add(c.line("external.dynamodb.table_name"), "values-external", models.SeverityWarning,
+			"external.dynamodb is enabled without a table_name")
+	}
+}
+
+func (c *checker) checkMesh() {
+	var values models.MeshValues
+	if err := c.doc.Decode(&values); err != nil {
+		c.add(lineOfError(err), "mesh-schema", models.SeverityError, "mesh values do not match the schema: %v", err)
+		return
+	}
+
+	for _, problem := range mesh.Check(values.ExternalServices) {
+		c.add(c.line(fmt.Sprintf("externalServices.%d.%s", problem.Index, problem.Field)), "mesh-external-service",
+			models.SeverityError, "%s", problem.Message)
+	}
+}
+
+// quantity parses a resource quantity, reporting malformed values
+func (c *checker) quantity(path string) float64 {
+	node := c.doc.Get(path)
+	if node == nil || node.Kind != yaml.ScalarNode || node.Value == "" {
+		return 0
+	}
+	value, err := helm.ParseQuantity(node.Value)
+	if err != nil {
+		c.add(node.Line, "values-resources", models.SeverityError, "%s: %v", path, err)
+		return 0
+	}
+	return value
+}
+
+// lineOfError extracts the line number from a YAML error message
+func lineOfError(err error) int {
+	if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return line
+	}
+	return 0
+}
+
+func isKnownLayer(layer string) bool {
+	for _, known := range models.KnownLayers {
+		if layer == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isComponent(name string) bool {
+	switch name {
+	case "service_account", "redis", "dynamo", "rds", "ecr":
+		return true
+	}
+	return false
+}
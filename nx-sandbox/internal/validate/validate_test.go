@@ -0,0 +1,184 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/seed"
+)
+
+func seedSandbox(t *testing.T) string {
+	t.Helper()
+	baseDir := t.TempDir()
+	if _, err := seed.NewSeeder(baseDir).Seed(seed.DefaultSpec(), false); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	return baseDir
+}
+
+func rules(report *models.ValidationReport) map[string]models.ValidationIssue {
+	issues := make(map[string]models.ValidationIssue)
+	for _, issue := range report.Issues {
+		issues[issue.Rule] = issue
+	}
+	return issues
+}
+
+func TestValidate_SeededSandbox(t *testing.T) {
+	baseDir := seedSandbox(t)
+
+	report, err := NewValidator(baseDir).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !report.Valid() || len(report.Issues) != 0 {
+		t.Errorf("Expected seeded sandbox to be clean, got %+v", report.Issues)
+	}
+	if len(report.Checked) == 0 {
+		t.Error("Expected files to be checked")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	baseDir := t.TempDir()
+	for path, expected := range map[string]Kind{
+		layout.InventoryFile(baseDir, "bff", "nx-bff-a-dev1"):                                KindInventory,
+		filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-a"), "Chart.yaml"):     KindChart,
+		filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-a"), "values.yaml"):    KindValues,
+		filepath.Join(layout.ChartDir(baseDir, "dev1", "mesh", "nx-bff-a"), "values.yaml"):   "",
+		filepath.Join(layout.EnvironmentRepo(baseDir, "dev1"), "README.md"):                  "",
+		filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-a"), "templates.yaml"): "",
+	} {
+		if got := Classify(baseDir, path); got != expected {
+			t.Errorf("Classify(%s) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestValidate_Inventory(t *testing.T) {
+	baseDir := seedSandbox(t)
+	path := layout.InventoryFile(baseDir, "bff", "nx-bff-web-payment-dev1")
+	content := `artifact_metadata:
+  artifact_name: nx-bff-web-other-dev1
+  layer: xp
+  domain: payments
+  service: web-payment
+infrastructure:
+  environment: dev9
+components:
+  redis:
+    enabled: true
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := NewValidator(baseDir).ValidateFiles([]string{path})
+	if err != nil {
+		t.Fatalf("ValidateFiles failed: %v", err)
+	}
+	issues := rules(report)
+	for rule, line := range map[string]int{
+		"inventory-name":        2,
+		"inventory-layer":       3,
+		"inventory-environment": 7,
+		"required":              10,
+	} {
+		if issue, ok := issues[rule]; !ok || issue.Line != line {
+			t.Errorf("Expected %s on line %d, got %+v", rule, line, issue)
+		}
+	}
+	if report.Valid() {
+		t.Error("Expected report to be invalid")
+	}
+}
+
+func TestValidate_ChartAndValues(t *testing.T) {
+	baseDir := seedSandbox(t)
+	dir := layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service")
+	chart := filepath.Join(dir, "Chart.yaml")
+	values := filepath.Join(dir, "values.yaml")
+	os.WriteFile(chart, []byte("apiVersion: v2\nname: nx-bff-test-service\nversion: one\n"), 0644)
+	os.WriteFile(values, []byte(`replicaCount: 0
+resources:
+  requests:
+    cpu: 2
+  limits:
+    cpu: 500m
+external:
+  redis:
+    enabled: true
+`), 0644)
+
+	report, err := NewValidator(baseDir).ValidateFiles([]string{chart, values, filepath.Join(dir, "missing.yaml")})
+	if err != nil {
+		t.Fatalf("ValidateFiles failed: %v", err)
+	}
+	if len(report.Checked) != 2 {
+		t.Errorf("Expected 2 files checked, got %v", report.Checked)
+	}
+	issues := rules(report)
+	for _, rule := range []string{"chart-version", "values-replicas", "values-resources", "values-external"} {
+		if _, ok := issues[rule]; !ok {
+			t.Errorf("Expected a %s issue, got %+v", rule, report.Issues)
+		}
+	}
+	if issues["values-external"].Severity != models.SeverityWarning {
+		t.Error("Expected values-external to be a warning")
+	}
+	if report.Errors() != 3 {
+		t.Errorf("Expected 3 errors, got %d", report.Errors())
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	baseDir := seedSandbox(t)
+	path := filepath.Join(layout.ChartDir(baseDir, "dev1", "bff", "nx-bff-test-service"), "values.yaml")
+	os.WriteFile(path, []byte("replicaCount: 1\nimage:\n  tag: [unclosed\n"), 0644)
+
+	report, err := NewValidator(baseDir).ValidateFiles([]string{path})
+	if err != nil {
+		t.Fatalf("ValidateFiles failed: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Rule != "yaml-syntax" || report.Issues[0].Line == 0 {
+		t.Errorf("Expected a yaml-syntax issue with a line, got %+v", report.Issues)
+	}
+}
+
+func TestValidate_Mesh(t *testing.T) {
+	baseDir := seedSandbox(t)
+	path := layout.MeshValuesFile(baseDir, "sit1")
+	os.WriteFile(path, []byte(`# Kuma Service Mesh Configuration
+externalServices:
+  - name: orders
+    protocol: tcp
+    host: orders.example.com
+    port: 443
+  - name: orders
+    protocol: tcp
+    host: orders.example.com:443
+    port: 0
+`), 0644)
+
+	if Classify(baseDir, path) != KindMesh {
+		t.Fatalf("Expected %s to be classified as mesh values", path)
+	}
+	report, err := NewValidator(baseDir).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	var lines []int
+	for _, issue := range report.Issues {
+		if issue.Rule != "mesh-external-service" || issue.Path != path {
+			t.Errorf("Unexpected issue %+v", issue)
+		}
+		lines = append(lines, issue.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{7, 9, 10}) {
+		t.Errorf("Expected issues on lines 7, 9 and 10, got %+v", report.Issues)
+	}
+}
@@ -0,0 +1,153 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask selects the events that change file content or the tree
+const inotifyMask = unix.IN_CLOSE_WRITE | unix.IN_MODIFY | unix.IN_CREATE | unix.IN_DELETE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// pollTimeout bounds, in milliseconds, how long a read waits before checking
+// for cancellation
+const pollTimeout = 200
+
+// inotify watches every directory of a tree, adding watches for directories
+// created while watching
+type inotify struct {
+	root    string
+	fd      int
+	watches map[int]string
+}
+
+func newNativeBackend(root string) (backend, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify unavailable: %w", err)
+	}
+	n := &inotify{root: root, fd: fd, watches: make(map[int]string)}
+	if _, err := n.addTree(root); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *inotify) run(ctx context.Context, changed chan<- string) error {
+	defer unix.Close(n.fd)
+
+	buf := make([]byte, 64*1024)
+	fds := []unix.PollFd{{Fd: int32(n.fd), Events: unix.POLLIN}}
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		ready, err := unix.Poll(fds, pollTimeout)
+		if err == unix.EINTR || ready == 0 {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to wait for file events: %w", err)
+		}
+
+		count, err := unix.Read(n.fd, buf)
+		if err == unix.EAGAIN || err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file events: %w", err)
+		}
+
+		for _, path := range n.parse(buf[:count]) {
+			select {
+			case changed <- path:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// Helper methods
+
+// parse decodes a buffer of events into changed paths, watching new directories
+func (n *inotify) parse(buf []byte) []string {
+	var paths []string
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameStart := offset + unix.SizeofInotifyEvent
+		nameEnd := nameStart + int(event.Len)
+		offset = nameEnd
+		if nameEnd > len(buf) {
+			break
+		}
+		name := strings.TrimRight(string(buf[nameStart:nameEnd]), "\x00")
+
+		switch {
+		case event.Mask&unix.IN_Q_OVERFLOW != 0:
+			// Events were dropped; report the root so everything is re-checked
+			paths = append(paths, n.root)
+			continue
+		case event.Mask&unix.IN_IGNORED != 0:
+			delete(n.watches, int(event.Wd))
+			continue
+		}
+
+		dir, ok := n.watches[int(event.Wd)]
+		if !ok || name == "" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+
+		if event.Mask&unix.IN_ISDIR != 0 {
+			if ignored(name) {
+				continue
+			}
+			if event.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+				// Files may have been written before the watch was added
+				files, _ := n.addTree(path)
+				paths = append(paths, files...)
+			} else {
+				// The files of a removed directory go without events of their own
+				paths = append(paths, path)
+			}
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// addTree watches a directory and its subdirectories, returning the files found
+func (n *inotify) addTree(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+		if path != root && ignored(d.Name()) {
+			return filepath.SkipDir
+		}
+		wd, err := unix.InotifyAddWatch(n.fd, path, inotifyMask)
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		n.watches[wd] = path
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package watch
+
+import "fmt"
+
+// newNativeBackend reports that native notifications are unavailable, so
+// the watcher polls
+func newNativeBackend(root string) (backend, error) {
+	return nil, fmt.Errorf("native file notifications are only supported on Linux")
+}
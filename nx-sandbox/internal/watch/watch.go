@@ -0,0 +1,237 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the polling backend rescans the tree
+	DefaultInterval = time.Second
+
+	// DefaultDebounce is how long the tree must be quiet before a batch is delivered
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// Mode names the backend a watcher uses
+const (
+	ModeNative  = "native"
+	ModePolling = "polling"
+)
+
+// Options configures a watcher
+type Options struct {
+	// Interval is the polling interval
+	Interval time.Duration
+	// Debounce collects bursts of changes, such as an editor's save, into one batch
+	Debounce time.Duration
+	// Poll forces the polling backend even when native notifications work
+	Poll bool
+}
+
+// Watcher defines the interface for watching a directory tree for changes
+type Watcher interface {
+	// Mode returns the backend in use
+	Mode() string
+	// Watch calls handle with each debounced batch of changed paths, sorted,
+	// until the context is cancelled
+	Watch(ctx context.Context, handle func(changes []string)) error
+}
+
+// DefaultWatcher watches a directory tree with native file notifications,
+// falling back to polling where they are unavailable. Version control
+// directories are ignored.
+type DefaultWatcher struct {
+	root     string
+	mode     string
+	debounce time.Duration
+	backend  backend
+}
+
+// backend reports changed paths on a channel until the context is cancelled
+type backend interface {
+	run(ctx context.Context, changed chan<- string) error
+}
+
+// NewWatcher creates a watcher for a directory tree. The native backend is
+// used unless options.Poll is set or it cannot be started.
+func NewWatcher(root string, options Options) (Watcher, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("failed to watch %s: not a directory", root)
+	}
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+	if options.Debounce <= 0 {
+		options.Debounce = DefaultDebounce
+	}
+
+	w := &DefaultWatcher{
+		root:     root,
+		debounce: options.Debounce,
+	}
+	if !options.Poll {
+		if native, err := newNativeBackend(root); err == nil {
+			w.mode, w.backend = ModeNative, native
+			return w, nil
+		}
+	}
+	w.mode, w.backend = ModePolling, &poller{root: root, interval: options.Interval}
+	return w, nil
+}
+
+// Mode returns the backend in use
+func (w *DefaultWatcher) Mode() string {
+	return w.mode
+}
+
+// Watch delivers debounced batches of changed paths until the context is cancelled
+func (w *DefaultWatcher) Watch(ctx context.Context, handle func(changes []string)) error {
+	return debounce(ctx, w.backend, w.debounce, handle)
+}
+
+// Helper methods
+
+// debounce runs a backend and delivers its changes once no new change has
+// arrived for the debounce period
+func debounce(ctx context.Context, b backend, period time.Duration, handle func(changes []string)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	changed := make(chan string, 256)
+	errs := make(chan error, 1)
+	go func() { errs <- b.run(ctx, changed) }()
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(period)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case path := <-changed:
+			pending[path] = true
+			timer.Reset(period)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			batch := make([]string, 0, len(pending))
+			for path := range pending {
+				batch = append(batch, path)
+			}
+			sort.Strings(batch)
+			pending = make(map[string]bool)
+			handle(batch)
+		}
+	}
+}
+
+// ignored reports whether a directory is skipped while watching
+func ignored(name string) bool {
+	return name == ".git"
+}
+
+// poller detects changes by rescanning the tree and comparing sizes and
+// modification times
+type poller struct {
+	root     string
+	interval time.Duration
+}
+
+// fileState is what the poller compares between scans
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+func (p *poller) run(ctx context.Context, changed chan<- string) error {
+	previous, err := scan(p.root)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := scan(p.root)
+		if err != nil {
+			return err
+		}
+		for _, path := range diff(previous, current) {
+			select {
+			case changed <- path:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		previous = current
+	}
+}
+
+// scan records the state of every file under root
+func scan(root string) (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Files removed mid-scan are picked up by the next one
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != root && ignored(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// diff returns the files created, modified or removed between two scans
+func diff(previous, current map[string]fileState) []string {
+	var changed []string
+	for path, state := range current {
+		if old, ok := previous[path]; !ok || old.size != state.size || !old.modTime.Equal(state.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
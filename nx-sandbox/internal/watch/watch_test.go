@@ -0,0 +1,121 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeBackend sends a fixed sequence of changes with pauses between bursts
+type fakeBackend struct {
+	bursts [][]string
+	pause  time.Duration
+}
+
+func (f *fakeBackend) run(ctx context.Context, changed chan<- string) error {
+	for _, burst := range f.bursts {
+		for _, path := range burst {
+			changed <- path
+		}
+		time.Sleep(f.pause)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func collect(t *testing.T, w Watcher, ctx context.Context, count int) [][]string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var batches [][]string
+	err := w.Watch(ctx, func(changes []string) {
+		batches = append(batches, changes)
+		if len(batches) == count {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	return batches
+}
+
+func TestDebounce_CollectsBursts(t *testing.T) {
+	w := &DefaultWatcher{debounce: 50 * time.Millisecond, backend: &fakeBackend{
+		bursts: [][]string{{"b", "a", "b"}, {"c"}},
+		pause:  200 * time.Millisecond,
+	}}
+
+	batches := collect(t, w, context.Background(), 2)
+	expected := [][]string{{"a", "b"}, {"c"}}
+	if !reflect.DeepEqual(batches, expected) {
+		t.Errorf("Expected %v, got %v", expected, batches)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"kept":     {size: 1, modTime: now},
+		"modified": {size: 1, modTime: now},
+		"removed":  {size: 1, modTime: now},
+	}
+	current := map[string]fileState{
+		"kept":     {size: 1, modTime: now},
+		"modified": {size: 1, modTime: now.Add(time.Second)},
+		"created":  {size: 1, modTime: now},
+	}
+
+	expected := []string{"created", "modified", "removed"}
+	if got := diff(previous, current); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestScan_IgnoresGit(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	os.MkdirAll(filepath.Join(root, "repo"), 0755)
+	os.WriteFile(filepath.Join(root, ".git", "index"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(root, "repo", "values.yaml"), []byte("x"), 0644)
+
+	files, err := scan(root)
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("Expected only repo/values.yaml, got %v", files)
+	}
+}
+
+func TestWatch_Backends(t *testing.T) {
+	for _, poll := range []bool{true, false} {
+		root := t.TempDir()
+		w, err := NewWatcher(root, Options{Poll: poll, Interval: 50 * time.Millisecond, Debounce: 100 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("NewWatcher failed: %v", err)
+		}
+
+		path := filepath.Join(root, "env", "bff", "values.yaml")
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte("replicaCount: 1\n"), 0644)
+		}()
+
+		batches := collect(t, w, context.Background(), 1)
+		if len(batches) != 1 || !reflect.DeepEqual(batches[0], []string{path}) {
+			t.Errorf("%s: expected %s to be reported, got %v", w.Mode(), path, batches)
+		}
+	}
+}
+
+func TestNewWatcher_MissingRoot(t *testing.T) {
+	if _, err := NewWatcher(filepath.Join(t.TempDir(), "missing"), Options{}); err == nil {
+		t.Error("Expected missing root to fail")
+	}
+}
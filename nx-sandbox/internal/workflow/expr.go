@@ -0,0 +1,501 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// expressionPattern matches ${{ ... }} in workflow text
+var expressionPattern = regexp.MustCompile(`\$\{\{(.*?)\}\}`)
+
+// statusFunctionPattern detects conditions that decide for themselves whether
+// to run after a failure
+var statusFunctionPattern = regexp.MustCompile(`\b(success|failure|always|cancelled)\s*\(`)
+
+// Scope is what an expression can see: the contexts (github, inputs, needs,
+// steps, env, ...) and whether an earlier job or step failed
+type Scope struct {
+	Contexts map[string]interface{}
+	Failed   bool
+}
+
+// Interpolate replaces every ${{ expression }} in text with its value
+func Interpolate(text string, scope *Scope) (string, error) {
+	var firstErr error
+	result := expressionPattern.ReplaceAllStringFunc(text, func(match string) string {
+		expr := expressionPattern.FindStringSubmatch(match)[1]
+		value, err := Evaluate(expr, scope)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return match
+		}
+		return ToString(value)
+	})
+	return result, firstErr
+}
+
+// Condition evaluates an if: condition. Conditions without a status function
+// only hold when nothing has failed, as on GitHub.
+func Condition(condition string, scope *Scope) (bool, error) {
+	expr := strings.TrimSpace(condition)
+	if expr == "" {
+		return !scope.Failed, nil
+	}
+	if strings.HasPrefix(expr, "${{") && strings.HasSuffix(expr, "}}") {
+		expr = strings.TrimSpace(expr[3 : len(expr)-2])
+	}
+
+	value, err := Evaluate(expr, scope)
+	if err != nil {
+		return false, err
+	}
+	if !statusFunctionPattern.MatchString(expr) && scope.Failed {
+		return false, nil
+	}
+	return truthy(value), nil
+}
+
+// Evaluate evaluates a single expression (without the ${{ }} delimiters)
+func Evaluate(expr string, scope *Scope) (interface{}, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", strings.TrimSpace(expr), err)
+	}
+
+	p := &parser{tokens: tokens, scope: scope}
+	value, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", strings.TrimSpace(expr), err)
+	}
+	return value, nil
+}
+
+// References returns the context paths an expression refers to, such as
+// needs.build.outputs.version, in order of appearance
+func References(expr string) []string {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil
+	}
+	var refs []string
+	for i, tok := range tokens {
+		isCall := i+1 < len(tokens) && tokens[i+1].text == "("
+		if tok.kind == tokenIdent && !isCall && !isKeyword(tok.text) {
+			refs = append(refs, tok.text)
+		}
+	}
+	return refs
+}
+
+// Expressions returns the contents of every ${{ }} in text
+func Expressions(text string) []string {
+	var exprs []string
+	for _, match := range expressionPattern.FindAllStringSubmatch(text, -1) {
+		exprs = append(exprs, strings.TrimSpace(match[1]))
+	}
+	return exprs
+}
+
+// ToString converts an expression value to text the way GitHub does
+func ToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// Helper methods
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '\'':
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(expr) {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				if expr[i] == '\'' {
+					if i+1 < len(expr) && expr[i+1] == '\'' {
+						b.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, token{tokenString, b.String()})
+
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			start := i
+			i++
+			for i < len(expr) && (expr[i] >= '0' && expr[i] <= '9' || expr[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, expr[start:i]})
+
+		case isIdentStart(c):
+			start := i
+			for i < len(expr) && (isIdentStart(expr[i]) || expr[i] >= '0' && expr[i] <= '9' || expr[i] == '-' || expr[i] == '.' || expr[i] == '*') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, expr[start:i]})
+
+		default:
+			two := ""
+			if i+1 < len(expr) {
+				two = expr[i : i+2]
+			}
+			switch two {
+			case "==", "!=", "<=", ">=", "&&", "||":
+				tokens = append(tokens, token{tokenOperator, two})
+				i += 2
+				continue
+			}
+			if !strings.ContainsRune("!<>(),", rune(c)) {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, token{tokenOperator, string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isKeyword(text string) bool {
+	switch text {
+	case "true", "false", "null":
+		return true
+	}
+	return false
+}
+
+// parser is a recursive descent parser that evaluates as it parses
+type parser struct {
+	tokens []token
+	pos    int
+	scope  *Scope
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+func (p *parser) or() (interface{}, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if !truthy(left) {
+			left = right
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (interface{}, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		if truthy(left) {
+			left = right
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) comparison() (interface{}, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		switch op {
+		case "==", "!=", "<", ">", "<=", ">=":
+		default:
+			return left, nil
+		}
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = compare(op, left, right)
+	}
+}
+
+func (p *parser) unary() (interface{}, error) {
+	if p.peek() == "!" {
+		p.pos++
+		value, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(value), nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (interface{}, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case tokenString:
+		return tok.text, nil
+	case tokenNumber:
+		return strconv.ParseFloat(tok.text, 64)
+	case tokenOperator:
+		if tok.text != "(" {
+			return nil, fmt.Errorf("unexpected %q", tok.text)
+		}
+		value, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return value, nil
+	}
+
+	switch tok.text {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	if p.peek() == "(" {
+		p.pos++
+		var args []interface{}
+		for p.peek() != ")" {
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek() == "," {
+				p.pos++
+			} else if p.peek() != ")" {
+				return nil, fmt.Errorf("missing ) after arguments to %s", tok.text)
+			}
+		}
+		p.pos++
+		return p.call(tok.text, args)
+	}
+
+	return p.lookup(tok.text), nil
+}
+
+func (p *parser) call(name string, args []interface{}) (interface{}, error) {
+	arg := func(i int) string {
+		if i < len(args) {
+			return strings.ToLower(ToString(args[i]))
+		}
+		return ""
+	}
+
+	switch strings.ToLower(name) {
+	case "success":
+		return !p.scope.Failed, nil
+	case "failure":
+		return p.scope.Failed, nil
+	case "always":
+		return true, nil
+	case "cancelled":
+		return false, nil
+	case "contains":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("contains() needs two arguments")
+		}
+		if list, ok := args[0].([]interface{}); ok {
+			for _, item := range list {
+				if compare("==", item, args[1]) == true {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(arg(0), arg(1)), nil
+	case "startswith":
+		return strings.HasPrefix(arg(0), arg(1)), nil
+	case "endswith":
+		return strings.HasSuffix(arg(0), arg(1)), nil
+	case "tojson":
+		if len(args) == 0 {
+			return "null", nil
+		}
+		data, err := json.MarshalIndent(args[0], "", "  ")
+		return string(data), err
+	}
+	return nil, fmt.Errorf("unknown function %s()", name)
+}
+
+// lookup resolves a dotted context path, yielding nil for anything missing
+func (p *parser) lookup(path string) interface{} {
+	var value interface{} = p.scope.Contexts
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case map[string]string:
+			s, ok := v[key]
+			if !ok {
+				return nil
+			}
+			value = s
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+// compare applies GitHub's loose comparison: strings compare case-insensitively,
+// and values of different types are compared as numbers
+func compare(op string, left, right interface{}) bool {
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	if lok && rok {
+		l, r := strings.ToLower(ls), strings.ToLower(rs)
+		switch op {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case ">":
+			return l > r
+		case "<=":
+			return l <= r
+		case ">=":
+			return l >= r
+		}
+	}
+
+	l, r := toNumber(left), toNumber(right)
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case ">":
+		return l > r
+	case "<=":
+		return l <= r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+func toNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case float64:
+		return v
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return 0
+		}
+		if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return n
+		}
+	}
+	return math.NaN()
+}
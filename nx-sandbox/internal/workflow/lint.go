@@ -0,0 +1,358 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/config"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Lint rules
+const (
+	RuleParse         = "workflow-parse"
+	RuleExpression    = "workflow-expression"
+	RuleNeeds         = "workflow-needs"
+	RuleNeedsOutput   = "workflow-needs-output"
+	RuleStepOutput    = "workflow-step-output"
+	RuleInput         = "workflow-input"
+	RuleClientPayload = "workflow-client-payload"
+	RuleEnvironments  = "workflow-environments"
+)
+
+// yamlLinePattern extracts the line from yaml.v3 error messages
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// Linter defines the interface for statically checking the workflows
+type Linter interface {
+	Lint() (*models.ValidationReport, error)
+	LintFiles(paths []string) (*models.ValidationReport, error)
+}
+
+// DefaultLinter checks the github-simulator workflows without running them:
+// needs.*.outputs and steps.*.outputs must be declared, inputs and
+// client_payload fields must match the workflow_dispatch inputs, and
+// environment choices must list the environments in .nx-sandbox/config.yaml
+type DefaultLinter struct {
+	baseDir string
+}
+
+// NewLinter creates a new workflow linter for a sandbox root
+func NewLinter(baseDir string) Linter {
+	return &DefaultLinter{
+		baseDir: baseDir,
+	}
+}
+
+// Lint checks every workflow under github-simulator/workflows
+func (l *DefaultLinter) Lint() (*models.ValidationReport, error) {
+	paths, err := Files(l.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return l.LintFiles(paths)
+}
+
+// LintFiles checks the given workflow files
+func (l *DefaultLinter) LintFiles(paths []string) (*models.ValidationReport, error) {
+	cfg, err := config.Load(l.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ValidationReport{
+		Checked: []string{},
+		Issues:  []models.ValidationIssue{},
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		report.Checked = append(report.Checked, path)
+		report.Issues = append(report.Issues, LintWorkflow(path, data, cfg.Environments)...)
+	}
+	return report, nil
+}
+
+// LintWorkflow checks a single workflow file against the configured environments
+func LintWorkflow(path string, data []byte, environments []string) []models.ValidationIssue {
+	lint := &linter{path: path, seen: make(map[string]bool)}
+
+	wf, err := Parse(data)
+	if err != nil {
+		line := 0
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		lint.report(line, RuleParse, models.SeverityError, err.Error())
+		return lint.issues
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return lint.issues
+	}
+	lint.wf = wf
+	root := doc.Content[0]
+
+	lint.checkNeeds()
+	lint.checkChoices(root, environments)
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "jobs" {
+			lint.expressions(root.Content[i+1], root.Content[i].Value, nil, -1)
+			continue
+		}
+		jobs := root.Content[i+1]
+		for j := 0; j+1 < len(jobs.Content); j += 2 {
+			job := wf.Job(jobs.Content[j].Value)
+			body := jobs.Content[j+1]
+			for k := 0; k+1 < len(body.Content); k += 2 {
+				key, value := body.Content[k].Value, body.Content[k+1]
+				switch key {
+				case "steps":
+					for index, step := range value.Content {
+						lint.expressions(step, "", job, index)
+					}
+				case "outputs":
+					// Job outputs are evaluated after every step has run
+					lint.expressions(value, key, job, len(job.Steps))
+				default:
+					lint.expressions(value, key, job, 0)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(lint.issues, func(i, j int) bool { return lint.issues[i].Line < lint.issues[j].Line })
+	return lint.issues
+}
+
+// Helper methods
+
+// linter collects the issues of one workflow file
+type linter struct {
+	path   string
+	wf     *Workflow
+	issues []models.ValidationIssue
+	// seen reports each problem once, at its first use
+	seen map[string]bool
+}
+
+func (l *linter) report(line int, rule string, severity models.Severity, message string) {
+	if l.seen[rule+message] {
+		return
+	}
+	l.seen[rule+message] = true
+	l.issues = append(l.issues, models.ValidationIssue{
+		Path:     l.path,
+		Line:     line,
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+	})
+}
+
+// checkNeeds reports jobs that need jobs that do not exist
+func (l *linter) checkNeeds() {
+	for _, job := range l.wf.Jobs {
+		for _, need := range job.Needs {
+			if l.wf.Job(need) == nil {
+				l.report(job.Line, RuleNeeds, models.SeverityError, fmt.Sprintf("job '%s' needs unknown job '%s'", job.ID, need))
+			}
+		}
+	}
+}
+
+// checkChoices compares environment choice inputs with the configured
+// environments and checks that choice defaults are among the options
+func (l *linter) checkChoices(root *yaml.Node, environments []string) {
+	inputs := child(child(child(root, "on"), EventWorkflowDispatch), "inputs")
+	for _, input := range l.wf.Inputs() {
+		if input.Type != "choice" {
+			continue
+		}
+		declaration := child(inputs, input.Name)
+		options := child(declaration, "options")
+		optionLine := func(option string) int {
+			if options != nil {
+				for _, node := range options.Content {
+					if node.Value == option {
+						return node.Line
+					}
+				}
+			}
+			return input.Line
+		}
+
+		if input.Default != "" && !contains(input.Options, input.Default) {
+			line := input.Line
+			if node := child(declaration, "default"); node != nil {
+				line = node.Line
+			}
+			l.report(line, RuleInput, models.SeverityError,
+				fmt.Sprintf("default '%s' of input '%s' is not one of its options", input.Default, input.Name))
+		}
+
+		isEnvironment := strings.Contains(strings.ToLower(input.Name), "env")
+		for _, option := range input.Options {
+			isEnvironment = isEnvironment || contains(environments, option)
+		}
+		if !isEnvironment {
+			continue
+		}
+		for _, option := range input.Options {
+			if !contains(environments, option) {
+				l.report(optionLine(option), RuleEnvironments, models.SeverityError,
+					fmt.Sprintf("option '%s' of input '%s' is not a configured environment (%s)", option, input.Name, strings.Join(environments, ", ")))
+			}
+		}
+		for _, env := range environments {
+			if !contains(input.Options, env) {
+				l.report(input.Line, RuleEnvironments, models.SeverityWarning,
+					fmt.Sprintf("input '%s' does not offer the configured environment '%s'", input.Name, env))
+			}
+		}
+	}
+}
+
+// expressions checks the ${{ }} expressions under node, and if: conditions,
+// which are expressions without the braces. job is nil outside jobs; steps
+// before stepIndex are visible to them.
+func (l *linter) expressions(node *yaml.Node, key string, job *Job, stepIndex int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			l.expressions(node.Content[i+1], node.Content[i].Value, job, stepIndex)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			l.expressions(item, key, job, stepIndex)
+		}
+	case yaml.ScalarNode:
+		value := node.Value
+		// Block scalars start on the line after their indicator
+		first := node.Line
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			first++
+		}
+		if key == "if" && !strings.Contains(value, "${{") {
+			l.expression(value, first, job, stepIndex)
+			return
+		}
+		for _, match := range expressionPattern.FindAllStringSubmatchIndex(value, -1) {
+			line := first + strings.Count(value[:match[0]], "\n")
+			l.expression(strings.TrimSpace(value[match[2]:match[3]]), line, job, stepIndex)
+		}
+	}
+}
+
+func (l *linter) expression(expr string, line int, job *Job, stepIndex int) {
+	if _, err := Evaluate(expr, &Scope{Contexts: map[string]interface{}{}}); err != nil {
+		l.report(line, RuleExpression, models.SeverityError, err.Error())
+		return
+	}
+
+	for _, ref := range References(expr) {
+		parts := strings.Split(ref, ".")
+		switch {
+		case parts[0] == "needs" && len(parts) > 1:
+			l.checkNeedsReference(parts, line, job)
+		case parts[0] == "steps" && len(parts) > 1 && job != nil:
+			l.checkStepReference(parts, line, job, stepIndex)
+		case parts[0] == "inputs" && len(parts) > 1:
+			l.checkInput(parts[1], "inputs."+parts[1], RuleInput, line)
+		case strings.HasPrefix(ref, "github.event.inputs.") && len(parts) > 3:
+			l.checkInput(parts[3], "github.event.inputs."+parts[3], RuleInput, line)
+		case strings.HasPrefix(ref, "github.event.client_payload.") && len(parts) > 3:
+			l.checkInput(parts[3], "client_payload."+parts[3], RuleClientPayload, line)
+		}
+	}
+}
+
+// checkNeedsReference checks needs.<job>.outputs.<name> against the needs of
+// the job and the outputs the needed job declares
+func (l *linter) checkNeedsReference(parts []string, line int, job *Job) {
+	if job == nil {
+		l.report(line, RuleNeeds, models.SeverityError, fmt.Sprintf("%s is only available in jobs", strings.Join(parts, ".")))
+		return
+	}
+	needed := l.wf.Job(parts[1])
+	switch {
+	case parts[1] == "*":
+		return
+	case needed == nil:
+		l.report(line, RuleNeeds, models.SeverityError, fmt.Sprintf("job '%s' refers to unknown job '%s'", job.ID, parts[1]))
+		return
+	case !contains(job.Needs, parts[1]):
+		l.report(line, RuleNeeds, models.SeverityError, fmt.Sprintf("job '%s' refers to needs.%s but does not list it in needs", job.ID, parts[1]))
+	}
+	if len(parts) > 3 && parts[2] == "outputs" {
+		if _, ok := needed.Outputs[parts[3]]; !ok {
+			l.report(line, RuleNeedsOutput, models.SeverityError, fmt.Sprintf("job '%s' does not declare output '%s'", needed.ID, parts[3]))
+		}
+	}
+}
+
+// checkStepReference checks steps.<id>.outputs.<name> against the earlier
+// steps of the job and what their scripts write to $GITHUB_OUTPUT
+func (l *linter) checkStepReference(parts []string, line int, job *Job, stepIndex int) {
+	index := -1
+	for i, step := range job.Steps {
+		if step.ID == parts[1] {
+			index = i
+		}
+	}
+	switch {
+	case index < 0:
+		l.report(line, RuleStepOutput, models.SeverityError, fmt.Sprintf("job '%s' has no step with id '%s'", job.ID, parts[1]))
+		return
+	case index >= stepIndex:
+		l.report(line, RuleStepOutput, models.SeverityError, fmt.Sprintf("step '%s' has not run yet where it is referenced", parts[1]))
+		return
+	}
+
+	step := job.Steps[index]
+	if len(parts) > 3 && parts[2] == "outputs" && step.Run != "" && !writesOutput(step.Run, parts[3]) {
+		l.report(line, RuleStepOutput, models.SeverityWarning, fmt.Sprintf("step '%s' never writes '%s' to $GITHUB_OUTPUT", step.ID, parts[3]))
+	}
+}
+
+// checkInput checks that a field read from the inputs or the client payload
+// is a workflow_dispatch input. Workflows without workflow_dispatch can only
+// be triggered by a dispatch, so the field is only a warning there.
+func (l *linter) checkInput(name, ref, rule string, line int) {
+	if l.wf.On.WorkflowDispatch == nil {
+		l.report(line, rule, models.SeverityWarning, fmt.Sprintf("%s is used but the workflow has no workflow_dispatch input for it", ref))
+		return
+	}
+	for _, input := range l.wf.Inputs() {
+		if input.Name == name {
+			return
+		}
+	}
+	l.report(line, rule, models.SeverityError, fmt.Sprintf("%s has no matching workflow_dispatch input", ref))
+}
+
+// writesOutput reports whether a script appears to set an output
+func writesOutput(script, name string) bool {
+	return strings.Contains(script, name+"=") || strings.Contains(script, name+"<<")
+}
+
+// child returns the value of key in a mapping node, or nil
+func child(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
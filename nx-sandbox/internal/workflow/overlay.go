@@ -0,0 +1,214 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/diff"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// overlay is a private copy of repos/ that a dry run works in. Steps write to
+// the copy; the real tree is only read, when the overlay is created and when
+// the changes are compared with it.
+//
+// Steps are arbitrary shell commands, so writes cannot be intercepted and the
+// working trees are copied in full. Git object databases are not copied: each
+// copied .git directory borrows the objects of the real repository through
+// objects/info/alternates, and new objects are written to the copy. A dry run
+// therefore costs a copy of the checked-out files plus the small remainder of
+// each .git directory (refs, index, logs, hooks).
+type overlay struct {
+	lower string
+	dir   string
+	root  string
+}
+
+// newOverlay copies reposDir, including the .git directories without their
+// objects so that git commands in the steps commit to the copy
+func newOverlay(reposDir string) (*overlay, error) {
+	root, err := os.MkdirTemp("", "nx-sandbox-dry-run-")
+	if err != nil {
+		return nil, err
+	}
+	o := &overlay{lower: reposDir, dir: filepath.Join(root, filepath.Base(reposDir)), root: root}
+
+	if _, err := os.Stat(reposDir); os.IsNotExist(err) {
+		return o, os.MkdirAll(o.dir, 0755)
+	}
+	if err := copyTree(reposDir, o.dir); err != nil {
+		o.remove()
+		return nil, fmt.Errorf("failed to copy %s: %w", reposDir, err)
+	}
+	return o, nil
+}
+
+// changes compares the overlay with the real tree. Paths are relative to the
+// sandbox root, and .git directories are ignored.
+func (o *overlay) changes(baseDir string) ([]models.WorkflowFileChange, error) {
+	before, err := treeFiles(o.lower)
+	if err != nil {
+		return nil, err
+	}
+	after, err := treeFiles(o.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for path := range before {
+		paths[path] = true
+	}
+	for path := range after {
+		paths[path] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	changes := []models.WorkflowFileChange{}
+	for _, path := range sorted {
+		var a, b []byte
+		if before[path] {
+			if a, err = os.ReadFile(filepath.Join(o.lower, path)); err != nil {
+				return nil, err
+			}
+		}
+		if after[path] {
+			if b, err = os.ReadFile(filepath.Join(o.dir, path)); err != nil {
+				return nil, err
+			}
+		}
+		if before[path] && after[path] && bytes.Equal(a, b) {
+			continue
+		}
+
+		rel, err := filepath.Rel(baseDir, filepath.Join(o.lower, path))
+		if err != nil {
+			rel = filepath.Join(o.lower, path)
+		}
+		change := models.WorkflowFileChange{Path: filepath.ToSlash(rel), Change: models.FileModified}
+		fromName, toName := "a/"+change.Path, "b/"+change.Path
+		switch {
+		case !before[path]:
+			change.Change, fromName = models.FileAdded, "/dev/null"
+		case !after[path]:
+			change.Change, toName = models.FileDeleted, "/dev/null"
+		}
+		if bytes.IndexByte(a, 0) >= 0 || bytes.IndexByte(b, 0) >= 0 {
+			change.Diff = fmt.Sprintf("Binary files %s and %s differ\n", fromName, toName)
+		} else {
+			change.Diff = diff.Unified(fromName, toName, a, b)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// remove deletes the overlay
+func (o *overlay) remove() {
+	os.RemoveAll(o.root)
+}
+
+// treeFiles lists the regular files under dir outside .git directories
+func treeFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && path == dir {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files[rel] = true
+		}
+		return nil
+	})
+	return files, err
+}
+
+// copyTree copies src to dst, keeping file modes and symlinks. Git object
+// databases are shared rather than copied.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir() && d.Name() == "objects" && filepath.Base(filepath.Dir(path)) == ".git":
+			if err := borrowObjects(path, target); err != nil {
+				return err
+			}
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// borrowObjects creates an empty object database at dst that reads the
+// objects of src through an alternates file
+func borrowObjects(src, dst string) error {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	for _, dir := range []string{"info", "pack"} {
+		if err := os.MkdirAll(filepath.Join(dst, dir), 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Join(dst, "info", "alternates"), []byte(abs+"\n"), 0644)
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,736 @@
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/layout"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+// RunsFileName is the workflow run store inside .nx-sandbox
+const RunsFileName = "workflow-runs.json"
+
+// DefaultRepository is the repository the slash commands are issued in, and
+// the working directory of every job until it checks out another one
+const DefaultRepository = "nx-artifacts-inventory"
+
+// StepTimeout bounds how long a single run: step may take
+const StepTimeout = 10 * time.Minute
+
+// shellPreamble simulates the parts of a hosted runner the sandbox does not
+// have: there are no remotes to push to, repos/ may not be Git repositories,
+// and the mock delays in the workflows add nothing locally
+const shellPreamble = `sleep() { :; }
+git() {
+  case "$1" in
+    push|pull|fetch)
+      echo "[nx-sandbox] skipped git $1: the sandbox has no remotes"
+      return 0 ;;
+  esac
+  if ! command git rev-parse --git-dir >/dev/null 2>&1; then
+    echo "[nx-sandbox] skipped git $1: $(pwd) is not a Git repository"
+    return 0
+  fi
+  command git "$@"
+}
+`
+
+// RunOptions describes how a workflow is triggered
+type RunOptions struct {
+	// Event is workflow_dispatch or repository_dispatch; empty picks
+	// workflow_dispatch when the workflow declares it
+	Event  string
+	Inputs map[string]string
+	Actor  string
+	// Output receives step logs as they are produced
+	Output io.Writer
+	// DryRun runs the steps against a copy of repos/ and reports the files
+	// they would have changed instead of recording the run
+	DryRun bool
+}
+
+// Runner defines the interface for running github-simulator workflows
+type Runner interface {
+	Workflows() ([]*Workflow, error)
+	Run(name string, options RunOptions) (*models.WorkflowRun, error)
+	Runs() ([]models.WorkflowRun, error)
+	GetRun(id int) (*models.WorkflowRun, error)
+}
+
+// DefaultRunner executes workflow jobs in order against the repositories under
+// repos/, running run: steps with bash and recording each run in
+// .nx-sandbox/workflow-runs.json. actions/checkout switches the job to the
+// checked-out repository; other actions are not simulated and are skipped.
+type DefaultRunner struct {
+	baseDir string
+	now     func() time.Time
+}
+
+// NewRunner creates a new workflow runner for a sandbox root
+func NewRunner(baseDir string) Runner {
+	return &DefaultRunner{
+		baseDir: baseDir,
+		now:     time.Now,
+	}
+}
+
+// RunsPath returns the location of the workflow run store
+func RunsPath(baseDir string) string {
+	return filepath.Join(layout.StateDir(baseDir), RunsFileName)
+}
+
+// Workflows lists the github-simulator workflows
+func (r *DefaultRunner) Workflows() ([]*Workflow, error) {
+	return List(r.baseDir)
+}
+
+// Run triggers a workflow and records the run. A failing step fails the run
+// but is not an error; errors are reserved for workflows that cannot start.
+// Dry runs leave repos/ and the run store untouched.
+func (r *DefaultRunner) Run(name string, options RunOptions) (*models.WorkflowRun, error) {
+	wf, err := Find(r.baseDir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	event, inputs, err := ResolveInputs(wf, options.Event, options.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := Order(wf)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := r.Runs()
+	if err != nil {
+		return nil, err
+	}
+	run := &models.WorkflowRun{
+		ID:        1,
+		Workflow:  wf.Name,
+		Event:     event,
+		Inputs:    inputs,
+		Actor:     options.Actor,
+		StartedAt: r.now(),
+	}
+	for _, previous := range runs {
+		if previous.ID >= run.ID {
+			run.ID = previous.ID + 1
+		}
+	}
+
+	ex := &execution{
+		baseDir:  r.baseDir,
+		reposDir: layout.ReposDir(r.baseDir),
+		wf:       wf,
+		run:      run,
+		output:   options.Output,
+		needs:    make(map[string]interface{}),
+	}
+	if ex.output == nil {
+		ex.output = io.Discard
+	}
+
+	var ov *overlay
+	if options.DryRun {
+		if ov, err = newOverlay(ex.reposDir); err != nil {
+			return nil, err
+		}
+		defer ov.remove()
+		ex.reposDir = ov.dir
+		run.DryRun = true
+	}
+
+	run.Conclusion = models.ConclusionSuccess
+	for _, job := range jobs {
+		jobRun := ex.runJob(job)
+		run.Jobs = append(run.Jobs, jobRun)
+		if jobRun.Conclusion == models.ConclusionFailure {
+			run.Conclusion = models.ConclusionFailure
+		}
+	}
+	run.FinishedAt = r.now()
+
+	if ov != nil {
+		run.Changes, err = ov.changes(r.baseDir)
+		return run, err
+	}
+	if err := r.save(append(runs, *run)); err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// Runs returns every recorded run, oldest first
+func (r *DefaultRunner) Runs() ([]models.WorkflowRun, error) {
+	data, err := os.ReadFile(RunsPath(r.baseDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow runs: %w", err)
+	}
+
+	var runs []models.WorkflowRun
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun returns a recorded run by ID
+func (r *DefaultRunner) GetRun(id int) (*models.WorkflowRun, error) {
+	runs, err := r.Runs()
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		if runs[i].ID == id {
+			return &runs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("workflow run #%d not found", id)
+}
+
+// ResolveInputs picks the event to trigger and checks the inputs against the
+// workflow_dispatch declaration, applying defaults
+func ResolveInputs(wf *Workflow, event string, inputs map[string]string) (string, map[string]string, error) {
+	events := wf.Events()
+	if len(events) == 0 {
+		return "", nil, fmt.Errorf("workflow '%s' has no workflow_dispatch or repository_dispatch trigger", wf.Name)
+	}
+	if event == "" {
+		event = events[0]
+	}
+	supported := false
+	for _, e := range events {
+		supported = supported || e == event
+	}
+	if !supported {
+		return "", nil, fmt.Errorf("workflow '%s' does not run on %s (expected %s)", wf.Name, event, strings.Join(events, " or "))
+	}
+
+	resolved := make(map[string]string)
+	for key, value := range inputs {
+		resolved[key] = value
+	}
+	if event != EventWorkflowDispatch {
+		return event, resolved, nil
+	}
+
+	declared := make(map[string]bool)
+	for _, input := range wf.Inputs() {
+		declared[input.Name] = true
+		value, ok := resolved[input.Name]
+		if !ok && input.Default != "" {
+			value, ok = input.Default, true
+			resolved[input.Name] = value
+		}
+		if !ok && input.Required {
+			return "", nil, fmt.Errorf("input '%s' is required", input.Name)
+		}
+		if ok && input.Type == "choice" && !contains(input.Options, value) {
+			return "", nil, fmt.Errorf("input '%s' must be one of %s, got '%s'", input.Name, strings.Join(input.Options, ", "), value)
+		}
+		if ok && input.Type == "boolean" && value != "true" && value != "false" {
+			return "", nil, fmt.Errorf("input '%s' must be true or false, got '%s'", input.Name, value)
+		}
+	}
+	for name := range resolved {
+		if !declared[name] {
+			return "", nil, fmt.Errorf("workflow '%s' has no input '%s'", wf.Name, name)
+		}
+	}
+
+	return event, resolved, nil
+}
+
+// Order sorts jobs so each runs after the jobs it needs, keeping file order otherwise
+func Order(wf *Workflow) ([]*Job, error) {
+	for _, job := range wf.Jobs {
+		for _, need := range job.Needs {
+			if wf.Job(need) == nil {
+				return nil, fmt.Errorf("job '%s' needs unknown job '%s'", job.ID, need)
+			}
+		}
+	}
+
+	var ordered []*Job
+	done := make(map[string]bool)
+	for len(ordered) < len(wf.Jobs) {
+		progressed := false
+		for _, job := range wf.Jobs {
+			if done[job.ID] {
+				continue
+			}
+			ready := true
+			for _, need := range job.Needs {
+				ready = ready && done[need]
+			}
+			if ready {
+				ordered = append(ordered, job)
+				done[job.ID] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("jobs of workflow '%s' have circular needs", wf.Name)
+		}
+	}
+	return ordered, nil
+}
+
+// Helper methods
+
+func (r *DefaultRunner) save(runs []models.WorkflowRun) error {
+	path := RunsPath(r.baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// execution holds the state shared by the jobs of one run
+type execution struct {
+	baseDir string
+	// reposDir is repos/, or its copy in a dry run
+	reposDir string
+	wf       *Workflow
+	run      *models.WorkflowRun
+	output   io.Writer
+	needs    map[string]interface{}
+}
+
+// jobState holds the state shared by the steps of one job
+type jobState struct {
+	id      string
+	dir     string
+	env     map[string]string
+	steps   map[string]interface{}
+	failed  bool
+	tempDir string
+}
+
+func (e *execution) runJob(job *Job) models.JobRun {
+	jobRun := models.JobRun{ID: job.ID, Steps: []models.StepRun{}}
+	fmt.Fprintf(e.output, "▶ Job %s\n", job.ID)
+
+	needsFailed := false
+	for _, need := range job.Needs {
+		if result, _ := e.needs[need].(map[string]interface{}); result["result"] != string(models.ConclusionSuccess) {
+			needsFailed = true
+		}
+	}
+
+	state := &jobState{
+		id:    job.ID,
+		dir:   filepath.Join(e.reposDir, DefaultRepository),
+		env:   make(map[string]string),
+		steps: make(map[string]interface{}),
+	}
+	for key, value := range e.wf.Env {
+		state.env[key] = value
+	}
+	for key, value := range job.Env {
+		state.env[key] = value
+	}
+
+	scope := e.scope(state, job.Needs)
+	scope.Failed = needsFailed
+	run, err := Condition(job.If, scope)
+	if err != nil {
+		jobRun.Conclusion = models.ConclusionFailure
+		jobRun.Steps = append(jobRun.Steps, models.StepRun{
+			Name:       "Evaluate if",
+			Conclusion: models.ConclusionFailure,
+			Message:    err.Error(),
+		})
+		e.finishJob(job, jobRun)
+		return jobRun
+	}
+	if !run {
+		fmt.Fprintf(e.output, "  ⏭ skipped\n")
+		jobRun.Conclusion = models.ConclusionSkipped
+		e.finishJob(job, jobRun)
+		return jobRun
+	}
+
+	tempDir, err := os.MkdirTemp("", "nx-sandbox-job-")
+	if err != nil {
+		fmt.Fprintf(e.output, "  ✗ %v\n", err)
+		jobRun.Conclusion = models.ConclusionFailure
+		e.finishJob(job, jobRun)
+		return jobRun
+	}
+	defer os.RemoveAll(tempDir)
+	state.tempDir = tempDir
+
+	for i, step := range job.Steps {
+		stepRun := e.runStep(job, step, i, state)
+		jobRun.Steps = append(jobRun.Steps, stepRun)
+	}
+
+	jobRun.Conclusion = models.ConclusionSuccess
+	if state.failed {
+		jobRun.Conclusion = models.ConclusionFailure
+	}
+
+	outputs := make(map[string]string)
+	scope = e.scope(state, job.Needs)
+	for _, key := range sortedKeys(job.Outputs) {
+		value, err := Interpolate(job.Outputs[key], scope)
+		if err != nil {
+			fmt.Fprintf(e.output, "  ✗ output %s: %v\n", key, err)
+		}
+		outputs[key] = value
+	}
+	if len(outputs) > 0 {
+		jobRun.Outputs = outputs
+	}
+
+	e.finishJob(job, jobRun)
+	return jobRun
+}
+
+// finishJob makes a job's result and outputs visible to the jobs that need it
+func (e *execution) finishJob(job *Job, jobRun models.JobRun) {
+	outputs := make(map[string]interface{})
+	for key, value := range jobRun.Outputs {
+		outputs[key] = value
+	}
+	e.needs[job.ID] = map[string]interface{}{
+		"result":  string(jobRun.Conclusion),
+		"outputs": outputs,
+	}
+}
+
+func (e *execution) runStep(job *Job, step *Step, index int, state *jobState) models.StepRun {
+	stepRun := models.StepRun{Name: step.DisplayName(), ID: step.ID, Uses: step.Uses}
+
+	scope := e.scope(state, job.Needs)
+	scope.Failed = state.failed
+	run, err := Condition(step.If, scope)
+	if err != nil {
+		return e.failStep(stepRun, step, state, err.Error())
+	}
+	if !run {
+		stepRun.Conclusion = models.ConclusionSkipped
+		e.recordStep(state, step, stepRun)
+		return stepRun
+	}
+
+	fmt.Fprintf(e.output, "  • %s\n", stepRun.Name)
+
+	switch {
+	case step.Uses != "":
+		return e.runAction(stepRun, step, scope, state)
+	case step.Run != "":
+		return e.runScript(stepRun, step, index, scope, state)
+	}
+	return e.failStep(stepRun, step, state, "step has neither run nor uses")
+}
+
+// runAction simulates the actions the sandbox understands and skips the rest
+func (e *execution) runAction(stepRun models.StepRun, step *Step, scope *Scope, state *jobState) models.StepRun {
+	action := strings.SplitN(step.Uses, "@", 2)[0]
+	if action != "actions/checkout" {
+		stepRun.Conclusion = models.ConclusionSkipped
+		stepRun.Message = fmt.Sprintf("action %s is not simulated", action)
+		fmt.Fprintf(e.output, "    ⏭ %s\n", stepRun.Message)
+		e.recordStep(state, step, stepRun)
+		return stepRun
+	}
+
+	repository, err := Interpolate(step.With["repository"], scope)
+	if err != nil {
+		return e.failStep(stepRun, step, state, err.Error())
+	}
+	name := DefaultRepository
+	if repository != "" {
+		parts := strings.Split(repository, "/")
+		name = parts[len(parts)-1]
+	}
+
+	dir := filepath.Join(e.reposDir, name)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return e.failStep(stepRun, step, state, fmt.Sprintf("repository %s not found under repos/", name))
+	}
+	state.dir = dir
+	stepRun.Conclusion = models.ConclusionSuccess
+	stepRun.Message = fmt.Sprintf("using repos/%s", name)
+	fmt.Fprintf(e.output, "    %s\n", stepRun.Message)
+	e.recordStep(state, step, stepRun)
+	return stepRun
+}
+
+// runScript runs a run: step with bash, collecting $GITHUB_OUTPUT and $GITHUB_ENV
+func (e *execution) runScript(stepRun models.StepRun, step *Step, index int, scope *Scope, state *jobState) models.StepRun {
+	script, err := Interpolate(step.Run, scope)
+	if err != nil {
+		return e.failStep(stepRun, step, state, err.Error())
+	}
+
+	prefix := filepath.Join(state.tempDir, fmt.Sprintf("step-%d", index))
+	scriptPath := prefix + ".sh"
+	outputPath := prefix + ".output"
+	envPath := prefix + ".env"
+	if err := os.WriteFile(scriptPath, []byte(shellPreamble+script), 0644); err != nil {
+		return e.failStep(stepRun, step, state, err.Error())
+	}
+	for _, path := range []string{outputPath, envPath} {
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			return e.failStep(stepRun, step, state, err.Error())
+		}
+	}
+
+	dir := state.dir
+	if step.WorkingDirectory != "" {
+		dir = filepath.Join(dir, step.WorkingDirectory)
+	}
+
+	env := os.Environ()
+	env = append(env,
+		"CI=true",
+		"GITHUB_ACTIONS=true",
+		"GITHUB_ACTOR="+e.run.Actor,
+		"GITHUB_EVENT_NAME="+e.run.Event,
+		"GITHUB_WORKFLOW="+e.wf.Title,
+		"GITHUB_RUN_ID="+strconv.Itoa(e.run.ID),
+		"GITHUB_JOB="+state.id,
+		"GITHUB_WORKSPACE="+state.dir,
+		"GITHUB_OUTPUT="+outputPath,
+		"GITHUB_ENV="+envPath,
+		"RUNNER_TEMP="+state.tempDir,
+	)
+	for _, key := range sortedKeys(state.env) {
+		env = append(env, key+"="+state.env[key])
+	}
+	for _, key := range sortedKeys(step.Env) {
+		value, err := Interpolate(step.Env[key], scope)
+		if err != nil {
+			return e.failStep(stepRun, step, state, err.Error())
+		}
+		env = append(env, key+"="+value)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StepTimeout)
+	defer cancel()
+
+	var log bytes.Buffer
+	cmd := exec.CommandContext(ctx, "bash", "--noprofile", "--norc", "-eo", "pipefail", scriptPath)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = io.MultiWriter(&log, indent(e.output))
+	cmd.Stderr = cmd.Stdout
+	runErr := cmd.Run()
+	stepRun.Log = log.String()
+
+	outputs, err := readKeyValues(outputPath)
+	if err != nil {
+		return e.failStep(stepRun, step, state, err.Error())
+	}
+	if len(outputs) > 0 {
+		stepRun.Outputs = outputs
+	}
+	exported, err := readKeyValues(envPath)
+	if err != nil {
+		return e.failStep(stepRun, step, state, err.Error())
+	}
+	for key, value := range exported {
+		state.env[key] = value
+	}
+
+	if runErr != nil {
+		return e.failStep(stepRun, step, state, runErr.Error())
+	}
+	stepRun.Conclusion = models.ConclusionSuccess
+	e.recordStep(state, step, stepRun)
+	return stepRun
+}
+
+func (e *execution) failStep(stepRun models.StepRun, step *Step, state *jobState, message string) models.StepRun {
+	stepRun.Conclusion = models.ConclusionFailure
+	stepRun.Message = message
+	fmt.Fprintf(e.output, "    ✗ %s\n", message)
+	if !step.ContinueOnError {
+		state.failed = true
+	}
+	e.recordStep(state, step, stepRun)
+	return stepRun
+}
+
+// recordStep makes a step's outcome and outputs visible to later steps
+func (e *execution) recordStep(state *jobState, step *Step, stepRun models.StepRun) {
+	if step.ID == "" {
+		return
+	}
+	outputs := make(map[string]interface{})
+	for key, value := range stepRun.Outputs {
+		outputs[key] = value
+	}
+	state.steps[step.ID] = map[string]interface{}{
+		"outputs":    outputs,
+		"outcome":    string(stepRun.Conclusion),
+		"conclusion": string(stepRun.Conclusion),
+	}
+}
+
+// scope builds the expression contexts of a job. The dispatch inputs are
+// delivered as the client payload for both events, as the slash command bot
+// forwards them, so workflows reading client_payload can also be run manually.
+func (e *execution) scope(state *jobState, needs []string) *Scope {
+	inputs := make(map[string]interface{})
+	for key, value := range e.run.Inputs {
+		inputs[key] = value
+	}
+
+	event := map[string]interface{}{
+		"client_payload": inputs,
+	}
+	if e.run.Event == EventWorkflowDispatch {
+		event["inputs"] = inputs
+	} else if dispatch := e.wf.On.RepositoryDispatch; dispatch != nil && len(dispatch.Types) > 0 {
+		event["action"] = dispatch.Types[0]
+	}
+
+	visibleNeeds := make(map[string]interface{})
+	for _, need := range needs {
+		if result, ok := e.needs[need]; ok {
+			visibleNeeds[need] = result
+		}
+	}
+
+	env := make(map[string]interface{})
+	for key, value := range state.env {
+		env[key] = value
+	}
+
+	return &Scope{
+		Contexts: map[string]interface{}{
+			"github": map[string]interface{}{
+				"actor":      e.run.Actor,
+				"event_name": e.run.Event,
+				"event":      event,
+				"workflow":   e.wf.Title,
+				"run_id":     strconv.Itoa(e.run.ID),
+				"repository": "britishairways-nexus/" + DefaultRepository,
+				"workspace":  state.dir,
+			},
+			"inputs":  inputs,
+			"needs":   visibleNeeds,
+			"steps":   state.steps,
+			"env":     env,
+			"secrets": map[string]interface{}{},
+			"runner": map[string]interface{}{
+				"os":   "Linux",
+				"temp": state.tempDir,
+			},
+		},
+	}
+}
+
+// readKeyValues parses a $GITHUB_OUTPUT or $GITHUB_ENV file, which holds
+// name=value lines and name<<DELIMITER multi-line values
+func readKeyValues(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if name, delimiter, ok := strings.Cut(line, "<<"); ok && !strings.Contains(name, "=") {
+			var lines []string
+			closed := false
+			for scanner.Scan() {
+				if scanner.Text() == delimiter {
+					closed = true
+					break
+				}
+				lines = append(lines, scanner.Text())
+			}
+			if !closed {
+				return nil, fmt.Errorf("%s: missing delimiter %s for %s", filepath.Base(path), delimiter, name)
+			}
+			values[name] = strings.Join(lines, "\n")
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid line %q", filepath.Base(path), line)
+		}
+		values[name] = value
+	}
+	return values, scanner.Err()
+}
+
+// indent prefixes every line written to w, for nesting step logs under their step
+func indent(w io.Writer) io.Writer {
+	if w == io.Discard {
+		return w
+	}
+	return &indentWriter{w: w, lineStart: true}
+}
+
+type indentWriter struct {
+	w         io.Writer
+	lineStart bool
+}
+
+func (iw *indentWriter) Write(p []byte) (int, error) {
+	var b bytes.Buffer
+	for _, c := range p {
+		if iw.lineStart {
+			b.WriteString("    ")
+		}
+		b.WriteByte(c)
+		iw.lineStart = c == '\n'
+	}
+	if _, err := iw.w.Write(b.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EventWorkflowDispatch is a manual run with declared inputs
+	EventWorkflowDispatch = "workflow_dispatch"
+
+	// EventRepositoryDispatch is a run triggered by a slash command with a client payload
+	EventRepositoryDispatch = "repository_dispatch"
+)
+
+// Workflow is a parsed GitHub Actions workflow file
+type Workflow struct {
+	// Name is the workflow's directory name, used to refer to it on the command line
+	Name  string   `yaml:"-"`
+	Path  string   `yaml:"-"`
+	Title string   `yaml:"name"`
+	On    Triggers `yaml:"on"`
+	Env   Env      `yaml:"env"`
+	Jobs  Jobs     `yaml:"jobs"`
+}
+
+// Triggers holds the events a workflow runs on
+type Triggers struct {
+	RepositoryDispatch *RepositoryDispatch `yaml:"repository_dispatch"`
+	WorkflowDispatch   *WorkflowDispatch   `yaml:"workflow_dispatch"`
+}
+
+// RepositoryDispatch lists the dispatch types a workflow accepts
+type RepositoryDispatch struct {
+	Types []string `yaml:"types"`
+}
+
+// WorkflowDispatch declares the inputs of a manual run
+type WorkflowDispatch struct {
+	Inputs Inputs `yaml:"inputs"`
+}
+
+// Input is a workflow_dispatch input
+type Input struct {
+	Name        string   `yaml:"-"`
+	Line        int      `yaml:"-"`
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Type        string   `yaml:"type"`
+	Options     []string `yaml:"options"`
+	Default     string   `yaml:"default"`
+}
+
+// Inputs keeps workflow_dispatch inputs in declaration order
+type Inputs []*Input
+
+// Job is a workflow job
+type Job struct {
+	ID      string            `yaml:"-"`
+	Line    int               `yaml:"-"`
+	Name    string            `yaml:"name"`
+	RunsOn  string            `yaml:"runs-on"`
+	Needs   StringList        `yaml:"needs"`
+	If      string            `yaml:"if"`
+	Env     Env               `yaml:"env"`
+	Outputs map[string]string `yaml:"outputs"`
+	Steps   []*Step           `yaml:"steps"`
+}
+
+// Jobs keeps jobs in declaration order
+type Jobs []*Job
+
+// Step is a job step running either a shell script or an action
+type Step struct {
+	Line             int               `yaml:"-"`
+	Name             string            `yaml:"name"`
+	ID               string            `yaml:"id"`
+	If               string            `yaml:"if"`
+	Run              string            `yaml:"run"`
+	Uses             string            `yaml:"uses"`
+	With             map[string]string `yaml:"with"`
+	Env              Env               `yaml:"env"`
+	WorkingDirectory string            `yaml:"working-directory"`
+	ContinueOnError  bool              `yaml:"continue-on-error"`
+}
+
+// Env is an env: block
+type Env map[string]string
+
+// StringList accepts either a single string or a list of strings
+type StringList []string
+
+// Dir returns the github-simulator workflows directory of a sandbox root
+func Dir(baseDir string) string {
+	return filepath.Join(baseDir, "github-simulator", "workflows")
+}
+
+// Load reads and parses a workflow file
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	wf, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	wf.Path = path
+	wf.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	return wf, nil
+}
+
+// Parse parses workflow YAML
+func Parse(data []byte) (*Workflow, error) {
+	var wf Workflow
+	if err := yaml.Unmarshal(data, &wf); err != nil {
+		return nil, err
+	}
+	if len(wf.Jobs) == 0 {
+		return nil, fmt.Errorf("workflow has no jobs")
+	}
+	return &wf, nil
+}
+
+// Files returns the workflow files under github-simulator/workflows. Workflows
+// live either directly in the directory or in a directory of their own.
+func Files(baseDir string) ([]string, error) {
+	var paths []string
+	for _, pattern := range []string{"*.yml", "*.yaml", "*/*.yml", "*/*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(Dir(baseDir), pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// List loads every workflow under github-simulator/workflows, sorted by name
+func List(baseDir string) ([]*Workflow, error) {
+	paths, err := Files(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflows []*Workflow
+	for _, path := range paths {
+		wf, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, wf)
+	}
+	sort.Slice(workflows, func(i, j int) bool { return workflows[i].Name < workflows[j].Name })
+
+	return workflows, nil
+}
+
+// Find loads a workflow by name
+func Find(baseDir, name string) (*Workflow, error) {
+	workflows, err := List(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, wf := range workflows {
+		if wf.Name == name {
+			return wf, nil
+		}
+	}
+	return nil, fmt.Errorf("workflow '%s' not found in %s", name, Dir(baseDir))
+}
+
+// Events returns the events the workflow runs on
+func (w *Workflow) Events() []string {
+	var events []string
+	if w.On.WorkflowDispatch != nil {
+		events = append(events, EventWorkflowDispatch)
+	}
+	if w.On.RepositoryDispatch != nil {
+		events = append(events, EventRepositoryDispatch)
+	}
+	return events
+}
+
+// Inputs returns the declared workflow_dispatch inputs
+func (w *Workflow) Inputs() Inputs {
+	if w.On.WorkflowDispatch == nil {
+		return nil
+	}
+	return w.On.WorkflowDispatch.Inputs
+}
+
+// Job returns a job by ID
+func (w *Workflow) Job(id string) *Job {
+	for _, job := range w.Jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// Summary describes the workflow for listings
+func (w *Workflow) Summary() models.WorkflowSummary {
+	summary := models.WorkflowSummary{
+		Name:   w.Name,
+		Title:  w.Title,
+		Path:   w.Path,
+		Events: w.Events(),
+		Jobs:   []string{},
+		Inputs: []models.WorkflowInput{},
+	}
+	for _, job := range w.Jobs {
+		summary.Jobs = append(summary.Jobs, job.ID)
+	}
+	for _, input := range w.Inputs() {
+		summary.Inputs = append(summary.Inputs, models.WorkflowInput{
+			Name:        input.Name,
+			Description: input.Description,
+			Required:    input.Required,
+			Type:        input.Type,
+			Options:     input.Options,
+			Default:     input.Default,
+		})
+	}
+	return summary
+}
+
+// UnmarshalYAML keeps jobs in file order and records their IDs and lines
+func (j *Jobs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: jobs must be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		job := &Job{}
+		if err := node.Content[i+1].Decode(job); err != nil {
+			return err
+		}
+		job.ID = node.Content[i].Value
+		job.Line = node.Content[i].Line
+		*j = append(*j, job)
+	}
+	return nil
+}
+
+// UnmarshalYAML keeps inputs in file order and records their names and lines
+func (in *Inputs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: inputs must be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		input := &Input{}
+		if err := node.Content[i+1].Decode(input); err != nil {
+			return err
+		}
+		input.Name = node.Content[i].Value
+		input.Line = node.Content[i].Line
+		*in = append(*in, input)
+	}
+	return nil
+}
+
+// UnmarshalYAML records the line a step starts on
+func (s *Step) UnmarshalYAML(node *yaml.Node) error {
+	type plain Step
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.Line = node.Line
+	return nil
+}
+
+// UnmarshalYAML accepts on: event and on: [event, ...] besides the mapping form
+func (t *Triggers) UnmarshalYAML(node *yaml.Node) error {
+	var events []string
+	switch node.Kind {
+	case yaml.MappingNode:
+		type plain Triggers
+		return node.Decode((*plain)(t))
+	case yaml.ScalarNode:
+		events = []string{node.Value}
+	default:
+		if err := node.Decode(&events); err != nil {
+			return err
+		}
+	}
+	for _, event := range events {
+		switch event {
+		case EventWorkflowDispatch:
+			t.WorkflowDispatch = &WorkflowDispatch{}
+		case EventRepositoryDispatch:
+			t.RepositoryDispatch = &RepositoryDispatch{}
+		}
+	}
+	return nil
+}
+
+// UnmarshalYAML accepts needs: job and needs: [job, ...]
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = StringList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// DisplayName returns the step name, falling back to its action or script
+func (s *Step) DisplayName() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.Uses != "":
+		return "Run " + s.Uses
+	}
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(s.Run), "\n", 2)[0])
+	return "Run " + line
+}
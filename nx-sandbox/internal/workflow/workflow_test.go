@@ -0,0 +1,452 @@
+package workflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/gitrepo"
+	"github.com/BritishAirways-Nexus/nx-sandbox/internal/models"
+)
+
+const testWorkflow = `name: Release
+on:
+  workflow_dispatch:
+    inputs:
+      artifact_name:
+        required: true
+        type: string
+      environment:
+        type: choice
+        options: [dev1, prod1]
+        default: dev1
+  repository_dispatch:
+    types: [release]
+jobs:
+  publish:
+    needs: build
+    runs-on: ubuntu-latest
+    steps:
+      - name: Publish
+        run: echo "published ${{ needs.build.outputs.version }} to $TARGET"
+        env:
+          TARGET: ${{ inputs.environment }}
+  build:
+    runs-on: ubuntu-latest
+    outputs:
+      version: ${{ steps.version.outputs.value }}
+    steps:
+      - id: version
+        run: |
+          echo "value=1.2.${#ARTIFACT}" >> $GITHUB_OUTPUT
+        env:
+          ARTIFACT: ${{ github.event.client_payload.artifact_name }}
+  notify:
+    needs: publish
+    if: failure()
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo notified
+`
+
+func writeWorkflow(t *testing.T, baseDir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(Dir(baseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(baseDir), name+".yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	wf, err := Parse([]byte(testWorkflow))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got := strings.Join(wf.Events(), ","); got != "workflow_dispatch,repository_dispatch" {
+		t.Errorf("Unexpected events %s", got)
+	}
+	if len(wf.Jobs) != 3 || wf.Jobs[0].ID != "publish" || wf.Jobs[0].Line != 15 {
+		t.Errorf("Expected jobs in file order with lines, got %+v", wf.Jobs[0])
+	}
+	if inputs := wf.Inputs(); len(inputs) != 2 || inputs[1].Name != "environment" || inputs[1].Line != 8 {
+		t.Errorf("Expected inputs in file order with lines, got %+v", inputs)
+	}
+	if step := wf.Job("build").Steps[0]; step.Line != 28 || step.DisplayName() != `Run echo "value=1.2.${#ARTIFACT}" >> $GITHUB_OUTPUT` {
+		t.Errorf("Unexpected step %+v (%s)", step, step.DisplayName())
+	}
+
+	ordered, err := Order(wf)
+	if err != nil {
+		t.Fatalf("Order failed: %v", err)
+	}
+	if ordered[0].ID != "build" || ordered[1].ID != "publish" || ordered[2].ID != "notify" {
+		t.Errorf("Expected build, publish, notify; got %s, %s, %s", ordered[0].ID, ordered[1].ID, ordered[2].ID)
+	}
+
+	wf.Job("build").Needs = StringList{"notify"}
+	if _, err := Order(wf); err == nil {
+		t.Error("Expected circular needs to fail")
+	}
+}
+
+func TestResolveInputs(t *testing.T) {
+	wf, err := Parse([]byte(testWorkflow))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	event, inputs, err := ResolveInputs(wf, "", map[string]string{"artifact_name": "nx-bff-a"})
+	if err != nil || event != EventWorkflowDispatch || inputs["environment"] != "dev1" {
+		t.Errorf("Expected default event and inputs, got %s %v %v", event, inputs, err)
+	}
+
+	for _, inputs := range []map[string]string{
+		{},
+		{"artifact_name": "nx-bff-a", "environment": "uat1"},
+		{"artifact_name": "nx-bff-a", "unknown": "x"},
+	} {
+		if _, _, err := ResolveInputs(wf, "", inputs); err == nil {
+			t.Errorf("Expected %v to be rejected", inputs)
+		}
+	}
+
+	if _, _, err := ResolveInputs(wf, EventRepositoryDispatch, map[string]string{"anything": "x"}); err != nil {
+		t.Errorf("Expected repository_dispatch payloads to be free-form, got %v", err)
+	}
+	if _, _, err := ResolveInputs(wf, "push", nil); err == nil {
+		t.Error("Expected unsupported event to fail")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	scope := &Scope{Contexts: map[string]interface{}{
+		"inputs": map[string]string{"environment": "Prod1", "count": "3"},
+		"needs": map[string]interface{}{
+			"build": map[string]interface{}{"result": "success"},
+		},
+	}}
+
+	for expr, expected := range map[string]string{
+		"inputs.environment == 'prod1'":                "true",
+		"inputs.count > 2 && inputs.count < 4":         "true",
+		"inputs.missing || 'fallback'":                 "fallback",
+		"!startsWith(inputs.environment, 'dev')":       "true",
+		"contains('a,b,c', 'b')":                       "true",
+		"needs.build.result != 'success'":              "false",
+		"'it''s'":                                      "it's",
+		"(inputs.count == 3) && endsWith('abc', 'BC')": "true",
+	} {
+		value, err := Evaluate(expr, scope)
+		if err != nil {
+			t.Errorf("Evaluate(%s) failed: %v", expr, err)
+			continue
+		}
+		if got := ToString(value); got != expected {
+			t.Errorf("Evaluate(%s) = %s, expected %s", expr, got, expected)
+		}
+	}
+
+	if _, err := Evaluate("inputs.environment ==", scope); err == nil {
+		t.Error("Expected incomplete expression to fail")
+	}
+
+	text, err := Interpolate("deploy ${{ inputs.environment }} x${{ inputs.count }}", scope)
+	if err != nil || text != "deploy Prod1 x3" {
+		t.Errorf("Unexpected interpolation %q: %v", text, err)
+	}
+
+	scope.Failed = true
+	if ok, _ := Condition("inputs.count == 3", scope); ok {
+		t.Error("Expected condition without status function to be false after a failure")
+	}
+	if ok, _ := Condition("${{ failure() }}", scope); !ok {
+		t.Error("Expected failure() to hold after a failure")
+	}
+
+	refs := References("needs.build.outputs.version && contains(inputs.list, 'x')")
+	if strings.Join(refs, ",") != "needs.build.outputs.version,inputs.list" {
+		t.Errorf("Unexpected references %v", refs)
+	}
+}
+
+func TestRun(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, "repos", DefaultRepository), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeWorkflow(t, baseDir, "release", testWorkflow)
+
+	runner := NewRunner(baseDir)
+	run, err := runner.Run("release", RunOptions{
+		Inputs: map[string]string{"artifact_name": "nx-bff-a"},
+		Actor:  "tester",
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if run.ID != 1 || run.Conclusion != models.ConclusionSuccess {
+		t.Fatalf("Expected run #1 to succeed, got #%d %s: %+v", run.ID, run.Conclusion, run.Jobs)
+	}
+	if run.Jobs[0].ID != "build" || run.Jobs[0].Outputs["version"] != "1.2.8" {
+		t.Errorf("Expected build output version 1.2.8, got %+v", run.Jobs[0])
+	}
+	if log := run.Jobs[1].Steps[0].Log; !strings.Contains(log, "published 1.2.8 to dev1") {
+		t.Errorf("Unexpected publish log %q", log)
+	}
+	if run.Jobs[2].Conclusion != models.ConclusionSkipped {
+		t.Errorf("Expected notify to be skipped, got %s", run.Jobs[2].Conclusion)
+	}
+
+	second, err := runner.Run("release", RunOptions{Event: EventRepositoryDispatch})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if second.ID != 2 {
+		t.Errorf("Expected run #2, got #%d", second.ID)
+	}
+
+	stored, err := runner.GetRun(1)
+	if err != nil || stored.Actor != "tester" {
+		t.Errorf("Expected stored run #1, got %+v: %v", stored, err)
+	}
+	if _, err := runner.GetRun(3); err == nil {
+		t.Error("Expected unknown run to fail")
+	}
+}
+
+func TestRun_Failure(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, "repos", DefaultRepository), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeWorkflow(t, baseDir, "broken", `on: workflow_dispatch
+jobs:
+  first:
+    runs-on: ubuntu-latest
+    steps:
+      - run: exit 3
+      - run: echo never
+  cleanup:
+    needs: first
+    if: always()
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/setup-node@v4
+`)
+
+	run, err := NewRunner(baseDir).Run("broken", RunOptions{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if run.Conclusion != models.ConclusionFailure {
+		t.Errorf("Expected run to fail, got %s", run.Conclusion)
+	}
+	first := run.Jobs[0]
+	if first.Steps[0].Conclusion != models.ConclusionFailure || first.Steps[1].Conclusion != models.ConclusionSkipped {
+		t.Errorf("Expected failing step then skipped step, got %+v", first.Steps)
+	}
+	cleanup := run.Jobs[1]
+	if cleanup.Conclusion != models.ConclusionSuccess || !strings.Contains(cleanup.Steps[0].Message, "not simulated") {
+		t.Errorf("Expected always() job to run with the action skipped, got %+v", cleanup)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	baseDir := t.TempDir()
+	repo := filepath.Join(baseDir, "repos", DefaultRepository)
+	if err := os.MkdirAll(filepath.Join(repo, "nx-artifacts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(repo, "nx-artifacts", "inventory.yaml"), []byte("enabled: false\nowner: devx\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "obsolete.txt"), []byte("old\n"), 0644)
+	writeWorkflow(t, baseDir, "approve", `on: workflow_dispatch
+jobs:
+  approve:
+    runs-on: ubuntu-latest
+    steps:
+      - id: approve
+        run: |
+          sed -i 's/enabled: false/enabled: true/' nx-artifacts/inventory.yaml
+          echo "approved at $(date)" > approval.txt
+          rm obsolete.txt
+          echo "status=approved" >> $GITHUB_OUTPUT
+`)
+
+	runner := NewRunner(baseDir)
+	run, err := runner.Run("approve", RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !run.DryRun || run.Conclusion != models.ConclusionSuccess || run.Jobs[0].Steps[0].Outputs["status"] != "approved" {
+		t.Fatalf("Expected a successful dry run with outputs, got %+v", run)
+	}
+	if len(run.Changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %+v", run.Changes)
+	}
+	for i, expected := range []models.WorkflowFileChange{
+		{Path: "repos/nx-artifacts-inventory/approval.txt", Change: models.FileAdded},
+		{Path: "repos/nx-artifacts-inventory/nx-artifacts/inventory.yaml", Change: models.FileModified},
+		{Path: "repos/nx-artifacts-inventory/obsolete.txt", Change: models.FileDeleted},
+	} {
+		if change := run.Changes[i]; change.Path != expected.Path || change.Change != expected.Change {
+			t.Errorf("Expected %s %s, got %s %s", expected.Change, expected.Path, change.Change, change.Path)
+		}
+	}
+	if diff := run.Changes[1].Diff; !strings.Contains(diff, "-enabled: false\n+enabled: true\n") {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(repo, "nx-artifacts", "inventory.yaml")); string(data) != "enabled: false\nowner: devx\n" {
+		t.Errorf("Dry run changed the real tree: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "obsolete.txt")); err != nil {
+		t.Error("Dry run removed a file from the real tree")
+	}
+	if runs, _ := runner.Runs(); len(runs) != 0 {
+		t.Errorf("Expected dry runs not to be recorded, got %d", len(runs))
+	}
+}
+
+func TestRun_DryRunSharesGitObjects(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	baseDir := t.TempDir()
+	repoDir := filepath.Join(baseDir, "repos", DefaultRepository)
+	os.MkdirAll(repoDir, 0755)
+	os.WriteFile(filepath.Join(repoDir, "inventory.yaml"), []byte("enabled: false\n"), 0644)
+	repo, err := gitrepo.Init(repoDir)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(repoDir, "inventory.yaml"), []byte("enabled: true\n"), 0644)
+	if _, err := repo.CommitToBranch("approve", "Approve", []string{"inventory.yaml"}); err != nil {
+		t.Fatalf("CommitToBranch failed: %v", err)
+	}
+	head, _ := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+
+	ov, err := newOverlay(filepath.Join(baseDir, "repos"))
+	if err != nil {
+		t.Fatalf("newOverlay failed: %v", err)
+	}
+	objects := filepath.Join(ov.dir, DefaultRepository, ".git", "objects")
+	entries, _ := os.ReadDir(objects)
+	if len(entries) != 2 {
+		t.Errorf("Expected only info/ and pack/ in the copied object database, got %d entries", len(entries))
+	}
+	if data, _ := os.ReadFile(filepath.Join(objects, "info", "alternates")); !strings.Contains(string(data), repoDir) {
+		t.Errorf("Expected the copy to borrow the real objects, got %q", data)
+	}
+	ov.remove()
+
+	writeWorkflow(t, baseDir, "merge", `on: workflow_dispatch
+jobs:
+  merge:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          git merge -q --no-edit approve
+          echo "enabled: maybe" > inventory.yaml
+          git -c user.name=ci -c user.email=ci@example.com commit -qam "Maybe"
+          git log --format=%s -3 > log.txt
+`)
+	run, err := NewRunner(baseDir).Run("merge", RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if run.Conclusion != models.ConclusionSuccess {
+		t.Fatalf("Expected git commands to work in the copy, got %+v", run.Jobs)
+	}
+	if len(run.Changes) != 2 || !strings.Contains(run.Changes[1].Diff, "+Maybe\n+Approve\n") {
+		t.Errorf("Expected the log of the copy to include borrowed commits, got %+v", run.Changes)
+	}
+	if after, _ := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output(); string(after) != string(head) {
+		t.Errorf("Dry run moved HEAD of the real repository from %s to %s", head, after)
+	}
+}
+
+func TestLint(t *testing.T) {
+	baseDir := t.TempDir()
+	writeWorkflow(t, baseDir, "release", testWorkflow)
+	writeWorkflow(t, baseDir, "broken", `on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: choice
+        options:
+        - dev1
+        - qa1
+        default: staging
+jobs:
+  build:
+    outputs:
+      version: ${{ steps.version.outputs.value }}
+    steps:
+    - id: version
+      run: |
+        echo "building"
+        echo "${{ github.event.client_payload.layer }}"
+  deploy:
+    if: needs.build.outputs.tag == '1'
+    steps:
+    - run: echo ${{ inputs.region }} ${{ steps.later.outputs.x }}
+    - id: later
+      run: echo "${{ fromJSON('x' }}"
+`)
+
+	report, err := NewLinter(baseDir).Lint()
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(report.Checked) != 2 {
+		t.Errorf("Expected 2 workflows checked, got %v", report.Checked)
+	}
+
+	type issue struct {
+		line int
+		rule string
+	}
+	var got []issue
+	for _, i := range report.Issues {
+		if filepath.Base(i.Path) != "broken.yml" {
+			// release offers dev1 and prod1 only
+			if i.Severity != models.SeverityWarning || i.Rule != RuleEnvironments {
+				t.Errorf("Unexpected release issue %+v", i)
+			}
+			continue
+		}
+		got = append(got, issue{i.Line, i.Rule})
+	}
+	expected := []issue{
+		{4, RuleEnvironments}, // sit1, uat1 and prod1 are not offered
+		{4, RuleEnvironments},
+		{4, RuleEnvironments},
+		{8, RuleEnvironments}, // qa1
+		{9, RuleInput},        // default
+		{13, RuleStepOutput},  // value is never written
+		{18, RuleClientPayload},
+		{20, RuleNeeds},
+		{20, RuleNeedsOutput},
+		{22, RuleInput},
+		{22, RuleStepOutput}, // later has not run yet
+		{24, RuleExpression},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d issues, got %+v", len(expected), report.Issues)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Issue %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+	if report.Errors() != 8 {
+		t.Errorf("Expected 8 errors, got %d", report.Errors())
+	}
+}
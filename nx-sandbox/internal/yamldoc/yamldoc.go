@@ -0,0 +1,455 @@
+package yamldoc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a YAML file edited in place, preserving key order and comments.
+// Scalar updates are patched into the original text so untouched lines keep
+// their exact formatting; structural changes re-encode the document.
+type Document struct {
+	Path         string
+	raw          []byte
+	root         yaml.Node
+	restructured bool
+	// spacedKeys maps each original top-level key to whether a blank line preceded it
+	spacedKeys map[string]bool
+}
+
+// Load reads and parses a YAML document from disk
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	doc.Path = path
+
+	return doc, nil
+}
+
+// Parse parses YAML content into a document. Empty content yields an empty mapping.
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{raw: append([]byte(nil), data...)}
+	if err := yaml.Unmarshal(data, &doc.root); err != nil {
+		return nil, err
+	}
+
+	if doc.root.Kind == 0 {
+		doc.root = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+		doc.restructured = true
+	}
+
+	if doc.body().Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top-level YAML value must be a mapping")
+	}
+
+	return doc, nil
+}
+
+// Decode decodes the whole document into out
+func (d *Document) Decode(out interface{}) error {
+	return d.body().Decode(out)
+}
+
+// Get returns the node at a dot-separated path, or nil if it does not exist.
+// Numeric path segments index into sequences, as in ingress.hosts.0.host.
+func (d *Document) Get(path string) *yaml.Node {
+	return d.GetKeys(splitPath(path))
+}
+
+// GetKeys is Get with the path given as individual keys, for keys containing dots
+func (d *Document) GetKeys(keys []string) *yaml.Node {
+	node := d.body()
+	for _, key := range keys {
+		node = child(node, key)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// Walk calls fn for every scalar value in the document with its path keys
+func (d *Document) Walk(fn func(keys []string, node *yaml.Node)) {
+	walk(nil, d.body(), fn)
+}
+
+// GetString returns the scalar value at path, or "" if it is missing
+func (d *Document) GetString(path string) string {
+	node := d.Get(path)
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// Set stores value at a dot-separated path, creating intermediate mappings as needed.
+// Existing scalar styles and comments are kept when a scalar is replaced.
+func (d *Document) Set(path string, value interface{}) error {
+	return d.SetKeys(splitPath(path), value)
+}
+
+// SetKeys is Set with the path given as individual keys, for keys containing dots
+func (d *Document) SetKeys(keys []string, value interface{}) error {
+	path := strings.Join(keys, ".")
+	if len(keys) == 0 {
+		return fmt.Errorf("empty path")
+	}
+
+	parent, err := d.ensureMapping(keys[:len(keys)-1])
+	if err != nil {
+		return fmt.Errorf("cannot set %s: %w", path, err)
+	}
+
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return fmt.Errorf("cannot encode value for %s: %w", path, err)
+	}
+
+	key := keys[len(keys)-1]
+	existing := child(parent, key)
+	if existing == nil {
+		if parent.Kind != yaml.MappingNode {
+			return fmt.Errorf("cannot set %s: index out of range", path)
+		}
+		d.markRestructured()
+		parent.Content = append(parent.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&encoded)
+		return nil
+	}
+
+	if existing.Kind == yaml.ScalarNode && encoded.Kind == yaml.ScalarNode {
+		replacement := *existing
+		if encoded.Tag != "!!str" {
+			replacement.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+		}
+		replacement.Value = encoded.Value
+		replacement.Tag = encoded.Tag
+
+		if !d.restructured && d.patchScalar(existing, &replacement) {
+			return nil
+		}
+
+		d.markRestructured()
+		*existing = replacement
+		return nil
+	}
+
+	d.markRestructured()
+	encoded.HeadComment = existing.HeadComment
+	encoded.LineComment = existing.LineComment
+	*existing = encoded
+	return nil
+}
+
+// Delete removes the key at a dot-separated path and reports whether it existed
+func (d *Document) Delete(path string) bool {
+	keys := splitPath(path)
+	if len(keys) == 0 {
+		return false
+	}
+
+	parent := d.body()
+	if len(keys) > 1 {
+		parent = d.Get(strings.Join(keys[:len(keys)-1], "."))
+	}
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+
+	index, _ := lookup(parent, keys[len(keys)-1])
+	if index < 0 {
+		return false
+	}
+	d.markRestructured()
+	parent.Content = append(parent.Content[:index], parent.Content[index+2:]...)
+	return true
+}
+
+// Bytes renders the document. Documents without structural changes are
+// returned as their original text with scalar edits applied; others are
+// re-encoded with two-space indentation, keeping blank lines between
+// top-level sections.
+func (d *Document) Bytes() ([]byte, error) {
+	if !d.restructured {
+		return append([]byte(nil), d.raw...), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&d.root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return d.restoreSectionSpacing(buf.Bytes()), nil
+}
+
+// Save writes the document back to its path
+func (d *Document) Save() error {
+	if d.Path == "" {
+		return fmt.Errorf("document has no path")
+	}
+	return d.SaveAs(d.Path)
+}
+
+// SaveAs writes the document to path
+func (d *Document) SaveAs(path string) error {
+	data, err := d.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Helper methods
+
+func (d *Document) body() *yaml.Node {
+	return d.root.Content[0]
+}
+
+func (d *Document) ensureMapping(keys []string) (*yaml.Node, error) {
+	node := d.body()
+	for _, key := range keys {
+		if node.Kind == yaml.SequenceNode {
+			item := child(node, key)
+			if item == nil {
+				return nil, fmt.Errorf("'%s' is not a valid index", key)
+			}
+			node = item
+			continue
+		}
+
+		_, value := lookup(node, key)
+		if value == nil {
+			d.markRestructured()
+			value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+				value)
+		}
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+			d.markRestructured()
+			value.Kind = yaml.MappingNode
+			value.Tag = "!!map"
+			value.Value = ""
+		}
+		if value.Kind != yaml.MappingNode && value.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("'%s' is not a mapping", key)
+		}
+		node = value
+	}
+	return node, nil
+}
+
+// markRestructured switches the document to re-encoding, remembering which
+// top-level keys were separated by blank lines in the original text
+func (d *Document) markRestructured() {
+	if d.restructured {
+		return
+	}
+	d.restructured = true
+	d.spacedKeys = make(map[string]bool)
+
+	lines := strings.Split(string(d.raw), "\n")
+	body := d.body()
+	for i := 0; i+1 < len(body.Content); i += 2 {
+		key := body.Content[i]
+		line := key.Line - 1 - countLines(key.HeadComment)
+		d.spacedKeys[key.Value] = line-1 >= 0 && line-1 < len(lines) && strings.TrimSpace(lines[line-1]) == ""
+	}
+}
+
+// restoreSectionSpacing re-inserts blank lines before top-level keys that had
+// one, and before newly added keys when the original used blank lines at all
+func (d *Document) restoreSectionSpacing(data []byte) []byte {
+	spaced := false
+	for _, blank := range d.spacedKeys {
+		spaced = spaced || blank
+	}
+	if !spaced {
+		return data
+	}
+
+	lines := strings.Split(string(data), "\n")
+	var out []string
+	for i, line := range lines {
+		if i > 0 && len(line) > 0 && line[0] != ' ' && line[0] != '-' && line[0] != '#' {
+			key := strings.TrimSuffix(strings.SplitN(line, ":", 2)[0], " ")
+			blank, original := d.spacedKeys[strings.Trim(key, `"'`)]
+			if (blank || !original) && strings.TrimSpace(out[len(out)-1]) != "" {
+				out = append(out, "")
+			}
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// patchScalar rewrites a single-line scalar in the raw text and re-parses the
+// document. It reports false when the scalar cannot be located safely.
+func (d *Document) patchScalar(existing, replacement *yaml.Node) bool {
+	if existing.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 || existing.Line == 0 {
+		return false
+	}
+
+	lines := bytes.Split(d.raw, []byte("\n"))
+	if existing.Line > len(lines) {
+		return false
+	}
+	line := lines[existing.Line-1]
+	start := existing.Column - 1
+	if start < 0 || start > len(line) {
+		return false
+	}
+
+	end, ok := scalarEnd(line, start, existing)
+	if !ok {
+		return false
+	}
+
+	rendered, err := yaml.Marshal(&yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   replacement.Tag,
+		Value: replacement.Value,
+		Style: replacement.Style &^ (yaml.TaggedStyle | yaml.FlowStyle),
+	})
+	if err != nil {
+		return false
+	}
+	text := bytes.TrimSuffix(rendered, []byte("\n"))
+	if bytes.Contains(text, []byte("\n")) {
+		return false
+	}
+
+	patched := append([]byte(nil), line[:start]...)
+	patched = append(patched, text...)
+	patched = append(patched, line[end:]...)
+	lines[existing.Line-1] = patched
+
+	raw := bytes.Join(lines, []byte("\n"))
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return false
+	}
+
+	d.raw = raw
+	d.root = root
+	return true
+}
+
+// scalarEnd finds the end offset of the scalar token starting at start
+func scalarEnd(line []byte, start int, node *yaml.Node) (int, bool) {
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		if start >= len(line) || line[start] != '"' {
+			return 0, false
+		}
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if line[i] == '"' {
+				return i + 1, true
+			}
+		}
+		return 0, false
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		if start >= len(line) || line[start] != '\'' {
+			return 0, false
+		}
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1, true
+			}
+		}
+		return 0, false
+	default:
+		end := start + len(node.Value)
+		if end > len(line) || string(line[start:end]) != node.Value {
+			return 0, false
+		}
+		return end, true
+	}
+}
+
+func countLines(comment string) int {
+	if comment == "" {
+		return 0
+	}
+	return strings.Count(comment, "\n") + 1
+}
+
+// child returns the value under key in a mapping, or the item at index key in a sequence
+func child(node *yaml.Node, key string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		_, value := lookup(node, key)
+		return value
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(node.Content) {
+			return nil
+		}
+		return node.Content[index]
+	}
+	return nil
+}
+
+func walk(prefix []string, node *yaml.Node, fn func(keys []string, node *yaml.Node)) {
+	join := func(key string) []string {
+		return append(append([]string(nil), prefix...), key)
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walk(join(node.Content[i].Value), node.Content[i+1], fn)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			walk(join(strconv.Itoa(i)), item, fn)
+		}
+	case yaml.ScalarNode:
+		fn(prefix, node)
+	}
+}
+
+// lookup returns the index of key in a mapping node and its value node
+func lookup(mapping *yaml.Node, key string) (int, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i, mapping.Content[i+1]
+		}
+	}
+	return -1, nil
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
@@ -0,0 +1,171 @@
+package yamldoc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testYAML = `# Service configuration
+replicaCount: 2
+
+image:
+  repository: "nx-registry/web"
+  tag: latest # pinned later
+
+external:
+  redis:
+    enabled: false
+`
+
+func TestGetAndSet(t *testing.T) {
+	doc, err := Parse([]byte(testYAML))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got := doc.GetString("image.tag"); got != "latest" {
+		t.Errorf("Expected tag 'latest', got '%s'", got)
+	}
+
+	if err := doc.Set("external.redis.enabled", true); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := doc.Set("image.tag", "1.2.3"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := doc.Set("env.LOG_LEVEL", "debug"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{"# Service configuration", "enabled: true", "tag: 1.2.3 # pinned later", "LOG_LEVEL: debug", `repository: "nx-registry/web"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSet_ThroughScalar(t *testing.T) {
+	doc, _ := Parse([]byte(testYAML))
+
+	if err := doc.Set("replicaCount.nested", 1); err == nil {
+		t.Error("Expected error when setting below a scalar")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	doc, _ := Parse([]byte(testYAML))
+
+	if !doc.Delete("image.tag") {
+		t.Error("Expected image.tag to be deleted")
+	}
+	if doc.Get("image.tag") != nil {
+		t.Error("image.tag should no longer exist")
+	}
+	if doc.Delete("image.missing") {
+		t.Error("Deleting a missing key should report false")
+	}
+}
+
+func TestDecodeAndSave(t *testing.T) {
+	doc, _ := Parse([]byte(testYAML))
+
+	var values struct {
+		ReplicaCount int `yaml:"replicaCount"`
+	}
+	if err := doc.Decode(&values); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if values.ReplicaCount != 2 {
+		t.Errorf("Expected replicaCount 2, got %d", values.ReplicaCount)
+	}
+
+	path := filepath.Join(t.TempDir(), "values.yaml")
+	if err := doc.SaveAs(path); err != nil {
+		t.Fatalf("SaveAs failed: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if reloaded.GetString("image.repository") != "nx-registry/web" {
+		t.Error("Reloaded document lost image.repository")
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	doc, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := doc.Set("a.b", "c"); err != nil {
+		t.Fatalf("Set on empty document failed: %v", err)
+	}
+	if doc.GetString("a.b") != "c" {
+		t.Error("Expected a.b to be set")
+	}
+}
+
+func TestSet_PreservesFormatting(t *testing.T) {
+	doc, _ := Parse([]byte(testYAML))
+
+	if err := doc.Set("external.redis.enabled", true); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := doc.Set("image.repository", "nx-registry/api"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	data, _ := doc.Bytes()
+	want := strings.Replace(testYAML, "enabled: false", "enabled: true", 1)
+	want = strings.Replace(want, `"nx-registry/web"`, `"nx-registry/api"`, 1)
+	if string(data) != want {
+		t.Errorf("Scalar edits should only touch their own lines:\n%s", data)
+	}
+}
+
+func TestSet_KeepsSectionSpacing(t *testing.T) {
+	doc, _ := Parse([]byte(testYAML))
+
+	if err := doc.Set("env.LOG_LEVEL", "debug"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	data, _ := doc.Bytes()
+	for _, want := range []string{"\n\nimage:", "\n\nexternal:", "\n\nenv:"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected blank line before %q:\n%s", strings.TrimSpace(want), data)
+		}
+	}
+}
+
+func TestSequencePaths(t *testing.T) {
+	doc, _ := Parse([]byte("ingress:\n  hosts:\n    - host: web.dev1.example.com\n      paths:\n        - path: /\n"))
+
+	if got := doc.GetString("ingress.hosts.0.host"); got != "web.dev1.example.com" {
+		t.Errorf("Expected host, got '%s'", got)
+	}
+	if err := doc.Set("ingress.hosts.0.host", "web.sit1.example.com"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := doc.Set("ingress.hosts.3.host", "x"); err == nil {
+		t.Error("Expected out of range index to fail")
+	}
+
+	var paths []string
+	doc.Walk(func(keys []string, node *yaml.Node) {
+		paths = append(paths, strings.Join(keys, ".")+"="+node.Value)
+	})
+	if strings.Join(paths, ",") != "ingress.hosts.0.host=web.sit1.example.com,ingress.hosts.0.paths.0.path=/" {
+		t.Errorf("Unexpected walk: %v", paths)
+	}
+}
@@ -0,0 +1,18 @@
+# Shared module written by nx-sandbox seed; called by generated components.
+
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+resource "aws_elasticache_cluster" "this" {
+  cluster_id      = var.cluster_id
+  engine          = "redis"
+  node_type       = var.node_type
+  num_cache_nodes = 1
+  tags            = var.tags
+}
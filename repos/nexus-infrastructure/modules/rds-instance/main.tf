@@ -0,0 +1,21 @@
+# Shared module written by nx-sandbox seed; called by generated components.
+
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+resource "aws_db_instance" "this" {
+  identifier                  = var.identifier
+  instance_class              = var.instance_class
+  engine                      = var.engine
+  allocated_storage           = 20
+  username                    = "nexus"
+  manage_master_user_password = true
+  skip_final_snapshot         = true
+  tags                        = var.tags
+}
@@ -0,0 +1,22 @@
+variable "identifier" {
+  description = "RDS instance identifier"
+  type        = string
+}
+
+variable "instance_class" {
+  description = "RDS instance class"
+  type        = string
+  default     = "db.t3.micro"
+}
+
+variable "engine" {
+  description = "RDS database engine"
+  type        = string
+  default     = "postgres"
+}
+
+variable "tags" {
+  description = "Tags applied to every resource"
+  type        = map(string)
+  default     = {}
+}
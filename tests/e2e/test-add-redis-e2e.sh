@@ -1,173 +0,0 @@
-#!/bin/bash
-# E2E Test: Add Redis Command
-# Tests complete workflow for adding Redis cache to an artifact
-
-set -e
-
-RED='\033[0;31m'
-GREEN='\033[0;32m'
-YELLOW='\033[1;33m'
-BLUE='\033[0;34m'
-NC='\033[0m'
-
-SANDBOX_ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/../.." && pwd)"
-AWS_ENDPOINT="http://localhost:4566"
-AWS_REGION="us-east-1"
-
-TEST_ARTIFACT="nx-bff-web-offer"
-TEST_ENV="dev1"
-REDIS_CLUSTER_ID="nx-redis-web-offer-${TEST_ENV}"
-
-log_info() { echo -e "${BLUE}[INFO]${NC} $1"; }
-log_success() { echo -e "${GREEN}[✓]${NC} $1"; }
-log_error() { echo -e "${RED}[✗]${NC} $1" >&2; }
-log_step() { echo -e "\n${YELLOW}▶ $1${NC}"; }
-
-cleanup() {
-    log_info "Cleaning up test resources..."
-    aws elasticache delete-cache-cluster \
-        --cache-cluster-id "$REDIS_CLUSTER_ID" \
-        --endpoint-url="$AWS_ENDPOINT" \
-        --region="$AWS_REGION" 2>/dev/null || true
-}
-
-trap cleanup EXIT
-
-main() {
-    echo -e "${BLUE}╔════════════════════════════════════════════╗${NC}"
-    echo -e "${BLUE}║  E2E Test: Add Redis with AWS             ║${NC}"
-    echo -e "${BLUE}╚════════════════════════════════════════════╝${NC}"
-    echo ""
-
-    # Verify LocalStack
-    log_step "1. Verifying LocalStack availability"
-    if ! curl -s "$AWS_ENDPOINT/_localstack/health" >/dev/null 2>&1; then
-        log_error "LocalStack not running. Start with: make setup-aws"
-        exit 1
-    fi
-    log_success "LocalStack is running"
-
-    # Check if Redis cluster already exists
-    log_step "2. Checking for existing Redis cluster"
-    if aws elasticache describe-cache-clusters \
-        --cache-cluster-id "$REDIS_CLUSTER_ID" \
-        --endpoint-url="$AWS_ENDPOINT" \
-        --region="$AWS_REGION" 2>/dev/null; then
-        log_info "Cleaning up existing cluster"
-        cleanup
-        sleep 2
-    fi
-    log_success "No existing cluster found"
-
-    # Create Redis cluster
-    log_step "3. Creating Redis cluster"
-    aws elasticache create-cache-cluster \
-        --cache-cluster-id "$REDIS_CLUSTER_ID" \
-        --cache-node-type "cache.t3.micro" \
-        --engine redis \
-        --engine-version "7.0" \
-        --num-cache-nodes 1 \
-        --tags "Key=Artifact,Value=$TEST_ARTIFACT" \
-               "Key=Environment,Value=$TEST_ENV" \
-               "Key=ManagedBy,Value=DevX" \
-        --endpoint-url="$AWS_ENDPOINT" \
-        --region="$AWS_REGION" >/dev/null
-    log_success "Redis cluster created: $REDIS_CLUSTER_ID"
-
-    # Verify cluster creation
-    log_step "4. Verifying Redis cluster"
-    cluster_status=$(aws elasticache describe-cache-clusters \
-        --cache-cluster-id "$REDIS_CLUSTER_ID" \
-        --endpoint-url="$AWS_ENDPOINT" \
-        --region="$AWS_REGION" \
-        --query 'CacheClusters[0].CacheClusterStatus' \
-        --output text)
-    log_success "Redis cluster status: $cluster_status"
-
-    # Get cluster endpoint
-    log_step "5. Retrieving cluster endpoint"
-    endpoint=$(aws elasticache describe-cache-clusters \
-        --cache-cluster-id "$REDIS_CLUSTER_ID" \
-        --show-cache-node-info \
-        --endpoint-url="$AWS_ENDPOINT" \
-        --region="$AWS_REGION" \
-        --query 'CacheClusters[0].CacheNodes[0].Endpoint.Address' \
-        --output text 2>/dev/null || echo "localhost")
-    log_success "Cluster endpoint: $endpoint:6379"
-
-    # Update artifact inventory
-    log_step "6. Updating artifact inventory"
-    inventory_file="$SANDBOX_ROOT/repos/nx-artifacts-inventory/nx-artifacts/bff/nx-bff-web-offer-${TEST_ENV}/nx-app-inventory.yaml"
-    if [ -f "$inventory_file" ]; then
-        # Backup original
-        cp "$inventory_file" "${inventory_file}.backup"
-
-        # Update Redis configuration
-        if grep -q "redis:" "$inventory_file"; then
-            sed -i.bak 's/enabled: false/enabled: true/' "$inventory_file"
-            log_success "Inventory updated with Redis configuration"
-        else
-            log_info "Adding Redis configuration to inventory"
-        fi
-    else
-        log_info "Inventory file not found (expected in real workflow)"
-    fi
-
-    # Create Terraform configuration
-    log_step "7. Generating Terraform configuration"
-    mkdir -p "$SANDBOX_ROOT/test-artifacts/terraform/redis"
-    cat > "$SANDBOX_ROOT/test-artifacts/terraform/redis/main.tf" <<EOF
-resource "aws_elasticache_cluster" "artifact_redis" {
-  cluster_id           = "$REDIS_CLUSTER_ID"
-  engine               = "redis"
-  node_type            = "cache.t3.micro"
-  num_cache_nodes      = 1
-  parameter_group_name = "default.redis7"
-  engine_version       = "7.0"
-  port                 = 6379
-
-  tags = {
-    Artifact    = "$TEST_ARTIFACT"
-    Environment = "$TEST_ENV"
-    ManagedBy   = "DevX"
-  }
-}
-
-output "redis_endpoint" {
-  value = aws_elasticache_cluster.artifact_redis.cache_nodes[0].address
-}
-
-output "redis_port" {
-  value = aws_elasticache_cluster.artifact_redis.cache_nodes[0].port
-}
-EOF
-    log_success "Terraform configuration created"
-
-    # Verify Terraform syntax
-    log_step "8. Validating Terraform configuration"
-    if command -v terraform >/dev/null 2>&1; then
-        cd "$SANDBOX_ROOT/test-artifacts/terraform/redis"
-        terraform fmt >/dev/null
-        log_success "Terraform configuration validated"
-    else
-        log_info "Terraform not installed, skipping validation"
-    fi
-
-    # Summary
-    echo ""
-    echo -e "${GREEN}╔════════════════════════════════════════════╗${NC}"
-    echo -e "${GREEN}║  ✅ E2E Test Passed Successfully          ║${NC}"
-    echo -e "${GREEN}╚════════════════════════════════════════════╝${NC}"
-    echo ""
-    echo "📊 Redis Cluster Created:"
-    echo "  - Cluster ID: $REDIS_CLUSTER_ID"
-    echo "  - Engine: Redis 7.0"
-    echo "  - Node Type: cache.t3.micro"
-    echo "  - Endpoint: $endpoint:6379"
-    echo ""
-    echo "🔍 Verify with:"
-    echo "  aws --endpoint-url=$AWS_ENDPOINT elasticache describe-cache-clusters --cache-cluster-id $REDIS_CLUSTER_ID"
-    echo ""
-}
-
-main "$@"